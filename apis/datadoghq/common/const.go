@@ -17,6 +17,10 @@ const (
 	AgentDeploymentNameLabelKey = "agent.datadoghq.com/name"
 	// AgentDeploymentComponentLabelKey label key use to know with component is it
 	AgentDeploymentComponentLabelKey = "agent.datadoghq.com/component"
+	// AgentDeploymentProfileLabelKey label key use to know which profile a node Agent belongs to
+	AgentDeploymentProfileLabelKey = "agent.datadoghq.com/profile"
+	// DefaultAgentProfileName profile label value of the default node Agent ExtendedDaemonSet pods, set when profiles are defined
+	DefaultAgentProfileName = "default"
 	// MD5AgentDeploymentAnnotationKey annotation key used on a Resource in order to identify which AgentDeployment have been used to generate it.
 	MD5AgentDeploymentAnnotationKey = "agent.datadoghq.com/agentspechash"
 	// MD5ChecksumAnnotationKey annotation key is used to identify customConfig configurations
@@ -95,6 +95,7 @@ const (
 	DDRuntimeSecurityConfigRemoteTaggerEnabled      = "DD_RUNTIME_SECURITY_CONFIG_REMOTE_TAGGER"
 	DDRuntimeSecurityConfigSocket                   = "DD_RUNTIME_SECURITY_CONFIG_SOCKET"
 	DDRuntimeSecurityConfigSyscallMonitorEnabled    = "DD_RUNTIME_SECURITY_CONFIG_SYSCALL_MONITOR_ENABLED"
+	DDSecretBackendArguments                        = "DD_SECRET_BACKEND_ARGUMENTS"
 	DDSecretBackendCommand                          = "DD_SECRET_BACKEND_COMMAND"
 	DDSite                                          = "DD_SITE"
 	DDSystemProbeAgentEnabled                       = "DD_SYSTEM_PROBE_ENABLED"
@@ -31,6 +31,10 @@ func convertDatadogAgentSpec(src *DatadogAgentSpecAgentSpec, dst *v2alpha1.Datad
 		getV2TemplateOverride(&dst.Spec, v2alpha1.NodeAgentComponentName).Name = &src.DaemonsetName
 	}
 
+	if src.DeploymentStrategy != nil {
+		getV2TemplateOverride(&dst.Spec, v2alpha1.NodeAgentComponentName).UpdateStrategy = convertDeploymentStrategy(src.DeploymentStrategy)
+	}
+
 	if src.Config != nil {
 		if src.Config.SecurityContext != nil {
 			getV2TemplateOverride(&dst.Spec, v2alpha1.NodeAgentComponentName).SecurityContext = src.Config.SecurityContext
@@ -510,3 +514,23 @@ func convertSecurityAgentSpec(src *SecuritySpec, dst *v2alpha1.DatadogAgent) {
 		getV2Container(getV2TemplateOverride(&dst.Spec, v2alpha1.NodeAgentComponentName), commonv1.SecurityAgentContainerName).Args = src.Args
 	}
 }
+
+func convertDeploymentStrategy(src *DaemonSetDeploymentStrategy) *v2alpha1.DaemonSetUpdateStrategy {
+	dst := &v2alpha1.DaemonSetUpdateStrategy{
+		Type:               src.UpdateStrategyType,
+		Canary:             src.Canary,
+		ReconcileFrequency: src.ReconcileFrequency,
+	}
+
+	if src.RollingUpdate != (DaemonSetRollingUpdateSpec{}) {
+		dst.RollingUpdate = &v2alpha1.DaemonSetRollingUpdateSpec{
+			MaxUnavailable:            src.RollingUpdate.MaxUnavailable,
+			MaxPodSchedulerFailure:    src.RollingUpdate.MaxPodSchedulerFailure,
+			MaxParallelPodCreation:    src.RollingUpdate.MaxParallelPodCreation,
+			SlowStartIntervalDuration: src.RollingUpdate.SlowStartIntervalDuration,
+			SlowStartAdditiveIncrease: src.RollingUpdate.SlowStartAdditiveIncrease,
+		}
+	}
+
+	return dst
+}
@@ -0,0 +1,151 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package v1alpha1
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// DatadogDowntimeSpec defines the desired state of DatadogDowntime
+// Exactly one of MonitorRef, MonitorTags and MonitorSelector must be set
+// +k8s:openapi-gen=true
+type DatadogDowntimeSpec struct {
+	// Message is a message to include with notifications for this downtime
+	// +optional
+	Message string `json:"message,omitempty"`
+	// Scope is the list of scopes to which the downtime applies, e.g. `env:staging`. Defaults to `*`.
+	// +optional
+	// +listType=atomic
+	Scope []string `json:"scope,omitempty"`
+	// MonitorRef references a DatadogMonitor of the DatadogDowntime namespace to silence
+	// +optional
+	MonitorRef *corev1.LocalObjectReference `json:"monitorRef,omitempty"`
+	// MonitorTags silences the monitors with all these monitor tags, including the ones not managed by a DatadogMonitor
+	// +optional
+	// +listType=atomic
+	MonitorTags []string `json:"monitorTags,omitempty"`
+	// MonitorSelector silences the DatadogMonitors of the DatadogDowntime namespace matching this label selector
+	// +optional
+	MonitorSelector *metav1.LabelSelector `json:"monitorSelector,omitempty"`
+	// Start is the time to start the downtime. The downtime starts when it is created if not set.
+	// +optional
+	Start *metav1.Time `json:"start,omitempty"`
+	// End is the time to end the downtime. The downtime lasts until the DatadogDowntime is deleted if not set.
+	// +optional
+	End *metav1.Time `json:"end,omitempty"`
+	// Timezone is the timezone in which to display the downtime start and end times in Datadog, e.g. `Europe/Paris`
+	// +optional
+	Timezone string `json:"timezone,omitempty"`
+	// Recurrence repeats the downtime
+	// +optional
+	Recurrence *DatadogDowntimeRecurrence `json:"recurrence,omitempty"`
+	// CredentialsSecretRef references a Secret of the DatadogDowntime namespace containing the Datadog credentials
+	// used to manage the downtime. The operator credentials are used if not set.
+	// It must match the credentials of the silenced DatadogMonitors.
+	// +optional
+	CredentialsSecretRef *DatadogCredentialsSecretRef `json:"credentialsSecretRef,omitempty"`
+}
+
+// DatadogDowntimeRecurrence defines how a downtime is repeated
+// +k8s:openapi-gen=true
+type DatadogDowntimeRecurrence struct {
+	// Type is the type of recurrence
+	Type DatadogDowntimeRecurrenceType `json:"type"`
+	// Period is how often to repeat the downtime, e.g. every 3 days with a `days` type and a period of 3
+	// +optional
+	Period int32 `json:"period,omitempty"`
+	// WeekDays is the list of week days to repeat on, e.g. `Mon`. Only applicable with the `weeks` type.
+	// +optional
+	// +listType=atomic
+	WeekDays []string `json:"weekDays,omitempty"`
+	// RRule is the RRULE standard definition of the recurrence, e.g. `FREQ=MONTHLY;BYMONTHDAY=1`. Requires the `rrule` type.
+	// +optional
+	RRule string `json:"rrule,omitempty"`
+	// UntilDate is the time at which the recurrence ends. Mutually exclusive with UntilOccurrences.
+	// +optional
+	UntilDate *metav1.Time `json:"untilDate,omitempty"`
+	// UntilOccurrences is how many times the downtime is repeated. Mutually exclusive with UntilDate.
+	// +optional
+	UntilOccurrences *int32 `json:"untilOccurrences,omitempty"`
+}
+
+// DatadogDowntimeRecurrenceType defines the type of recurrence of a downtime
+// +kubebuilder:validation:Enum=days;weeks;months;years;rrule
+type DatadogDowntimeRecurrenceType string
+
+const (
+	// DatadogDowntimeRecurrenceTypeDays repeats the downtime every Period days
+	DatadogDowntimeRecurrenceTypeDays DatadogDowntimeRecurrenceType = "days"
+	// DatadogDowntimeRecurrenceTypeWeeks repeats the downtime every Period weeks
+	DatadogDowntimeRecurrenceTypeWeeks DatadogDowntimeRecurrenceType = "weeks"
+	// DatadogDowntimeRecurrenceTypeMonths repeats the downtime every Period months
+	DatadogDowntimeRecurrenceTypeMonths DatadogDowntimeRecurrenceType = "months"
+	// DatadogDowntimeRecurrenceTypeYears repeats the downtime every Period years
+	DatadogDowntimeRecurrenceTypeYears DatadogDowntimeRecurrenceType = "years"
+	// DatadogDowntimeRecurrenceTypeRRule repeats the downtime following RRule
+	DatadogDowntimeRecurrenceTypeRRule DatadogDowntimeRecurrenceType = "rrule"
+)
+
+// DatadogDowntimeStatus defines the observed state of DatadogDowntime
+// +k8s:openapi-gen=true
+type DatadogDowntimeStatus struct {
+	// Conditions Represents the latest available observations of a DatadogDowntime's current state.
+	// +optional
+	// +listType=map
+	// +listMapKey=type
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+
+	// Downtimes is the list of downtimes created in Datadog, one per silenced monitor
+	// +optional
+	// +listType=atomic
+	Downtimes []DatadogDowntimeStatusDowntime `json:"downtimes,omitempty"`
+
+	// CurrentHash tracks the hash of the current DatadogDowntimeSpec to know
+	// if the Spec has changed and the downtimes need an update
+	// +optional
+	CurrentHash string `json:"currentHash,omitempty"`
+}
+
+// DatadogDowntimeStatusDowntime is a downtime created in Datadog
+// +k8s:openapi-gen=true
+type DatadogDowntimeStatusDowntime struct {
+	// ID is the downtime ID generated in Datadog
+	ID int `json:"id"`
+	// MonitorID is the ID of the silenced monitor, unset when silencing by monitor tags
+	// +optional
+	MonitorID int `json:"monitorId,omitempty"`
+}
+
+// DatadogDowntime allows to define and manage Downtimes from your Kubernetes Cluster
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:path=datadogdowntimes,scope=Namespaced
+// +kubebuilder:printcolumn:name="active",type="string",JSONPath=".status.conditions[?(@.type=='Active')].status"
+// +kubebuilder:printcolumn:name="start",type="string",JSONPath=".spec.start"
+// +kubebuilder:printcolumn:name="end",type="string",JSONPath=".spec.end"
+// +kubebuilder:printcolumn:name="age",type="date",JSONPath=".metadata.creationTimestamp"
+// +k8s:openapi-gen=true
+// +genclient
+type DatadogDowntime struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   DatadogDowntimeSpec   `json:"spec,omitempty"`
+	Status DatadogDowntimeStatus `json:"status,omitempty"`
+}
+
+// DatadogDowntimeList contains a list of DatadogDowntimes
+// +kubebuilder:object:root=true
+type DatadogDowntimeList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []DatadogDowntime `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&DatadogDowntime{}, &DatadogDowntimeList{})
+}
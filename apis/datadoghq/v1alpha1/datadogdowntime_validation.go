@@ -0,0 +1,59 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package v1alpha1
+
+import (
+	"fmt"
+
+	utilserrors "k8s.io/apimachinery/pkg/util/errors"
+)
+
+// IsValidDatadogDowntime use to check if a DatadogDowntimeSpec is valid by checking
+// that it targets monitors and that its schedule is consistent
+func IsValidDatadogDowntime(spec *DatadogDowntimeSpec) error {
+	var errs []error
+
+	targets := 0
+	if spec.MonitorRef != nil {
+		targets++
+		if spec.MonitorRef.Name == "" {
+			errs = append(errs, fmt.Errorf("spec.MonitorRef.Name must be defined"))
+		}
+	}
+	if len(spec.MonitorTags) > 0 {
+		targets++
+	}
+	if spec.MonitorSelector != nil {
+		targets++
+	}
+	if targets != 1 {
+		errs = append(errs, fmt.Errorf("exactly one of spec.MonitorRef, spec.MonitorTags and spec.MonitorSelector must be defined"))
+	}
+
+	if spec.Start != nil && spec.End != nil && !spec.End.After(spec.Start.Time) {
+		errs = append(errs, fmt.Errorf("spec.End must be after spec.Start"))
+	}
+
+	if r := spec.Recurrence; r != nil {
+		if r.RRule != "" && r.Type != DatadogDowntimeRecurrenceTypeRRule {
+			errs = append(errs, fmt.Errorf("spec.Recurrence.RRule requires spec.Recurrence.Type to be %s", DatadogDowntimeRecurrenceTypeRRule))
+		}
+		if r.Type == DatadogDowntimeRecurrenceTypeRRule && r.RRule == "" {
+			errs = append(errs, fmt.Errorf("spec.Recurrence.RRule must be defined with the %s type", DatadogDowntimeRecurrenceTypeRRule))
+		}
+		if len(r.WeekDays) > 0 && r.Type != DatadogDowntimeRecurrenceTypeWeeks {
+			errs = append(errs, fmt.Errorf("spec.Recurrence.WeekDays requires spec.Recurrence.Type to be %s", DatadogDowntimeRecurrenceTypeWeeks))
+		}
+		if r.UntilDate != nil && r.UntilOccurrences != nil {
+			errs = append(errs, fmt.Errorf("spec.Recurrence.UntilDate and spec.Recurrence.UntilOccurrences are mutually exclusive"))
+		}
+		if r.Period < 0 {
+			errs = append(errs, fmt.Errorf("spec.Recurrence.Period must be positive"))
+		}
+	}
+
+	return utilserrors.NewAggregate(errs)
+}
@@ -0,0 +1,136 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package v1alpha1
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestIsValidDatadogDowntime(t *testing.T) {
+	start := metav1.NewTime(time.Date(2022, 6, 1, 10, 0, 0, 0, time.UTC))
+	end := metav1.NewTime(start.Add(time.Hour))
+	occurrences := int32(3)
+
+	testCases := []struct {
+		name    string
+		spec    *DatadogDowntimeSpec
+		wantErr string
+	}{
+		{
+			name: "monitor reference",
+			spec: &DatadogDowntimeSpec{MonitorRef: &corev1.LocalObjectReference{Name: "foo"}},
+		},
+		{
+			name: "monitor tags with schedule",
+			spec: &DatadogDowntimeSpec{
+				MonitorTags: []string{"team:foo"},
+				Start:       &start,
+				End:         &end,
+				Recurrence: &DatadogDowntimeRecurrence{
+					Type:             DatadogDowntimeRecurrenceTypeWeeks,
+					Period:           1,
+					WeekDays:         []string{"Mon", "Tue"},
+					UntilOccurrences: &occurrences,
+				},
+			},
+		},
+		{
+			name: "monitor selector with rrule",
+			spec: &DatadogDowntimeSpec{
+				MonitorSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"team": "foo"}},
+				Recurrence: &DatadogDowntimeRecurrence{
+					Type:  DatadogDowntimeRecurrenceTypeRRule,
+					RRule: "FREQ=MONTHLY;BYMONTHDAY=1",
+				},
+			},
+		},
+		{
+			name:    "no target",
+			spec:    &DatadogDowntimeSpec{},
+			wantErr: "exactly one of spec.MonitorRef, spec.MonitorTags and spec.MonitorSelector must be defined",
+		},
+		{
+			name: "several targets",
+			spec: &DatadogDowntimeSpec{
+				MonitorRef:  &corev1.LocalObjectReference{Name: "foo"},
+				MonitorTags: []string{"team:foo"},
+			},
+			wantErr: "exactly one of spec.MonitorRef, spec.MonitorTags and spec.MonitorSelector must be defined",
+		},
+		{
+			name:    "empty monitor reference",
+			spec:    &DatadogDowntimeSpec{MonitorRef: &corev1.LocalObjectReference{}},
+			wantErr: "spec.MonitorRef.Name must be defined",
+		},
+		{
+			name: "end before start",
+			spec: &DatadogDowntimeSpec{
+				MonitorTags: []string{"team:foo"},
+				Start:       &end,
+				End:         &start,
+			},
+			wantErr: "spec.End must be after spec.Start",
+		},
+		{
+			name: "rrule without rrule type",
+			spec: &DatadogDowntimeSpec{
+				MonitorTags: []string{"team:foo"},
+				Recurrence: &DatadogDowntimeRecurrence{
+					Type:  DatadogDowntimeRecurrenceTypeDays,
+					RRule: "FREQ=MONTHLY;BYMONTHDAY=1",
+				},
+			},
+			wantErr: "spec.Recurrence.RRule requires spec.Recurrence.Type to be rrule",
+		},
+		{
+			name: "rrule type without rrule",
+			spec: &DatadogDowntimeSpec{
+				MonitorTags: []string{"team:foo"},
+				Recurrence:  &DatadogDowntimeRecurrence{Type: DatadogDowntimeRecurrenceTypeRRule},
+			},
+			wantErr: "spec.Recurrence.RRule must be defined with the rrule type",
+		},
+		{
+			name: "week days without weeks type",
+			spec: &DatadogDowntimeSpec{
+				MonitorTags: []string{"team:foo"},
+				Recurrence: &DatadogDowntimeRecurrence{
+					Type:     DatadogDowntimeRecurrenceTypeDays,
+					WeekDays: []string{"Mon"},
+				},
+			},
+			wantErr: "spec.Recurrence.WeekDays requires spec.Recurrence.Type to be weeks",
+		},
+		{
+			name: "until date and occurrences",
+			spec: &DatadogDowntimeSpec{
+				MonitorTags: []string{"team:foo"},
+				Recurrence: &DatadogDowntimeRecurrence{
+					Type:             DatadogDowntimeRecurrenceTypeDays,
+					UntilDate:        &end,
+					UntilOccurrences: &occurrences,
+				},
+			},
+			wantErr: "spec.Recurrence.UntilDate and spec.Recurrence.UntilOccurrences are mutually exclusive",
+		},
+	}
+	for _, test := range testCases {
+		t.Run(test.name, func(t *testing.T) {
+			result := IsValidDatadogDowntime(test.spec)
+			if test.wantErr != "" {
+				assert.Error(t, result)
+				assert.EqualError(t, result, test.wantErr)
+			} else {
+				assert.NoError(t, result)
+			}
+		})
+	}
+}
@@ -18,7 +18,8 @@ type DatadogMonitorSpec struct {
 	Message string `json:"message,omitempty"`
 	// Priority is an integer from 1 (high) to 5 (low) indicating alert severity
 	Priority int64 `json:"priority,omitempty"`
-	// Query is the Datadog monitor query
+	// Query is the Datadog monitor query. The query of a composite monitor can reference the DatadogMonitors
+	// of the same namespace by name instead of ID, e.g. `${monitor-a} && ${monitor-b}`.
 	Query string `json:"query,omitempty"`
 	// Tags is the monitor tags associated with your monitor
 	Tags []string `json:"tags,omitempty"`
@@ -26,8 +27,87 @@ type DatadogMonitorSpec struct {
 	Type DatadogMonitorType `json:"type,omitempty"`
 	// Options are the optional parameters associated with your monitor
 	Options DatadogMonitorOptions `json:"options,omitempty"`
+	// CredentialsSecretRef references a Secret of the DatadogMonitor namespace containing the Datadog credentials
+	// used to manage the monitor. The operator credentials are used if not set.
+	// +optional
+	CredentialsSecretRef *DatadogCredentialsSecretRef `json:"credentialsSecretRef,omitempty"`
+	// DriftPolicy is the action taken when the monitor is changed outside of the DatadogMonitor, e.g. in the Datadog UI:
+	// `Revert` overwrites the changes with the DatadogMonitor spec, `Report` keeps them and reports them with the
+	// `Drifted` condition and an event, `Ignore` keeps them silently. Defaults to `Report`.
+	// +kubebuilder:validation:Enum=Revert;Report;Ignore
+	// +optional
+	DriftPolicy DatadogMonitorDriftPolicy `json:"driftPolicy,omitempty"`
+	// DeletionPolicy is the action taken on the monitor when the DatadogMonitor is deleted: `Delete` deletes the
+	// monitor, `Retain` keeps it and removes its `generated:kubernetes` tag, so that it can be adopted later.
+	// Defaults to the operator `datadogMonitorDeletionPolicy` flag, `Delete` by default.
+	// +kubebuilder:validation:Enum=Delete;Retain
+	// +optional
+	DeletionPolicy DatadogMonitorDeletionPolicy `json:"deletionPolicy,omitempty"`
+	// TargetRef references a workload of the DatadogMonitor namespace reflecting the monitor state, with the
+	// `monitor.datadoghq.com/<DatadogMonitor name>` annotation and optionally a workload status condition.
+	// +optional
+	TargetRef *DatadogMonitorTargetRef `json:"targetRef,omitempty"`
 }
 
+// DatadogMonitorDriftPolicy defines the action taken when the monitor drifts from the DatadogMonitor spec
+type DatadogMonitorDriftPolicy string
+
+const (
+	// DatadogMonitorDriftPolicyRevert overwrites the changes made outside of the DatadogMonitor
+	DatadogMonitorDriftPolicyRevert DatadogMonitorDriftPolicy = "Revert"
+	// DatadogMonitorDriftPolicyReport reports the changes made outside of the DatadogMonitor
+	DatadogMonitorDriftPolicyReport DatadogMonitorDriftPolicy = "Report"
+	// DatadogMonitorDriftPolicyIgnore ignores the changes made outside of the DatadogMonitor
+	DatadogMonitorDriftPolicyIgnore DatadogMonitorDriftPolicy = "Ignore"
+)
+
+// DatadogMonitorDeletionPolicy defines the action taken on the monitor when the DatadogMonitor is deleted
+type DatadogMonitorDeletionPolicy string
+
+const (
+	// DatadogMonitorDeletionPolicyDelete deletes the monitor with the DatadogMonitor
+	DatadogMonitorDeletionPolicyDelete DatadogMonitorDeletionPolicy = "Delete"
+	// DatadogMonitorDeletionPolicyRetain keeps the monitor when the DatadogMonitor is deleted
+	DatadogMonitorDeletionPolicyRetain DatadogMonitorDeletionPolicy = "Retain"
+)
+
+// DatadogMonitorTargetRef references a workload reflecting the state of a monitor
+type DatadogMonitorTargetRef struct {
+	// Kind is the kind of the workload: `Deployment` or `StatefulSet`.
+	// +kubebuilder:validation:Enum=Deployment;StatefulSet
+	Kind string `json:"kind"`
+	// Name is the name of the workload, in the namespace of the DatadogMonitor.
+	Name string `json:"name"`
+	// Conditions sets a condition reflecting the monitor state in the workload status, in addition to the annotation.
+	// The condition is `True` when the monitor is in Alert, Warn or No Data state.
+	// +optional
+	Conditions bool `json:"conditions,omitempty"`
+}
+
+// DatadogMonitorStateKeyPrefix is the prefix of the annotation and condition type holding the state of a monitor on
+// the workload referenced by the DatadogMonitor spec.targetRef, followed by the DatadogMonitor name
+const DatadogMonitorStateKeyPrefix = "monitor.datadoghq.com/"
+
+// DatadogCredentialsSecretRef references a Secret containing Datadog API and APP keys, and optionally a Datadog site
+type DatadogCredentialsSecretRef struct {
+	// Name is the name of the Secret, in the namespace of the resource referencing it.
+	Name string `json:"name"`
+	// APIKeyKey is the key of the Secret containing the API key. Defaults to `api_key`.
+	// +optional
+	APIKeyKey string `json:"apiKeyKey,omitempty"`
+	// APPKeyKey is the key of the Secret containing the APP key. Defaults to `app_key`.
+	// +optional
+	APPKeyKey string `json:"appKeyKey,omitempty"`
+	// SiteKey is the key of the Secret containing the Datadog site, e.g. `datadoghq.eu`. Defaults to `site`.
+	// The operator site is used if the Secret doesn't contain this key.
+	// +optional
+	SiteKey string `json:"siteKey,omitempty"`
+}
+
+// DatadogMonitorAdoptAnnotationKey is the annotation holding the ID of an existing Datadog monitor to adopt. Instead of
+// creating a new monitor, the DatadogMonitor takes ownership of this monitor and overwrites it with its spec.
+const DatadogMonitorAdoptAnnotationKey = "datadoghq.com/adopt-monitor-id"
+
 // DatadogMonitorType defines the type of monitor
 type DatadogMonitorType string
 
@@ -149,6 +229,18 @@ type DatadogMonitorStatus struct {
 	// resource (true) or outside Kubernetes (false)
 	Primary bool `json:"primary,omitempty"`
 
+	// ResolvedQuery is the query of a composite monitor sent to Datadog, with the DatadogMonitor
+	// references replaced by their IDs
+	ResolvedQuery string `json:"resolvedQuery,omitempty"`
+
+	// SyncedQuery is the monitor query returned by Datadog at the last creation or update of the monitor.
+	// Datadog normalizes the queries, so the drift of the query is detected against it.
+	SyncedQuery string `json:"syncedQuery,omitempty"`
+
+	// SyncedTargetRef is the workload reflecting the monitor state at the last sync, so that its annotation and
+	// condition are removed when spec.targetRef changes.
+	SyncedTargetRef *DatadogMonitorTargetRef `json:"syncedTargetRef,omitempty"`
+
 	// CurrentHash tracks the hash of the current DatadogMonitorSpec to know
 	// if the Spec has changed and needs an update
 	CurrentHash string `json:"currentHash,omitempty"`
@@ -187,6 +279,8 @@ const (
 	DatadogMonitorConditionTypeUpdated DatadogMonitorConditionType = "Updated"
 	// DatadogMonitorConditionTypeError means the DatadogMonitor has an error
 	DatadogMonitorConditionTypeError DatadogMonitorConditionType = "Error"
+	// DatadogMonitorConditionTypeDrifted means the monitor was changed outside of the DatadogMonitor
+	DatadogMonitorConditionTypeDrifted DatadogMonitorConditionType = "Drifted"
 )
 
 // DatadogMonitorState represents the overall DatadogMonitor state
@@ -221,6 +315,10 @@ const (
 	SyncStatusUpdateError SyncStatusMessage = "error updating monitor"
 	// SyncStatusGetError means there is an error getting the monitor
 	SyncStatusGetError SyncStatusMessage = "error getting monitor"
+	// SyncStatusCredentialsError means there is an error getting the Datadog credentials of the monitor
+	SyncStatusCredentialsError SyncStatusMessage = "error getting credentials"
+	// SyncStatusAdoptError means there is an error adopting an existing monitor
+	SyncStatusAdoptError SyncStatusMessage = "error adopting monitor"
 )
 
 // DatadogMonitorTriggeredState represents the details of a triggering DatadogMonitor
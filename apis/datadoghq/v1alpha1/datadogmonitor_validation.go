@@ -11,6 +11,9 @@ import (
 	utilserrors "k8s.io/apimachinery/pkg/util/errors"
 )
 
+// The DatadogMonitor validating webhook is served by the DatadogMonitor controller, see controllers/datadogmonitor/webhook.go
+// +kubebuilder:webhook:path=/validate-datadoghq-com-v1alpha1-datadogmonitor,mutating=false,failurePolicy=fail,sideEffects=None,groups=datadoghq.com,resources=datadogmonitors,verbs=create;update,versions=v1alpha1,name=vdatadogmonitor.kb.io,admissionReviewVersions=v1
+
 // IsValidDatadogMonitor use to check if a DatadogMonitorSpec is valid by checking
 // that the required fields are defined
 func IsValidDatadogMonitor(spec *DatadogMonitorSpec) error {
@@ -0,0 +1,197 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package v1alpha1
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// DatadogSLOSpec defines the desired state of DatadogSLO
+// +k8s:openapi-gen=true
+type DatadogSLOSpec struct {
+	// Name is the SLO name
+	Name string `json:"name"`
+	// Description is the description of the SLO
+	// +optional
+	Description string `json:"description,omitempty"`
+	// Tags is the SLO tags
+	// +optional
+	// +listType=atomic
+	Tags []string `json:"tags,omitempty"`
+	// Type is the SLO type
+	Type DatadogSLOType `json:"type"`
+	// MonitorRefs references the DatadogMonitors of the DatadogSLO namespace the SLO is based on. Only applicable with the `monitor` type.
+	// +optional
+	// +listType=atomic
+	MonitorRefs []corev1.LocalObjectReference `json:"monitorRefs,omitempty"`
+	// MonitorIDs is the list of IDs of the monitors the SLO is based on, for the monitors not managed by a DatadogMonitor.
+	// Only applicable with the `monitor` type.
+	// +optional
+	// +listType=atomic
+	MonitorIDs []int `json:"monitorIds,omitempty"`
+	// Groups is the list of monitor groups the SLO is based on, e.g. `env:prod`. Requires a single monitor.
+	// +optional
+	// +listType=atomic
+	Groups []string `json:"groups,omitempty"`
+	// Query is the metric query of the SLO. Required with the `metric` type.
+	// +optional
+	Query *DatadogSLOQuery `json:"query,omitempty"`
+	// Thresholds is the list of target thresholds of the SLO, one per timeframe
+	// +listType=atomic
+	Thresholds []DatadogSLOThreshold `json:"thresholds"`
+	// CredentialsSecretRef references a Secret of the DatadogSLO namespace containing the Datadog credentials
+	// used to manage the SLO. The operator credentials are used if not set.
+	// It must match the credentials of the referenced DatadogMonitors.
+	// +optional
+	CredentialsSecretRef *DatadogCredentialsSecretRef `json:"credentialsSecretRef,omitempty"`
+}
+
+// DatadogSLOType defines the type of SLO
+// +kubebuilder:validation:Enum=monitor;metric
+type DatadogSLOType string
+
+const (
+	// DatadogSLOTypeMonitor is the monitor-based SLO type
+	DatadogSLOTypeMonitor DatadogSLOType = "monitor"
+	// DatadogSLOTypeMetric is the metric-based SLO type
+	DatadogSLOTypeMetric DatadogSLOType = "metric"
+)
+
+// DatadogSLOQuery defines the metric query of a metric-based SLO: the ratio of good events to total events
+// +k8s:openapi-gen=true
+type DatadogSLOQuery struct {
+	// Numerator is the sum of the good events, e.g. `sum:requests.success{service:checkout}.as_count()`
+	Numerator string `json:"numerator"`
+	// Denominator is the sum of the total events, e.g. `sum:requests.total{service:checkout}.as_count()`
+	Denominator string `json:"denominator"`
+}
+
+// DatadogSLOThreshold defines the target of a SLO over a timeframe
+// +k8s:openapi-gen=true
+type DatadogSLOThreshold struct {
+	// Timeframe is the timeframe of the target
+	Timeframe DatadogSLOTimeframe `json:"timeframe"`
+	// Target is the target percentage of the SLO, between 0 and 100 excluded, e.g. `99.9`
+	Target string `json:"target"`
+	// Warning is the warning percentage of the SLO, greater than Target
+	// +optional
+	Warning *string `json:"warning,omitempty"`
+}
+
+// DatadogSLOTimeframe defines the timeframe of a SLO threshold
+// +kubebuilder:validation:Enum=7d;30d;90d
+type DatadogSLOTimeframe string
+
+const (
+	// DatadogSLOTimeframe7d is the 7 days timeframe
+	DatadogSLOTimeframe7d DatadogSLOTimeframe = "7d"
+	// DatadogSLOTimeframe30d is the 30 days timeframe
+	DatadogSLOTimeframe30d DatadogSLOTimeframe = "30d"
+	// DatadogSLOTimeframe90d is the 90 days timeframe
+	DatadogSLOTimeframe90d DatadogSLOTimeframe = "90d"
+)
+
+// DatadogSLOStatus defines the observed state of DatadogSLO
+// +k8s:openapi-gen=true
+type DatadogSLOStatus struct {
+	// Conditions Represents the latest available observations of a DatadogSLO's current state.
+	// +optional
+	// +listType=map
+	// +listMapKey=type
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+
+	// ID is the SLO ID generated in Datadog
+	// +optional
+	ID string `json:"id,omitempty"`
+	// Creator is the identity of the SLO creator
+	// +optional
+	Creator string `json:"creator,omitempty"`
+	// Created is the time the SLO was created
+	// +optional
+	Created *metav1.Time `json:"created,omitempty"`
+
+	// MonitorIDs is the list of IDs of the monitors the SLO is based on, including the resolved MonitorRefs
+	// +optional
+	// +listType=atomic
+	MonitorIDs []int `json:"monitorIds,omitempty"`
+
+	// Timeframes is the current state of the SLO for each threshold timeframe
+	// +optional
+	// +listType=map
+	// +listMapKey=timeframe
+	Timeframes []DatadogSLOTimeframeStatus `json:"timeframes,omitempty"`
+	// LastStateUpdateTime is the last time the SLO state was updated
+	// +optional
+	LastStateUpdateTime *metav1.Time `json:"lastStateUpdateTime,omitempty"`
+
+	// CurrentHash tracks the hash of the current DatadogSLOSpec to know
+	// if the Spec has changed and needs an update
+	// +optional
+	CurrentHash string `json:"currentHash,omitempty"`
+}
+
+// DatadogSLOTimeframeStatus is the state of a SLO over a threshold timeframe
+// +k8s:openapi-gen=true
+type DatadogSLOTimeframeStatus struct {
+	// Timeframe is the threshold timeframe
+	Timeframe DatadogSLOTimeframe `json:"timeframe"`
+	// State is the state of the SLO over the timeframe
+	// +optional
+	State DatadogSLOState `json:"state,omitempty"`
+	// SLIValue is the current SLI percentage over the timeframe
+	// +optional
+	SLIValue string `json:"sliValue,omitempty"`
+	// ErrorBudgetRemaining is the percentage of the error budget remaining over the timeframe
+	// +optional
+	ErrorBudgetRemaining string `json:"errorBudgetRemaining,omitempty"`
+}
+
+// DatadogSLOState is the state of a SLO over a timeframe
+type DatadogSLOState string
+
+const (
+	// DatadogSLOStateOK means the SLI is above the warning threshold, or the target if no warning is set
+	DatadogSLOStateOK DatadogSLOState = "OK"
+	// DatadogSLOStateWarning means the SLI is between the target and the warning threshold
+	DatadogSLOStateWarning DatadogSLOState = "Warning"
+	// DatadogSLOStateBreached means the SLI is below the target
+	DatadogSLOStateBreached DatadogSLOState = "Breached"
+	// DatadogSLOStateNoData means Datadog has no SLI value over the timeframe yet
+	DatadogSLOStateNoData DatadogSLOState = "No Data"
+)
+
+// DatadogSLO allows to define and manage Service Level Objectives from your Kubernetes Cluster
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:path=datadogslos,scope=Namespaced
+// +kubebuilder:printcolumn:name="id",type="string",JSONPath=".status.id"
+// +kubebuilder:printcolumn:name="type",type="string",JSONPath=".spec.type"
+// +kubebuilder:printcolumn:name="active",type="string",JSONPath=".status.conditions[?(@.type=='Active')].status"
+// +kubebuilder:printcolumn:name="sli",type="string",JSONPath=".status.timeframes[0].sliValue"
+// +kubebuilder:printcolumn:name="state",type="string",JSONPath=".status.timeframes[0].state"
+// +kubebuilder:printcolumn:name="age",type="date",JSONPath=".metadata.creationTimestamp"
+// +k8s:openapi-gen=true
+// +genclient
+type DatadogSLO struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   DatadogSLOSpec   `json:"spec,omitempty"`
+	Status DatadogSLOStatus `json:"status,omitempty"`
+}
+
+// DatadogSLOList contains a list of DatadogSLOs
+// +kubebuilder:object:root=true
+type DatadogSLOList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []DatadogSLO `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&DatadogSLO{}, &DatadogSLOList{})
+}
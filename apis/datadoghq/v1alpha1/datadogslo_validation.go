@@ -0,0 +1,76 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package v1alpha1
+
+import (
+	"fmt"
+	"strconv"
+
+	utilserrors "k8s.io/apimachinery/pkg/util/errors"
+)
+
+// IsValidDatadogSLO use to check if a DatadogSLOSpec is valid by checking
+// that the fields required by its type are defined and that its thresholds are consistent
+func IsValidDatadogSLO(spec *DatadogSLOSpec) error {
+	var errs []error
+
+	if spec.Name == "" {
+		errs = append(errs, fmt.Errorf("spec.Name must be defined"))
+	}
+
+	monitors := len(spec.MonitorRefs) + len(spec.MonitorIDs)
+	switch spec.Type {
+	case DatadogSLOTypeMonitor:
+		if monitors == 0 {
+			errs = append(errs, fmt.Errorf("spec.MonitorRefs or spec.MonitorIDs must be defined with the %s type", DatadogSLOTypeMonitor))
+		}
+		if spec.Query != nil {
+			errs = append(errs, fmt.Errorf("spec.Query requires spec.Type to be %s", DatadogSLOTypeMetric))
+		}
+		if len(spec.Groups) > 0 && monitors != 1 {
+			errs = append(errs, fmt.Errorf("spec.Groups requires a single monitor"))
+		}
+		for i, ref := range spec.MonitorRefs {
+			if ref.Name == "" {
+				errs = append(errs, fmt.Errorf("spec.MonitorRefs[%d].Name must be defined", i))
+			}
+		}
+	case DatadogSLOTypeMetric:
+		if spec.Query == nil || spec.Query.Numerator == "" || spec.Query.Denominator == "" {
+			errs = append(errs, fmt.Errorf("spec.Query.Numerator and spec.Query.Denominator must be defined with the %s type", DatadogSLOTypeMetric))
+		}
+		if monitors > 0 || len(spec.Groups) > 0 {
+			errs = append(errs, fmt.Errorf("spec.MonitorRefs, spec.MonitorIDs and spec.Groups require spec.Type to be %s", DatadogSLOTypeMonitor))
+		}
+	default:
+		errs = append(errs, fmt.Errorf("spec.Type must be one of %s and %s", DatadogSLOTypeMonitor, DatadogSLOTypeMetric))
+	}
+
+	if len(spec.Thresholds) == 0 {
+		errs = append(errs, fmt.Errorf("spec.Thresholds must be defined"))
+	}
+	timeframes := make(map[DatadogSLOTimeframe]bool, len(spec.Thresholds))
+	for i, threshold := range spec.Thresholds {
+		if timeframes[threshold.Timeframe] {
+			errs = append(errs, fmt.Errorf("spec.Thresholds[%d].Timeframe %s is defined more than once", i, threshold.Timeframe))
+		}
+		timeframes[threshold.Timeframe] = true
+
+		target, err := strconv.ParseFloat(threshold.Target, 64)
+		if err != nil || target <= 0 || target >= 100 {
+			errs = append(errs, fmt.Errorf("spec.Thresholds[%d].Target must be a number between 0 and 100 excluded", i))
+			continue
+		}
+		if threshold.Warning != nil {
+			warning, err := strconv.ParseFloat(*threshold.Warning, 64)
+			if err != nil || warning <= target || warning >= 100 {
+				errs = append(errs, fmt.Errorf("spec.Thresholds[%d].Warning must be a number between the target and 100 excluded", i))
+			}
+		}
+	}
+
+	return utilserrors.NewAggregate(errs)
+}
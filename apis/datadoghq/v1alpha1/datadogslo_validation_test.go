@@ -0,0 +1,179 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package v1alpha1
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+)
+
+func TestIsValidDatadogSLO(t *testing.T) {
+	warning := "99.95"
+	lowWarning := "99"
+	thresholds := []DatadogSLOThreshold{{Timeframe: DatadogSLOTimeframe30d, Target: "99.9", Warning: &warning}}
+
+	testCases := []struct {
+		name    string
+		spec    *DatadogSLOSpec
+		wantErr string
+	}{
+		{
+			name: "monitor SLO",
+			spec: &DatadogSLOSpec{
+				Name:        "foo",
+				Type:        DatadogSLOTypeMonitor,
+				MonitorRefs: []corev1.LocalObjectReference{{Name: "foo"}},
+				MonitorIDs:  []int{12345},
+				Thresholds:  thresholds,
+			},
+		},
+		{
+			name: "monitor SLO with groups",
+			spec: &DatadogSLOSpec{
+				Name:        "foo",
+				Type:        DatadogSLOTypeMonitor,
+				MonitorRefs: []corev1.LocalObjectReference{{Name: "foo"}},
+				Groups:      []string{"env:prod"},
+				Thresholds:  thresholds,
+			},
+		},
+		{
+			name: "metric SLO",
+			spec: &DatadogSLOSpec{
+				Name:       "foo",
+				Type:       DatadogSLOTypeMetric,
+				Query:      &DatadogSLOQuery{Numerator: "sum:good{*}.as_count()", Denominator: "sum:total{*}.as_count()"},
+				Thresholds: thresholds,
+			},
+		},
+		{
+			name: "missing name and thresholds",
+			spec: &DatadogSLOSpec{
+				Type:       DatadogSLOTypeMonitor,
+				MonitorIDs: []int{12345},
+			},
+			wantErr: "[spec.Name must be defined, spec.Thresholds must be defined]",
+		},
+		{
+			name: "monitor SLO without monitors",
+			spec: &DatadogSLOSpec{
+				Name:       "foo",
+				Type:       DatadogSLOTypeMonitor,
+				Thresholds: thresholds,
+			},
+			wantErr: "spec.MonitorRefs or spec.MonitorIDs must be defined with the monitor type",
+		},
+		{
+			name: "monitor SLO with query",
+			spec: &DatadogSLOSpec{
+				Name:       "foo",
+				Type:       DatadogSLOTypeMonitor,
+				MonitorIDs: []int{12345},
+				Query:      &DatadogSLOQuery{Numerator: "sum:good{*}.as_count()", Denominator: "sum:total{*}.as_count()"},
+				Thresholds: thresholds,
+			},
+			wantErr: "spec.Query requires spec.Type to be metric",
+		},
+		{
+			name: "groups with several monitors",
+			spec: &DatadogSLOSpec{
+				Name:        "foo",
+				Type:        DatadogSLOTypeMonitor,
+				MonitorRefs: []corev1.LocalObjectReference{{Name: "foo"}},
+				MonitorIDs:  []int{12345},
+				Groups:      []string{"env:prod"},
+				Thresholds:  thresholds,
+			},
+			wantErr: "spec.Groups requires a single monitor",
+		},
+		{
+			name: "empty monitor reference",
+			spec: &DatadogSLOSpec{
+				Name:        "foo",
+				Type:        DatadogSLOTypeMonitor,
+				MonitorRefs: []corev1.LocalObjectReference{{}},
+				Thresholds:  thresholds,
+			},
+			wantErr: "spec.MonitorRefs[0].Name must be defined",
+		},
+		{
+			name: "metric SLO without denominator",
+			spec: &DatadogSLOSpec{
+				Name:       "foo",
+				Type:       DatadogSLOTypeMetric,
+				Query:      &DatadogSLOQuery{Numerator: "sum:good{*}.as_count()"},
+				Thresholds: thresholds,
+			},
+			wantErr: "spec.Query.Numerator and spec.Query.Denominator must be defined with the metric type",
+		},
+		{
+			name: "metric SLO with monitors",
+			spec: &DatadogSLOSpec{
+				Name:       "foo",
+				Type:       DatadogSLOTypeMetric,
+				Query:      &DatadogSLOQuery{Numerator: "sum:good{*}.as_count()", Denominator: "sum:total{*}.as_count()"},
+				MonitorIDs: []int{12345},
+				Thresholds: thresholds,
+			},
+			wantErr: "spec.MonitorRefs, spec.MonitorIDs and spec.Groups require spec.Type to be monitor",
+		},
+		{
+			name: "unknown type",
+			spec: &DatadogSLOSpec{
+				Name:       "foo",
+				Type:       "foo",
+				Thresholds: thresholds,
+			},
+			wantErr: "spec.Type must be one of monitor and metric",
+		},
+		{
+			name: "duplicated timeframe",
+			spec: &DatadogSLOSpec{
+				Name:       "foo",
+				Type:       DatadogSLOTypeMonitor,
+				MonitorIDs: []int{12345},
+				Thresholds: []DatadogSLOThreshold{
+					{Timeframe: DatadogSLOTimeframe7d, Target: "99"},
+					{Timeframe: DatadogSLOTimeframe7d, Target: "99.9"},
+				},
+			},
+			wantErr: "spec.Thresholds[1].Timeframe 7d is defined more than once",
+		},
+		{
+			name: "invalid target",
+			spec: &DatadogSLOSpec{
+				Name:       "foo",
+				Type:       DatadogSLOTypeMonitor,
+				MonitorIDs: []int{12345},
+				Thresholds: []DatadogSLOThreshold{{Timeframe: DatadogSLOTimeframe7d, Target: "100"}},
+			},
+			wantErr: "spec.Thresholds[0].Target must be a number between 0 and 100 excluded",
+		},
+		{
+			name: "warning below target",
+			spec: &DatadogSLOSpec{
+				Name:       "foo",
+				Type:       DatadogSLOTypeMonitor,
+				MonitorIDs: []int{12345},
+				Thresholds: []DatadogSLOThreshold{{Timeframe: DatadogSLOTimeframe7d, Target: "99.9", Warning: &lowWarning}},
+			},
+			wantErr: "spec.Thresholds[0].Warning must be a number between the target and 100 excluded",
+		},
+	}
+	for _, test := range testCases {
+		t.Run(test.name, func(t *testing.T) {
+			result := IsValidDatadogSLO(test.spec)
+			if test.wantErr != "" {
+				assert.Error(t, result)
+				assert.EqualError(t, result, test.wantErr)
+			} else {
+				assert.NoError(t, result)
+			}
+		})
+	}
+}
@@ -1004,6 +1004,204 @@ func (in *DatadogCredentials) DeepCopy() *DatadogCredentials {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DatadogCredentialsSecretRef) DeepCopyInto(out *DatadogCredentialsSecretRef) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DatadogCredentialsSecretRef.
+func (in *DatadogCredentialsSecretRef) DeepCopy() *DatadogCredentialsSecretRef {
+	if in == nil {
+		return nil
+	}
+	out := new(DatadogCredentialsSecretRef)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DatadogDowntime) DeepCopyInto(out *DatadogDowntime) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DatadogDowntime.
+func (in *DatadogDowntime) DeepCopy() *DatadogDowntime {
+	if in == nil {
+		return nil
+	}
+	out := new(DatadogDowntime)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *DatadogDowntime) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DatadogDowntimeList) DeepCopyInto(out *DatadogDowntimeList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]DatadogDowntime, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DatadogDowntimeList.
+func (in *DatadogDowntimeList) DeepCopy() *DatadogDowntimeList {
+	if in == nil {
+		return nil
+	}
+	out := new(DatadogDowntimeList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *DatadogDowntimeList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DatadogDowntimeRecurrence) DeepCopyInto(out *DatadogDowntimeRecurrence) {
+	*out = *in
+	if in.WeekDays != nil {
+		in, out := &in.WeekDays, &out.WeekDays
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.UntilDate != nil {
+		in, out := &in.UntilDate, &out.UntilDate
+		*out = (*in).DeepCopy()
+	}
+	if in.UntilOccurrences != nil {
+		in, out := &in.UntilOccurrences, &out.UntilOccurrences
+		*out = new(int32)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DatadogDowntimeRecurrence.
+func (in *DatadogDowntimeRecurrence) DeepCopy() *DatadogDowntimeRecurrence {
+	if in == nil {
+		return nil
+	}
+	out := new(DatadogDowntimeRecurrence)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DatadogDowntimeSpec) DeepCopyInto(out *DatadogDowntimeSpec) {
+	*out = *in
+	if in.Scope != nil {
+		in, out := &in.Scope, &out.Scope
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.MonitorRef != nil {
+		in, out := &in.MonitorRef, &out.MonitorRef
+		*out = new(corev1.LocalObjectReference)
+		**out = **in
+	}
+	if in.MonitorTags != nil {
+		in, out := &in.MonitorTags, &out.MonitorTags
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.MonitorSelector != nil {
+		in, out := &in.MonitorSelector, &out.MonitorSelector
+		*out = new(metav1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Start != nil {
+		in, out := &in.Start, &out.Start
+		*out = (*in).DeepCopy()
+	}
+	if in.End != nil {
+		in, out := &in.End, &out.End
+		*out = (*in).DeepCopy()
+	}
+	if in.Recurrence != nil {
+		in, out := &in.Recurrence, &out.Recurrence
+		*out = new(DatadogDowntimeRecurrence)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.CredentialsSecretRef != nil {
+		in, out := &in.CredentialsSecretRef, &out.CredentialsSecretRef
+		*out = new(DatadogCredentialsSecretRef)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DatadogDowntimeSpec.
+func (in *DatadogDowntimeSpec) DeepCopy() *DatadogDowntimeSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(DatadogDowntimeSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DatadogDowntimeStatus) DeepCopyInto(out *DatadogDowntimeStatus) {
+	*out = *in
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]metav1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Downtimes != nil {
+		in, out := &in.Downtimes, &out.Downtimes
+		*out = make([]DatadogDowntimeStatusDowntime, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DatadogDowntimeStatus.
+func (in *DatadogDowntimeStatus) DeepCopy() *DatadogDowntimeStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(DatadogDowntimeStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DatadogDowntimeStatusDowntime) DeepCopyInto(out *DatadogDowntimeStatusDowntime) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DatadogDowntimeStatusDowntime.
+func (in *DatadogDowntimeStatusDowntime) DeepCopy() *DatadogDowntimeStatusDowntime {
+	if in == nil {
+		return nil
+	}
+	out := new(DatadogDowntimeStatusDowntime)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *DatadogFeatures) DeepCopyInto(out *DatadogFeatures) {
 	*out = *in
@@ -1409,6 +1607,16 @@ func (in *DatadogMonitorSpec) DeepCopyInto(out *DatadogMonitorSpec) {
 		copy(*out, *in)
 	}
 	in.Options.DeepCopyInto(&out.Options)
+	if in.CredentialsSecretRef != nil {
+		in, out := &in.CredentialsSecretRef, &out.CredentialsSecretRef
+		*out = new(DatadogCredentialsSecretRef)
+		**out = **in
+	}
+	if in.TargetRef != nil {
+		in, out := &in.TargetRef, &out.TargetRef
+		*out = new(DatadogMonitorTargetRef)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DatadogMonitorSpec.
@@ -1451,6 +1659,11 @@ func (in *DatadogMonitorStatus) DeepCopyInto(out *DatadogMonitorStatus) {
 		}
 	}
 	out.DowntimeStatus = in.DowntimeStatus
+	if in.SyncedTargetRef != nil {
+		in, out := &in.SyncedTargetRef, &out.SyncedTargetRef
+		*out = new(DatadogMonitorTargetRef)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DatadogMonitorStatus.
@@ -1463,6 +1676,21 @@ func (in *DatadogMonitorStatus) DeepCopy() *DatadogMonitorStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DatadogMonitorTargetRef) DeepCopyInto(out *DatadogMonitorTargetRef) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DatadogMonitorTargetRef.
+func (in *DatadogMonitorTargetRef) DeepCopy() *DatadogMonitorTargetRef {
+	if in == nil {
+		return nil
+	}
+	out := new(DatadogMonitorTargetRef)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *DatadogMonitorTriggeredState) DeepCopyInto(out *DatadogMonitorTriggeredState) {
 	*out = *in
@@ -1479,6 +1707,207 @@ func (in *DatadogMonitorTriggeredState) DeepCopy() *DatadogMonitorTriggeredState
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DatadogSLO) DeepCopyInto(out *DatadogSLO) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DatadogSLO.
+func (in *DatadogSLO) DeepCopy() *DatadogSLO {
+	if in == nil {
+		return nil
+	}
+	out := new(DatadogSLO)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *DatadogSLO) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DatadogSLOList) DeepCopyInto(out *DatadogSLOList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]DatadogSLO, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DatadogSLOList.
+func (in *DatadogSLOList) DeepCopy() *DatadogSLOList {
+	if in == nil {
+		return nil
+	}
+	out := new(DatadogSLOList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *DatadogSLOList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DatadogSLOQuery) DeepCopyInto(out *DatadogSLOQuery) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DatadogSLOQuery.
+func (in *DatadogSLOQuery) DeepCopy() *DatadogSLOQuery {
+	if in == nil {
+		return nil
+	}
+	out := new(DatadogSLOQuery)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DatadogSLOSpec) DeepCopyInto(out *DatadogSLOSpec) {
+	*out = *in
+	if in.Tags != nil {
+		in, out := &in.Tags, &out.Tags
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.MonitorRefs != nil {
+		in, out := &in.MonitorRefs, &out.MonitorRefs
+		*out = make([]corev1.LocalObjectReference, len(*in))
+		copy(*out, *in)
+	}
+	if in.MonitorIDs != nil {
+		in, out := &in.MonitorIDs, &out.MonitorIDs
+		*out = make([]int, len(*in))
+		copy(*out, *in)
+	}
+	if in.Groups != nil {
+		in, out := &in.Groups, &out.Groups
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Query != nil {
+		in, out := &in.Query, &out.Query
+		*out = new(DatadogSLOQuery)
+		**out = **in
+	}
+	if in.Thresholds != nil {
+		in, out := &in.Thresholds, &out.Thresholds
+		*out = make([]DatadogSLOThreshold, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.CredentialsSecretRef != nil {
+		in, out := &in.CredentialsSecretRef, &out.CredentialsSecretRef
+		*out = new(DatadogCredentialsSecretRef)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DatadogSLOSpec.
+func (in *DatadogSLOSpec) DeepCopy() *DatadogSLOSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(DatadogSLOSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DatadogSLOStatus) DeepCopyInto(out *DatadogSLOStatus) {
+	*out = *in
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]metav1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Created != nil {
+		in, out := &in.Created, &out.Created
+		*out = (*in).DeepCopy()
+	}
+	if in.MonitorIDs != nil {
+		in, out := &in.MonitorIDs, &out.MonitorIDs
+		*out = make([]int, len(*in))
+		copy(*out, *in)
+	}
+	if in.Timeframes != nil {
+		in, out := &in.Timeframes, &out.Timeframes
+		*out = make([]DatadogSLOTimeframeStatus, len(*in))
+		copy(*out, *in)
+	}
+	if in.LastStateUpdateTime != nil {
+		in, out := &in.LastStateUpdateTime, &out.LastStateUpdateTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DatadogSLOStatus.
+func (in *DatadogSLOStatus) DeepCopy() *DatadogSLOStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(DatadogSLOStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DatadogSLOThreshold) DeepCopyInto(out *DatadogSLOThreshold) {
+	*out = *in
+	if in.Warning != nil {
+		in, out := &in.Warning, &out.Warning
+		*out = new(string)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DatadogSLOThreshold.
+func (in *DatadogSLOThreshold) DeepCopy() *DatadogSLOThreshold {
+	if in == nil {
+		return nil
+	}
+	out := new(DatadogSLOThreshold)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DatadogSLOTimeframeStatus) DeepCopyInto(out *DatadogSLOTimeframeStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DatadogSLOTimeframeStatus.
+func (in *DatadogSLOTimeframeStatus) DeepCopy() *DatadogSLOTimeframeStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(DatadogSLOTimeframeStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *DogstatsdConfig) DeepCopyInto(out *DogstatsdConfig) {
 	*out = *in
@@ -12,6 +12,8 @@ const (
 	OverrideReconcileConflictConditionType = "OverrideReconcileConflict"
 	// DatadogAgentReconcileErrorConditionType ReconcileConditionType for DatadogAgent reconcile error
 	DatadogAgentReconcileErrorConditionType = "DatadogAgentReconcileError"
+	// FeatureReconcileConditionTypePrefix prefix of the ReconcileConditionType for each enabled feature, followed by the feature ID
+	FeatureReconcileConditionTypePrefix = "feature.datadoghq.com/"
 
 	// ExtraConfdConfigMapName is the name of the ConfigMap storing Custom Confd data
 	ExtraConfdConfigMapName = "%s-extra-confd"
@@ -6,9 +6,12 @@
 package v2alpha1
 
 import (
+	edsv1alpha1 "github.com/DataDog/extendeddaemonset/api/v1alpha1"
 	securityv1 "github.com/openshift/api/security/v1"
+	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
 
 	commonv1 "github.com/DataDog/datadog-operator/apis/datadoghq/common/v1"
 )
@@ -27,6 +30,11 @@ const (
 
 // DatadogAgentSpec defines the desired state of DatadogAgent
 type DatadogAgentSpec struct {
+	// BaseRef references the DatadogAgentTemplate whose spec is merged under this spec: the fields set in this spec
+	// take precedence over the template.
+	// +optional
+	BaseRef *DatadogAgentTemplateReference `json:"baseRef,omitempty"`
+
 	// Features running on the Agent and Cluster Agent
 	// +optional
 	Features *DatadogFeatures `json:"features,omitempty"`
@@ -38,6 +46,30 @@ type DatadogAgentSpec struct {
 	// Override the default configurations of the agents
 	// +optional
 	Override map[ComponentName]*DatadogAgentComponentOverride `json:"override,omitempty"`
+
+	// Profiles deploy a dedicated node Agent on the nodes matching their node selector, each with its own configuration.
+	// A node runs the node Agent of the first profile matching it, or the default node Agent if no profile matches it.
+	// +optional
+	// +listType=map
+	// +listMapKey=name
+	Profiles []DatadogAgentProfile `json:"profiles,omitempty"`
+}
+
+// DatadogAgentProfile configures the node Agent deployed on a pool of nodes.
+// +k8s:openapi-gen=true
+type DatadogAgentProfile struct {
+	// Name of the profile, used as suffix of the node Agent DaemonSet name.
+	Name string `json:"name"`
+
+	// NodeSelector selects the nodes of the profile: a node is part of the profile if it matches all the requirements.
+	// Supported operators are `In`, `NotIn`, `Exists` and `DoesNotExist`.
+	// +listType=atomic
+	NodeSelector []corev1.NodeSelectorRequirement `json:"nodeSelector"`
+
+	// Override the configuration of the node Agent of this profile. It is applied on top of the `nodeAgent` override.
+	// The fields `createRbac`, `extraConfd`, `extraChecksd` and `securityContextConstraints` are not supported.
+	// +optional
+	Override *DatadogAgentComponentOverride `json:"override,omitempty"`
 }
 
 // DatadogFeatures are features running on the Agent and Cluster Agent.
@@ -657,6 +689,11 @@ type GlobalConfig struct {
 	// Path to the container runtime socket (if different from Docker).
 	// +optional
 	CriSocketPath *string `json:"criSocketPath,omitempty"`
+
+	// SecretBackend configures the secret backend used by the Agents to resolve `ENC[]` secret handles.
+	// See also: https://docs.datadoghq.com/agent/guide/secrets-management
+	// +optional
+	SecretBackend *SecretBackendConfig `json:"secretBackend,omitempty"`
 }
 
 // DatadogCredentials is a generic structure that holds credentials to access Datadog.
@@ -684,12 +721,38 @@ type DatadogCredentials struct {
 }
 
 // SecretBackendConfig provides configuration for the secret backend.
+// +k8s:openapi-gen=true
 type SecretBackendConfig struct {
 	// Command defines the secret backend command to use
+	// +optional
 	Command *string `json:"command,omitempty"`
 
 	// Args defines the list of arguments to pass to the command
+	// +optional
+	// +listType=atomic
 	Args []string `json:"args,omitempty"`
+
+	// EnableGlobalPermissions grants the Agents the permission to read the Secrets of all the namespaces.
+	// Required by a secret backend command reading Kubernetes Secrets, like the one of the Operator helpers.
+	// +optional
+	EnableGlobalPermissions *bool `json:"enableGlobalPermissions,omitempty"`
+
+	// Roles grant the Agents the permission to read the given Secrets of a namespace.
+	// Use it instead of EnableGlobalPermissions to restrict the Secrets the Agents can read.
+	// +optional
+	// +listType=atomic
+	Roles []SecretBackendRolesConfig `json:"roles,omitempty"`
+}
+
+// SecretBackendRolesConfig provides the configuration of the Role granting the Agents the permission to read Secrets in a namespace.
+// +k8s:openapi-gen=true
+type SecretBackendRolesConfig struct {
+	// Namespace of the Secrets.
+	Namespace string `json:"namespace"`
+
+	// Secrets lists the names of the Secrets the Agents can read.
+	// +listType=set
+	Secrets []string `json:"secrets"`
 }
 
 // NetworkPolicyFlavor specifies which flavor of Network Policy to use.
@@ -828,6 +891,11 @@ type DatadogAgentComponentOverride struct {
 	// +optional
 	SecurityContextConstraints *SecurityContextConstraintsConfig `json:"securityContextConstraints,omitempty"`
 
+	// The update strategy of the node Agent DaemonSet or ExtendedDaemonSet.
+	// Only applicable for the node Agent.
+	// +optional
+	UpdateStrategy *DaemonSetUpdateStrategy `json:"updateStrategy,omitempty"`
+
 	// Pod-level SecurityContext.
 	// +optional
 	SecurityContext *corev1.PodSecurityContext `json:"securityContext,omitempty"`
@@ -885,6 +953,65 @@ type SecurityContextConstraintsConfig struct {
 	CustomConfiguration *securityv1.SecurityContextConstraints `json:"customConfiguration,omitempty"`
 }
 
+// DaemonSetUpdateStrategy contains the update strategy configuration of the node Agent.
+// +k8s:openapi-gen=true
+type DaemonSetUpdateStrategy struct {
+	// Type of the DaemonSet update strategy: "RollingUpdate" or "OnDelete". Default is RollingUpdate.
+	// Not applicable for an ExtendedDaemonSet deployment.
+	// +optional
+	// +kubebuilder:validation:Enum=RollingUpdate;OnDelete
+	Type *appsv1.DaemonSetUpdateStrategyType `json:"type,omitempty"`
+
+	// Configure the rolling update strategy of the DaemonSet or the ExtendedDaemonSet.
+	// +optional
+	RollingUpdate *DaemonSetRollingUpdateSpec `json:"rollingUpdate,omitempty"`
+
+	// Configure the canary deployment configuration of the ExtendedDaemonSet.
+	// Not applicable for a DaemonSet deployment.
+	// +optional
+	Canary *edsv1alpha1.ExtendedDaemonSetSpecStrategyCanary `json:"canary,omitempty"`
+
+	// The reconcile frequency of the ExtendedDaemonSet.
+	// Not applicable for a DaemonSet deployment.
+	// +optional
+	ReconcileFrequency *metav1.Duration `json:"reconcileFrequency,omitempty"`
+}
+
+// DaemonSetRollingUpdateSpec contains configuration fields of the rolling update strategy.
+// The configuration is shared between DaemonSet and ExtendedDaemonSet.
+// +k8s:openapi-gen=true
+type DaemonSetRollingUpdateSpec struct {
+	// The maximum number of DaemonSet pods that can be unavailable during the
+	// update. Value can be an absolute number (ex: 5) or a percentage of total
+	// number of DaemonSet pods at the start of the update (ex: 10%). Absolute
+	// number is calculated from percentage by rounding up.
+	// This cannot be 0.
+	// +optional
+	MaxUnavailable *intstr.IntOrString `json:"maxUnavailable,omitempty"`
+
+	// The maximum number of pods not scheduled on their Node due to a scheduler failure: resource constraints.
+	// Value can be an absolute number (ex: 5) or a percentage of total number of DaemonSet pods at the start of the update (ex: 10%).
+	// Only applicable for an ExtendedDaemonSet deployment.
+	// +optional
+	MaxPodSchedulerFailure *intstr.IntOrString `json:"maxPodSchedulerFailure,omitempty"`
+
+	// The maximum number of pods created in parallel.
+	// Only applicable for an ExtendedDaemonSet deployment.
+	// +optional
+	MaxParallelPodCreation *int32 `json:"maxParallelPodCreation,omitempty"`
+
+	// SlowStartIntervalDuration is the duration between two increases of the number of pods created in parallel.
+	// Only applicable for an ExtendedDaemonSet deployment.
+	// +optional
+	SlowStartIntervalDuration *metav1.Duration `json:"slowStartIntervalDuration,omitempty"`
+
+	// SlowStartAdditiveIncrease is the number of pods added to the number of pods created in parallel at each slow start interval.
+	// Value can be an absolute number (ex: 5) or a percentage of total number of DaemonSet pods at the start of the update (ex: 10%).
+	// Only applicable for an ExtendedDaemonSet deployment.
+	// +optional
+	SlowStartAdditiveIncrease *intstr.IntOrString `json:"slowStartAdditiveIncrease,omitempty"`
+}
+
 // DatadogAgentGenericContainer is the generic structure describing any container's common configuration.
 // +k8s:openapi-gen=true
 type DatadogAgentGenericContainer struct {
@@ -969,6 +1096,69 @@ type DatadogAgentStatus struct {
 	// The actual state of the Cluster Checks Runner as a deployment.
 	// +optional
 	ClusterChecksRunner *commonv1.DeploymentStatus `json:"clusterChecksRunner,omitempty"`
+	// The actual state of the node Agent of each profile.
+	// +optional
+	// +listType=map
+	// +listMapKey=name
+	Profiles []DatadogAgentProfileStatus `json:"profiles,omitempty"`
+	// The enabled features and the components they require.
+	// The result of the reconcile of each feature is reported in the condition of type `feature.datadoghq.com/<feature ID>`.
+	// +optional
+	// +listType=map
+	// +listMapKey=id
+	Features []DatadogAgentFeatureStatus `json:"features,omitempty"`
+	// The DatadogAgentTemplate merged under the spec during the last reconcile.
+	// +optional
+	Template *DatadogAgentTemplateStatus `json:"template,omitempty"`
+}
+
+// DatadogAgentProfileStatus defines the observed state of the node Agent of a profile.
+// +k8s:openapi-gen=true
+type DatadogAgentProfileStatus struct {
+	// Name of the profile.
+	Name string `json:"name"`
+	// The actual state of the node Agent of the profile.
+	// +optional
+	Agent *commonv1.DaemonSetStatus `json:"agent,omitempty"`
+}
+
+// DatadogAgentFeatureStatus defines the observed state of an enabled feature.
+// +k8s:openapi-gen=true
+type DatadogAgentFeatureStatus struct {
+	// ID of the feature.
+	ID string `json:"id"`
+	// The components required by the feature.
+	// +optional
+	// +listType=map
+	// +listMapKey=name
+	RequiredComponents []DatadogAgentFeatureComponent `json:"requiredComponents,omitempty"`
+}
+
+// DatadogAgentFeatureComponent defines a component required by a feature.
+// +k8s:openapi-gen=true
+type DatadogAgentFeatureComponent struct {
+	// Name of the component.
+	Name ComponentName `json:"name"`
+	// The containers of the component required by the feature.
+	// +optional
+	// +listType=set
+	Containers []commonv1.AgentContainerName `json:"containers,omitempty"`
+}
+
+// DatadogAgentTemplateReference references a DatadogAgentTemplate.
+// +k8s:openapi-gen=true
+type DatadogAgentTemplateReference struct {
+	// Name of the DatadogAgentTemplate.
+	Name string `json:"name"`
+}
+
+// DatadogAgentTemplateStatus defines the DatadogAgentTemplate merged under the spec of a DatadogAgent.
+// +k8s:openapi-gen=true
+type DatadogAgentTemplateStatus struct {
+	// Name of the DatadogAgentTemplate.
+	Name string `json:"name"`
+	// Generation of the DatadogAgentTemplate merged under the spec.
+	Generation int64 `json:"generation"`
 }
 
 // DatadogAgent Deployment with the Datadog Operator.
@@ -0,0 +1,239 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package v2alpha1
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	apicommon "github.com/DataDog/datadog-operator/apis/datadoghq/common"
+
+	corev1 "k8s.io/api/core/v1"
+	utilserrors "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"sigs.k8s.io/yaml"
+
+	apiutils "github.com/DataDog/datadog-operator/apis/utils"
+)
+
+var endpointPortRegexp = regexp.MustCompile(`:(\d+)$`)
+
+// IsValidDatadogAgent use to check if a DatadogAgentSpec is valid
+func IsValidDatadogAgent(spec *DatadogAgentSpec) error {
+	var errs []error
+
+	profileNames := make(map[string]bool, len(spec.Profiles))
+	for i, profile := range spec.Profiles {
+		if profileNames[profile.Name] {
+			errs = append(errs, fmt.Errorf("invalid spec.profiles[%d], err: duplicate profile name %q", i, profile.Name))
+		}
+		profileNames[profile.Name] = true
+
+		if err := IsValidDatadogAgentProfile(&profile); err != nil {
+			errs = append(errs, fmt.Errorf("invalid spec.profiles[%d], err: %w", i, err))
+		}
+	}
+
+	errs = append(errs, validateCustomConfigs(spec)...)
+
+	// The host ports depend on the default values of the features
+	defaulted := &DatadogAgent{Spec: *spec.DeepCopy()}
+	DefaultDatadogAgent(defaulted)
+	errs = append(errs, validateHostPorts(&defaulted.Spec)...)
+
+	return utilserrors.NewAggregate(errs)
+}
+
+// validateCustomConfigs checks that the custom configurations are set either inline or with a ConfigMap, and that the
+// inline YAML configurations can be parsed
+func validateCustomConfigs(spec *DatadogAgentSpec) []error {
+	var errs []error
+	validate := func(path string, config *CustomConfig, isYAML bool) {
+		if config == nil {
+			return
+		}
+		if config.ConfigData != nil && config.ConfigMap != nil {
+			errs = append(errs, fmt.Errorf("invalid %s: 'configData' and 'configMap' cannot be set together", path))
+		}
+		if isYAML && config.ConfigData != nil {
+			if err := isValidYAML(*config.ConfigData); err != nil {
+				errs = append(errs, fmt.Errorf("invalid %s.configData: %w", path, err))
+			}
+		}
+	}
+
+	if features := spec.Features; features != nil {
+		if features.CSPM != nil {
+			validate("spec.features.cspm.customBenchmarks", features.CSPM.CustomBenchmarks, false)
+		}
+		if features.CWS != nil {
+			validate("spec.features.cws.customPolicies", features.CWS.CustomPolicies, false)
+		}
+		if features.Dogstatsd != nil {
+			validate("spec.features.dogstatsd.mapperProfiles", features.Dogstatsd.MapperProfiles, true)
+		}
+		if features.OrchestratorExplorer != nil {
+			validate("spec.features.orchestratorExplorer.conf", features.OrchestratorExplorer.Conf, true)
+		}
+		if features.KubeStateMetricsCore != nil {
+			validate("spec.features.kubeStateMetricsCore.conf", features.KubeStateMetricsCore.Conf, true)
+		}
+	}
+
+	// Sort the components for stable error messages
+	components := make([]string, 0, len(spec.Override))
+	for component := range spec.Override {
+		components = append(components, string(component))
+	}
+	sort.Strings(components)
+	for _, component := range components {
+		override := spec.Override[ComponentName(component)]
+		if override == nil {
+			continue
+		}
+		fileNames := make([]string, 0, len(override.CustomConfigurations))
+		for fileName := range override.CustomConfigurations {
+			fileNames = append(fileNames, string(fileName))
+		}
+		sort.Strings(fileNames)
+		for _, fileName := range fileNames {
+			config := override.CustomConfigurations[AgentConfigFileName(fileName)]
+			validate(fmt.Sprintf("spec.override.%s.customConfigurations[%s]", component, fileName), &config, true)
+		}
+		for _, multi := range []struct {
+			name   string
+			config *MultiCustomConfig
+		}{{"extraConfd", override.ExtraConfd}, {"extraChecksd", override.ExtraChecksd}} {
+			if multi.config == nil {
+				continue
+			}
+			if len(multi.config.ConfigDataMap) > 0 && multi.config.ConfigMap != nil {
+				errs = append(errs, fmt.Errorf("invalid spec.override.%s.%s: 'configDataMap' and 'configMap' cannot be set together", component, multi.name))
+			}
+		}
+		if override.ExtraConfd != nil {
+			fileNames = make([]string, 0, len(override.ExtraConfd.ConfigDataMap))
+			for fileName := range override.ExtraConfd.ConfigDataMap {
+				fileNames = append(fileNames, fileName)
+			}
+			sort.Strings(fileNames)
+			for _, fileName := range fileNames {
+				if err := isValidYAML(override.ExtraConfd.ConfigDataMap[fileName]); err != nil {
+					errs = append(errs, fmt.Errorf("invalid spec.override.%s.extraConfd.configDataMap[%s]: %w", component, fileName, err))
+				}
+			}
+		}
+	}
+
+	return errs
+}
+
+func isValidYAML(data string) error {
+	var out interface{}
+	return yaml.Unmarshal([]byte(data), &out)
+}
+
+// validateHostPorts checks that the host ports opened by the node Agent don't conflict
+func validateHostPorts(spec *DatadogAgentSpec) []error {
+	var errs []error
+	features := spec.Features
+	if features == nil {
+		return nil
+	}
+
+	hostPorts := map[string]string{}
+	add := func(port int32, protocol corev1.Protocol, path string) {
+		key := fmt.Sprintf("%d/%s", port, protocol)
+		if other, found := hostPorts[key]; found {
+			errs = append(errs, fmt.Errorf("host port %s of %s is already used by %s", key, path, other))
+			return
+		}
+		hostPorts[key] = path
+	}
+
+	if apm := features.APM; apm != nil && apiutils.BoolValue(apm.Enabled) && apm.HostPortConfig != nil && apiutils.BoolValue(apm.HostPortConfig.Enabled) && apm.HostPortConfig.Port != nil {
+		add(*apm.HostPortConfig.Port, corev1.ProtocolTCP, "spec.features.apm.hostPortConfig")
+	}
+	if dsd := features.Dogstatsd; dsd != nil && dsd.HostPortConfig != nil && apiutils.BoolValue(dsd.HostPortConfig.Enabled) && dsd.HostPortConfig.Port != nil {
+		add(*dsd.HostPortConfig.Port, corev1.ProtocolUDP, "spec.features.dogstatsd.hostPortConfig")
+	}
+	addEndpoint := func(enabled *bool, endpoint *string, path string) {
+		if !apiutils.BoolValue(enabled) || endpoint == nil {
+			return
+		}
+		match := endpointPortRegexp.FindStringSubmatch(*endpoint)
+		if match == nil {
+			errs = append(errs, fmt.Errorf("invalid %s.endpoint %q: the port must be set", path, *endpoint))
+			return
+		}
+		port, err := strconv.Atoi(match[1])
+		if err != nil || port > 65535 {
+			errs = append(errs, fmt.Errorf("invalid %s.endpoint %q: invalid port", path, *endpoint))
+			return
+		}
+		add(int32(port), corev1.ProtocolTCP, path)
+	}
+	if otlp := features.OTLP; otlp != nil {
+		if grpc := otlp.Receiver.Protocols.GRPC; grpc != nil {
+			addEndpoint(grpc.Enabled, grpc.Endpoint, "spec.features.otlp.receiver.protocols.grpc")
+		}
+		if http := otlp.Receiver.Protocols.HTTP; http != nil {
+			addEndpoint(http.Enabled, http.Endpoint, "spec.features.otlp.receiver.protocols.http")
+		}
+	}
+
+	return errs
+}
+
+// IsValidDatadogAgentProfile use to check if a DatadogAgentProfile is valid
+func IsValidDatadogAgentProfile(profile *DatadogAgentProfile) error {
+	var errs []error
+
+	if msgs := validation.IsDNS1123Label(profile.Name); len(msgs) > 0 {
+		errs = append(errs, fmt.Errorf("invalid name %q: %s", profile.Name, strings.Join(msgs, ", ")))
+	}
+	if profile.Name == apicommon.DefaultAgentProfileName {
+		errs = append(errs, fmt.Errorf("invalid name %q: reserved for the default node Agent", profile.Name))
+	}
+
+	if len(profile.NodeSelector) == 0 {
+		errs = append(errs, fmt.Errorf("'nodeSelector' should contain at least one requirement"))
+	}
+	for _, req := range profile.NodeSelector {
+		switch req.Operator {
+		case corev1.NodeSelectorOpIn, corev1.NodeSelectorOpNotIn:
+			if len(req.Values) == 0 {
+				errs = append(errs, fmt.Errorf("nodeSelector requirement on %q: operator %s requires values", req.Key, req.Operator))
+			}
+		case corev1.NodeSelectorOpExists, corev1.NodeSelectorOpDoesNotExist:
+			if len(req.Values) > 0 {
+				errs = append(errs, fmt.Errorf("nodeSelector requirement on %q: operator %s doesn't support values", req.Key, req.Operator))
+			}
+		default:
+			errs = append(errs, fmt.Errorf("nodeSelector requirement on %q: unsupported operator %q", req.Key, req.Operator))
+		}
+	}
+
+	if override := profile.Override; override != nil {
+		if override.CreateRbac != nil {
+			errs = append(errs, fmt.Errorf("'override.createRbac' is not supported in a profile"))
+		}
+		if override.ExtraConfd != nil {
+			errs = append(errs, fmt.Errorf("'override.extraConfd' is not supported in a profile"))
+		}
+		if override.ExtraChecksd != nil {
+			errs = append(errs, fmt.Errorf("'override.extraChecksd' is not supported in a profile"))
+		}
+		if override.SecurityContextConstraints != nil {
+			errs = append(errs, fmt.Errorf("'override.securityContextConstraints' is not supported in a profile"))
+		}
+	}
+
+	return utilserrors.NewAggregate(errs)
+}
@@ -0,0 +1,207 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package v2alpha1
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+
+	commonv1 "github.com/DataDog/datadog-operator/apis/datadoghq/common/v1"
+	apiutils "github.com/DataDog/datadog-operator/apis/utils"
+)
+
+func TestIsValidDatadogAgent(t *testing.T) {
+	gpuSelector := []corev1.NodeSelectorRequirement{{Key: "pool", Operator: corev1.NodeSelectorOpIn, Values: []string{"gpu"}}}
+
+	tests := []struct {
+		name     string
+		profiles []DatadogAgentProfile
+		wantErr  bool
+	}{
+		{
+			name: "no profiles",
+		},
+		{
+			name: "valid profiles",
+			profiles: []DatadogAgentProfile{
+				{Name: "gpu", NodeSelector: gpuSelector, Override: &DatadogAgentComponentOverride{PriorityClassName: apiutils.NewStringPointer("high")}},
+				{Name: "spot", NodeSelector: []corev1.NodeSelectorRequirement{{Key: "spot", Operator: corev1.NodeSelectorOpExists}}},
+			},
+		},
+		{
+			name: "duplicate names",
+			profiles: []DatadogAgentProfile{
+				{Name: "gpu", NodeSelector: gpuSelector},
+				{Name: "gpu", NodeSelector: gpuSelector},
+			},
+			wantErr: true,
+		},
+		{
+			name:     "invalid name",
+			profiles: []DatadogAgentProfile{{Name: "GPU_pool", NodeSelector: gpuSelector}},
+			wantErr:  true,
+		},
+		{
+			name:     "reserved name",
+			profiles: []DatadogAgentProfile{{Name: "default", NodeSelector: gpuSelector}},
+			wantErr:  true,
+		},
+		{
+			name:     "empty node selector",
+			profiles: []DatadogAgentProfile{{Name: "gpu"}},
+			wantErr:  true,
+		},
+		{
+			name:     "unsupported operator",
+			profiles: []DatadogAgentProfile{{Name: "big", NodeSelector: []corev1.NodeSelectorRequirement{{Key: "cpu", Operator: corev1.NodeSelectorOpGt, Values: []string{"32"}}}}},
+			wantErr:  true,
+		},
+		{
+			name:     "missing values",
+			profiles: []DatadogAgentProfile{{Name: "gpu", NodeSelector: []corev1.NodeSelectorRequirement{{Key: "pool", Operator: corev1.NodeSelectorOpIn}}}},
+			wantErr:  true,
+		},
+		{
+			name:     "unsupported override",
+			profiles: []DatadogAgentProfile{{Name: "gpu", NodeSelector: gpuSelector, Override: &DatadogAgentComponentOverride{CreateRbac: apiutils.NewBoolPointer(false)}}},
+			wantErr:  true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := IsValidDatadogAgent(&DatadogAgentSpec{Profiles: tt.profiles})
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestIsValidDatadogAgent_customConfigs(t *testing.T) {
+	tests := []struct {
+		name    string
+		spec    DatadogAgentSpec
+		wantErr string
+	}{
+		{
+			name: "valid custom configurations",
+			spec: DatadogAgentSpec{
+				Features: &DatadogFeatures{
+					KubeStateMetricsCore: &KubeStateMetricsCoreFeatureConfig{Conf: &CustomConfig{ConfigData: apiutils.NewStringPointer("cluster_check: true")}},
+				},
+				Override: map[ComponentName]*DatadogAgentComponentOverride{
+					NodeAgentComponentName: {
+						CustomConfigurations: map[AgentConfigFileName]CustomConfig{AgentGeneralConfigFile: {ConfigData: apiutils.NewStringPointer("log_level: debug")}},
+						ExtraConfd:           &MultiCustomConfig{ConfigDataMap: map[string]string{"http_check.yaml": "instances: []"}},
+					},
+				},
+			},
+		},
+		{
+			name: "configData and configMap",
+			spec: DatadogAgentSpec{
+				Features: &DatadogFeatures{
+					CWS: &CWSFeatureConfig{CustomPolicies: &CustomConfig{ConfigData: apiutils.NewStringPointer("policies"), ConfigMap: &commonv1.ConfigMapConfig{Name: "policies"}}},
+				},
+			},
+			wantErr: "invalid spec.features.cws.customPolicies: 'configData' and 'configMap' cannot be set together",
+		},
+		{
+			name: "malformed custom configuration",
+			spec: DatadogAgentSpec{
+				Override: map[ComponentName]*DatadogAgentComponentOverride{
+					ClusterAgentComponentName: {
+						CustomConfigurations: map[AgentConfigFileName]CustomConfig{ClusterAgentConfigFile: {ConfigData: apiutils.NewStringPointer("log_level: [debug")}},
+					},
+				},
+			},
+			wantErr: "invalid spec.override.clusterAgent.customConfigurations[datadog-cluster.yaml].configData",
+		},
+		{
+			name: "malformed extra check configuration",
+			spec: DatadogAgentSpec{
+				Override: map[ComponentName]*DatadogAgentComponentOverride{
+					NodeAgentComponentName: {
+						ExtraConfd: &MultiCustomConfig{ConfigDataMap: map[string]string{"http_check.yaml": "instances: {"}},
+					},
+				},
+			},
+			wantErr: "invalid spec.override.nodeAgent.extraConfd.configDataMap[http_check.yaml]",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := IsValidDatadogAgent(&tt.spec)
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+				return
+			}
+			assert.Error(t, err)
+			assert.Contains(t, err.Error(), tt.wantErr)
+		})
+	}
+}
+
+func TestIsValidDatadogAgent_hostPorts(t *testing.T) {
+	tests := []struct {
+		name     string
+		features *DatadogFeatures
+		wantErr  string
+	}{
+		{
+			name: "default ports",
+			features: &DatadogFeatures{
+				APM:       &APMFeatureConfig{Enabled: apiutils.NewBoolPointer(true), HostPortConfig: &HostPortConfig{Enabled: apiutils.NewBoolPointer(true)}},
+				Dogstatsd: &DogstatsdFeatureConfig{HostPortConfig: &HostPortConfig{Enabled: apiutils.NewBoolPointer(true)}},
+				OTLP: &OTLPFeatureConfig{Receiver: OTLPReceiverConfig{Protocols: OTLPProtocolsConfig{
+					GRPC: &OTLPGRPCConfig{Enabled: apiutils.NewBoolPointer(true)},
+					HTTP: &OTLPHTTPConfig{Enabled: apiutils.NewBoolPointer(true)},
+				}}},
+			},
+		},
+		{
+			name: "same port, different protocols",
+			features: &DatadogFeatures{
+				APM:       &APMFeatureConfig{Enabled: apiutils.NewBoolPointer(true), HostPortConfig: &HostPortConfig{Enabled: apiutils.NewBoolPointer(true), Port: apiutils.NewInt32Pointer(8125)}},
+				Dogstatsd: &DogstatsdFeatureConfig{HostPortConfig: &HostPortConfig{Enabled: apiutils.NewBoolPointer(true)}},
+			},
+		},
+		{
+			name: "duplicate host ports",
+			features: &DatadogFeatures{
+				APM: &APMFeatureConfig{Enabled: apiutils.NewBoolPointer(true), HostPortConfig: &HostPortConfig{Enabled: apiutils.NewBoolPointer(true), Port: apiutils.NewInt32Pointer(4317)}},
+				OTLP: &OTLPFeatureConfig{Receiver: OTLPReceiverConfig{Protocols: OTLPProtocolsConfig{
+					GRPC: &OTLPGRPCConfig{Enabled: apiutils.NewBoolPointer(true)},
+				}}},
+			},
+			wantErr: "host port 4317/TCP of spec.features.otlp.receiver.protocols.grpc is already used by spec.features.apm.hostPortConfig",
+		},
+		{
+			name: "endpoint without port",
+			features: &DatadogFeatures{
+				OTLP: &OTLPFeatureConfig{Receiver: OTLPReceiverConfig{Protocols: OTLPProtocolsConfig{
+					HTTP: &OTLPHTTPConfig{Enabled: apiutils.NewBoolPointer(true), Endpoint: apiutils.NewStringPointer("0.0.0.0")},
+				}}},
+			},
+			wantErr: "the port must be set",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := IsValidDatadogAgent(&DatadogAgentSpec{Features: tt.features})
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+				return
+			}
+			assert.Error(t, err)
+			assert.Contains(t, err.Error(), tt.wantErr)
+		})
+	}
+}
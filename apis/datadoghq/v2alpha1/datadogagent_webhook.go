@@ -9,6 +9,10 @@ import (
 	ctrl "sigs.k8s.io/controller-runtime"
 )
 
+// The DatadogAgent validating and defaulting webhooks are served by the DatadogAgent controller, see controllers/datadogagent/webhook.go
+// +kubebuilder:webhook:path=/validate-datadoghq-com-v2alpha1-datadogagent,mutating=false,failurePolicy=ignore,sideEffects=None,groups=datadoghq.com,resources=datadogagents,verbs=create;update,versions=v2alpha1,name=vdatadogagent.kb.io,admissionReviewVersions=v1
+// +kubebuilder:webhook:path=/mutate-datadoghq-com-v2alpha1-datadogagent,mutating=true,failurePolicy=ignore,sideEffects=None,groups=datadoghq.com,resources=datadogagents,verbs=create;update,versions=v2alpha1,name=mdatadogagent.kb.io,admissionReviewVersions=v1
+
 // SetupWebhookWithManager starts the conversion webhook
 func (r *DatadogAgent) SetupWebhookWithManager(mgr ctrl.Manager) error {
 	return ctrl.NewWebhookManagedBy(mgr).
@@ -0,0 +1,101 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package v2alpha1
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// DatadogAgentFeatureSpec defines the additions of a DatadogAgentFeature to the DatadogAgents it applies to.
+// +k8s:openapi-gen=true
+type DatadogAgentFeatureSpec struct {
+	// Selector selects by label the DatadogAgents the feature applies to, in all the namespaces.
+	// The feature applies to all the DatadogAgents if not set.
+	// +optional
+	Selector *metav1.LabelSelector `json:"selector,omitempty"`
+	// Override defines the additions to the pods of each component, by component name.
+	// +optional
+	Override map[ComponentName]*DatadogAgentFeatureComponentOverride `json:"override,omitempty"`
+	// ConfigMaps created in the namespace of each DatadogAgent the feature applies to.
+	// +optional
+	// +listType=map
+	// +listMapKey=name
+	ConfigMaps []DatadogAgentFeatureConfigMap `json:"configMaps,omitempty"`
+}
+
+// DatadogAgentFeatureComponentOverride defines the additions of a DatadogAgentFeature to the pods of a component.
+// +k8s:openapi-gen=true
+type DatadogAgentFeatureComponentOverride struct {
+	// Env is added to all the containers of the component.
+	// +optional
+	// +listType=map
+	// +listMapKey=name
+	Env []corev1.EnvVar `json:"env,omitempty"`
+	// VolumeMounts are added to all the containers of the component.
+	// +optional
+	// +listType=map
+	// +listMapKey=mountPath
+	VolumeMounts []corev1.VolumeMount `json:"volumeMounts,omitempty"`
+	// Volumes are added to the pods of the component.
+	// +optional
+	// +listType=map
+	// +listMapKey=name
+	Volumes []corev1.Volume `json:"volumes,omitempty"`
+	// Sidecars are containers added to the pods of the component. They don't get the env and volumeMounts of
+	// the feature.
+	// +optional
+	// +kubebuilder:validation:Schemaless
+	// +kubebuilder:pruning:PreserveUnknownFields
+	Sidecars []corev1.Container `json:"sidecars,omitempty"`
+	// Annotations are added to the pods of the component.
+	// +optional
+	Annotations map[string]string `json:"annotations,omitempty"`
+	// ClusterRules are added to a ClusterRole bound to the ServiceAccount of the component.
+	// The Operator needs to have these permissions to grant them.
+	// +optional
+	// +listType=atomic
+	ClusterRules []rbacv1.PolicyRule `json:"clusterRules,omitempty"`
+}
+
+// DatadogAgentFeatureConfigMap defines a ConfigMap created by a DatadogAgentFeature.
+// +k8s:openapi-gen=true
+type DatadogAgentFeatureConfigMap struct {
+	// Name of the ConfigMap.
+	Name string `json:"name"`
+	// Data of the ConfigMap.
+	// +optional
+	Data map[string]string `json:"data,omitempty"`
+}
+
+// DatadogAgentFeature adds sidecars, env vars, volumes, annotations, ConfigMaps and RBAC to the components of the
+// DatadogAgents it selects, as a feature of these DatadogAgents.
+// +kubebuilder:object:root=true
+// +kubebuilder:resource:path=datadogagentfeatures,scope=Cluster
+// +kubebuilder:printcolumn:name="age",type="date",JSONPath=".metadata.creationTimestamp"
+// +k8s:openapi-gen=true
+// +genclient
+// +genclient:nonNamespaced
+type DatadogAgentFeature struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec DatadogAgentFeatureSpec `json:"spec,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// DatadogAgentFeatureList contains a list of DatadogAgentFeature.
+type DatadogAgentFeatureList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []DatadogAgentFeature `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&DatadogAgentFeature{}, &DatadogAgentFeatureList{})
+}
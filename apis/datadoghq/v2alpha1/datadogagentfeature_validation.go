@@ -0,0 +1,85 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package v2alpha1
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	utilserrors "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/apimachinery/pkg/util/validation"
+)
+
+// IsValidDatadogAgentFeature use to check if a DatadogAgentFeatureSpec is valid
+func IsValidDatadogAgentFeature(spec *DatadogAgentFeatureSpec) error {
+	var errs []error
+
+	if spec.Selector != nil {
+		if _, err := metav1.LabelSelectorAsSelector(spec.Selector); err != nil {
+			errs = append(errs, fmt.Errorf("invalid spec.selector, err: %w", err))
+		}
+	}
+
+	// Sort the components for stable errors
+	components := make([]string, 0, len(spec.Override))
+	for component := range spec.Override {
+		components = append(components, string(component))
+	}
+	sort.Strings(components)
+	for _, component := range components {
+		switch ComponentName(component) {
+		case NodeAgentComponentName, ClusterAgentComponentName, ClusterChecksRunnerComponentName:
+		default:
+			errs = append(errs, fmt.Errorf("invalid spec.override.%s: unknown component", component))
+			continue
+		}
+		if override := spec.Override[ComponentName(component)]; override != nil {
+			errs = append(errs, validateFeatureComponentOverride(fmt.Sprintf("spec.override.%s", component), override)...)
+		}
+	}
+
+	configMaps := make(map[string]bool, len(spec.ConfigMaps))
+	for i, configMap := range spec.ConfigMaps {
+		if msgs := validation.IsDNS1123Subdomain(configMap.Name); len(msgs) > 0 {
+			errs = append(errs, fmt.Errorf("invalid spec.configMaps[%d].name: %s", i, strings.Join(msgs, ", ")))
+		}
+		if configMaps[configMap.Name] {
+			errs = append(errs, fmt.Errorf("invalid spec.configMaps[%d]: duplicate ConfigMap name %q", i, configMap.Name))
+		}
+		configMaps[configMap.Name] = true
+	}
+
+	return utilserrors.NewAggregate(errs)
+}
+
+// validateFeatureComponentOverride checks that the volumes and sidecars of a component override are named uniquely
+func validateFeatureComponentOverride(path string, override *DatadogAgentFeatureComponentOverride) []error {
+	var errs []error
+
+	volumes := make(map[string]bool, len(override.Volumes))
+	for i, volume := range override.Volumes {
+		if volume.Name == "" {
+			errs = append(errs, fmt.Errorf("invalid %s.volumes[%d]: 'name' must be defined", path, i))
+		} else if volumes[volume.Name] {
+			errs = append(errs, fmt.Errorf("invalid %s.volumes[%d]: duplicate volume name %q", path, i, volume.Name))
+		}
+		volumes[volume.Name] = true
+	}
+
+	sidecars := make(map[string]bool, len(override.Sidecars))
+	for i, sidecar := range override.Sidecars {
+		if sidecar.Name == "" || sidecar.Image == "" {
+			errs = append(errs, fmt.Errorf("invalid %s.sidecars[%d]: 'name' and 'image' must be defined", path, i))
+		} else if sidecars[sidecar.Name] {
+			errs = append(errs, fmt.Errorf("invalid %s.sidecars[%d]: duplicate container name %q", path, i, sidecar.Name))
+		}
+		sidecars[sidecar.Name] = true
+	}
+
+	return errs
+}
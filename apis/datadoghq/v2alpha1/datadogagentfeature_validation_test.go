@@ -0,0 +1,98 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package v2alpha1
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestIsValidDatadogAgentFeature(t *testing.T) {
+	tests := []struct {
+		name    string
+		spec    DatadogAgentFeatureSpec
+		wantErr string
+	}{
+		{
+			name: "empty feature",
+		},
+		{
+			name: "valid feature",
+			spec: DatadogAgentFeatureSpec{
+				Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"team": "foo"}},
+				Override: map[ComponentName]*DatadogAgentFeatureComponentOverride{
+					NodeAgentComponentName: {
+						Env:      []corev1.EnvVar{{Name: "DD_FOO", Value: "bar"}},
+						Volumes:  []corev1.Volume{{Name: "foo"}, {Name: "bar"}},
+						Sidecars: []corev1.Container{{Name: "foo", Image: "foo:latest"}},
+					},
+					ClusterAgentComponentName: nil,
+				},
+				ConfigMaps: []DatadogAgentFeatureConfigMap{{Name: "foo-config", Data: map[string]string{"foo.yaml": "foo: bar"}}},
+			},
+		},
+		{
+			name: "invalid selector",
+			spec: DatadogAgentFeatureSpec{
+				Selector: &metav1.LabelSelector{MatchExpressions: []metav1.LabelSelectorRequirement{{Key: "team", Operator: "Foo"}}},
+			},
+			wantErr: "invalid spec.selector",
+		},
+		{
+			name: "unknown component",
+			spec: DatadogAgentFeatureSpec{
+				Override: map[ComponentName]*DatadogAgentFeatureComponentOverride{"foo": {}},
+			},
+			wantErr: "invalid spec.override.foo: unknown component",
+		},
+		{
+			name: "duplicate volumes",
+			spec: DatadogAgentFeatureSpec{
+				Override: map[ComponentName]*DatadogAgentFeatureComponentOverride{
+					ClusterChecksRunnerComponentName: {Volumes: []corev1.Volume{{Name: "foo"}, {Name: "foo"}}},
+				},
+			},
+			wantErr: `invalid spec.override.clusterChecksRunner.volumes[1]: duplicate volume name "foo"`,
+		},
+		{
+			name: "sidecar without image",
+			spec: DatadogAgentFeatureSpec{
+				Override: map[ComponentName]*DatadogAgentFeatureComponentOverride{
+					NodeAgentComponentName: {Sidecars: []corev1.Container{{Name: "foo"}}},
+				},
+			},
+			wantErr: "invalid spec.override.nodeAgent.sidecars[0]: 'name' and 'image' must be defined",
+		},
+		{
+			name: "invalid ConfigMap name",
+			spec: DatadogAgentFeatureSpec{
+				ConfigMaps: []DatadogAgentFeatureConfigMap{{Name: "Foo_Config"}},
+			},
+			wantErr: "invalid spec.configMaps[0].name",
+		},
+		{
+			name: "duplicate ConfigMaps",
+			spec: DatadogAgentFeatureSpec{
+				ConfigMaps: []DatadogAgentFeatureConfigMap{{Name: "foo"}, {Name: "foo"}},
+			},
+			wantErr: `invalid spec.configMaps[1]: duplicate ConfigMap name "foo"`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := IsValidDatadogAgentFeature(&tt.spec)
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+				return
+			}
+			assert.Error(t, err)
+			assert.Contains(t, err.Error(), tt.wantErr)
+		})
+	}
+}
@@ -0,0 +1,73 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package v2alpha1
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// MergeDatadogAgentTemplate returns the spec of a DatadogAgent merged over the spec of its DatadogAgentTemplate.
+// The precedence rules are:
+//   - a field set in the DatadogAgent spec takes precedence over the template
+//   - objects and maps, for instance spec.global or spec.override, are merged field by field
+//   - lists, for instance spec.global.tags or spec.profiles, are not merged: a list set in the DatadogAgent spec replaces the list of the template
+//   - a field set in the template can't be unset by the DatadogAgent spec, but it can be set to another value, including false
+func MergeDatadogAgentTemplate(spec, template *DatadogAgentSpec) (*DatadogAgentSpec, error) {
+	if template.BaseRef != nil {
+		return nil, fmt.Errorf("a DatadogAgentTemplate can't reference another DatadogAgentTemplate in spec.baseRef")
+	}
+
+	base, err := toJSONObject(template)
+	if err != nil {
+		return nil, fmt.Errorf("unable to convert the DatadogAgentTemplate spec, err: %w", err)
+	}
+	overlay, err := toJSONObject(spec)
+	if err != nil {
+		return nil, fmt.Errorf("unable to convert the DatadogAgent spec, err: %w", err)
+	}
+
+	data, err := json.Marshal(mergeJSONObjects(base, overlay))
+	if err != nil {
+		return nil, fmt.Errorf("unable to convert the merged spec, err: %w", err)
+	}
+	merged := &DatadogAgentSpec{}
+	if err = json.Unmarshal(data, merged); err != nil {
+		return nil, fmt.Errorf("unable to convert the merged spec, err: %w", err)
+	}
+
+	return merged, nil
+}
+
+func toJSONObject(spec *DatadogAgentSpec) (map[string]interface{}, error) {
+	data, err := json.Marshal(spec)
+	if err != nil {
+		return nil, err
+	}
+	obj := map[string]interface{}{}
+	err = json.Unmarshal(data, &obj)
+
+	return obj, err
+}
+
+// mergeJSONObjects merges overlay into base recursively, the values of overlay taking precedence.
+// The null values of overlay are ignored, they are the unset pointers and maps of the spec.
+func mergeJSONObjects(base, overlay map[string]interface{}) map[string]interface{} {
+	for key, value := range overlay {
+		if value == nil {
+			continue
+		}
+		if overlayObj, ok := value.(map[string]interface{}); ok {
+			if baseObj, ok := base[key].(map[string]interface{}); ok {
+				base[key] = mergeJSONObjects(baseObj, overlayObj)
+				continue
+			}
+		}
+		base[key] = value
+	}
+
+	return base
+}
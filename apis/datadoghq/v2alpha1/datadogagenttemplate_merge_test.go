@@ -0,0 +1,89 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package v2alpha1
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+
+	apiutils "github.com/DataDog/datadog-operator/apis/utils"
+)
+
+func TestMergeDatadogAgentTemplate(t *testing.T) {
+	template := &DatadogAgentSpec{
+		Global: &GlobalConfig{
+			Credentials:     &DatadogCredentials{APIKey: apiutils.NewStringPointer("template-api-key")},
+			Registry:        apiutils.NewStringPointer("registry.example.com"),
+			Tags:            []string{"team:platform", "env:prod"},
+			PodLabelsAsTags: map[string]string{"app": "app"},
+		},
+		Features: &DatadogFeatures{
+			LogCollection: &LogCollectionFeatureConfig{Enabled: apiutils.NewBoolPointer(true), ContainerCollectAll: apiutils.NewBoolPointer(true)},
+		},
+		Override: map[ComponentName]*DatadogAgentComponentOverride{
+			NodeAgentComponentName: {
+				Env: []corev1.EnvVar{{Name: "DD_FOO", Value: "template"}},
+			},
+			ClusterAgentComponentName: {
+				Replicas: apiutils.NewInt32Pointer(2),
+			},
+		},
+	}
+	spec := &DatadogAgentSpec{
+		BaseRef: &DatadogAgentTemplateReference{Name: "base"},
+		Global: &GlobalConfig{
+			ClusterName:     apiutils.NewStringPointer("cluster-1"),
+			Tags:            []string{"env:staging"},
+			PodLabelsAsTags: map[string]string{"team": "team"},
+		},
+		Features: &DatadogFeatures{
+			LogCollection: &LogCollectionFeatureConfig{ContainerCollectAll: apiutils.NewBoolPointer(false)},
+		},
+		Override: map[ComponentName]*DatadogAgentComponentOverride{
+			NodeAgentComponentName: {
+				Env: []corev1.EnvVar{{Name: "DD_BAR", Value: "dda"}},
+			},
+			ClusterAgentComponentName: nil,
+		},
+	}
+
+	merged, err := MergeDatadogAgentTemplate(spec, template)
+	assert.NoError(t, err)
+	assert.Equal(t, &DatadogAgentSpec{
+		BaseRef: &DatadogAgentTemplateReference{Name: "base"},
+		Global: &GlobalConfig{
+			Credentials:     &DatadogCredentials{APIKey: apiutils.NewStringPointer("template-api-key")},
+			ClusterName:     apiutils.NewStringPointer("cluster-1"),
+			Registry:        apiutils.NewStringPointer("registry.example.com"),
+			Tags:            []string{"env:staging"},
+			PodLabelsAsTags: map[string]string{"app": "app", "team": "team"},
+		},
+		Features: &DatadogFeatures{
+			LogCollection: &LogCollectionFeatureConfig{Enabled: apiutils.NewBoolPointer(true), ContainerCollectAll: apiutils.NewBoolPointer(false)},
+		},
+		Override: map[ComponentName]*DatadogAgentComponentOverride{
+			NodeAgentComponentName: {
+				Env: []corev1.EnvVar{{Name: "DD_BAR", Value: "dda"}},
+			},
+			ClusterAgentComponentName: {
+				Replicas: apiutils.NewInt32Pointer(2),
+			},
+		},
+	}, merged)
+
+	// The specs are left unchanged
+	assert.Equal(t, []string{"team:platform", "env:prod"}, template.Global.Tags)
+	assert.Nil(t, spec.Global.Credentials)
+}
+
+func TestMergeDatadogAgentTemplate_nestedBaseRef(t *testing.T) {
+	template := &DatadogAgentSpec{BaseRef: &DatadogAgentTemplateReference{Name: "other"}}
+
+	_, err := MergeDatadogAgentTemplate(&DatadogAgentSpec{}, template)
+	assert.EqualError(t, err, "a DatadogAgentTemplate can't reference another DatadogAgentTemplate in spec.baseRef")
+}
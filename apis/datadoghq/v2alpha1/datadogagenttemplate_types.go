@@ -0,0 +1,38 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package v2alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// DatadogAgentTemplate defines a base DatadogAgent spec, inherited by the DatadogAgents referencing it in their
+// spec.baseRef.
+// +kubebuilder:object:root=true
+// +kubebuilder:resource:path=datadogagenttemplates,scope=Cluster
+// +kubebuilder:printcolumn:name="age",type="date",JSONPath=".metadata.creationTimestamp"
+// +k8s:openapi-gen=true
+// +genclient
+// +genclient:nonNamespaced
+type DatadogAgentTemplate struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec DatadogAgentSpec `json:"spec,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// DatadogAgentTemplateList contains a list of DatadogAgentTemplate.
+type DatadogAgentTemplateList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []DatadogAgentTemplate `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&DatadogAgentTemplate{}, &DatadogAgentTemplateList{})
+}
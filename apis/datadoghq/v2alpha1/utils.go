@@ -24,6 +24,11 @@ func GetConfName(owner metav1.Object, conf *CustomConfig, defaultName string) st
 	return fmt.Sprintf("%s-%s", owner.GetName(), defaultName)
 }
 
+// GetProfileResourcesPrefix returns the prefix used to name the resources dedicated to a profile, like its custom configuration ConfigMaps
+func GetProfileResourcesPrefix(owner metav1.Object, profileName string) string {
+	return fmt.Sprintf("%s-%s", owner.GetName(), profileName)
+}
+
 // GetClusterAgentServiceAccount return the cluster-agent serviceAccountName
 func GetClusterAgentServiceAccount(dda *DatadogAgent) string {
 	saDefault := fmt.Sprintf("%s-%s", dda.Name, common.DefaultClusterAgentResourceSuffix)
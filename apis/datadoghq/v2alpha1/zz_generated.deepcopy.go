@@ -12,10 +12,14 @@ package v2alpha1
 
 import (
 	commonv1 "github.com/DataDog/datadog-operator/apis/datadoghq/common/v1"
+	apiv1alpha1 "github.com/DataDog/extendeddaemonset/api/v1alpha1"
 	securityv1 "github.com/openshift/api/security/v1"
+	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1"
 	runtime "k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/intstr"
 )
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
@@ -198,6 +202,81 @@ func (in *CustomConfig) DeepCopy() *CustomConfig {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DaemonSetRollingUpdateSpec) DeepCopyInto(out *DaemonSetRollingUpdateSpec) {
+	*out = *in
+	if in.MaxUnavailable != nil {
+		in, out := &in.MaxUnavailable, &out.MaxUnavailable
+		*out = new(intstr.IntOrString)
+		**out = **in
+	}
+	if in.MaxPodSchedulerFailure != nil {
+		in, out := &in.MaxPodSchedulerFailure, &out.MaxPodSchedulerFailure
+		*out = new(intstr.IntOrString)
+		**out = **in
+	}
+	if in.MaxParallelPodCreation != nil {
+		in, out := &in.MaxParallelPodCreation, &out.MaxParallelPodCreation
+		*out = new(int32)
+		**out = **in
+	}
+	if in.SlowStartIntervalDuration != nil {
+		in, out := &in.SlowStartIntervalDuration, &out.SlowStartIntervalDuration
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	if in.SlowStartAdditiveIncrease != nil {
+		in, out := &in.SlowStartAdditiveIncrease, &out.SlowStartAdditiveIncrease
+		*out = new(intstr.IntOrString)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DaemonSetRollingUpdateSpec.
+func (in *DaemonSetRollingUpdateSpec) DeepCopy() *DaemonSetRollingUpdateSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(DaemonSetRollingUpdateSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DaemonSetUpdateStrategy) DeepCopyInto(out *DaemonSetUpdateStrategy) {
+	*out = *in
+	if in.Type != nil {
+		in, out := &in.Type, &out.Type
+		*out = new(appsv1.DaemonSetUpdateStrategyType)
+		**out = **in
+	}
+	if in.RollingUpdate != nil {
+		in, out := &in.RollingUpdate, &out.RollingUpdate
+		*out = new(DaemonSetRollingUpdateSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Canary != nil {
+		in, out := &in.Canary, &out.Canary
+		*out = new(apiv1alpha1.ExtendedDaemonSetSpecStrategyCanary)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ReconcileFrequency != nil {
+		in, out := &in.ReconcileFrequency, &out.ReconcileFrequency
+		*out = new(v1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DaemonSetUpdateStrategy.
+func (in *DaemonSetUpdateStrategy) DeepCopy() *DaemonSetUpdateStrategy {
+	if in == nil {
+		return nil
+	}
+	out := new(DaemonSetUpdateStrategy)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *DatadogAgent) DeepCopyInto(out *DatadogAgent) {
 	*out = *in
@@ -304,6 +383,11 @@ func (in *DatadogAgentComponentOverride) DeepCopyInto(out *DatadogAgentComponent
 		*out = new(SecurityContextConstraintsConfig)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.UpdateStrategy != nil {
+		in, out := &in.UpdateStrategy, &out.UpdateStrategy
+		*out = new(DaemonSetUpdateStrategy)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.SecurityContext != nil {
 		in, out := &in.SecurityContext, &out.SecurityContext
 		*out = new(corev1.PodSecurityContext)
@@ -374,6 +458,227 @@ func (in *DatadogAgentComponentOverride) DeepCopy() *DatadogAgentComponentOverri
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DatadogAgentFeature) DeepCopyInto(out *DatadogAgentFeature) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DatadogAgentFeature.
+func (in *DatadogAgentFeature) DeepCopy() *DatadogAgentFeature {
+	if in == nil {
+		return nil
+	}
+	out := new(DatadogAgentFeature)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *DatadogAgentFeature) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DatadogAgentFeatureComponent) DeepCopyInto(out *DatadogAgentFeatureComponent) {
+	*out = *in
+	if in.Containers != nil {
+		in, out := &in.Containers, &out.Containers
+		*out = make([]commonv1.AgentContainerName, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DatadogAgentFeatureComponent.
+func (in *DatadogAgentFeatureComponent) DeepCopy() *DatadogAgentFeatureComponent {
+	if in == nil {
+		return nil
+	}
+	out := new(DatadogAgentFeatureComponent)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DatadogAgentFeatureComponentOverride) DeepCopyInto(out *DatadogAgentFeatureComponentOverride) {
+	*out = *in
+	if in.Env != nil {
+		in, out := &in.Env, &out.Env
+		*out = make([]corev1.EnvVar, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.VolumeMounts != nil {
+		in, out := &in.VolumeMounts, &out.VolumeMounts
+		*out = make([]corev1.VolumeMount, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Volumes != nil {
+		in, out := &in.Volumes, &out.Volumes
+		*out = make([]corev1.Volume, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Sidecars != nil {
+		in, out := &in.Sidecars, &out.Sidecars
+		*out = make([]corev1.Container, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Annotations != nil {
+		in, out := &in.Annotations, &out.Annotations
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.ClusterRules != nil {
+		in, out := &in.ClusterRules, &out.ClusterRules
+		*out = make([]rbacv1.PolicyRule, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DatadogAgentFeatureComponentOverride.
+func (in *DatadogAgentFeatureComponentOverride) DeepCopy() *DatadogAgentFeatureComponentOverride {
+	if in == nil {
+		return nil
+	}
+	out := new(DatadogAgentFeatureComponentOverride)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DatadogAgentFeatureConfigMap) DeepCopyInto(out *DatadogAgentFeatureConfigMap) {
+	*out = *in
+	if in.Data != nil {
+		in, out := &in.Data, &out.Data
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DatadogAgentFeatureConfigMap.
+func (in *DatadogAgentFeatureConfigMap) DeepCopy() *DatadogAgentFeatureConfigMap {
+	if in == nil {
+		return nil
+	}
+	out := new(DatadogAgentFeatureConfigMap)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DatadogAgentFeatureList) DeepCopyInto(out *DatadogAgentFeatureList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]DatadogAgentFeature, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DatadogAgentFeatureList.
+func (in *DatadogAgentFeatureList) DeepCopy() *DatadogAgentFeatureList {
+	if in == nil {
+		return nil
+	}
+	out := new(DatadogAgentFeatureList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *DatadogAgentFeatureList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DatadogAgentFeatureSpec) DeepCopyInto(out *DatadogAgentFeatureSpec) {
+	*out = *in
+	if in.Selector != nil {
+		in, out := &in.Selector, &out.Selector
+		*out = new(v1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Override != nil {
+		in, out := &in.Override, &out.Override
+		*out = make(map[ComponentName]*DatadogAgentFeatureComponentOverride, len(*in))
+		for key, val := range *in {
+			var outVal *DatadogAgentFeatureComponentOverride
+			if val == nil {
+				(*out)[key] = nil
+			} else {
+				in, out := &val, &outVal
+				*out = new(DatadogAgentFeatureComponentOverride)
+				(*in).DeepCopyInto(*out)
+			}
+			(*out)[key] = outVal
+		}
+	}
+	if in.ConfigMaps != nil {
+		in, out := &in.ConfigMaps, &out.ConfigMaps
+		*out = make([]DatadogAgentFeatureConfigMap, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DatadogAgentFeatureSpec.
+func (in *DatadogAgentFeatureSpec) DeepCopy() *DatadogAgentFeatureSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(DatadogAgentFeatureSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DatadogAgentFeatureStatus) DeepCopyInto(out *DatadogAgentFeatureStatus) {
+	*out = *in
+	if in.RequiredComponents != nil {
+		in, out := &in.RequiredComponents, &out.RequiredComponents
+		*out = make([]DatadogAgentFeatureComponent, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DatadogAgentFeatureStatus.
+func (in *DatadogAgentFeatureStatus) DeepCopy() *DatadogAgentFeatureStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(DatadogAgentFeatureStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *DatadogAgentGenericContainer) DeepCopyInto(out *DatadogAgentGenericContainer) {
 	*out = *in
@@ -490,9 +795,61 @@ func (in *DatadogAgentList) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DatadogAgentProfile) DeepCopyInto(out *DatadogAgentProfile) {
+	*out = *in
+	if in.NodeSelector != nil {
+		in, out := &in.NodeSelector, &out.NodeSelector
+		*out = make([]corev1.NodeSelectorRequirement, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Override != nil {
+		in, out := &in.Override, &out.Override
+		*out = new(DatadogAgentComponentOverride)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DatadogAgentProfile.
+func (in *DatadogAgentProfile) DeepCopy() *DatadogAgentProfile {
+	if in == nil {
+		return nil
+	}
+	out := new(DatadogAgentProfile)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DatadogAgentProfileStatus) DeepCopyInto(out *DatadogAgentProfileStatus) {
+	*out = *in
+	if in.Agent != nil {
+		in, out := &in.Agent, &out.Agent
+		*out = new(commonv1.DaemonSetStatus)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DatadogAgentProfileStatus.
+func (in *DatadogAgentProfileStatus) DeepCopy() *DatadogAgentProfileStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(DatadogAgentProfileStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *DatadogAgentSpec) DeepCopyInto(out *DatadogAgentSpec) {
 	*out = *in
+	if in.BaseRef != nil {
+		in, out := &in.BaseRef, &out.BaseRef
+		*out = new(DatadogAgentTemplateReference)
+		**out = **in
+	}
 	if in.Features != nil {
 		in, out := &in.Features, &out.Features
 		*out = new(DatadogFeatures)
@@ -518,6 +875,13 @@ func (in *DatadogAgentSpec) DeepCopyInto(out *DatadogAgentSpec) {
 			(*out)[key] = outVal
 		}
 	}
+	if in.Profiles != nil {
+		in, out := &in.Profiles, &out.Profiles
+		*out = make([]DatadogAgentProfile, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DatadogAgentSpec.
@@ -555,6 +919,25 @@ func (in *DatadogAgentStatus) DeepCopyInto(out *DatadogAgentStatus) {
 		*out = new(commonv1.DeploymentStatus)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.Profiles != nil {
+		in, out := &in.Profiles, &out.Profiles
+		*out = make([]DatadogAgentProfileStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Features != nil {
+		in, out := &in.Features, &out.Features
+		*out = make([]DatadogAgentFeatureStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Template != nil {
+		in, out := &in.Template, &out.Template
+		*out = new(DatadogAgentTemplateStatus)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DatadogAgentStatus.
@@ -567,6 +950,94 @@ func (in *DatadogAgentStatus) DeepCopy() *DatadogAgentStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DatadogAgentTemplate) DeepCopyInto(out *DatadogAgentTemplate) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DatadogAgentTemplate.
+func (in *DatadogAgentTemplate) DeepCopy() *DatadogAgentTemplate {
+	if in == nil {
+		return nil
+	}
+	out := new(DatadogAgentTemplate)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *DatadogAgentTemplate) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DatadogAgentTemplateList) DeepCopyInto(out *DatadogAgentTemplateList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]DatadogAgentTemplate, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DatadogAgentTemplateList.
+func (in *DatadogAgentTemplateList) DeepCopy() *DatadogAgentTemplateList {
+	if in == nil {
+		return nil
+	}
+	out := new(DatadogAgentTemplateList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *DatadogAgentTemplateList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DatadogAgentTemplateReference) DeepCopyInto(out *DatadogAgentTemplateReference) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DatadogAgentTemplateReference.
+func (in *DatadogAgentTemplateReference) DeepCopy() *DatadogAgentTemplateReference {
+	if in == nil {
+		return nil
+	}
+	out := new(DatadogAgentTemplateReference)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DatadogAgentTemplateStatus) DeepCopyInto(out *DatadogAgentTemplateStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DatadogAgentTemplateStatus.
+func (in *DatadogAgentTemplateStatus) DeepCopy() *DatadogAgentTemplateStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(DatadogAgentTemplateStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *DatadogCredentials) DeepCopyInto(out *DatadogCredentials) {
 	*out = *in
@@ -933,6 +1404,11 @@ func (in *GlobalConfig) DeepCopyInto(out *GlobalConfig) {
 		*out = new(string)
 		**out = **in
 	}
+	if in.SecretBackend != nil {
+		in, out := &in.SecretBackend, &out.SecretBackend
+		*out = new(SecretBackendConfig)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GlobalConfig.
@@ -1449,6 +1925,18 @@ func (in *SecretBackendConfig) DeepCopyInto(out *SecretBackendConfig) {
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
+	if in.EnableGlobalPermissions != nil {
+		in, out := &in.EnableGlobalPermissions, &out.EnableGlobalPermissions
+		*out = new(bool)
+		**out = **in
+	}
+	if in.Roles != nil {
+		in, out := &in.Roles, &out.Roles
+		*out = make([]SecretBackendRolesConfig, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SecretBackendConfig.
@@ -1461,6 +1949,26 @@ func (in *SecretBackendConfig) DeepCopy() *SecretBackendConfig {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SecretBackendRolesConfig) DeepCopyInto(out *SecretBackendRolesConfig) {
+	*out = *in
+	if in.Secrets != nil {
+		in, out := &in.Secrets, &out.Secrets
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SecretBackendRolesConfig.
+func (in *SecretBackendRolesConfig) DeepCopy() *SecretBackendRolesConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(SecretBackendRolesConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *SecurityContextConstraintsConfig) DeepCopyInto(out *SecurityContextConstraintsConfig) {
 	*out = *in
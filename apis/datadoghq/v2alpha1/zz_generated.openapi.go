@@ -19,27 +19,42 @@ import (
 
 func GetOpenAPIDefinitions(ref common.ReferenceCallback) map[string]common.OpenAPIDefinition {
 	return map[string]common.OpenAPIDefinition{
-		"./apis/datadoghq/v2alpha1.CustomConfig":                      schema__apis_datadoghq_v2alpha1_CustomConfig(ref),
-		"./apis/datadoghq/v2alpha1.DatadogAgent":                      schema__apis_datadoghq_v2alpha1_DatadogAgent(ref),
-		"./apis/datadoghq/v2alpha1.DatadogAgentGenericContainer":      schema__apis_datadoghq_v2alpha1_DatadogAgentGenericContainer(ref),
-		"./apis/datadoghq/v2alpha1.DatadogAgentStatus":                schema__apis_datadoghq_v2alpha1_DatadogAgentStatus(ref),
-		"./apis/datadoghq/v2alpha1.DatadogCredentials":                schema__apis_datadoghq_v2alpha1_DatadogCredentials(ref),
-		"./apis/datadoghq/v2alpha1.DatadogFeatures":                   schema__apis_datadoghq_v2alpha1_DatadogFeatures(ref),
-		"./apis/datadoghq/v2alpha1.DogstatsdFeatureConfig":            schema__apis_datadoghq_v2alpha1_DogstatsdFeatureConfig(ref),
-		"./apis/datadoghq/v2alpha1.EventCollectionFeatureConfig":      schema__apis_datadoghq_v2alpha1_EventCollectionFeatureConfig(ref),
-		"./apis/datadoghq/v2alpha1.KubeStateMetricsCoreFeatureConfig": schema__apis_datadoghq_v2alpha1_KubeStateMetricsCoreFeatureConfig(ref),
-		"./apis/datadoghq/v2alpha1.LocalService":                      schema__apis_datadoghq_v2alpha1_LocalService(ref),
-		"./apis/datadoghq/v2alpha1.MultiCustomConfig":                 schema__apis_datadoghq_v2alpha1_MultiCustomConfig(ref),
-		"./apis/datadoghq/v2alpha1.NetworkPolicyConfig":               schema__apis_datadoghq_v2alpha1_NetworkPolicyConfig(ref),
-		"./apis/datadoghq/v2alpha1.OTLPFeatureConfig":                 schema__apis_datadoghq_v2alpha1_OTLPFeatureConfig(ref),
-		"./apis/datadoghq/v2alpha1.OTLPGRPCConfig":                    schema__apis_datadoghq_v2alpha1_OTLPGRPCConfig(ref),
-		"./apis/datadoghq/v2alpha1.OTLPHTTPConfig":                    schema__apis_datadoghq_v2alpha1_OTLPHTTPConfig(ref),
-		"./apis/datadoghq/v2alpha1.OTLPProtocolsConfig":               schema__apis_datadoghq_v2alpha1_OTLPProtocolsConfig(ref),
-		"./apis/datadoghq/v2alpha1.OTLPReceiverConfig":                schema__apis_datadoghq_v2alpha1_OTLPReceiverConfig(ref),
-		"./apis/datadoghq/v2alpha1.OrchestratorExplorerFeatureConfig": schema__apis_datadoghq_v2alpha1_OrchestratorExplorerFeatureConfig(ref),
-		"./apis/datadoghq/v2alpha1.PrometheusScrapeFeatureConfig":     schema__apis_datadoghq_v2alpha1_PrometheusScrapeFeatureConfig(ref),
-		"./apis/datadoghq/v2alpha1.SecurityContextConstraintsConfig":  schema__apis_datadoghq_v2alpha1_SecurityContextConstraintsConfig(ref),
-		"./apis/datadoghq/v2alpha1.UnixDomainSocketConfig":            schema__apis_datadoghq_v2alpha1_UnixDomainSocketConfig(ref),
+		"./apis/datadoghq/v2alpha1.CustomConfig":                         schema__apis_datadoghq_v2alpha1_CustomConfig(ref),
+		"./apis/datadoghq/v2alpha1.DaemonSetRollingUpdateSpec":           schema__apis_datadoghq_v2alpha1_DaemonSetRollingUpdateSpec(ref),
+		"./apis/datadoghq/v2alpha1.DaemonSetUpdateStrategy":              schema__apis_datadoghq_v2alpha1_DaemonSetUpdateStrategy(ref),
+		"./apis/datadoghq/v2alpha1.DatadogAgent":                         schema__apis_datadoghq_v2alpha1_DatadogAgent(ref),
+		"./apis/datadoghq/v2alpha1.DatadogAgentFeature":                  schema__apis_datadoghq_v2alpha1_DatadogAgentFeature(ref),
+		"./apis/datadoghq/v2alpha1.DatadogAgentFeatureComponent":         schema__apis_datadoghq_v2alpha1_DatadogAgentFeatureComponent(ref),
+		"./apis/datadoghq/v2alpha1.DatadogAgentFeatureComponentOverride": schema__apis_datadoghq_v2alpha1_DatadogAgentFeatureComponentOverride(ref),
+		"./apis/datadoghq/v2alpha1.DatadogAgentFeatureConfigMap":         schema__apis_datadoghq_v2alpha1_DatadogAgentFeatureConfigMap(ref),
+		"./apis/datadoghq/v2alpha1.DatadogAgentFeatureSpec":              schema__apis_datadoghq_v2alpha1_DatadogAgentFeatureSpec(ref),
+		"./apis/datadoghq/v2alpha1.DatadogAgentFeatureStatus":            schema__apis_datadoghq_v2alpha1_DatadogAgentFeatureStatus(ref),
+		"./apis/datadoghq/v2alpha1.DatadogAgentGenericContainer":         schema__apis_datadoghq_v2alpha1_DatadogAgentGenericContainer(ref),
+		"./apis/datadoghq/v2alpha1.DatadogAgentProfile":                  schema__apis_datadoghq_v2alpha1_DatadogAgentProfile(ref),
+		"./apis/datadoghq/v2alpha1.DatadogAgentProfileStatus":            schema__apis_datadoghq_v2alpha1_DatadogAgentProfileStatus(ref),
+		"./apis/datadoghq/v2alpha1.DatadogAgentStatus":                   schema__apis_datadoghq_v2alpha1_DatadogAgentStatus(ref),
+		"./apis/datadoghq/v2alpha1.DatadogAgentTemplate":                 schema__apis_datadoghq_v2alpha1_DatadogAgentTemplate(ref),
+		"./apis/datadoghq/v2alpha1.DatadogAgentTemplateReference":        schema__apis_datadoghq_v2alpha1_DatadogAgentTemplateReference(ref),
+		"./apis/datadoghq/v2alpha1.DatadogAgentTemplateStatus":           schema__apis_datadoghq_v2alpha1_DatadogAgentTemplateStatus(ref),
+		"./apis/datadoghq/v2alpha1.DatadogCredentials":                   schema__apis_datadoghq_v2alpha1_DatadogCredentials(ref),
+		"./apis/datadoghq/v2alpha1.DatadogFeatures":                      schema__apis_datadoghq_v2alpha1_DatadogFeatures(ref),
+		"./apis/datadoghq/v2alpha1.DogstatsdFeatureConfig":               schema__apis_datadoghq_v2alpha1_DogstatsdFeatureConfig(ref),
+		"./apis/datadoghq/v2alpha1.EventCollectionFeatureConfig":         schema__apis_datadoghq_v2alpha1_EventCollectionFeatureConfig(ref),
+		"./apis/datadoghq/v2alpha1.KubeStateMetricsCoreFeatureConfig":    schema__apis_datadoghq_v2alpha1_KubeStateMetricsCoreFeatureConfig(ref),
+		"./apis/datadoghq/v2alpha1.LocalService":                         schema__apis_datadoghq_v2alpha1_LocalService(ref),
+		"./apis/datadoghq/v2alpha1.MultiCustomConfig":                    schema__apis_datadoghq_v2alpha1_MultiCustomConfig(ref),
+		"./apis/datadoghq/v2alpha1.NetworkPolicyConfig":                  schema__apis_datadoghq_v2alpha1_NetworkPolicyConfig(ref),
+		"./apis/datadoghq/v2alpha1.OTLPFeatureConfig":                    schema__apis_datadoghq_v2alpha1_OTLPFeatureConfig(ref),
+		"./apis/datadoghq/v2alpha1.OTLPGRPCConfig":                       schema__apis_datadoghq_v2alpha1_OTLPGRPCConfig(ref),
+		"./apis/datadoghq/v2alpha1.OTLPHTTPConfig":                       schema__apis_datadoghq_v2alpha1_OTLPHTTPConfig(ref),
+		"./apis/datadoghq/v2alpha1.OTLPProtocolsConfig":                  schema__apis_datadoghq_v2alpha1_OTLPProtocolsConfig(ref),
+		"./apis/datadoghq/v2alpha1.OTLPReceiverConfig":                   schema__apis_datadoghq_v2alpha1_OTLPReceiverConfig(ref),
+		"./apis/datadoghq/v2alpha1.OrchestratorExplorerFeatureConfig":    schema__apis_datadoghq_v2alpha1_OrchestratorExplorerFeatureConfig(ref),
+		"./apis/datadoghq/v2alpha1.PrometheusScrapeFeatureConfig":        schema__apis_datadoghq_v2alpha1_PrometheusScrapeFeatureConfig(ref),
+		"./apis/datadoghq/v2alpha1.SecretBackendConfig":                  schema__apis_datadoghq_v2alpha1_SecretBackendConfig(ref),
+		"./apis/datadoghq/v2alpha1.SecretBackendRolesConfig":             schema__apis_datadoghq_v2alpha1_SecretBackendRolesConfig(ref),
+		"./apis/datadoghq/v2alpha1.SecurityContextConstraintsConfig":     schema__apis_datadoghq_v2alpha1_SecurityContextConstraintsConfig(ref),
+		"./apis/datadoghq/v2alpha1.UnixDomainSocketConfig":               schema__apis_datadoghq_v2alpha1_UnixDomainSocketConfig(ref),
 	}
 }
 
@@ -71,6 +86,92 @@ func schema__apis_datadoghq_v2alpha1_CustomConfig(ref common.ReferenceCallback)
 	}
 }
 
+func schema__apis_datadoghq_v2alpha1_DaemonSetRollingUpdateSpec(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "DaemonSetRollingUpdateSpec contains configuration fields of the rolling update strategy. The configuration is shared between DaemonSet and ExtendedDaemonSet.",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"maxUnavailable": {
+						SchemaProps: spec.SchemaProps{
+							Description: "The maximum number of DaemonSet pods that can be unavailable during the update. Value can be an absolute number (ex: 5) or a percentage of total number of DaemonSet pods at the start of the update (ex: 10%). Absolute number is calculated from percentage by rounding up. This cannot be 0.",
+							Ref:         ref("k8s.io/apimachinery/pkg/util/intstr.IntOrString"),
+						},
+					},
+					"maxPodSchedulerFailure": {
+						SchemaProps: spec.SchemaProps{
+							Description: "The maximum number of pods not scheduled on their Node due to a scheduler failure: resource constraints. Value can be an absolute number (ex: 5) or a percentage of total number of DaemonSet pods at the start of the update (ex: 10%). Only applicable for an ExtendedDaemonSet deployment.",
+							Ref:         ref("k8s.io/apimachinery/pkg/util/intstr.IntOrString"),
+						},
+					},
+					"maxParallelPodCreation": {
+						SchemaProps: spec.SchemaProps{
+							Description: "The maximum number of pods created in parallel. Only applicable for an ExtendedDaemonSet deployment.",
+							Type:        []string{"integer"},
+							Format:      "int32",
+						},
+					},
+					"slowStartIntervalDuration": {
+						SchemaProps: spec.SchemaProps{
+							Description: "SlowStartIntervalDuration is the duration between two increases of the number of pods created in parallel. Only applicable for an ExtendedDaemonSet deployment.",
+							Ref:         ref("k8s.io/apimachinery/pkg/apis/meta/v1.Duration"),
+						},
+					},
+					"slowStartAdditiveIncrease": {
+						SchemaProps: spec.SchemaProps{
+							Description: "SlowStartAdditiveIncrease is the number of pods added to the number of pods created in parallel at each slow start interval. Value can be an absolute number (ex: 5) or a percentage of total number of DaemonSet pods at the start of the update (ex: 10%). Only applicable for an ExtendedDaemonSet deployment.",
+							Ref:         ref("k8s.io/apimachinery/pkg/util/intstr.IntOrString"),
+						},
+					},
+				},
+			},
+		},
+		Dependencies: []string{
+			"k8s.io/apimachinery/pkg/apis/meta/v1.Duration", "k8s.io/apimachinery/pkg/util/intstr.IntOrString"},
+	}
+}
+
+func schema__apis_datadoghq_v2alpha1_DaemonSetUpdateStrategy(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "DaemonSetUpdateStrategy contains the update strategy configuration of the node Agent.",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"type": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Type of the DaemonSet update strategy: \"RollingUpdate\" or \"OnDelete\". Default is RollingUpdate. Not applicable for an ExtendedDaemonSet deployment.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"rollingUpdate": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Configure the rolling update strategy of the DaemonSet or the ExtendedDaemonSet.",
+							Ref:         ref("./apis/datadoghq/v2alpha1.DaemonSetRollingUpdateSpec"),
+						},
+					},
+					"canary": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Configure the canary deployment configuration of the ExtendedDaemonSet. Not applicable for a DaemonSet deployment.",
+							Ref:         ref("github.com/DataDog/extendeddaemonset/api/v1alpha1.ExtendedDaemonSetSpecStrategyCanary"),
+						},
+					},
+					"reconcileFrequency": {
+						SchemaProps: spec.SchemaProps{
+							Description: "The reconcile frequency of the ExtendedDaemonSet. Not applicable for a DaemonSet deployment.",
+							Ref:         ref("k8s.io/apimachinery/pkg/apis/meta/v1.Duration"),
+						},
+					},
+				},
+			},
+		},
+		Dependencies: []string{
+			"./apis/datadoghq/v2alpha1.DaemonSetRollingUpdateSpec", "github.com/DataDog/extendeddaemonset/api/v1alpha1.ExtendedDaemonSetSpecStrategyCanary", "k8s.io/apimachinery/pkg/apis/meta/v1.Duration"},
+	}
+}
+
 func schema__apis_datadoghq_v2alpha1_DatadogAgent(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
@@ -118,216 +219,792 @@ func schema__apis_datadoghq_v2alpha1_DatadogAgent(ref common.ReferenceCallback)
 	}
 }
 
-func schema__apis_datadoghq_v2alpha1_DatadogAgentGenericContainer(ref common.ReferenceCallback) common.OpenAPIDefinition {
+func schema__apis_datadoghq_v2alpha1_DatadogAgentFeature(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
 			SchemaProps: spec.SchemaProps{
-				Description: "DatadogAgentGenericContainer is the generic structure describing any container's common configuration.",
+				Description: "DatadogAgentFeature adds sidecars, env vars, volumes, annotations, ConfigMaps and RBAC to the components of the DatadogAgents it selects, as a feature of these DatadogAgents.",
 				Type:        []string{"object"},
 				Properties: map[string]spec.Schema{
-					"name": {
+					"kind": {
 						SchemaProps: spec.SchemaProps{
-							Description: "Name of the container that is overridden",
+							Description: "Kind is a string value representing the REST resource this object represents. Servers may infer this from the endpoint the client submits requests to. Cannot be updated. In CamelCase. More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#types-kinds",
 							Type:        []string{"string"},
 							Format:      "",
 						},
 					},
-					"logLevel": {
+					"apiVersion": {
 						SchemaProps: spec.SchemaProps{
-							Description: "LogLevel sets logging verbosity (overrides global setting) Valid log levels are: trace, debug, info, warn, error, critical, and off. Default: 'info'",
+							Description: "APIVersion defines the versioned schema of this representation of an object. Servers should convert recognized schemas to the latest internal value, and may reject unrecognized values. More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#resources",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"metadata": {
+						SchemaProps: spec.SchemaProps{
+							Default: map[string]interface{}{},
+							Ref:     ref("k8s.io/apimachinery/pkg/apis/meta/v1.ObjectMeta"),
+						},
+					},
+					"spec": {
+						SchemaProps: spec.SchemaProps{
+							Default: map[string]interface{}{},
+							Ref:     ref("./apis/datadoghq/v2alpha1.DatadogAgentFeatureSpec"),
+						},
+					},
+				},
+			},
+		},
+		Dependencies: []string{
+			"./apis/datadoghq/v2alpha1.DatadogAgentFeatureSpec", "k8s.io/apimachinery/pkg/apis/meta/v1.ObjectMeta"},
+	}
+}
+
+func schema__apis_datadoghq_v2alpha1_DatadogAgentFeatureComponent(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "DatadogAgentFeatureComponent defines a component required by a feature.",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"name": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Name of the component.",
+							Default:     "",
 							Type:        []string{"string"},
 							Format:      "",
 						},
 					},
+					"containers": {
+						VendorExtensible: spec.VendorExtensible{
+							Extensions: spec.Extensions{
+								"x-kubernetes-list-type": "set",
+							},
+						},
+						SchemaProps: spec.SchemaProps{
+							Description: "The containers of the component required by the feature.",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Default: "",
+										Type:    []string{"string"},
+										Format:  "",
+									},
+								},
+							},
+						},
+					},
+				},
+				Required: []string{"name"},
+			},
+		},
+	}
+}
+
+func schema__apis_datadoghq_v2alpha1_DatadogAgentFeatureComponentOverride(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "DatadogAgentFeatureComponentOverride defines the additions of a DatadogAgentFeature to the pods of a component.",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
 					"env": {
 						VendorExtensible: spec.VendorExtensible{
 							Extensions: spec.Extensions{
 								"x-kubernetes-list-map-keys": []interface{}{
-									"name",
+									"name",
+								},
+								"x-kubernetes-list-type": "map",
+							},
+						},
+						SchemaProps: spec.SchemaProps{
+							Description: "Env is added to all the containers of the component.",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Default: map[string]interface{}{},
+										Ref:     ref("k8s.io/api/core/v1.EnvVar"),
+									},
+								},
+							},
+						},
+					},
+					"volumeMounts": {
+						VendorExtensible: spec.VendorExtensible{
+							Extensions: spec.Extensions{
+								"x-kubernetes-list-map-keys": []interface{}{
+									"mountPath",
+								},
+								"x-kubernetes-list-type": "map",
+							},
+						},
+						SchemaProps: spec.SchemaProps{
+							Description: "VolumeMounts are added to all the containers of the component.",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Default: map[string]interface{}{},
+										Ref:     ref("k8s.io/api/core/v1.VolumeMount"),
+									},
+								},
+							},
+						},
+					},
+					"volumes": {
+						VendorExtensible: spec.VendorExtensible{
+							Extensions: spec.Extensions{
+								"x-kubernetes-list-map-keys": []interface{}{
+									"name",
+								},
+								"x-kubernetes-list-type": "map",
+							},
+						},
+						SchemaProps: spec.SchemaProps{
+							Description: "Volumes are added to the pods of the component.",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Default: map[string]interface{}{},
+										Ref:     ref("k8s.io/api/core/v1.Volume"),
+									},
+								},
+							},
+						},
+					},
+					"sidecars": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Sidecars are containers added to the pods of the component. They don't get the env and volumeMounts of the feature.",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Default: map[string]interface{}{},
+										Ref:     ref("k8s.io/api/core/v1.Container"),
+									},
+								},
+							},
+						},
+					},
+					"annotations": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Annotations are added to the pods of the component.",
+							Type:        []string{"object"},
+							AdditionalProperties: &spec.SchemaOrBool{
+								Allows: true,
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Default: "",
+										Type:    []string{"string"},
+										Format:  "",
+									},
+								},
+							},
+						},
+					},
+					"clusterRules": {
+						VendorExtensible: spec.VendorExtensible{
+							Extensions: spec.Extensions{
+								"x-kubernetes-list-type": "atomic",
+							},
+						},
+						SchemaProps: spec.SchemaProps{
+							Description: "ClusterRules are added to a ClusterRole bound to the ServiceAccount of the component. The Operator needs to have these permissions to grant them.",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Default: map[string]interface{}{},
+										Ref:     ref("k8s.io/api/rbac/v1.PolicyRule"),
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+		Dependencies: []string{
+			"k8s.io/api/core/v1.Container", "k8s.io/api/core/v1.EnvVar", "k8s.io/api/core/v1.Volume", "k8s.io/api/core/v1.VolumeMount", "k8s.io/api/rbac/v1.PolicyRule"},
+	}
+}
+
+func schema__apis_datadoghq_v2alpha1_DatadogAgentFeatureConfigMap(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "DatadogAgentFeatureConfigMap defines a ConfigMap created by a DatadogAgentFeature.",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"name": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Name of the ConfigMap.",
+							Default:     "",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"data": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Data of the ConfigMap.",
+							Type:        []string{"object"},
+							AdditionalProperties: &spec.SchemaOrBool{
+								Allows: true,
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Default: "",
+										Type:    []string{"string"},
+										Format:  "",
+									},
+								},
+							},
+						},
+					},
+				},
+				Required: []string{"name"},
+			},
+		},
+	}
+}
+
+func schema__apis_datadoghq_v2alpha1_DatadogAgentFeatureSpec(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "DatadogAgentFeatureSpec defines the additions of a DatadogAgentFeature to the DatadogAgents it applies to.",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"selector": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Selector selects by label the DatadogAgents the feature applies to, in all the namespaces. The feature applies to all the DatadogAgents if not set.",
+							Ref:         ref("k8s.io/apimachinery/pkg/apis/meta/v1.LabelSelector"),
+						},
+					},
+					"override": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Override defines the additions to the pods of each component, by component name.",
+							Type:        []string{"object"},
+							AdditionalProperties: &spec.SchemaOrBool{
+								Allows: true,
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Ref: ref("./apis/datadoghq/v2alpha1.DatadogAgentFeatureComponentOverride"),
+									},
+								},
+							},
+						},
+					},
+					"configMaps": {
+						VendorExtensible: spec.VendorExtensible{
+							Extensions: spec.Extensions{
+								"x-kubernetes-list-map-keys": []interface{}{
+									"name",
+								},
+								"x-kubernetes-list-type": "map",
+							},
+						},
+						SchemaProps: spec.SchemaProps{
+							Description: "ConfigMaps created in the namespace of each DatadogAgent the feature applies to.",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Default: map[string]interface{}{},
+										Ref:     ref("./apis/datadoghq/v2alpha1.DatadogAgentFeatureConfigMap"),
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+		Dependencies: []string{
+			"./apis/datadoghq/v2alpha1.DatadogAgentFeatureComponentOverride", "./apis/datadoghq/v2alpha1.DatadogAgentFeatureConfigMap", "k8s.io/apimachinery/pkg/apis/meta/v1.LabelSelector"},
+	}
+}
+
+func schema__apis_datadoghq_v2alpha1_DatadogAgentFeatureStatus(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "DatadogAgentFeatureStatus defines the observed state of an enabled feature.",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"id": {
+						SchemaProps: spec.SchemaProps{
+							Description: "ID of the feature.",
+							Default:     "",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"requiredComponents": {
+						VendorExtensible: spec.VendorExtensible{
+							Extensions: spec.Extensions{
+								"x-kubernetes-list-map-keys": []interface{}{
+									"name",
+								},
+								"x-kubernetes-list-type": "map",
+							},
+						},
+						SchemaProps: spec.SchemaProps{
+							Description: "The components required by the feature.",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Default: map[string]interface{}{},
+										Ref:     ref("./apis/datadoghq/v2alpha1.DatadogAgentFeatureComponent"),
+									},
+								},
+							},
+						},
+					},
+				},
+				Required: []string{"id"},
+			},
+		},
+		Dependencies: []string{
+			"./apis/datadoghq/v2alpha1.DatadogAgentFeatureComponent"},
+	}
+}
+
+func schema__apis_datadoghq_v2alpha1_DatadogAgentGenericContainer(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "DatadogAgentGenericContainer is the generic structure describing any container's common configuration.",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"name": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Name of the container that is overridden",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"logLevel": {
+						SchemaProps: spec.SchemaProps{
+							Description: "LogLevel sets logging verbosity (overrides global setting) Valid log levels are: trace, debug, info, warn, error, critical, and off. Default: 'info'",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"env": {
+						VendorExtensible: spec.VendorExtensible{
+							Extensions: spec.Extensions{
+								"x-kubernetes-list-map-keys": []interface{}{
+									"name",
+								},
+								"x-kubernetes-list-type": "map",
+							},
+						},
+						SchemaProps: spec.SchemaProps{
+							Description: "Specify additional environmental variables in the container See also: https://docs.datadoghq.com/agent/kubernetes/?tab=helm#environment-variables",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Default: map[string]interface{}{},
+										Ref:     ref("k8s.io/api/core/v1.EnvVar"),
+									},
+								},
+							},
+						},
+					},
+					"volumeMounts": {
+						VendorExtensible: spec.VendorExtensible{
+							Extensions: spec.Extensions{
+								"x-kubernetes-list-map-keys": []interface{}{
+									"name",
+									"mountPath",
+								},
+								"x-kubernetes-list-type": "map",
+							},
+						},
+						SchemaProps: spec.SchemaProps{
+							Description: "Specify additional volume mounts in the container.",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Default: map[string]interface{}{},
+										Ref:     ref("k8s.io/api/core/v1.VolumeMount"),
+									},
+								},
+							},
+						},
+					},
+					"resources": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Specify the Request and Limits of the pods To get guaranteed QoS class, specify requests and limits equal. See also: http://kubernetes.io/docs/user-guide/compute-resources/",
+							Ref:         ref("k8s.io/api/core/v1.ResourceRequirements"),
+						},
+					},
+					"command": {
+						VendorExtensible: spec.VendorExtensible{
+							Extensions: spec.Extensions{
+								"x-kubernetes-list-type": "atomic",
+							},
+						},
+						SchemaProps: spec.SchemaProps{
+							Description: "Command allows the specification of a custom entrypoint for container",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Default: "",
+										Type:    []string{"string"},
+										Format:  "",
+									},
+								},
+							},
+						},
+					},
+					"args": {
+						VendorExtensible: spec.VendorExtensible{
+							Extensions: spec.Extensions{
+								"x-kubernetes-list-type": "atomic",
+							},
+						},
+						SchemaProps: spec.SchemaProps{
+							Description: "Args allows the specification of extra args to the `Command` parameter",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Default: "",
+										Type:    []string{"string"},
+										Format:  "",
+									},
+								},
+							},
+						},
+					},
+					"healthPort": {
+						SchemaProps: spec.SchemaProps{
+							Description: "HealthPort of the container for the internal liveness probe. Must be the same as the Liveness/Readiness probes.",
+							Type:        []string{"integer"},
+							Format:      "int32",
+						},
+					},
+					"readinessProbe": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Configure the Readiness Probe of the container",
+							Ref:         ref("k8s.io/api/core/v1.Probe"),
+						},
+					},
+					"livenessProbe": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Configure the Liveness Probe of the container",
+							Ref:         ref("k8s.io/api/core/v1.Probe"),
+						},
+					},
+					"securityContext": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Container-level SecurityContext.",
+							Ref:         ref("k8s.io/api/core/v1.SecurityContext"),
+						},
+					},
+					"seccompConfig": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Seccomp configurations to override Operator actions. For all other Seccomp Profile manipulation, use SecurityContext.",
+							Ref:         ref("./apis/datadoghq/v2alpha1.SeccompConfig"),
+						},
+					},
+					"appArmorProfileName": {
+						SchemaProps: spec.SchemaProps{
+							Description: "AppArmorProfileName specifies an apparmor profile.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+				},
+			},
+		},
+		Dependencies: []string{
+			"./apis/datadoghq/v2alpha1.SeccompConfig", "k8s.io/api/core/v1.EnvVar", "k8s.io/api/core/v1.Probe", "k8s.io/api/core/v1.ResourceRequirements", "k8s.io/api/core/v1.SecurityContext", "k8s.io/api/core/v1.VolumeMount"},
+	}
+}
+
+func schema__apis_datadoghq_v2alpha1_DatadogAgentProfile(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "DatadogAgentProfile configures the node Agent deployed on a pool of nodes.",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"name": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Name of the profile, used as suffix of the node Agent DaemonSet name.",
+							Default:     "",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"nodeSelector": {
+						VendorExtensible: spec.VendorExtensible{
+							Extensions: spec.Extensions{
+								"x-kubernetes-list-type": "atomic",
+							},
+						},
+						SchemaProps: spec.SchemaProps{
+							Description: "NodeSelector selects the nodes of the profile: a node is part of the profile if it matches all the requirements. Supported operators are `In`, `NotIn`, `Exists` and `DoesNotExist`.",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Default: map[string]interface{}{},
+										Ref:     ref("k8s.io/api/core/v1.NodeSelectorRequirement"),
+									},
+								},
+							},
+						},
+					},
+					"override": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Override the configuration of the node Agent of this profile. It is applied on top of the `nodeAgent` override. The fields `createRbac`, `extraConfd`, `extraChecksd` and `securityContextConstraints` are not supported.",
+							Ref:         ref("./apis/datadoghq/v2alpha1.DatadogAgentComponentOverride"),
+						},
+					},
+				},
+				Required: []string{"name", "nodeSelector"},
+			},
+		},
+		Dependencies: []string{
+			"./apis/datadoghq/v2alpha1.DatadogAgentComponentOverride", "k8s.io/api/core/v1.NodeSelectorRequirement"},
+	}
+}
+
+func schema__apis_datadoghq_v2alpha1_DatadogAgentProfileStatus(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "DatadogAgentProfileStatus defines the observed state of the node Agent of a profile.",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"name": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Name of the profile.",
+							Default:     "",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"agent": {
+						SchemaProps: spec.SchemaProps{
+							Description: "The actual state of the node Agent of the profile.",
+							Ref:         ref("github.com/DataDog/datadog-operator/apis/datadoghq/common/v1.DaemonSetStatus"),
+						},
+					},
+				},
+				Required: []string{"name"},
+			},
+		},
+		Dependencies: []string{
+			"github.com/DataDog/datadog-operator/apis/datadoghq/common/v1.DaemonSetStatus"},
+	}
+}
+
+func schema__apis_datadoghq_v2alpha1_DatadogAgentStatus(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "DatadogAgentStatus defines the observed state of DatadogAgent.",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"conditions": {
+						VendorExtensible: spec.VendorExtensible{
+							Extensions: spec.Extensions{
+								"x-kubernetes-list-map-keys": []interface{}{
+									"type",
 								},
 								"x-kubernetes-list-type": "map",
 							},
 						},
 						SchemaProps: spec.SchemaProps{
-							Description: "Specify additional environmental variables in the container See also: https://docs.datadoghq.com/agent/kubernetes/?tab=helm#environment-variables",
+							Description: "Conditions Represents the latest available observations of a DatadogAgent's current state.",
 							Type:        []string{"array"},
 							Items: &spec.SchemaOrArray{
 								Schema: &spec.Schema{
 									SchemaProps: spec.SchemaProps{
 										Default: map[string]interface{}{},
-										Ref:     ref("k8s.io/api/core/v1.EnvVar"),
+										Ref:     ref("k8s.io/apimachinery/pkg/apis/meta/v1.Condition"),
 									},
 								},
 							},
 						},
 					},
-					"volumeMounts": {
-						VendorExtensible: spec.VendorExtensible{
-							Extensions: spec.Extensions{
-								"x-kubernetes-list-map-keys": []interface{}{
-									"name",
-									"mountPath",
-								},
-								"x-kubernetes-list-type": "map",
-							},
+					"agent": {
+						SchemaProps: spec.SchemaProps{
+							Description: "The actual state of the Agent as an extended daemonset.",
+							Ref:         ref("github.com/DataDog/datadog-operator/apis/datadoghq/common/v1.DaemonSetStatus"),
 						},
+					},
+					"clusterAgent": {
 						SchemaProps: spec.SchemaProps{
-							Description: "Specify additional volume mounts in the container.",
-							Type:        []string{"array"},
-							Items: &spec.SchemaOrArray{
-								Schema: &spec.Schema{
-									SchemaProps: spec.SchemaProps{
-										Default: map[string]interface{}{},
-										Ref:     ref("k8s.io/api/core/v1.VolumeMount"),
-									},
-								},
-							},
+							Description: "The actual state of the Cluster Agent as a deployment.",
+							Ref:         ref("github.com/DataDog/datadog-operator/apis/datadoghq/common/v1.DeploymentStatus"),
 						},
 					},
-					"resources": {
+					"clusterChecksRunner": {
 						SchemaProps: spec.SchemaProps{
-							Description: "Specify the Request and Limits of the pods To get guaranteed QoS class, specify requests and limits equal. See also: http://kubernetes.io/docs/user-guide/compute-resources/",
-							Ref:         ref("k8s.io/api/core/v1.ResourceRequirements"),
+							Description: "The actual state of the Cluster Checks Runner as a deployment.",
+							Ref:         ref("github.com/DataDog/datadog-operator/apis/datadoghq/common/v1.DeploymentStatus"),
 						},
 					},
-					"command": {
+					"profiles": {
 						VendorExtensible: spec.VendorExtensible{
 							Extensions: spec.Extensions{
-								"x-kubernetes-list-type": "atomic",
+								"x-kubernetes-list-map-keys": []interface{}{
+									"name",
+								},
+								"x-kubernetes-list-type": "map",
 							},
 						},
 						SchemaProps: spec.SchemaProps{
-							Description: "Command allows the specification of a custom entrypoint for container",
+							Description: "The actual state of the node Agent of each profile.",
 							Type:        []string{"array"},
 							Items: &spec.SchemaOrArray{
 								Schema: &spec.Schema{
 									SchemaProps: spec.SchemaProps{
-										Default: "",
-										Type:    []string{"string"},
-										Format:  "",
+										Default: map[string]interface{}{},
+										Ref:     ref("./apis/datadoghq/v2alpha1.DatadogAgentProfileStatus"),
 									},
 								},
 							},
 						},
 					},
-					"args": {
+					"features": {
 						VendorExtensible: spec.VendorExtensible{
 							Extensions: spec.Extensions{
-								"x-kubernetes-list-type": "atomic",
+								"x-kubernetes-list-map-keys": []interface{}{
+									"id",
+								},
+								"x-kubernetes-list-type": "map",
 							},
 						},
 						SchemaProps: spec.SchemaProps{
-							Description: "Args allows the specification of extra args to the `Command` parameter",
+							Description: "The enabled features and the components they require. The result of the reconcile of each feature is reported in the condition of type `feature.datadoghq.com/<feature ID>`.",
 							Type:        []string{"array"},
 							Items: &spec.SchemaOrArray{
 								Schema: &spec.Schema{
 									SchemaProps: spec.SchemaProps{
-										Default: "",
-										Type:    []string{"string"},
-										Format:  "",
+										Default: map[string]interface{}{},
+										Ref:     ref("./apis/datadoghq/v2alpha1.DatadogAgentFeatureStatus"),
 									},
 								},
 							},
 						},
 					},
-					"healthPort": {
+					"template": {
 						SchemaProps: spec.SchemaProps{
-							Description: "HealthPort of the container for the internal liveness probe. Must be the same as the Liveness/Readiness probes.",
-							Type:        []string{"integer"},
-							Format:      "int32",
+							Description: "The DatadogAgentTemplate merged under the spec during the last reconcile.",
+							Ref:         ref("./apis/datadoghq/v2alpha1.DatadogAgentTemplateStatus"),
 						},
 					},
-					"readinessProbe": {
+				},
+			},
+		},
+		Dependencies: []string{
+			"./apis/datadoghq/v2alpha1.DatadogAgentFeatureStatus", "./apis/datadoghq/v2alpha1.DatadogAgentProfileStatus", "./apis/datadoghq/v2alpha1.DatadogAgentTemplateStatus", "github.com/DataDog/datadog-operator/apis/datadoghq/common/v1.DaemonSetStatus", "github.com/DataDog/datadog-operator/apis/datadoghq/common/v1.DeploymentStatus", "k8s.io/apimachinery/pkg/apis/meta/v1.Condition"},
+	}
+}
+
+func schema__apis_datadoghq_v2alpha1_DatadogAgentTemplate(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "DatadogAgentTemplate defines a base DatadogAgent spec, inherited by the DatadogAgents referencing it in their spec.baseRef.",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"kind": {
 						SchemaProps: spec.SchemaProps{
-							Description: "Configure the Readiness Probe of the container",
-							Ref:         ref("k8s.io/api/core/v1.Probe"),
+							Description: "Kind is a string value representing the REST resource this object represents. Servers may infer this from the endpoint the client submits requests to. Cannot be updated. In CamelCase. More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#types-kinds",
+							Type:        []string{"string"},
+							Format:      "",
 						},
 					},
-					"livenessProbe": {
+					"apiVersion": {
 						SchemaProps: spec.SchemaProps{
-							Description: "Configure the Liveness Probe of the container",
-							Ref:         ref("k8s.io/api/core/v1.Probe"),
+							Description: "APIVersion defines the versioned schema of this representation of an object. Servers should convert recognized schemas to the latest internal value, and may reject unrecognized values. More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#resources",
+							Type:        []string{"string"},
+							Format:      "",
 						},
 					},
-					"securityContext": {
+					"metadata": {
 						SchemaProps: spec.SchemaProps{
-							Description: "Container-level SecurityContext.",
-							Ref:         ref("k8s.io/api/core/v1.SecurityContext"),
+							Default: map[string]interface{}{},
+							Ref:     ref("k8s.io/apimachinery/pkg/apis/meta/v1.ObjectMeta"),
 						},
 					},
-					"seccompConfig": {
+					"spec": {
 						SchemaProps: spec.SchemaProps{
-							Description: "Seccomp configurations to override Operator actions. For all other Seccomp Profile manipulation, use SecurityContext.",
-							Ref:         ref("./apis/datadoghq/v2alpha1.SeccompConfig"),
+							Default: map[string]interface{}{},
+							Ref:     ref("./apis/datadoghq/v2alpha1.DatadogAgentSpec"),
 						},
 					},
-					"appArmorProfileName": {
+				},
+			},
+		},
+		Dependencies: []string{
+			"./apis/datadoghq/v2alpha1.DatadogAgentSpec", "k8s.io/apimachinery/pkg/apis/meta/v1.ObjectMeta"},
+	}
+}
+
+func schema__apis_datadoghq_v2alpha1_DatadogAgentTemplateReference(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "DatadogAgentTemplateReference references a DatadogAgentTemplate.",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"name": {
 						SchemaProps: spec.SchemaProps{
-							Description: "AppArmorProfileName specifies an apparmor profile.",
+							Description: "Name of the DatadogAgentTemplate.",
+							Default:     "",
 							Type:        []string{"string"},
 							Format:      "",
 						},
 					},
 				},
+				Required: []string{"name"},
 			},
 		},
-		Dependencies: []string{
-			"./apis/datadoghq/v2alpha1.SeccompConfig", "k8s.io/api/core/v1.EnvVar", "k8s.io/api/core/v1.Probe", "k8s.io/api/core/v1.ResourceRequirements", "k8s.io/api/core/v1.SecurityContext", "k8s.io/api/core/v1.VolumeMount"},
 	}
 }
 
-func schema__apis_datadoghq_v2alpha1_DatadogAgentStatus(ref common.ReferenceCallback) common.OpenAPIDefinition {
+func schema__apis_datadoghq_v2alpha1_DatadogAgentTemplateStatus(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
 			SchemaProps: spec.SchemaProps{
-				Description: "DatadogAgentStatus defines the observed state of DatadogAgent.",
+				Description: "DatadogAgentTemplateStatus defines the DatadogAgentTemplate merged under the spec of a DatadogAgent.",
 				Type:        []string{"object"},
 				Properties: map[string]spec.Schema{
-					"conditions": {
-						VendorExtensible: spec.VendorExtensible{
-							Extensions: spec.Extensions{
-								"x-kubernetes-list-map-keys": []interface{}{
-									"type",
-								},
-								"x-kubernetes-list-type": "map",
-							},
-						},
-						SchemaProps: spec.SchemaProps{
-							Description: "Conditions Represents the latest available observations of a DatadogAgent's current state.",
-							Type:        []string{"array"},
-							Items: &spec.SchemaOrArray{
-								Schema: &spec.Schema{
-									SchemaProps: spec.SchemaProps{
-										Default: map[string]interface{}{},
-										Ref:     ref("k8s.io/apimachinery/pkg/apis/meta/v1.Condition"),
-									},
-								},
-							},
-						},
-					},
-					"agent": {
-						SchemaProps: spec.SchemaProps{
-							Description: "The actual state of the Agent as an extended daemonset.",
-							Ref:         ref("github.com/DataDog/datadog-operator/apis/datadoghq/common/v1.DaemonSetStatus"),
-						},
-					},
-					"clusterAgent": {
+					"name": {
 						SchemaProps: spec.SchemaProps{
-							Description: "The actual state of the Cluster Agent as a deployment.",
-							Ref:         ref("github.com/DataDog/datadog-operator/apis/datadoghq/common/v1.DeploymentStatus"),
+							Description: "Name of the DatadogAgentTemplate.",
+							Default:     "",
+							Type:        []string{"string"},
+							Format:      "",
 						},
 					},
-					"clusterChecksRunner": {
+					"generation": {
 						SchemaProps: spec.SchemaProps{
-							Description: "The actual state of the Cluster Checks Runner as a deployment.",
-							Ref:         ref("github.com/DataDog/datadog-operator/apis/datadoghq/common/v1.DeploymentStatus"),
+							Description: "Generation of the DatadogAgentTemplate merged under the spec.",
+							Default:     0,
+							Type:        []string{"integer"},
+							Format:      "int64",
 						},
 					},
 				},
+				Required: []string{"name", "generation"},
 			},
 		},
-		Dependencies: []string{
-			"github.com/DataDog/datadog-operator/apis/datadoghq/common/v1.DaemonSetStatus", "github.com/DataDog/datadog-operator/apis/datadoghq/common/v1.DeploymentStatus", "k8s.io/apimachinery/pkg/apis/meta/v1.Condition"},
 	}
 }
 
@@ -929,6 +1606,116 @@ func schema__apis_datadoghq_v2alpha1_PrometheusScrapeFeatureConfig(ref common.Re
 	}
 }
 
+func schema__apis_datadoghq_v2alpha1_SecretBackendConfig(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "SecretBackendConfig provides configuration for the secret backend.",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"command": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Command defines the secret backend command to use",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"args": {
+						VendorExtensible: spec.VendorExtensible{
+							Extensions: spec.Extensions{
+								"x-kubernetes-list-type": "atomic",
+							},
+						},
+						SchemaProps: spec.SchemaProps{
+							Description: "Args defines the list of arguments to pass to the command",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Default: "",
+										Type:    []string{"string"},
+										Format:  "",
+									},
+								},
+							},
+						},
+					},
+					"enableGlobalPermissions": {
+						SchemaProps: spec.SchemaProps{
+							Description: "EnableGlobalPermissions grants the Agents the permission to read the Secrets of all the namespaces. Required by a secret backend command reading Kubernetes Secrets, like the one of the Operator helpers.",
+							Type:        []string{"boolean"},
+							Format:      "",
+						},
+					},
+					"roles": {
+						VendorExtensible: spec.VendorExtensible{
+							Extensions: spec.Extensions{
+								"x-kubernetes-list-type": "atomic",
+							},
+						},
+						SchemaProps: spec.SchemaProps{
+							Description: "Roles grant the Agents the permission to read the given Secrets of a namespace. Use it instead of EnableGlobalPermissions to restrict the Secrets the Agents can read.",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Default: map[string]interface{}{},
+										Ref:     ref("./apis/datadoghq/v2alpha1.SecretBackendRolesConfig"),
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+		Dependencies: []string{
+			"./apis/datadoghq/v2alpha1.SecretBackendRolesConfig"},
+	}
+}
+
+func schema__apis_datadoghq_v2alpha1_SecretBackendRolesConfig(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "SecretBackendRolesConfig provides the configuration of the Role granting the Agents the permission to read Secrets in a namespace.",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"namespace": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Namespace of the Secrets.",
+							Default:     "",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"secrets": {
+						VendorExtensible: spec.VendorExtensible{
+							Extensions: spec.Extensions{
+								"x-kubernetes-list-type": "set",
+							},
+						},
+						SchemaProps: spec.SchemaProps{
+							Description: "Secrets lists the names of the Secrets the Agents can read.",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Default: "",
+										Type:    []string{"string"},
+										Format:  "",
+									},
+								},
+							},
+						},
+					},
+				},
+				Required: []string{"namespace", "secrets"},
+			},
+		},
+	}
+}
+
 func schema__apis_datadoghq_v2alpha1_SecurityContextConstraintsConfig(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
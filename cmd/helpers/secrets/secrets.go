@@ -20,10 +20,6 @@ import (
 	"github.com/spf13/cobra"
 )
 
-const (
-	maxSecretFileSize = 8192
-)
-
 // NewCmd implements reading secrets from a directory/volume mount
 func NewCmd() *cobra.Command {
 	return &cobra.Command{
@@ -83,68 +79,10 @@ func splitVersion(ver string) []string {
 }
 
 func readSecret(path string) s.Secret {
-	value, err := readSecretFile(path)
+	value, err := s.ReadSecretFile(path)
 	var errMsg string
 	if err != nil {
 		errMsg = err.Error()
 	}
 	return s.Secret{Value: value, ErrorMsg: errMsg}
 }
-
-func readSecretFile(path string) (string, error) {
-	fi, err := os.Lstat(path)
-	if err != nil {
-		if os.IsNotExist(err) {
-			return "", errors.New("secret does not exist")
-		}
-		return "", err
-	}
-
-	if fi.Mode()&os.ModeSymlink != 0 {
-		// Ensure that the symlink is in the same dir
-		var target string
-		target, err = os.Readlink(path)
-		if err != nil {
-			return "", fmt.Errorf("failed to read symlink target: %w", err)
-		}
-
-		dir := filepath.Dir(path)
-		if !filepath.IsAbs(target) {
-			target, err = filepath.Abs(filepath.Join(dir, target))
-			if err != nil {
-				return "", fmt.Errorf("failed to resolve symlink absolute path: %w", err)
-			}
-		}
-
-		var dirAbs string
-		dirAbs, err = filepath.Abs(dir)
-		if err != nil {
-			return "", fmt.Errorf("failed to resolve absolute path of directory: %w", err)
-		}
-
-		if !strings.HasPrefix(target, dirAbs) {
-			return "", fmt.Errorf("not following symlink %q outside of %q", target, dir)
-		}
-	}
-	fi, err = os.Stat(path)
-	if err != nil {
-		return "", err
-	}
-
-	if fi.Size() > maxSecretFileSize {
-		return "", errors.New("secret exceeds max allowed size")
-	}
-
-	var file *os.File
-	file, err = os.Open(path)
-	if err != nil {
-		return "", err
-	}
-
-	var bytes []byte
-	bytes, err = ioutil.ReadAll(file)
-	if err != nil {
-		return "", err
-	}
-	return string(bytes), nil
-}
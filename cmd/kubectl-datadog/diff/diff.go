@@ -0,0 +1,195 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package diff
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/DataDog/datadog-operator/apis/datadoghq/v2alpha1"
+	"github.com/DataDog/datadog-operator/controllers/datadogagent"
+	"github.com/DataDog/datadog-operator/pkg/kubernetes"
+	"github.com/DataDog/datadog-operator/pkg/plugin/common"
+
+	edsv1alpha1 "github.com/DataDog/extendeddaemonset/api/v1alpha1"
+	"github.com/spf13/cobra"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/version"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+var diffExample = `
+  # show the pending changes of the objects managed for the DatadogAgent foo
+  %[1]s diff foo
+
+  # same, for an operator started with the ExtendedDaemonSet support
+  %[1]s diff foo --support-extendeddaemonset
+`
+
+// options provides information required by Datadog diff command
+type options struct {
+	genericclioptions.IOStreams
+	common.Options
+	args                     []string
+	userDatadogAgentName     string
+	supportExtendedDaemonset bool
+	supportCilium            bool
+}
+
+// newOptions provides an instance of options with default values
+func newOptions(streams genericclioptions.IOStreams) *options {
+	o := &options{
+		IOStreams: streams,
+	}
+	o.SetConfigFlags()
+	return o
+}
+
+// New provides a cobra command wrapping options for "diff" sub command
+func New(streams genericclioptions.IOStreams) *cobra.Command {
+	o := newOptions(streams)
+	cmd := &cobra.Command{
+		Use:          "diff [DatadogAgent name]",
+		Short:        "Show the pending changes between a DatadogAgent and the objects deployed by the operator",
+		Example:      fmt.Sprintf(diffExample, "kubectl datadog"),
+		SilenceUsage: true,
+		RunE: func(c *cobra.Command, args []string) error {
+			if err := o.complete(c, args); err != nil {
+				return err
+			}
+			if err := o.validate(); err != nil {
+				return err
+			}
+			return o.run()
+		},
+	}
+
+	cmd.Flags().BoolVarP(&o.supportExtendedDaemonset, "support-extendeddaemonset", "", false, "The operator deploys the node agent as an ExtendedDaemonSet")
+	cmd.Flags().BoolVarP(&o.supportCilium, "support-cilium", "", false, "The operator manages Cilium network policies")
+	o.ConfigFlags.AddFlags(cmd.Flags())
+
+	return cmd
+}
+
+// complete sets all information required for processing the command
+func (o *options) complete(cmd *cobra.Command, args []string) error {
+	o.args = args
+	if len(args) > 0 {
+		o.userDatadogAgentName = args[0]
+	}
+	return o.Init(cmd)
+}
+
+// validate ensures that all required arguments and flag values are provided
+func (o *options) validate() error {
+	if len(o.args) != 1 {
+		return errors.New("a DatadogAgent name is required")
+	}
+	if !o.IsDatadogAgentV2Available() {
+		return errors.New("the diff command requires the v2alpha1 DatadogAgent API")
+	}
+	return nil
+}
+
+// run runs the diff command
+func (o *options) run() error {
+	dda := &v2alpha1.DatadogAgent{}
+	err := o.Client.Get(context.TODO(), client.ObjectKey{Namespace: o.UserNamespace, Name: o.userDatadogAgentName}, dda)
+	if err != nil && apierrors.IsNotFound(err) {
+		return fmt.Errorf("DatadogAgent %s/%s not found", o.UserNamespace, o.userDatadogAgentName)
+	} else if err != nil {
+		return fmt.Errorf("unable to get DatadogAgent: %w", err)
+	}
+
+	if o.supportExtendedDaemonset {
+		// The plugin client scheme doesn't register the ExtendedDaemonSet API by default
+		if err = edsv1alpha1.AddToScheme(scheme.Scheme); err != nil {
+			return fmt.Errorf("unable to register ExtendedDaemonSet apis: %w", err)
+		}
+	}
+
+	platformInfo, versionInfo, err := o.getPlatformInfo()
+	if err != nil {
+		return err
+	}
+
+	renderOptions, err := o.getRenderOptions(context.TODO(), dda)
+	if err != nil {
+		return err
+	}
+	renderOptions.PlatformInfo = platformInfo
+	renderOptions.VersionInfo = versionInfo
+
+	diffs, err := datadogagent.Diff(context.TODO(), o.Client, dda, renderOptions)
+	if err != nil {
+		return fmt.Errorf("unable to compute the diff of DatadogAgent %s/%s: %w", dda.Namespace, dda.Name, err)
+	}
+
+	if len(diffs) == 0 {
+		fmt.Fprintln(o.Out, "No pending changes")
+		return nil
+	}
+	for _, diff := range diffs {
+		id := diff.Name
+		if diff.Namespace != "" {
+			id = fmt.Sprintf("%s/%s", diff.Namespace, diff.Name)
+		}
+		if diff.Create {
+			fmt.Fprintf(o.Out, "%s %s: to create\n", diff.Kind, id)
+			continue
+		}
+		fmt.Fprintf(o.Out, "%s %s: to update\n", diff.Kind, id)
+		for _, field := range diff.Fields {
+			fmt.Fprintf(o.Out, "  %s\n", field)
+		}
+	}
+	return nil
+}
+
+// getRenderOptions returns the options to render the DatadogAgent as the operator does, with the DatadogAgentTemplate
+// it references and the DatadogAgentFeatures of the cluster
+func (o *options) getRenderOptions(ctx context.Context, dda *v2alpha1.DatadogAgent) (*datadogagent.RenderOptions, error) {
+	renderOptions := &datadogagent.RenderOptions{
+		SupportExtendedDaemonset: o.supportExtendedDaemonset,
+		SupportCilium:            o.supportCilium,
+	}
+
+	ddafList := &v2alpha1.DatadogAgentFeatureList{}
+	if err := o.Client.List(ctx, ddafList); err != nil && !meta.IsNoMatchError(err) {
+		return nil, fmt.Errorf("unable to list the DatadogAgentFeatures: %w", err)
+	}
+	renderOptions.DatadogAgentFeatures = ddafList.Items
+
+	if dda.Spec.BaseRef != nil {
+		ddat := v2alpha1.DatadogAgentTemplate{}
+		err := o.Client.Get(ctx, client.ObjectKey{Name: dda.Spec.BaseRef.Name}, &ddat)
+		if err != nil && apierrors.IsNotFound(err) {
+			return nil, fmt.Errorf("DatadogAgentTemplate %s referenced in spec.baseRef not found", dda.Spec.BaseRef.Name)
+		} else if err != nil {
+			return nil, fmt.Errorf("unable to get DatadogAgentTemplate: %w", err)
+		}
+		renderOptions.DatadogAgentTemplates = []v2alpha1.DatadogAgentTemplate{ddat}
+	}
+
+	return renderOptions, nil
+}
+
+func (o *options) getPlatformInfo() (kubernetes.PlatformInfo, *version.Info, error) {
+	versionInfo, err := o.Clientset.Discovery().ServerVersion()
+	if err != nil {
+		return kubernetes.PlatformInfo{}, nil, fmt.Errorf("unable to get APIServer version: %w", err)
+	}
+	groups, resources, err := o.Clientset.Discovery().ServerGroupsAndResources()
+	if err != nil && !discovery.IsGroupDiscoveryFailedError(err) {
+		return kubernetes.PlatformInfo{}, nil, fmt.Errorf("unable to get API resource versions: %w", err)
+	}
+	return kubernetes.NewPlatformInfo(versionInfo, groups, resources), versionInfo, nil
+}
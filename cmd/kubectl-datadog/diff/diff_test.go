@@ -0,0 +1,59 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package diff
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/DataDog/datadog-operator/apis/datadoghq/v2alpha1"
+)
+
+func Test_options_getRenderOptions(t *testing.T) {
+	s := runtime.NewScheme()
+	require.NoError(t, v2alpha1.AddToScheme(s))
+	ddaf := &v2alpha1.DatadogAgentFeature{ObjectMeta: metav1.ObjectMeta{Name: "platform"}}
+	dda := &v2alpha1.DatadogAgent{ObjectMeta: metav1.ObjectMeta{Namespace: "bar", Name: "foo"}}
+
+	o := newOptions(genericclioptions.NewTestIOStreamsDiscard())
+	o.SetClient(fake.NewClientBuilder().WithScheme(s).WithObjects(ddaf).Build())
+	o.supportCilium = true
+
+	renderOptions, err := o.getRenderOptions(context.TODO(), dda)
+	require.NoError(t, err)
+	assert.True(t, renderOptions.SupportCilium)
+	if assert.Len(t, renderOptions.DatadogAgentFeatures, 1) {
+		assert.Equal(t, "platform", renderOptions.DatadogAgentFeatures[0].Name)
+	}
+}
+
+func Test_options_getRenderOptions_template(t *testing.T) {
+	s := runtime.NewScheme()
+	require.NoError(t, v2alpha1.AddToScheme(s))
+	ddat := &v2alpha1.DatadogAgentTemplate{ObjectMeta: metav1.ObjectMeta{Name: "base"}}
+	dda := &v2alpha1.DatadogAgent{ObjectMeta: metav1.ObjectMeta{Namespace: "bar", Name: "foo"}}
+	dda.Spec.BaseRef = &v2alpha1.DatadogAgentTemplateReference{Name: "base"}
+
+	o := newOptions(genericclioptions.NewTestIOStreamsDiscard())
+	o.SetClient(fake.NewClientBuilder().WithScheme(s).WithObjects(ddat).Build())
+
+	renderOptions, err := o.getRenderOptions(context.TODO(), dda)
+	require.NoError(t, err)
+	if assert.Len(t, renderOptions.DatadogAgentTemplates, 1) {
+		assert.Equal(t, "base", renderOptions.DatadogAgentTemplates[0].Name)
+	}
+
+	dda.Spec.BaseRef.Name = "missing"
+	_, err = o.getRenderOptions(context.TODO(), dda)
+	assert.EqualError(t, err, "DatadogAgentTemplate missing referenced in spec.baseRef not found")
+}
@@ -0,0 +1,233 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package importer
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+
+	datadogapiclientv1 "github.com/DataDog/datadog-api-client-go/api/v1/datadog"
+	"github.com/DataDog/datadog-operator/apis/datadoghq/v1alpha1"
+	"github.com/DataDog/datadog-operator/pkg/config"
+	"github.com/DataDog/datadog-operator/pkg/datadogclient"
+
+	"github.com/spf13/cobra"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"sigs.k8s.io/yaml"
+)
+
+var importExample = `
+  # write the DatadogMonitors adopting the monitors 1234 and 5678, using the DD_API_KEY and DD_APP_KEY credentials
+  %[1]s import 1234 5678 > monitors.yaml
+
+  # write a DatadogMonitor copying the monitor 1234 of the datadoghq.eu site, without adopting it
+  %[1]s import 1234 --site datadoghq.eu --adopt=false
+`
+
+// invalidNameCharsRegexp matches the characters not allowed in a DatadogMonitor name
+var invalidNameCharsRegexp = regexp.MustCompile(`[^a-z0-9]+`)
+
+// options provides information required by Datadog monitor import command
+type options struct {
+	genericclioptions.IOStreams
+	configFlags *genericclioptions.ConfigFlags
+	monitorIDs  []int64
+	apiKey      string
+	appKey      string
+	site        string
+	adopt       bool
+}
+
+// newOptions provides an instance of options with default values
+func newOptions(streams genericclioptions.IOStreams) *options {
+	return &options{
+		IOStreams:   streams,
+		configFlags: genericclioptions.NewConfigFlags(false),
+	}
+}
+
+// New provides a cobra command wrapping options for "import" sub command
+func New(streams genericclioptions.IOStreams) *cobra.Command {
+	o := newOptions(streams)
+	cmd := &cobra.Command{
+		Use:          "import [monitor ID]...",
+		Short:        "Write the DatadogMonitors equivalent to existing Datadog monitors",
+		Example:      fmt.Sprintf(importExample, "kubectl datadog monitor"),
+		SilenceUsage: true,
+		RunE: func(c *cobra.Command, args []string) error {
+			if err := o.complete(args); err != nil {
+				return err
+			}
+			if err := o.validate(); err != nil {
+				return err
+			}
+			return o.run()
+		},
+	}
+
+	cmd.Flags().StringVarP(&o.apiKey, "api-key", "", os.Getenv(config.DDAPIKeyEnvVar), "The Datadog API key, defaults to the DD_API_KEY environment variable")
+	cmd.Flags().StringVarP(&o.appKey, "app-key", "", os.Getenv(config.DDAppKeyEnvVar), "The Datadog application key, defaults to the DD_APP_KEY environment variable")
+	cmd.Flags().StringVarP(&o.site, "site", "", os.Getenv("DD_SITE"), "The Datadog site, e.g. datadoghq.eu, defaults to the DD_SITE environment variable")
+	cmd.Flags().BoolVarP(&o.adopt, "adopt", "", true, "Annotate the DatadogMonitors to adopt the existing monitors instead of creating new ones")
+	o.configFlags.AddFlags(cmd.Flags())
+
+	return cmd
+}
+
+// complete sets all information required for processing the command
+func (o *options) complete(args []string) error {
+	for _, arg := range args {
+		id, err := strconv.ParseInt(arg, 10, 64)
+		if err != nil || id <= 0 {
+			return fmt.Errorf("invalid monitor ID %q", arg)
+		}
+		o.monitorIDs = append(o.monitorIDs, id)
+	}
+	return nil
+}
+
+// validate ensures that all required arguments and flag values are provided
+func (o *options) validate() error {
+	if len(o.monitorIDs) == 0 {
+		return errors.New("at least one monitor ID must be provided")
+	}
+	if o.apiKey == "" || o.appKey == "" {
+		return errors.New("the Datadog API and application keys must be provided with --api-key and --app-key, or the DD_API_KEY and DD_APP_KEY environment variables")
+	}
+	return nil
+}
+
+// run runs the import command
+func (o *options) run() error {
+	ddClient, err := datadogclient.InitDatadogClientForSite(config.Creds{APIKey: o.apiKey, AppKey: o.appKey}, o.site)
+	if err != nil {
+		return err
+	}
+
+	namespace := ""
+	if o.configFlags.Namespace != nil {
+		namespace = *o.configFlags.Namespace
+	}
+
+	names := map[string]bool{}
+	for _, id := range o.monitorIDs {
+		m, _, err := ddClient.Client.MonitorsApi.GetMonitor(ddClient.Auth, id)
+		if err != nil {
+			return datadogclient.TranslateClientError(err, fmt.Sprintf("unable to get monitor %d", id))
+		}
+
+		dm := buildDatadogMonitor(m, namespace, o.adopt)
+		// Monitors may have the same name
+		if names[dm.Name] {
+			suffix := fmt.Sprintf("-%d", id)
+			dm.Name = truncateName(dm.Name, validation.DNS1123LabelMaxLength-len(suffix)) + suffix
+		}
+		names[dm.Name] = true
+
+		out, err := yaml.Marshal(dm)
+		if err != nil {
+			return fmt.Errorf("unable to marshal the DatadogMonitor of monitor %d: %w", id, err)
+		}
+		fmt.Fprintf(o.Out, "---\n%s", out)
+	}
+	return nil
+}
+
+// buildDatadogMonitor builds the DatadogMonitor equivalent to a Datadog monitor
+func buildDatadogMonitor(m datadogapiclientv1.Monitor, namespace string, adopt bool) *v1alpha1.DatadogMonitor {
+	dm := &v1alpha1.DatadogMonitor{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: v1alpha1.GroupVersion.String(),
+			Kind:       "DatadogMonitor",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      monitorObjectName(m),
+			Namespace: namespace,
+		},
+		Spec: v1alpha1.DatadogMonitorSpec{
+			Name:     m.GetName(),
+			Message:  m.GetMessage(),
+			Priority: m.GetPriority(),
+			Query:    m.GetQuery(),
+			Tags:     m.GetTags(),
+			Type:     v1alpha1.DatadogMonitorType(m.GetType()),
+			Options:  buildOptions(m.GetOptions()),
+		},
+	}
+	if adopt {
+		dm.Annotations = map[string]string{v1alpha1.DatadogMonitorAdoptAnnotationKey: strconv.FormatInt(m.GetId(), 10)}
+	}
+
+	return dm
+}
+
+func buildOptions(o datadogapiclientv1.MonitorOptions) v1alpha1.DatadogMonitorOptions {
+	options := v1alpha1.DatadogMonitorOptions{
+		EscalationMessage: o.EscalationMessage,
+		IncludeTags:       o.IncludeTags,
+		Locked:            o.Locked,
+		NotifyAudit:       o.NotifyAudit,
+		NotifyNoData:      o.NotifyNoData,
+		RequireFullWindow: o.RequireFullWindow,
+		EvaluationDelay:   o.EvaluationDelay.Get(),
+		NewGroupDelay:     o.NewGroupDelay.Get(),
+		NoDataTimeframe:   o.NoDataTimeframe.Get(),
+		RenotifyInterval:  o.RenotifyInterval.Get(),
+		TimeoutH:          o.TimeoutH.Get(),
+	}
+
+	if t, ok := o.GetThresholdsOk(); ok {
+		options.Thresholds = &v1alpha1.DatadogMonitorOptionsThresholds{
+			Critical:         formatThreshold(t.Critical),
+			CriticalRecovery: formatThreshold(t.CriticalRecovery.Get()),
+			OK:               formatThreshold(t.Ok.Get()),
+			Unknown:          formatThreshold(t.Unknown.Get()),
+			Warning:          formatThreshold(t.Warning.Get()),
+			WarningRecovery:  formatThreshold(t.WarningRecovery.Get()),
+		}
+	}
+
+	if w, ok := o.GetThresholdWindowsOk(); ok && (w.RecoveryWindow.Get() != nil || w.TriggerWindow.Get() != nil) {
+		options.ThresholdWindows = &v1alpha1.DatadogMonitorOptionsThresholdWindows{
+			RecoveryWindow: w.RecoveryWindow.Get(),
+			TriggerWindow:  w.TriggerWindow.Get(),
+		}
+	}
+
+	return options
+}
+
+func formatThreshold(value *float64) *string {
+	if value == nil {
+		return nil
+	}
+	s := strconv.FormatFloat(*value, 'f', -1, 64)
+	return &s
+}
+
+// monitorObjectName returns a valid DatadogMonitor name based on the monitor name
+func monitorObjectName(m datadogapiclientv1.Monitor) string {
+	name := invalidNameCharsRegexp.ReplaceAllString(strings.ToLower(m.GetName()), "-")
+	name = truncateName(strings.Trim(name, "-"), validation.DNS1123LabelMaxLength)
+	if name == "" {
+		return fmt.Sprintf("monitor-%d", m.GetId())
+	}
+	return name
+}
+
+// truncateName truncates a DatadogMonitor name to length, without trailing dash
+func truncateName(name string, length int) string {
+	if len(name) > length {
+		name = name[:length]
+	}
+	return strings.TrimRight(name, "-")
+}
@@ -0,0 +1,72 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package importer
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	datadogapiclientv1 "github.com/DataDog/datadog-api-client-go/api/v1/datadog"
+	"github.com/DataDog/datadog-operator/apis/datadoghq/v1alpha1"
+)
+
+func Test_buildDatadogMonitor(t *testing.T) {
+	m := datadogapiclientv1.NewMonitor("avg(last_5m):avg:system.load.1{*} > 2", datadogapiclientv1.MONITORTYPE_METRIC_ALERT)
+	m.SetId(1234)
+	m.SetName("High load on [prod] hosts!")
+	m.SetMessage("The load is high @team")
+	m.SetPriority(2)
+	m.SetTags([]string{"env:prod"})
+	options := datadogapiclientv1.NewMonitorOptions()
+	options.SetNotifyNoData(true)
+	options.SetRenotifyInterval(60)
+	thresholds := datadogapiclientv1.NewMonitorThresholds()
+	thresholds.SetCritical(2)
+	thresholds.SetWarning(1.5)
+	options.SetThresholds(*thresholds)
+	m.SetOptions(*options)
+
+	dm := buildDatadogMonitor(*m, "monitoring", true)
+
+	assert.Equal(t, "DatadogMonitor", dm.Kind)
+	assert.Equal(t, "datadoghq.com/v1alpha1", dm.APIVersion)
+	assert.Equal(t, "high-load-on-prod-hosts", dm.Name)
+	assert.Equal(t, "monitoring", dm.Namespace)
+	assert.Equal(t, map[string]string{v1alpha1.DatadogMonitorAdoptAnnotationKey: "1234"}, dm.Annotations)
+	assert.Equal(t, "High load on [prod] hosts!", dm.Spec.Name)
+	assert.Equal(t, "The load is high @team", dm.Spec.Message)
+	assert.Equal(t, int64(2), dm.Spec.Priority)
+	assert.Equal(t, "avg(last_5m):avg:system.load.1{*} > 2", dm.Spec.Query)
+	assert.Equal(t, []string{"env:prod"}, dm.Spec.Tags)
+	assert.Equal(t, v1alpha1.DatadogMonitorTypeMetric, dm.Spec.Type)
+	assert.True(t, *dm.Spec.Options.NotifyNoData)
+	assert.Equal(t, int64(60), *dm.Spec.Options.RenotifyInterval)
+	assert.Nil(t, dm.Spec.Options.EvaluationDelay)
+	assert.Equal(t, "2", *dm.Spec.Options.Thresholds.Critical)
+	assert.Equal(t, "1.5", *dm.Spec.Options.Thresholds.Warning)
+	assert.Nil(t, dm.Spec.Options.Thresholds.OK)
+	assert.Nil(t, dm.Spec.Options.ThresholdWindows)
+
+	dm = buildDatadogMonitor(*m, "", false)
+	assert.Empty(t, dm.Annotations)
+}
+
+func Test_monitorObjectName(t *testing.T) {
+	m := datadogapiclientv1.NewMonitor("", datadogapiclientv1.MONITORTYPE_METRIC_ALERT)
+	m.SetId(1234)
+
+	m.SetName("--CPU usage is high--")
+	assert.Equal(t, "cpu-usage-is-high", monitorObjectName(*m))
+
+	m.SetName("!!!")
+	assert.Equal(t, "monitor-1234", monitorObjectName(*m))
+
+	m.SetName("a very long monitor name that exceeds the maximum length of kubernetes object names")
+	name := monitorObjectName(*m)
+	assert.Equal(t, "a-very-long-monitor-name-that-exceeds-the-maximum-length-of-kub", name)
+	assert.LessOrEqual(t, len(name), 63)
+}
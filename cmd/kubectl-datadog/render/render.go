@@ -0,0 +1,219 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package render
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"strings"
+
+	"github.com/DataDog/datadog-operator/apis/datadoghq/v1alpha1"
+	"github.com/DataDog/datadog-operator/apis/datadoghq/v2alpha1"
+	"github.com/DataDog/datadog-operator/controllers/datadogagent"
+
+	"github.com/spf13/cobra"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	utilyaml "k8s.io/apimachinery/pkg/util/yaml"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"sigs.k8s.io/yaml"
+)
+
+var renderExample = `
+  # render the objects the operator would create for the DatadogAgent defined in dda.yaml
+  %[1]s render -f dda.yaml
+
+  # render the objects using an ExtendedDaemonSet for the node agent, reading the DatadogAgent from stdin
+  cat dda.yaml | %[1]s render -f - --support-extendeddaemonset
+
+  # render the objects for the DatadogAgent with its DatadogAgentTemplate and the DatadogAgentFeatures selecting it,
+  # all defined in manifests.yaml
+  %[1]s render -f manifests.yaml
+`
+
+// options provides information required by Datadog render command
+type options struct {
+	genericclioptions.IOStreams
+	filename                 string
+	supportExtendedDaemonset bool
+	supportCilium            bool
+}
+
+// newOptions provides an instance of options with default values
+func newOptions(streams genericclioptions.IOStreams) *options {
+	return &options{
+		IOStreams: streams,
+	}
+}
+
+// New provides a cobra command wrapping options for "render" sub command
+func New(streams genericclioptions.IOStreams) *cobra.Command {
+	o := newOptions(streams)
+	cmd := &cobra.Command{
+		Use:          "render -f [DatadogAgent manifests]",
+		Short:        "Render the objects the operator creates for a DatadogAgent, without a Kubernetes cluster",
+		Example:      fmt.Sprintf(renderExample, "kubectl datadog"),
+		SilenceUsage: true,
+		RunE: func(c *cobra.Command, args []string) error {
+			if err := o.validate(); err != nil {
+				return err
+			}
+			return o.run()
+		},
+	}
+
+	cmd.Flags().StringVarP(&o.filename, "filename", "f", "", "The manifests of the DatadogAgent to render, of its DatadogAgentTemplate and of the DatadogAgentFeatures, use - to read from stdin")
+	cmd.Flags().BoolVarP(&o.supportExtendedDaemonset, "support-extendeddaemonset", "", false, "Render the node agent as an ExtendedDaemonSet")
+	cmd.Flags().BoolVarP(&o.supportCilium, "support-cilium", "", false, "Render the Cilium network policies")
+
+	return cmd
+}
+
+// validate ensures that all required arguments and flag values are provided
+func (o *options) validate() error {
+	if o.filename == "" {
+		return errors.New("a DatadogAgent manifest must be provided with --filename")
+	}
+	return nil
+}
+
+// run runs the render command
+func (o *options) run() error {
+	data, err := o.readManifest()
+	if err != nil {
+		return err
+	}
+
+	dda, renderOptions, err := decodeManifests(data)
+	if err != nil {
+		return err
+	}
+	renderOptions.SupportExtendedDaemonset = o.supportExtendedDaemonset
+	renderOptions.SupportCilium = o.supportCilium
+
+	objs, err := datadogagent.Render(dda, renderOptions)
+	if err != nil {
+		return fmt.Errorf("unable to render DatadogAgent %s/%s: %w", dda.Namespace, dda.Name, err)
+	}
+
+	for _, obj := range objs {
+		out, err := yaml.Marshal(obj)
+		if err != nil {
+			return fmt.Errorf("unable to marshal %s %s/%s: %w", obj.GetObjectKind().GroupVersionKind().Kind, obj.GetNamespace(), obj.GetName(), err)
+		}
+		fmt.Fprintf(o.Out, "---\n%s", out)
+	}
+	return nil
+}
+
+func (o *options) readManifest() ([]byte, error) {
+	var reader io.Reader = o.In
+	if o.filename != "-" {
+		file, err := os.Open(o.filename)
+		if err != nil {
+			return nil, fmt.Errorf("unable to open %s: %w", o.filename, err)
+		}
+		defer file.Close()
+		reader = file
+	}
+	return ioutil.ReadAll(reader)
+}
+
+// decodeManifests decodes a multi-document manifest containing a single DatadogAgent, and the DatadogAgentTemplates
+// and DatadogAgentFeatures to apply to it. The DatadogAgentTemplates and DatadogAgentFeatures are returned in the
+// render options.
+func decodeManifests(data []byte) (*v2alpha1.DatadogAgent, *datadogagent.RenderOptions, error) {
+	var dda *v2alpha1.DatadogAgent
+	renderOptions := &datadogagent.RenderOptions{}
+
+	reader := utilyaml.NewYAMLReader(bufio.NewReader(bytes.NewReader(data)))
+	for {
+		doc, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, nil, fmt.Errorf("unable to read the manifests: %w", err)
+		}
+		if len(strings.TrimSpace(string(doc))) == 0 {
+			continue
+		}
+
+		typeMeta := metav1.TypeMeta{}
+		if err = yaml.Unmarshal(doc, &typeMeta); err != nil {
+			return nil, nil, fmt.Errorf("unable to decode the manifest: %w", err)
+		}
+		switch typeMeta.Kind {
+		case "DatadogAgent":
+			if dda != nil {
+				return nil, nil, errors.New("the manifests must contain a single DatadogAgent")
+			}
+			if dda, err = decodeDatadogAgent(doc, typeMeta); err != nil {
+				return nil, nil, err
+			}
+		case "DatadogAgentFeature":
+			ddaf := v2alpha1.DatadogAgentFeature{}
+			if err = decodeV2alpha1(doc, typeMeta, &ddaf); err != nil {
+				return nil, nil, err
+			}
+			renderOptions.DatadogAgentFeatures = append(renderOptions.DatadogAgentFeatures, ddaf)
+		case "DatadogAgentTemplate":
+			ddat := v2alpha1.DatadogAgentTemplate{}
+			if err = decodeV2alpha1(doc, typeMeta, &ddat); err != nil {
+				return nil, nil, err
+			}
+			renderOptions.DatadogAgentTemplates = append(renderOptions.DatadogAgentTemplates, ddat)
+		default:
+			return nil, nil, fmt.Errorf("unsupported kind %q, the manifests must contain a single DatadogAgent, DatadogAgentTemplates and DatadogAgentFeatures", typeMeta.Kind)
+		}
+	}
+	if dda == nil {
+		return nil, nil, errors.New("the manifests must contain a single DatadogAgent")
+	}
+
+	return dda, renderOptions, nil
+}
+
+// decodeV2alpha1 decodes the manifest of a v2alpha1 object
+func decodeV2alpha1(data []byte, typeMeta metav1.TypeMeta, obj interface{}) error {
+	if typeMeta.APIVersion != v2alpha1.GroupVersion.String() {
+		return fmt.Errorf("unsupported apiVersion %q for a %s", typeMeta.APIVersion, typeMeta.Kind)
+	}
+	if err := yaml.Unmarshal(data, obj); err != nil {
+		return fmt.Errorf("unable to decode the %s: %w", typeMeta.Kind, err)
+	}
+
+	return nil
+}
+
+// decodeDatadogAgent decodes a DatadogAgent manifest, v1alpha1 manifests are converted to v2alpha1.
+func decodeDatadogAgent(data []byte, typeMeta metav1.TypeMeta) (*v2alpha1.DatadogAgent, error) {
+
+	switch typeMeta.APIVersion {
+	case v2alpha1.GroupVersion.String():
+		dda := &v2alpha1.DatadogAgent{}
+		if err := yaml.Unmarshal(data, dda); err != nil {
+			return nil, fmt.Errorf("unable to decode the DatadogAgent: %w", err)
+		}
+		return dda, nil
+	case v1alpha1.GroupVersion.String():
+		ddaV1 := &v1alpha1.DatadogAgent{}
+		if err := yaml.Unmarshal(data, ddaV1); err != nil {
+			return nil, fmt.Errorf("unable to decode the DatadogAgent: %w", err)
+		}
+		dda := &v2alpha1.DatadogAgent{}
+		if err := v1alpha1.ConvertTo(ddaV1, dda); err != nil {
+			return nil, fmt.Errorf("unable to convert the DatadogAgent to v2alpha1: %w", err)
+		}
+		return dda, nil
+	default:
+		return nil, fmt.Errorf("unsupported apiVersion %q", typeMeta.APIVersion)
+	}
+}
@@ -0,0 +1,150 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package render
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+)
+
+const datadogAgentManifest = `apiVersion: datadoghq.com/v2alpha1
+kind: DatadogAgent
+metadata:
+  name: datadog
+  namespace: bar
+  labels:
+    team: platform
+spec:
+  global:
+    credentials:
+      apiKey: "0000000000000000000000"
+      appKey: "0000000000000000000000000000000000000000"
+`
+
+const datadogAgentFeatureManifest = `apiVersion: datadoghq.com/v2alpha1
+kind: DatadogAgentFeature
+metadata:
+  name: platform
+spec:
+  selector:
+    matchLabels:
+      team: platform
+  override:
+    nodeAgent:
+      sidecars:
+      - name: log-shipper
+        image: busybox:1.35
+`
+
+const datadogAgentTemplateManifest = `apiVersion: datadoghq.com/v2alpha1
+kind: DatadogAgentTemplate
+metadata:
+  name: base
+spec:
+  global:
+    credentials:
+      apiKey: "0000000000000000000000"
+      appKey: "0000000000000000000000000000000000000000"
+    registry: registry.example.com
+`
+
+const datadogAgentWithBaseRefManifest = `apiVersion: datadoghq.com/v2alpha1
+kind: DatadogAgent
+metadata:
+  name: datadog
+  namespace: bar
+spec:
+  baseRef:
+    name: base
+  global:
+    clusterName: cluster-1
+`
+
+func Test_decodeManifests(t *testing.T) {
+	tests := []struct {
+		name          string
+		manifests     string
+		wantFeatures  []string
+		wantTemplates []string
+		wantErr       string
+	}{
+		{
+			name:      "DatadogAgent",
+			manifests: datadogAgentManifest,
+		},
+		{
+			name:         "DatadogAgent and DatadogAgentFeature",
+			manifests:    datadogAgentManifest + "---\n" + datadogAgentFeatureManifest,
+			wantFeatures: []string{"platform"},
+		},
+		{
+			name:          "DatadogAgent and DatadogAgentTemplate",
+			manifests:     datadogAgentTemplateManifest + "---\n" + datadogAgentWithBaseRefManifest,
+			wantTemplates: []string{"base"},
+		},
+		{
+			name:      "no DatadogAgent",
+			manifests: datadogAgentFeatureManifest,
+			wantErr:   "the manifests must contain a single DatadogAgent",
+		},
+		{
+			name:      "two DatadogAgents",
+			manifests: datadogAgentManifest + "---\n" + datadogAgentManifest,
+			wantErr:   "the manifests must contain a single DatadogAgent",
+		},
+		{
+			name:      "unsupported kind",
+			manifests: datadogAgentManifest + "---\napiVersion: v1\nkind: ConfigMap\n",
+			wantErr:   `unsupported kind "ConfigMap"`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dda, renderOptions, err := decodeManifests([]byte(tt.manifests))
+			if tt.wantErr != "" {
+				if assert.Error(t, err) {
+					assert.Contains(t, err.Error(), tt.wantErr)
+				}
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, "datadog", dda.Name)
+			var features []string
+			for _, ddaf := range renderOptions.DatadogAgentFeatures {
+				features = append(features, ddaf.Name)
+			}
+			assert.Equal(t, tt.wantFeatures, features)
+			var templates []string
+			for _, ddat := range renderOptions.DatadogAgentTemplates {
+				templates = append(templates, ddat.Name)
+			}
+			assert.Equal(t, tt.wantTemplates, templates)
+		})
+	}
+}
+
+func Test_options_run(t *testing.T) {
+	streams, in, out, _ := genericclioptions.NewTestIOStreams()
+	in.WriteString(datadogAgentManifest + "---\n" + datadogAgentFeatureManifest)
+	o := newOptions(streams)
+	o.filename = "-"
+
+	require.NoError(t, o.run())
+	assert.Contains(t, out.String(), "name: log-shipper", "the sidecar of the DatadogAgentFeature is rendered")
+}
+
+func Test_options_run_template(t *testing.T) {
+	streams, in, out, _ := genericclioptions.NewTestIOStreams()
+	in.WriteString(datadogAgentWithBaseRefManifest + "---\n" + datadogAgentTemplateManifest)
+	o := newOptions(streams)
+	o.filename = "-"
+
+	require.NoError(t, o.run())
+	assert.Contains(t, out.String(), "image: registry.example.com/agent:", "the registry of the DatadogAgentTemplate is used")
+}
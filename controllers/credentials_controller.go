@@ -0,0 +1,99 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package controllers
+
+import (
+	"context"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+
+	"github.com/DataDog/datadog-operator/pkg/config"
+)
+
+const (
+	// CredentialsRotatedReason is the reason of the event recorded when the operator credentials are rotated
+	CredentialsRotatedReason = "CredentialsRotated"
+	// CredentialsRotationErrorReason is the reason of the event recorded when the new operator credentials can't be read
+	CredentialsRotationErrorReason = "CredentialsRotationError"
+)
+
+// CredentialsReconciler refreshes the operator credentials when the Secret containing them changes.
+type CredentialsReconciler struct {
+	// Client reads the credentials Secret, SetupWithManager sets it to a cache containing only this Secret
+	Client            client.Reader
+	CredentialManager *config.CredentialManager
+	Secret            types.NamespacedName
+	Log               logr.Logger
+	Recorder          record.EventRecorder
+}
+
+// Reconcile loop for the operator credentials Secret.
+func (r *CredentialsReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	logger := r.Log.WithValues("secret", req.NamespacedName)
+
+	secret := &corev1.Secret{}
+	if err := r.Client.Get(ctx, req.NamespacedName, secret); err != nil {
+		if apierrors.IsNotFound(err) {
+			// Keep using the current credentials until the Secret is recreated
+			logger.Info("Credentials Secret not found, keeping the current credentials")
+			return ctrl.Result{}, nil
+		}
+		return ctrl.Result{}, err
+	}
+
+	changed, err := r.CredentialManager.Refresh()
+	if err != nil {
+		logger.Error(err, "Unable to refresh the credentials, keeping the current credentials")
+		r.Recorder.Event(secret, corev1.EventTypeWarning, CredentialsRotationErrorReason, err.Error())
+		return ctrl.Result{}, err
+	}
+
+	if changed {
+		logger.Info("Credentials rotated")
+		r.Recorder.Event(secret, corev1.EventTypeNormal, CredentialsRotatedReason, "Datadog API and APP keys rotated")
+	}
+
+	return ctrl.Result{}, nil
+}
+
+// SetupWithManager creates a new controller watching the operator credentials Secret.
+// The Secret is watched through a dedicated cache, restricted to its namespace and name, so that the operator
+// doesn't cache all the Secrets of the cluster.
+func (r *CredentialsReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	secretCache, err := cache.New(mgr.GetConfig(), cache.Options{
+		Scheme:    mgr.GetScheme(),
+		Mapper:    mgr.GetRESTMapper(),
+		Namespace: r.Secret.Namespace,
+		SelectorsByObject: cache.SelectorsByObject{
+			&corev1.Secret{}: {Field: fields.OneTermEqualSelector("metadata.name", r.Secret.Name)},
+		},
+	})
+	if err != nil {
+		return err
+	}
+	if err = mgr.Add(secretCache); err != nil {
+		return err
+	}
+	r.Client = secretCache
+
+	c, err := controller.New("credentials", mgr, controller.Options{Reconciler: r})
+	if err != nil {
+		return err
+	}
+
+	return c.Watch(source.NewKindWithCache(&corev1.Secret{}, secretCache), &handler.EnqueueRequestForObject{})
+}
@@ -0,0 +1,81 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package controllers
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	"github.com/DataDog/datadog-operator/pkg/config"
+)
+
+func TestCredentialsReconciler_Reconcile(t *testing.T) {
+	secretNsName := types.NamespacedName{Namespace: "datadog", Name: "operator-creds"}
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Namespace: secretNsName.Namespace, Name: secretNsName.Name},
+		Data: map[string][]byte{
+			"api_key": []byte("foo"),
+			"app_key": []byte("bar"),
+		},
+	}
+	k8sClient := fake.NewClientBuilder().WithObjects(secret).Build()
+	credsManager := config.NewCredentialManagerFromSecret(k8sClient, secretNsName)
+	_, err := credsManager.GetCredentials()
+	assert.NoError(t, err)
+
+	var rotated []config.Creds
+	credsManager.RegisterCallback(func(creds config.Creds) { rotated = append(rotated, creds) })
+
+	recorder := record.NewFakeRecorder(10)
+	r := &CredentialsReconciler{
+		Client:            k8sClient,
+		CredentialManager: credsManager,
+		Secret:            secretNsName,
+		Log:               logf.Log.WithName(t.Name()),
+		Recorder:          recorder,
+	}
+	req := ctrl.Request{NamespacedName: secretNsName}
+
+	// Unchanged credentials
+	_, err = r.Reconcile(context.TODO(), req)
+	assert.NoError(t, err)
+	assert.Empty(t, rotated)
+	assert.Empty(t, recorder.Events)
+
+	// Rotated credentials
+	secret.Data["app_key"] = []byte("new-bar")
+	assert.NoError(t, k8sClient.Update(context.TODO(), secret))
+	_, err = r.Reconcile(context.TODO(), req)
+	assert.NoError(t, err)
+	assert.Equal(t, []config.Creds{{APIKey: "foo", AppKey: "new-bar"}}, rotated)
+	assert.Equal(t, "Normal CredentialsRotated Datadog API and APP keys rotated", <-recorder.Events)
+
+	// Invalid credentials
+	secret.Data["app_key"] = []byte{}
+	assert.NoError(t, k8sClient.Update(context.TODO(), secret))
+	_, err = r.Reconcile(context.TODO(), req)
+	assert.Error(t, err)
+	assert.Len(t, rotated, 1)
+	assert.Contains(t, <-recorder.Events, "Warning CredentialsRotationError")
+
+	// Deleted Secret
+	assert.NoError(t, k8sClient.Delete(context.TODO(), secret))
+	_, err = r.Reconcile(context.TODO(), req)
+	assert.NoError(t, err)
+	assert.Len(t, rotated, 1)
+	creds, err := credsManager.GetCredentials()
+	assert.NoError(t, err)
+	assert.Equal(t, config.Creds{APIKey: "foo", AppKey: "new-bar"}, creds)
+}
@@ -0,0 +1,100 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package agent
+
+import (
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/DataDog/datadog-operator/apis/datadoghq/v2alpha1"
+)
+
+// ProfileNodeSelectorTerms returns the node selector terms matching the nodes that run the node Agent of the given profile:
+// the nodes matching its node selector, but not the node selector of any of the previous profiles.
+// If profile is nil, it returns the terms matching the nodes that run the default node Agent: the nodes that don't match
+// any profile. It returns nil if all the nodes are matching.
+func ProfileNodeSelectorTerms(profiles []v2alpha1.DatadogAgentProfile, profile *v2alpha1.DatadogAgentProfile) []corev1.NodeSelectorTerm {
+	var terms []corev1.NodeSelectorTerm
+	for i := range profiles {
+		if profile != nil && profiles[i].Name == profile.Name {
+			return IntersectNodeSelectorTerms(terms, []corev1.NodeSelectorTerm{{MatchExpressions: profile.NodeSelector}})
+		}
+		terms = IntersectNodeSelectorTerms(terms, negateNodeSelectorRequirements(profiles[i].NodeSelector))
+	}
+	return terms
+}
+
+// ApplyProfileNodeAffinity restricts the pods of the PodTemplateSpec to the nodes matching the node selector terms.
+// Terms already present in the required node affinity are kept: a node must match both.
+func ApplyProfileNodeAffinity(podTemplate *corev1.PodTemplateSpec, terms []corev1.NodeSelectorTerm) {
+	if len(terms) == 0 {
+		return
+	}
+
+	// The affinity can be shared with the DatadogAgent override: copy it before updating it.
+	affinity := podTemplate.Spec.Affinity.DeepCopy()
+	if affinity == nil {
+		affinity = &corev1.Affinity{}
+	}
+	if affinity.NodeAffinity == nil {
+		affinity.NodeAffinity = &corev1.NodeAffinity{}
+	}
+	if affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution == nil {
+		affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution = &corev1.NodeSelector{}
+	}
+	nodeSelector := affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution
+	nodeSelector.NodeSelectorTerms = IntersectNodeSelectorTerms(nodeSelector.NodeSelectorTerms, terms)
+
+	podTemplate.Spec.Affinity = affinity
+}
+
+// IntersectNodeSelectorTerms returns the node selector terms matching the nodes matched by both a and b.
+// Terms are ORed, and the requirements of a term are ANDed, so the result is the cartesian product of a and b.
+// An empty list of terms matches all the nodes.
+func IntersectNodeSelectorTerms(a, b []corev1.NodeSelectorTerm) []corev1.NodeSelectorTerm {
+	if len(a) == 0 {
+		return b
+	}
+	if len(b) == 0 {
+		return a
+	}
+
+	terms := make([]corev1.NodeSelectorTerm, 0, len(a)*len(b))
+	for _, termA := range a {
+		for _, termB := range b {
+			term := corev1.NodeSelectorTerm{}
+			term.MatchExpressions = append(term.MatchExpressions, termA.MatchExpressions...)
+			term.MatchExpressions = append(term.MatchExpressions, termB.MatchExpressions...)
+			term.MatchFields = append(term.MatchFields, termA.MatchFields...)
+			term.MatchFields = append(term.MatchFields, termB.MatchFields...)
+			terms = append(terms, term)
+		}
+	}
+	return terms
+}
+
+// negateNodeSelectorRequirements returns the node selector terms matching the nodes that don't match all the requirements:
+// one term per negated requirement.
+func negateNodeSelectorRequirements(reqs []corev1.NodeSelectorRequirement) []corev1.NodeSelectorTerm {
+	terms := make([]corev1.NodeSelectorTerm, 0, len(reqs))
+	for _, req := range reqs {
+		negated := req
+		switch req.Operator {
+		case corev1.NodeSelectorOpIn:
+			negated.Operator = corev1.NodeSelectorOpNotIn
+		case corev1.NodeSelectorOpNotIn:
+			negated.Operator = corev1.NodeSelectorOpIn
+		case corev1.NodeSelectorOpExists:
+			negated.Operator = corev1.NodeSelectorOpDoesNotExist
+		case corev1.NodeSelectorOpDoesNotExist:
+			negated.Operator = corev1.NodeSelectorOpExists
+		default:
+			// Not supported in profiles, rejected by the DatadogAgent validation.
+			continue
+		}
+		terms = append(terms, corev1.NodeSelectorTerm{MatchExpressions: []corev1.NodeSelectorRequirement{negated}})
+	}
+	return terms
+}
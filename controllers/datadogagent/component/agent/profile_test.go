@@ -0,0 +1,103 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package agent
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/DataDog/datadog-operator/apis/datadoghq/v2alpha1"
+)
+
+func TestProfileNodeSelectorTerms(t *testing.T) {
+	gpu := corev1.NodeSelectorRequirement{Key: "pool", Operator: corev1.NodeSelectorOpIn, Values: []string{"gpu"}}
+	notGPU := corev1.NodeSelectorRequirement{Key: "pool", Operator: corev1.NodeSelectorOpNotIn, Values: []string{"gpu"}}
+	spot := corev1.NodeSelectorRequirement{Key: "spot", Operator: corev1.NodeSelectorOpExists}
+	notSpot := corev1.NodeSelectorRequirement{Key: "spot", Operator: corev1.NodeSelectorOpDoesNotExist}
+	linux := corev1.NodeSelectorRequirement{Key: "os", Operator: corev1.NodeSelectorOpNotIn, Values: []string{"windows"}}
+	notLinux := corev1.NodeSelectorRequirement{Key: "os", Operator: corev1.NodeSelectorOpIn, Values: []string{"windows"}}
+
+	profiles := []v2alpha1.DatadogAgentProfile{
+		{Name: "gpu", NodeSelector: []corev1.NodeSelectorRequirement{gpu}},
+		{Name: "linux-spot", NodeSelector: []corev1.NodeSelectorRequirement{spot, linux}},
+	}
+
+	tests := []struct {
+		name     string
+		profiles []v2alpha1.DatadogAgentProfile
+		profile  *v2alpha1.DatadogAgentProfile
+		want     []corev1.NodeSelectorTerm
+	}{
+		{
+			name: "no profiles",
+			want: nil,
+		},
+		{
+			name:     "default node agent",
+			profiles: profiles,
+			want: []corev1.NodeSelectorTerm{
+				{MatchExpressions: []corev1.NodeSelectorRequirement{notGPU, notSpot}},
+				{MatchExpressions: []corev1.NodeSelectorRequirement{notGPU, notLinux}},
+			},
+		},
+		{
+			name:     "first profile",
+			profiles: profiles,
+			profile:  &profiles[0],
+			want: []corev1.NodeSelectorTerm{
+				{MatchExpressions: []corev1.NodeSelectorRequirement{gpu}},
+			},
+		},
+		{
+			name:     "second profile excludes the nodes of the first one",
+			profiles: profiles,
+			profile:  &profiles[1],
+			want: []corev1.NodeSelectorTerm{
+				{MatchExpressions: []corev1.NodeSelectorRequirement{notGPU, spot, linux}},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, ProfileNodeSelectorTerms(tt.profiles, tt.profile))
+		})
+	}
+}
+
+func TestApplyProfileNodeAffinity(t *testing.T) {
+	gpu := corev1.NodeSelectorRequirement{Key: "pool", Operator: corev1.NodeSelectorOpIn, Values: []string{"gpu"}}
+	zoneA := corev1.NodeSelectorRequirement{Key: "zone", Operator: corev1.NodeSelectorOpIn, Values: []string{"a"}}
+	zoneB := corev1.NodeSelectorRequirement{Key: "zone", Operator: corev1.NodeSelectorOpIn, Values: []string{"b"}}
+
+	// Affinity set by the user, shared with the DatadogAgent override
+	userAffinity := &corev1.Affinity{
+		NodeAffinity: &corev1.NodeAffinity{
+			RequiredDuringSchedulingIgnoredDuringExecution: &corev1.NodeSelector{
+				NodeSelectorTerms: []corev1.NodeSelectorTerm{
+					{MatchExpressions: []corev1.NodeSelectorRequirement{zoneA}},
+					{MatchExpressions: []corev1.NodeSelectorRequirement{zoneB}},
+				},
+			},
+		},
+	}
+	userAffinityCopy := userAffinity.DeepCopy()
+
+	podTemplate := &corev1.PodTemplateSpec{Spec: corev1.PodSpec{Affinity: userAffinity}}
+	ApplyProfileNodeAffinity(podTemplate, []corev1.NodeSelectorTerm{{MatchExpressions: []corev1.NodeSelectorRequirement{gpu}}})
+
+	assert.Equal(t, []corev1.NodeSelectorTerm{
+		{MatchExpressions: []corev1.NodeSelectorRequirement{zoneA, gpu}},
+		{MatchExpressions: []corev1.NodeSelectorRequirement{zoneB, gpu}},
+	}, podTemplate.Spec.Affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution.NodeSelectorTerms)
+	assert.Equal(t, userAffinityCopy, userAffinity, "the user affinity must not be modified")
+
+	// No terms: the pod template is left untouched
+	podTemplate = &corev1.PodTemplateSpec{}
+	ApplyProfileNodeAffinity(podTemplate, nil)
+	assert.Nil(t, podTemplate.Spec.Affinity)
+}
@@ -1084,3 +1084,8 @@ func egressDCA(podSelector metav1.LabelSelector, nodeAgentPodSelector metav1.Lab
 		},
 	}
 }
+
+// GetSecretBackendRbacResourcesName returns the name of the RBAC resources granting the permission to read the secret backend Secrets
+func GetSecretBackendRbacResourcesName(dda metav1.Object) string {
+	return fmt.Sprintf("%s-secret-backend", dda.GetName())
+}
@@ -23,6 +23,7 @@ import (
 
 	datadoghqv1alpha1 "github.com/DataDog/datadog-operator/apis/datadoghq/v1alpha1"
 	"github.com/DataDog/datadog-operator/apis/datadoghq/v1alpha1/patch"
+	"github.com/DataDog/datadog-operator/pkg/config"
 	"github.com/DataDog/datadog-operator/pkg/controller/utils"
 	"github.com/DataDog/datadog-operator/pkg/controller/utils/condition"
 	"github.com/DataDog/datadog-operator/pkg/controller/utils/datadog"
@@ -61,10 +62,14 @@ const (
 
 // ReconcilerOptions provides options read from command line
 type ReconcilerOptions struct {
-	SupportExtendedDaemonset bool
-	SupportCilium            bool
-	OperatorMetricsEnabled   bool
-	V2Enabled                bool
+	SupportExtendedDaemonset    bool
+	SupportCilium               bool
+	OperatorMetricsEnabled      bool
+	V2Enabled                   bool
+	DependenciesDiffLogEnabled  bool
+	DatadogAgentFeatureEnabled  bool
+	DatadogAgentTemplateEnabled bool
+	CredentialManager           *config.CredentialManager
 }
 
 // Reconciler is the internal reconciler for Datadog Agent
@@ -175,6 +180,8 @@ func (r *Reconciler) reconcileInstance(ctx context.Context, logger logr.Logger,
 		Logger:        logger,
 		Scheme:        r.scheme,
 		PlatformInfo:  r.platformInfo,
+		EventRecorder: r.recorder,
+		VerboseDiff:   r.options.DependenciesDiffLogEnabled,
 	}
 	depsStore := dependencies.NewStore(instance, storeOptions)
 	resourcesManager := feature.NewResourceManagers(depsStore)
@@ -7,83 +7,195 @@ package datadogagent
 
 import (
 	"context"
+	"fmt"
 	"time"
 
+	apicommon "github.com/DataDog/datadog-operator/apis/datadoghq/common"
 	"github.com/DataDog/datadog-operator/apis/datadoghq/common/v1"
 	datadoghqv2alpha1 "github.com/DataDog/datadog-operator/apis/datadoghq/v2alpha1"
 	apiutils "github.com/DataDog/datadog-operator/apis/utils"
 	componentagent "github.com/DataDog/datadog-operator/controllers/datadogagent/component/agent"
 	"github.com/DataDog/datadog-operator/controllers/datadogagent/feature"
+	"github.com/DataDog/datadog-operator/controllers/datadogagent/object"
 	"github.com/DataDog/datadog-operator/controllers/datadogagent/override"
+	"github.com/DataDog/datadog-operator/pkg/controller/utils"
 	"github.com/DataDog/datadog-operator/pkg/controller/utils/datadog"
 
 	edsv1alpha1 "github.com/DataDog/extendeddaemonset/api/v1alpha1"
 	"github.com/go-logr/logr"
 	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	apiequality "k8s.io/apimachinery/pkg/api/equality"
 	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 )
 
 func (r *Reconciler) reconcileV2Agent(logger logr.Logger, requiredComponents feature.RequiredComponents, features []feature.Feature, dda *datadoghqv2alpha1.DatadogAgent, resourcesManager feature.ResourceManagers, newStatus *datadoghqv2alpha1.DatadogAgentStatus, requiredContainers []common.AgentContainerName) (reconcile.Result, error) {
 	var result reconcile.Result
-	var eds *edsv1alpha1.ExtendedDaemonSet
-	var daemonset *appsv1.DaemonSet
-	var podManagers feature.PodTemplateManagers
 
 	daemonsetLogger := logger.WithValues("component", datadoghqv2alpha1.NodeAgentComponentName)
 
 	// requiredComponents needs to be taken into account in case a feature(s) changes and
 	// a requiredComponent becomes disabled, in addition to taking into account override.Disabled
-	disabled := false
-
-	requiredEnabled := requiredComponents.Agent.IsEnabled()
+	disabled := !isV2AgentEnabled(dda, nil)
+	if disabled && requiredComponents.Agent.IsEnabled() {
+		// The override supersedes what's set in requiredComponents; update status to reflect the conflict
+		datadoghqv2alpha1.UpdateDatadogAgentStatusConditions(
+			newStatus,
+			metav1.NewTime(time.Now()),
+			datadoghqv2alpha1.OverrideReconcileConflictConditionType,
+			metav1.ConditionTrue,
+			"OverrideConflict",
+			"Agent component is set to disabled",
+			true,
+		)
+	}
 
 	if r.options.SupportExtendedDaemonset {
-		// Start by creating the Default Agent extendeddaemonset
-		eds = componentagent.NewDefaultAgentExtendedDaemonset(dda, requiredContainers)
-		podManagers = feature.NewPodTemplateManagers(&eds.Spec.Template)
+		eds, err := buildV2AgentExtendedDaemonSet(logger, features, dda, resourcesManager, requiredContainers, nil)
+		if err != nil {
+			return result, err
+		}
+		if disabled {
+			result, err = r.cleanupV2ExtendedDaemonSet(daemonsetLogger, dda, eds, newStatus)
+		} else {
+			result, err = r.createOrUpdateExtendedDaemonset(daemonsetLogger, dda, eds, newStatus, updateEDSStatusV2WithAgent)
+		}
+		if utils.ShouldReturn(result, err) {
+			return result, err
+		}
+	} else {
+		daemonset, err := buildV2AgentDaemonSet(logger, features, dda, resourcesManager, requiredContainers, nil)
+		if err != nil {
+			return result, err
+		}
+		if disabled {
+			result, err = r.cleanupV2DaemonSet(daemonsetLogger, dda, daemonset, newStatus)
+		} else {
+			result, err = r.migrateV2AgentSelector(daemonsetLogger, dda, daemonset)
+			if utils.ShouldReturn(result, err) {
+				return result, err
+			}
+			result, err = r.createOrUpdateDaemonset(daemonsetLogger, dda, daemonset, newStatus, updateDSStatusV2WithAgent)
+		}
+		if utils.ShouldReturn(result, err) {
+			return result, err
+		}
+	}
 
-		// Set Global setting on the default extendeddaemonset
-		eds.Spec.Template = *override.ApplyGlobalSettings(logger, podManagers, dda, resourcesManager, datadoghqv2alpha1.NodeAgentComponentName)
+	return r.reconcileV2AgentProfiles(logger, features, dda, resourcesManager, newStatus, requiredContainers)
+}
 
-		// Apply features changes on the Deployment.Spec.Template
-		for _, feat := range features {
-			if errFeat := feat.ManageNodeAgent(podManagers); errFeat != nil {
-				return result, errFeat
-			}
+// reconcileV2AgentProfiles creates or updates the node Agent of each profile, and deletes the node Agents of the
+// profiles that are disabled or no longer defined.
+func (r *Reconciler) reconcileV2AgentProfiles(logger logr.Logger, features []feature.Feature, dda *datadoghqv2alpha1.DatadogAgent, resourcesManager feature.ResourceManagers, newStatus *datadoghqv2alpha1.DatadogAgentStatus, requiredContainers []common.AgentContainerName) (reconcile.Result, error) {
+	var result reconcile.Result
+	var err error
+
+	profilesStatus := make([]datadoghqv2alpha1.DatadogAgentProfileStatus, 0, len(dda.Spec.Profiles))
+	enabledProfiles := make(map[string]bool, len(dda.Spec.Profiles))
+	for i := range dda.Spec.Profiles {
+		profile := &dda.Spec.Profiles[i]
+		if !isV2AgentEnabled(dda, profile) {
+			continue
 		}
+		enabledProfiles[profile.Name] = true
+		profileLogger := logger.WithValues("component", datadoghqv2alpha1.NodeAgentComponentName, "profile", profile.Name)
 
-		// If Override is defined for the node agent component, apply the override on the PodTemplateSpec, it will cascade to container.
-		if componentOverride, ok := dda.Spec.Override[datadoghqv2alpha1.NodeAgentComponentName]; ok {
-			if apiutils.BoolValue(componentOverride.Disabled) {
-				disabled = true
+		// The node Agent status of the profile is computed apart, so it doesn't override the default node Agent status.
+		profileStatus := &datadoghqv2alpha1.DatadogAgentStatus{Agent: getV2AgentProfileStatus(newStatus, profile.Name)}
+		if r.options.SupportExtendedDaemonset {
+			var eds *edsv1alpha1.ExtendedDaemonSet
+			if eds, err = buildV2AgentExtendedDaemonSet(logger, features, dda, resourcesManager, requiredContainers, profile); err != nil {
+				return result, err
 			}
-			override.PodTemplateSpec(logger, podManagers, componentOverride, datadoghqv2alpha1.NodeAgentComponentName, dda.Name)
-			override.ExtendedDaemonSet(eds, componentOverride)
-		}
-		if disabled {
-			if requiredEnabled {
-				// The override supersedes what's set in requiredComponents; update status to reflect the conflict
-				datadoghqv2alpha1.UpdateDatadogAgentStatusConditions(
-					newStatus,
-					metav1.NewTime(time.Now()),
-					datadoghqv2alpha1.OverrideReconcileConflictConditionType,
-					metav1.ConditionTrue,
-					"OverrideConflict",
-					"Agent component is set to disabled",
-					true,
-				)
+			result, err = r.createOrUpdateExtendedDaemonset(profileLogger, dda, eds, profileStatus, updateEDSStatusV2WithAgent)
+		} else {
+			var daemonset *appsv1.DaemonSet
+			if daemonset, err = buildV2AgentDaemonSet(logger, features, dda, resourcesManager, requiredContainers, profile); err != nil {
+				return result, err
 			}
-			return r.cleanupV2ExtendedDaemonSet(daemonsetLogger, dda, eds, newStatus)
+			result, err = r.createOrUpdateDaemonset(profileLogger, dda, daemonset, profileStatus, updateDSStatusV2WithAgent)
+		}
+		profilesStatus = append(profilesStatus, datadoghqv2alpha1.DatadogAgentProfileStatus{
+			Name:  profile.Name,
+			Agent: profileStatus.Agent,
+		})
+		if utils.ShouldReturn(result, err) {
+			newStatus.Profiles = profilesStatus
+			return result, err
 		}
-		return r.createOrUpdateExtendedDaemonset(daemonsetLogger, dda, eds, newStatus, updateEDSStatusV2WithAgent)
 	}
+	if len(profilesStatus) > 0 {
+		newStatus.Profiles = profilesStatus
+	} else {
+		newStatus.Profiles = nil
+	}
+
+	return r.cleanupV2AgentProfiles(logger, dda, enabledProfiles)
+}
 
+// cleanupV2AgentProfiles deletes the node Agents of the profiles that are not enabled.
+func (r *Reconciler) cleanupV2AgentProfiles(logger logr.Logger, dda *datadoghqv2alpha1.DatadogAgent, enabledProfiles map[string]bool) (reconcile.Result, error) {
+	selector, err := labels.Parse(fmt.Sprintf("%s=%s,%s=%s,%s",
+		apicommon.AgentDeploymentNameLabelKey, dda.Name,
+		apicommon.AgentDeploymentComponentLabelKey, apicommon.DefaultAgentResourceSuffix,
+		apicommon.AgentDeploymentProfileLabelKey,
+	))
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+	listOptions := &client.ListOptions{Namespace: dda.Namespace, LabelSelector: selector}
+
+	var objs []client.Object
+	if r.options.SupportExtendedDaemonset {
+		edsList := &edsv1alpha1.ExtendedDaemonSetList{}
+		if err = r.client.List(context.TODO(), edsList, listOptions); err != nil {
+			return reconcile.Result{}, err
+		}
+		for i := range edsList.Items {
+			objs = append(objs, &edsList.Items[i])
+		}
+	} else {
+		dsList := &appsv1.DaemonSetList{}
+		if err = r.client.List(context.TODO(), dsList, listOptions); err != nil {
+			return reconcile.Result{}, err
+		}
+		for i := range dsList.Items {
+			objs = append(objs, &dsList.Items[i])
+		}
+	}
+
+	for _, obj := range objs {
+		if enabledProfiles[obj.GetLabels()[apicommon.AgentDeploymentProfileLabelKey]] || !object.CheckOwnerReference(dda, obj) {
+			continue
+		}
+		if err = r.client.Delete(context.TODO(), obj); err != nil && !errors.IsNotFound(err) {
+			return reconcile.Result{}, err
+		}
+		kind := daemonSetKind
+		if r.options.SupportExtendedDaemonset {
+			kind = extendedDaemonSetKind
+		}
+		logger.Info("Delete profile node Agent", "kind", kind, "namespace", obj.GetNamespace(), "name", obj.GetName())
+		event := buildEventInfo(obj.GetName(), obj.GetNamespace(), kind, datadog.DeletionEvent)
+		r.recordEvent(dda, event)
+	}
+
+	return reconcile.Result{}, nil
+}
+
+// buildV2AgentDaemonSet builds the node Agent DaemonSet: default daemonset, global settings,
+// features configuration and, if defined, the component override.
+// If profile isn't nil, the DaemonSet is the node Agent of the profile.
+func buildV2AgentDaemonSet(logger logr.Logger, features []feature.Feature, dda *datadoghqv2alpha1.DatadogAgent, resourcesManager feature.ResourceManagers, requiredContainers []common.AgentContainerName, profile *datadoghqv2alpha1.DatadogAgentProfile) (*appsv1.DaemonSet, error) {
 	// Start by creating the Default Agent daemonset
-	daemonset = componentagent.NewDefaultAgentDaemonset(dda, requiredContainers)
-	podManagers = feature.NewPodTemplateManagers(&daemonset.Spec.Template)
+	daemonset := componentagent.NewDefaultAgentDaemonset(dda, requiredContainers)
+	podManagers := feature.NewPodTemplateManagers(&daemonset.Spec.Template)
 
 	// Set Global setting on the default daemonset
 	daemonset.Spec.Template = *override.ApplyGlobalSettings(logger, podManagers, dda, resourcesManager, datadoghqv2alpha1.NodeAgentComponentName)
@@ -91,34 +203,156 @@ func (r *Reconciler) reconcileV2Agent(logger logr.Logger, requiredComponents fea
 	// Apply features changes on the Deployment.Spec.Template
 	for _, feat := range features {
 		if errFeat := feat.ManageNodeAgent(podManagers); errFeat != nil {
-			return result, errFeat
+			return nil, newFeatureError(feat.ID(), featureManageNodeAgentErrorReason, errFeat)
 		}
 	}
 
 	// If Override is defined for the node agent component, apply the override on the PodTemplateSpec, it will cascade to container.
 	if componentOverride, ok := dda.Spec.Override[datadoghqv2alpha1.NodeAgentComponentName]; ok {
-		if apiutils.BoolValue(componentOverride.Disabled) {
-			disabled = true
-		}
 		override.PodTemplateSpec(logger, podManagers, componentOverride, datadoghqv2alpha1.NodeAgentComponentName, dda.Name)
 		override.DaemonSet(daemonset, componentOverride)
 	}
-	if disabled {
-		if requiredEnabled {
-			// The override supersedes what's set in requiredComponents; update status to reflect the conflict
-			datadoghqv2alpha1.UpdateDatadogAgentStatusConditions(
-				newStatus,
-				metav1.NewTime(time.Now()),
-				datadoghqv2alpha1.OverrideReconcileConflictConditionType,
-				metav1.ConditionTrue,
-				"OverrideConflict",
-				"Agent component is set to disabled",
-				true,
-			)
-		}
-		return r.cleanupV2DaemonSet(daemonsetLogger, dda, daemonset, newStatus)
-	}
-	return r.createOrUpdateDaemonset(daemonsetLogger, dda, daemonset, newStatus, updateDSStatusV2WithAgent)
+
+	if profile != nil {
+		setV2AgentProfileMetadata(&daemonset.ObjectMeta, daemonset.Spec.Selector, &daemonset.Spec.Template, profile)
+		if profile.Override != nil {
+			override.PodTemplateSpec(logger, podManagers, profile.Override, datadoghqv2alpha1.NodeAgentComponentName, datadoghqv2alpha1.GetProfileResourcesPrefix(dda, profile.Name))
+			override.DaemonSet(daemonset, profile.Override)
+		}
+	} else if len(dda.Spec.Profiles) > 0 {
+		excludeV2AgentProfilePods(daemonset.Spec.Selector)
+	}
+	componentagent.ApplyProfileNodeAffinity(&daemonset.Spec.Template, componentagent.ProfileNodeSelectorTerms(dda.Spec.Profiles, profile))
+
+	return daemonset, nil
+}
+
+// buildV2AgentExtendedDaemonSet builds the node Agent ExtendedDaemonSet: default extendeddaemonset, global settings,
+// features configuration and, if defined, the component override.
+// If profile isn't nil, the ExtendedDaemonSet is the node Agent of the profile.
+func buildV2AgentExtendedDaemonSet(logger logr.Logger, features []feature.Feature, dda *datadoghqv2alpha1.DatadogAgent, resourcesManager feature.ResourceManagers, requiredContainers []common.AgentContainerName, profile *datadoghqv2alpha1.DatadogAgentProfile) (*edsv1alpha1.ExtendedDaemonSet, error) {
+	// Start by creating the Default Agent extendeddaemonset
+	eds := componentagent.NewDefaultAgentExtendedDaemonset(dda, requiredContainers)
+	podManagers := feature.NewPodTemplateManagers(&eds.Spec.Template)
+
+	// Set Global setting on the default extendeddaemonset
+	eds.Spec.Template = *override.ApplyGlobalSettings(logger, podManagers, dda, resourcesManager, datadoghqv2alpha1.NodeAgentComponentName)
+
+	// Apply features changes on the Deployment.Spec.Template
+	for _, feat := range features {
+		if errFeat := feat.ManageNodeAgent(podManagers); errFeat != nil {
+			return nil, newFeatureError(feat.ID(), featureManageNodeAgentErrorReason, errFeat)
+		}
+	}
+
+	// If Override is defined for the node agent component, apply the override on the PodTemplateSpec, it will cascade to container.
+	if componentOverride, ok := dda.Spec.Override[datadoghqv2alpha1.NodeAgentComponentName]; ok {
+		override.PodTemplateSpec(logger, podManagers, componentOverride, datadoghqv2alpha1.NodeAgentComponentName, dda.Name)
+		override.ExtendedDaemonSet(eds, componentOverride)
+	}
+
+	if profile != nil {
+		// The ExtendedDaemonSet selector is nil, the pods are selected through the ExtendedDaemonSetReplicaSets.
+		setV2AgentProfileMetadata(&eds.ObjectMeta, nil, &eds.Spec.Template, profile)
+		if profile.Override != nil {
+			override.PodTemplateSpec(logger, podManagers, profile.Override, datadoghqv2alpha1.NodeAgentComponentName, datadoghqv2alpha1.GetProfileResourcesPrefix(dda, profile.Name))
+			override.ExtendedDaemonSet(eds, profile.Override)
+		}
+	} else if len(dda.Spec.Profiles) > 0 {
+		// The ExtendedDaemonSet selector is nil: the default node Agent pods get their own profile label value, so that
+		// they are distinct from the pods of the profiles. The ExtendedDaemonSet labels are shared with the pod template
+		// and are kept as is, as they select the node Agents of the profiles to clean up.
+		podLabels := make(map[string]string, len(eds.Spec.Template.Labels)+1)
+		for key, val := range eds.Spec.Template.Labels {
+			podLabels[key] = val
+		}
+		podLabels[apicommon.AgentDeploymentProfileLabelKey] = apicommon.DefaultAgentProfileName
+		eds.Spec.Template.Labels = podLabels
+	}
+	componentagent.ApplyProfileNodeAffinity(&eds.Spec.Template, componentagent.ProfileNodeSelectorTerms(dda.Spec.Profiles, profile))
+
+	return eds, nil
+}
+
+// setV2AgentProfileMetadata names and labels the node Agent of a profile, so that its pods are distinct from the pods
+// of the default node Agent and of the other profiles.
+func setV2AgentProfileMetadata(objMeta *metav1.ObjectMeta, selector *metav1.LabelSelector, podTemplate *corev1.PodTemplateSpec, profile *datadoghqv2alpha1.DatadogAgentProfile) {
+	objMeta.Name = fmt.Sprintf("%s-%s", objMeta.Name, profile.Name)
+	objMeta.Labels[apicommon.AgentDeploymentProfileLabelKey] = profile.Name
+	podTemplate.Labels[apicommon.AgentDeploymentProfileLabelKey] = profile.Name
+	if selector != nil {
+		selector.MatchLabels[apicommon.AgentDeploymentProfileLabelKey] = profile.Name
+	}
+}
+
+// excludeV2AgentProfilePods excludes the pods of the profiles from the selector of the default node Agent, as their
+// labels are a superset of the labels of the default node Agent pods.
+func excludeV2AgentProfilePods(selector *metav1.LabelSelector) {
+	for _, requirement := range selector.MatchExpressions {
+		if requirement.Key == apicommon.AgentDeploymentProfileLabelKey {
+			return
+		}
+	}
+	selector.MatchExpressions = append(selector.MatchExpressions, metav1.LabelSelectorRequirement{
+		Key:      apicommon.AgentDeploymentProfileLabelKey,
+		Operator: metav1.LabelSelectorOpDoesNotExist,
+	})
+}
+
+// migrateV2AgentSelector deletes the default node Agent DaemonSet if its selector doesn't exclude the pods of the
+// profiles, as the selector is immutable. The DaemonSet is deleted without its pods, which are adopted by the
+// DaemonSet created at the next reconcile, then rolled out. The DaemonSets of the DatadogAgents without profiles are
+// kept as is.
+func (r *Reconciler) migrateV2AgentSelector(logger logr.Logger, dda *datadoghqv2alpha1.DatadogAgent, daemonset *appsv1.DaemonSet) (reconcile.Result, error) {
+	if len(dda.Spec.Profiles) == 0 {
+		return reconcile.Result{}, nil
+	}
+
+	current := &appsv1.DaemonSet{}
+	if err := r.client.Get(context.TODO(), client.ObjectKeyFromObject(daemonset), current); err != nil {
+		if errors.IsNotFound(err) {
+			return reconcile.Result{}, nil
+		}
+		return reconcile.Result{}, err
+	}
+	if apiequality.Semantic.DeepEqual(current.Spec.Selector, daemonset.Spec.Selector) || !object.CheckOwnerReference(dda, current) {
+		return reconcile.Result{}, nil
+	}
+
+	if current.DeletionTimestamp == nil {
+		logger.Info("Recreate the node Agent DaemonSet to exclude the profile pods from its selector, keeping its pods")
+		if err := r.client.Delete(context.TODO(), current, client.PropagationPolicy(metav1.DeletePropagationOrphan)); err != nil && !errors.IsNotFound(err) {
+			return reconcile.Result{}, err
+		}
+		event := buildEventInfo(current.Name, current.Namespace, daemonSetKind, datadog.DeletionEvent)
+		r.recordEvent(dda, event)
+	}
+
+	// Wait for the deletion before creating the new DaemonSet
+	return reconcile.Result{RequeueAfter: 5 * time.Second}, nil
+}
+
+// isV2AgentEnabled returns true if the node Agent should be deployed.
+// The node Agent is always deployed, unless the component override disables it.
+// The node Agent of a profile is disabled by the profile override if set, or by the component override otherwise.
+func isV2AgentEnabled(dda *datadoghqv2alpha1.DatadogAgent, profile *datadoghqv2alpha1.DatadogAgentProfile) bool {
+	if profile != nil && profile.Override != nil && profile.Override.Disabled != nil {
+		return !*profile.Override.Disabled
+	}
+	if componentOverride, ok := dda.Spec.Override[datadoghqv2alpha1.NodeAgentComponentName]; ok {
+		return !apiutils.BoolValue(componentOverride.Disabled)
+	}
+	return true
+}
+
+// getV2AgentProfileStatus returns the node Agent status of a profile, or nil if not found.
+func getV2AgentProfileStatus(status *datadoghqv2alpha1.DatadogAgentStatus, profileName string) *common.DaemonSetStatus {
+	for _, profileStatus := range status.Profiles {
+		if profileStatus.Name == profileName {
+			return profileStatus.Agent
+		}
+	}
+	return nil
 }
 
 func updateDSStatusV2WithAgent(dda *appsv1.DaemonSet, newStatus *datadoghqv2alpha1.DatadogAgentStatus, updateTime metav1.Time, status metav1.ConditionStatus, reason, message string) {
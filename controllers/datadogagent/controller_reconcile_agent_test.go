@@ -0,0 +1,94 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package datadogagent
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	apicommon "github.com/DataDog/datadog-operator/apis/datadoghq/common"
+	datadoghqv2alpha1 "github.com/DataDog/datadog-operator/apis/datadoghq/v2alpha1"
+	v2alpha1test "github.com/DataDog/datadog-operator/apis/datadoghq/v2alpha1/test"
+)
+
+func TestReconciler_migrateV2AgentSelector(t *testing.T) {
+	s := runtime.NewScheme()
+	require.NoError(t, appsv1.AddToScheme(s))
+	require.NoError(t, datadoghqv2alpha1.AddToScheme(s))
+
+	tests := []struct {
+		name        string
+		profiles    []datadoghqv2alpha1.DatadogAgentProfile
+		excluded    bool
+		wantDeleted bool
+	}{
+		{
+			name:     "no profiles",
+			excluded: false,
+		},
+		{
+			name:        "profiles with a selector selecting their pods",
+			profiles:    []datadoghqv2alpha1.DatadogAgentProfile{newProfile("gpu", nil)},
+			excluded:    false,
+			wantDeleted: true,
+		},
+		{
+			name:     "profiles with a selector excluding their pods",
+			profiles: []datadoghqv2alpha1.DatadogAgentProfile{newProfile("gpu", nil)},
+			excluded: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dda := v2alpha1test.NewDatadogAgent("bar", "foo", nil)
+			dda.UID = "uid"
+			dda.Spec.Profiles = tt.profiles
+			daemonset := &appsv1.DaemonSet{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "bar", Name: "foo-agent"},
+				Spec: appsv1.DaemonSetSpec{
+					Selector: &metav1.LabelSelector{MatchLabels: map[string]string{
+						apicommon.AgentDeploymentNameLabelKey:      "foo",
+						apicommon.AgentDeploymentComponentLabelKey: apicommon.DefaultAgentResourceSuffix,
+					}},
+				},
+			}
+			current := daemonset.DeepCopy()
+			require.NoError(t, controllerutil.SetControllerReference(dda, current, s))
+			if tt.excluded {
+				excludeV2AgentProfilePods(current.Spec.Selector)
+			}
+			excludeV2AgentProfilePods(daemonset.Spec.Selector)
+
+			r := &Reconciler{
+				client:   fake.NewClientBuilder().WithScheme(s).WithObjects(current).Build(),
+				recorder: record.NewFakeRecorder(10),
+			}
+			result, err := r.migrateV2AgentSelector(logf.Log.WithName(t.Name()), dda, daemonset)
+			require.NoError(t, err)
+
+			err = r.client.Get(context.TODO(), client.ObjectKeyFromObject(daemonset), &appsv1.DaemonSet{})
+			if tt.wantDeleted {
+				assert.True(t, apierrors.IsNotFound(err))
+				assert.NotZero(t, result.RequeueAfter)
+			} else {
+				assert.NoError(t, err)
+				assert.Zero(t, result)
+			}
+		})
+	}
+}
@@ -27,18 +27,9 @@ import (
 func (r *Reconciler) reconcileV2ClusterChecksRunner(logger logr.Logger, requiredComponents feature.RequiredComponents, features []feature.Feature, dda *datadoghqv2alpha1.DatadogAgent, resourcesManager feature.ResourceManagers, newStatus *datadoghqv2alpha1.DatadogAgentStatus) (reconcile.Result, error) {
 	var result reconcile.Result
 
-	// Start by creating the Default Cluster-Agent deployment
-	deployment := componentccr.NewDefaultClusterChecksRunnerDeployment(dda)
-	podManagers := feature.NewPodTemplateManagers(&deployment.Spec.Template)
-
-	// Set Global setting on the default deployment
-	deployment.Spec.Template = *override.ApplyGlobalSettings(logger, podManagers, dda, resourcesManager, datadoghqv2alpha1.ClusterChecksRunnerComponentName)
-
-	// Apply features changes on the Deployment.Spec.Template
-	for _, feat := range features {
-		if errFeat := feat.ManageClusterChecksRunner(podManagers); errFeat != nil {
-			return result, errFeat
-		}
+	deployment, err := buildV2ClusterChecksRunnerDeployment(logger, features, dda, resourcesManager)
+	if err != nil {
+		return result, err
 	}
 
 	deploymentLogger := logger.WithValues("component", datadoghqv2alpha1.ClusterChecksRunnerReconcileConditionType)
@@ -56,7 +47,7 @@ func (r *Reconciler) reconcileV2ClusterChecksRunner(logger logr.Logger, required
 		return r.cleanupV2ClusterChecksRunner(deploymentLogger, dda, deployment, newStatus)
 	}
 
-	// If Override is defined for the CCR component, apply the override on the PodTemplateSpec, it will cascade to container.
+	// If Override is defined for the CCR component, it supersedes what's set in requiredComponents.
 	if componentOverride, ok := dda.Spec.Override[datadoghqv2alpha1.ClusterChecksRunnerComponentName]; ok {
 		if apiutils.BoolValue(componentOverride.Disabled) {
 			if requiredEnabled {
@@ -74,8 +65,6 @@ func (r *Reconciler) reconcileV2ClusterChecksRunner(logger logr.Logger, required
 			// Delete CCR
 			return r.cleanupV2ClusterChecksRunner(deploymentLogger, dda, deployment, newStatus)
 		}
-		override.PodTemplateSpec(logger, podManagers, componentOverride, datadoghqv2alpha1.ClusterChecksRunnerComponentName, dda.Name)
-		override.Deployment(deployment, componentOverride)
 	} else if !requiredEnabled {
 		return r.cleanupV2ClusterChecksRunner(deploymentLogger, dda, deployment, newStatus)
 	}
@@ -83,6 +72,44 @@ func (r *Reconciler) reconcileV2ClusterChecksRunner(logger logr.Logger, required
 	return r.createOrUpdateDeployment(deploymentLogger, dda, deployment, newStatus, updateStatusV2WithClusterChecksRunner)
 }
 
+// buildV2ClusterChecksRunnerDeployment builds the Cluster Checks Runner Deployment: default deployment, global settings,
+// features configuration and, if defined, the component override.
+func buildV2ClusterChecksRunnerDeployment(logger logr.Logger, features []feature.Feature, dda *datadoghqv2alpha1.DatadogAgent, resourcesManager feature.ResourceManagers) (*appsv1.Deployment, error) {
+	// Start by creating the Default Cluster Checks Runner deployment
+	deployment := componentccr.NewDefaultClusterChecksRunnerDeployment(dda)
+	podManagers := feature.NewPodTemplateManagers(&deployment.Spec.Template)
+
+	// Set Global setting on the default deployment
+	deployment.Spec.Template = *override.ApplyGlobalSettings(logger, podManagers, dda, resourcesManager, datadoghqv2alpha1.ClusterChecksRunnerComponentName)
+
+	// Apply features changes on the Deployment.Spec.Template
+	for _, feat := range features {
+		if errFeat := feat.ManageClusterChecksRunner(podManagers); errFeat != nil {
+			return nil, newFeatureError(feat.ID(), featureManageCCRErrorReason, errFeat)
+		}
+	}
+
+	// If Override is defined for the CCR component, apply the override on the PodTemplateSpec, it will cascade to container.
+	if componentOverride, ok := dda.Spec.Override[datadoghqv2alpha1.ClusterChecksRunnerComponentName]; ok && !apiutils.BoolValue(componentOverride.Disabled) {
+		override.PodTemplateSpec(logger, podManagers, componentOverride, datadoghqv2alpha1.ClusterChecksRunnerComponentName, dda.Name)
+		override.Deployment(deployment, componentOverride)
+	}
+
+	return deployment, nil
+}
+
+// isV2ClusterChecksRunnerEnabled returns true if the Cluster Checks Runner should be deployed.
+// The Cluster Checks Runner is never deployed without the Cluster Agent.
+func isV2ClusterChecksRunnerEnabled(requiredComponents feature.RequiredComponents, dda *datadoghqv2alpha1.DatadogAgent) bool {
+	if !isV2ClusterAgentEnabled(requiredComponents, dda) {
+		return false
+	}
+	if componentOverride, ok := dda.Spec.Override[datadoghqv2alpha1.ClusterChecksRunnerComponentName]; ok {
+		return !apiutils.BoolValue(componentOverride.Disabled)
+	}
+	return requiredComponents.ClusterChecksRunner.IsEnabled()
+}
+
 func updateStatusV2WithClusterChecksRunner(deployment *appsv1.Deployment, newStatus *datadoghqv2alpha1.DatadogAgentStatus, updateTime metav1.Time, status metav1.ConditionStatus, reason, message string) {
 	newStatus.ClusterChecksRunner = datadoghqv2alpha1.UpdateDeploymentStatus(deployment, newStatus.ClusterChecksRunner, &updateTime)
 	datadoghqv2alpha1.UpdateDatadogAgentStatusConditions(newStatus, updateTime, datadoghqv2alpha1.ClusterChecksRunnerReconcileConditionType, status, reason, message, true)
@@ -0,0 +1,172 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package datadogagent
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/go-logr/logr"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+
+	datadoghqv2alpha1 "github.com/DataDog/datadog-operator/apis/datadoghq/v2alpha1"
+	"github.com/DataDog/datadog-operator/controllers/datadogagent/feature"
+	"github.com/DataDog/datadog-operator/controllers/datadogagent/feature/dynamic"
+)
+
+const (
+	featureReconciledReason              = "FeatureReconciled"
+	featureManageDependenciesErrorReason = "ManageDependenciesError"
+	featureManageNodeAgentErrorReason    = "ManageNodeAgentError"
+	featureManageClusterAgentErrorReason = "ManageClusterAgentError"
+	featureManageCCRErrorReason          = "ManageClusterChecksRunnerError"
+	featureRelationErrorReason           = "FeatureRelationError"
+)
+
+// featureError is the error returned by a feature when managing its dependencies or the pod template of a component
+type featureError struct {
+	id     feature.IDType
+	reason string
+	err    error
+}
+
+func newFeatureError(id feature.IDType, reason string, err error) error {
+	return &featureError{id: id, reason: reason, err: err}
+}
+
+func (e *featureError) Error() string {
+	return fmt.Sprintf("feature %s: %v", e.id, e.err)
+}
+
+func (e *featureError) Unwrap() error {
+	return e.err
+}
+
+// featureErrors stores the first error of each enabled feature during a reconcile, indexed by feature ID
+type featureErrors map[feature.IDType]*featureError
+
+// record stores the featureError wrapped in err, if any
+func (fe featureErrors) record(err error) {
+	var featErr *featureError
+	if !errors.As(err, &featErr) {
+		return
+	}
+	if _, found := fe[featErr.id]; !found {
+		fe[featErr.id] = featErr
+	}
+}
+
+// recordRelationErrors stores the feature.RelationError of err, on the feature declaring the relation
+func (fe featureErrors) recordRelationErrors(err error) {
+	var errs []error
+	if agg, ok := err.(utilerrors.Aggregate); ok {
+		errs = agg.Errors()
+	} else {
+		errs = []error{err}
+	}
+	for _, err := range errs {
+		var relationErr *feature.RelationError
+		if errors.As(err, &relationErr) {
+			fe.record(newFeatureError(relationErr.ID, featureRelationErrorReason, relationErr))
+		}
+	}
+}
+
+// updateFeaturesStatusV2 sets the list of enabled features with their required components, and a reconcile condition
+// for each of them, in the DatadogAgent status. The conditions of the features no longer enabled are removed.
+func updateFeaturesStatusV2(newStatus *datadoghqv2alpha1.DatadogAgentStatus, features []feature.Feature, featureComponents map[feature.IDType]feature.RequiredComponents, errs featureErrors, now metav1.Time) {
+	featuresStatus := make([]datadoghqv2alpha1.DatadogAgentFeatureStatus, 0, len(features))
+	enabled := map[string]bool{}
+	for _, feat := range features {
+		conditionType := featureConditionType(feat.ID())
+		enabled[conditionType] = true
+		featuresStatus = append(featuresStatus, datadoghqv2alpha1.DatadogAgentFeatureStatus{
+			ID:                 string(feat.ID()),
+			RequiredComponents: featureRequiredComponents(featureComponents[feat.ID()]),
+		})
+
+		if featErr, found := errs[feat.ID()]; found {
+			datadoghqv2alpha1.UpdateDatadogAgentStatusConditions(newStatus, now, conditionType, metav1.ConditionFalse, featErr.reason, featErr.err.Error(), true)
+		} else {
+			datadoghqv2alpha1.UpdateDatadogAgentStatusConditions(newStatus, now, conditionType, metav1.ConditionTrue, featureReconciledReason, "Feature reconciled", true)
+		}
+	}
+	if len(featuresStatus) == 0 {
+		featuresStatus = nil
+	}
+	newStatus.Features = featuresStatus
+
+	conditions := newStatus.Conditions[:0]
+	for _, condition := range newStatus.Conditions {
+		if strings.HasPrefix(condition.Type, datadoghqv2alpha1.FeatureReconcileConditionTypePrefix) && !enabled[condition.Type] {
+			continue
+		}
+		conditions = append(conditions, condition)
+	}
+	newStatus.Conditions = conditions
+}
+
+// featureConditionType returns the type of the reconcile condition of a feature
+func featureConditionType(id feature.IDType) string {
+	return datadoghqv2alpha1.FeatureReconcileConditionTypePrefix + string(id)
+}
+
+// featureRequiredComponents converts the RequiredComponents of a feature to the components of its status
+func featureRequiredComponents(requiredComponents feature.RequiredComponents) []datadoghqv2alpha1.DatadogAgentFeatureComponent {
+	var components []datadoghqv2alpha1.DatadogAgentFeatureComponent
+	for _, component := range []struct {
+		name     datadoghqv2alpha1.ComponentName
+		required feature.RequiredComponent
+	}{
+		{datadoghqv2alpha1.NodeAgentComponentName, requiredComponents.Agent},
+		{datadoghqv2alpha1.ClusterAgentComponentName, requiredComponents.ClusterAgent},
+		{datadoghqv2alpha1.ClusterChecksRunnerComponentName, requiredComponents.ClusterChecksRunner},
+	} {
+		if !component.required.IsEnabled() {
+			continue
+		}
+		components = append(components, datadoghqv2alpha1.DatadogAgentFeatureComponent{
+			Name:       component.name,
+			Containers: component.required.Containers,
+		})
+	}
+
+	return components
+}
+
+// listDatadogAgentFeatures returns all the DatadogAgentFeatures, if they are enabled
+func (r *Reconciler) listDatadogAgentFeatures(ctx context.Context) ([]datadoghqv2alpha1.DatadogAgentFeature, error) {
+	if !r.options.DatadogAgentFeatureEnabled {
+		return nil, nil
+	}
+	ddafList := &datadoghqv2alpha1.DatadogAgentFeatureList{}
+	if err := r.client.List(ctx, ddafList); err != nil {
+		return nil, fmt.Errorf("unable to list the DatadogAgentFeatures: %w", err)
+	}
+
+	return ddafList.Items, nil
+}
+
+// buildDynamicFeaturesV2 returns the configured features of the DatadogAgentFeatures that apply to the DatadogAgent.
+// They are managed after the features of the DatadogAgent spec.
+func buildDynamicFeaturesV2(logger logr.Logger, dda *datadoghqv2alpha1.DatadogAgent, ddafs []datadoghqv2alpha1.DatadogAgentFeature, options *feature.Options) []feature.Feature {
+	var features []feature.Feature
+	selected := dynamic.Select(dda, ddafs)
+	for i := range selected {
+		if len(selected[i].Name) > dynamic.MaxNameLength {
+			logger.Info("Ignoring the DatadogAgentFeature, its name is too long", "name", selected[i].Name, "maxLength", dynamic.MaxNameLength)
+			continue
+		}
+		feat := dynamic.NewFeature(&selected[i], options)
+		feat.Configure(dda)
+		features = append(features, feat)
+	}
+
+	return features
+}
@@ -0,0 +1,213 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package datadogagent
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	apicommonv1 "github.com/DataDog/datadog-operator/apis/datadoghq/common/v1"
+	datadoghqv2alpha1 "github.com/DataDog/datadog-operator/apis/datadoghq/v2alpha1"
+	v2alpha1test "github.com/DataDog/datadog-operator/apis/datadoghq/v2alpha1/test"
+	apiutils "github.com/DataDog/datadog-operator/apis/utils"
+	"github.com/DataDog/datadog-operator/controllers/datadogagent/feature"
+)
+
+func Test_updateFeaturesStatusV2(t *testing.T) {
+	dda := &datadoghqv2alpha1.DatadogAgent{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "foo", Name: "bar"},
+		Spec: datadoghqv2alpha1.DatadogAgentSpec{
+			Features: &datadoghqv2alpha1.DatadogFeatures{
+				APM: &datadoghqv2alpha1.APMFeatureConfig{Enabled: apiutils.NewBoolPointer(true)},
+			},
+		},
+	}
+	datadoghqv2alpha1.DefaultDatadogAgent(dda)
+	features, featureComponents, _, err := feature.BuildFeaturesWithComponents(dda, &feature.Options{Logger: logf.Log})
+	assert.NoError(t, err)
+
+	now := metav1.Now()
+	newStatus := &datadoghqv2alpha1.DatadogAgentStatus{
+		Conditions: []metav1.Condition{
+			datadoghqv2alpha1.NewDatadogAgentStatusCondition(datadoghqv2alpha1.AgentReconcileConditionType, metav1.ConditionTrue, now, "", ""),
+			datadoghqv2alpha1.NewDatadogAgentStatusCondition(featureConditionType("disabled"), metav1.ConditionTrue, now, featureReconciledReason, ""),
+		},
+	}
+	errs := featureErrors{}
+	errs.record(fmt.Errorf("not a feature error"))
+	errs.record(newFeatureError(feature.APMIDType, featureManageNodeAgentErrorReason, errors.New("apm error")))
+	errs.record(newFeatureError(feature.APMIDType, featureManageClusterAgentErrorReason, errors.New("other apm error")))
+	assert.Len(t, errs, 1)
+
+	updateFeaturesStatusV2(newStatus, features, featureComponents, errs, now)
+
+	// The feature list follows the enabled features, with their required components
+	assert.Len(t, newStatus.Features, len(features))
+	var apmStatus *datadoghqv2alpha1.DatadogAgentFeatureStatus
+	for i := range newStatus.Features {
+		if newStatus.Features[i].ID == string(feature.APMIDType) {
+			apmStatus = &newStatus.Features[i]
+		}
+	}
+	if assert.NotNil(t, apmStatus) {
+		assert.Equal(t, []datadoghqv2alpha1.DatadogAgentFeatureComponent{{
+			Name:       datadoghqv2alpha1.NodeAgentComponentName,
+			Containers: []apicommonv1.AgentContainerName{apicommonv1.CoreAgentContainerName, apicommonv1.TraceAgentContainerName},
+		}}, apmStatus.RequiredComponents)
+	}
+
+	conditions := map[string]metav1.Condition{}
+	for _, condition := range newStatus.Conditions {
+		conditions[condition.Type] = condition
+	}
+	// The first error of the feature is reported in its condition
+	apmCondition := conditions[featureConditionType(feature.APMIDType)]
+	assert.Equal(t, metav1.ConditionFalse, apmCondition.Status)
+	assert.Equal(t, featureManageNodeAgentErrorReason, apmCondition.Reason)
+	assert.Equal(t, "apm error", apmCondition.Message)
+	// The other features are reconciled
+	assert.Equal(t, metav1.ConditionTrue, conditions[featureConditionType(feature.DefaultIDType)].Status)
+	// The conditions of the disabled features are removed, the other conditions are kept
+	assert.NotContains(t, conditions, featureConditionType("disabled"))
+	assert.Contains(t, conditions, datadoghqv2alpha1.AgentReconcileConditionType)
+	assert.Len(t, conditions, len(features)+1)
+}
+
+func Test_BuildFeaturesWithComponents_relations(t *testing.T) {
+	tests := []struct {
+		name         string
+		features     *datadoghqv2alpha1.DatadogFeatures
+		wantEnabled  []feature.IDType
+		wantDisabled []feature.IDType
+		wantErr      string
+	}{
+		{
+			name: "usm implies npm",
+			features: &datadoghqv2alpha1.DatadogFeatures{
+				USM: &datadoghqv2alpha1.USMFeatureConfig{Enabled: apiutils.NewBoolPointer(true)},
+			},
+			wantEnabled: []feature.IDType{feature.USMIDType, feature.NPMIDType},
+		},
+		{
+			name: "usm with npm disabled",
+			features: &datadoghqv2alpha1.DatadogFeatures{
+				USM: &datadoghqv2alpha1.USMFeatureConfig{Enabled: apiutils.NewBoolPointer(true)},
+				NPM: &datadoghqv2alpha1.NPMFeatureConfig{Enabled: apiutils.NewBoolPointer(false)},
+			},
+			wantEnabled:  []feature.IDType{feature.USMIDType},
+			wantDisabled: []feature.IDType{feature.NPMIDType},
+			wantErr:      "feature usm implies feature npm, which can't be enabled with this DatadogAgent",
+		},
+		{
+			name:         "npm isn't implied without usm",
+			features:     &datadoghqv2alpha1.DatadogFeatures{},
+			wantDisabled: []feature.IDType{feature.USMIDType, feature.NPMIDType},
+		},
+		{
+			name: "external metrics requires the cluster agent",
+			features: &datadoghqv2alpha1.DatadogFeatures{
+				ExternalMetricsServer: &datadoghqv2alpha1.ExternalMetricsServerFeatureConfig{Enabled: apiutils.NewBoolPointer(true)},
+			},
+			wantEnabled: []feature.IDType{feature.ExternalMetricsIDType, feature.DefaultIDType},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dda := v2alpha1test.NewDatadogAgent("bar", "foo", nil)
+			dda.Spec.Features = tt.features
+			datadoghqv2alpha1.DefaultDatadogAgent(dda)
+
+			features, featureComponents, requiredComponents, err := feature.BuildFeaturesWithComponents(dda, &feature.Options{Logger: logf.Log})
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+			} else if assert.Error(t, err) {
+				assert.Equal(t, tt.wantErr, err.Error())
+			}
+
+			enabled := map[feature.IDType]bool{}
+			for _, feat := range features {
+				enabled[feat.ID()] = true
+			}
+			for _, id := range tt.wantEnabled {
+				assert.True(t, enabled[id], "feature %s should be enabled", id)
+				assert.Contains(t, featureComponents, id)
+			}
+			for _, id := range tt.wantDisabled {
+				assert.False(t, enabled[id], "feature %s should be disabled", id)
+				assert.NotContains(t, featureComponents, id)
+			}
+			if enabled[feature.NPMIDType] {
+				assert.Contains(t, requiredComponents.Agent.Containers, apicommonv1.SystemProbeContainerName)
+			}
+		})
+	}
+}
+
+func Test_featureErrors_recordRelationErrors(t *testing.T) {
+	errs := featureErrors{}
+	errs.recordRelationErrors(utilerrors.NewAggregate([]error{
+		&feature.RelationError{ID: feature.USMIDType, Relation: feature.RequiresRelationType, Other: feature.NPMIDType, Reason: "which isn't enabled"},
+		fmt.Errorf("not a relation error"),
+	}))
+
+	assert.Len(t, errs, 1)
+	if assert.NotNil(t, errs[feature.USMIDType]) {
+		assert.Equal(t, featureRelationErrorReason, errs[feature.USMIDType].reason)
+		assert.Equal(t, "feature usm requires feature npm, which isn't enabled", errs[feature.USMIDType].err.Error())
+	}
+}
+
+// failingServiceAccountClient fails the creation of the ServiceAccounts
+type failingServiceAccountClient struct {
+	client.Client
+}
+
+func (c *failingServiceAccountClient) Create(ctx context.Context, obj client.Object, opts ...client.CreateOption) error {
+	if _, ok := obj.(*corev1.ServiceAccount); ok {
+		return errors.New("create error")
+	}
+	return c.Client.Create(ctx, obj, opts...)
+}
+
+func TestReconciler_reconcileInstanceV2_dependenciesError(t *testing.T) {
+	dda := v2alpha1test.NewDatadogAgent("bar", "foo", nil)
+	datadoghqv2alpha1.DefaultDatadogAgent(dda)
+	r := &Reconciler{
+		client:   &failingServiceAccountClient{Client: fake.NewClientBuilder().WithScheme(NewRenderScheme()).WithObjects(dda).Build()},
+		scheme:   NewRenderScheme(),
+		recorder: record.NewFakeRecorder(100),
+	}
+
+	_, err := r.reconcileInstanceV2(context.TODO(), logf.Log.WithName(t.Name()), dda, nil)
+	require.Error(t, err)
+
+	// The feature conditions are stored with the reconcile error, even though the dependencies can't be applied
+	stored := &datadoghqv2alpha1.DatadogAgent{}
+	require.NoError(t, r.client.Get(context.TODO(), client.ObjectKeyFromObject(dda), stored))
+	conditions := map[string]metav1.Condition{}
+	for _, condition := range stored.Status.Conditions {
+		conditions[condition.Type] = condition
+	}
+	if assert.Contains(t, conditions, featureConditionType(feature.DefaultIDType)) {
+		assert.Equal(t, metav1.ConditionTrue, conditions[featureConditionType(feature.DefaultIDType)].Status)
+	}
+	if assert.Contains(t, conditions, datadoghqv2alpha1.DatadogAgentReconcileErrorConditionType) {
+		assert.Equal(t, metav1.ConditionTrue, conditions[datadoghqv2alpha1.DatadogAgentReconcileErrorConditionType].Status)
+	}
+	assert.NotEmpty(t, stored.Status.Features)
+}
@@ -0,0 +1,48 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package datadogagent
+
+import (
+	"context"
+	"fmt"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	datadoghqv2alpha1 "github.com/DataDog/datadog-operator/apis/datadoghq/v2alpha1"
+)
+
+// applyDatadogAgentTemplate merges the spec of the DatadogAgentTemplate referenced in spec.baseRef under the spec of the
+// DatadogAgent. It returns the status of the merged template, nil if the DatadogAgent doesn't reference any template.
+// The DatadogAgent is left unchanged if the template can't be merged.
+func (r *Reconciler) applyDatadogAgentTemplate(ctx context.Context, dda *datadoghqv2alpha1.DatadogAgent) (*datadoghqv2alpha1.DatadogAgentTemplateStatus, error) {
+	if dda.Spec.BaseRef == nil {
+		return nil, nil
+	}
+	if !r.options.DatadogAgentTemplateEnabled {
+		return nil, fmt.Errorf("spec.baseRef references the DatadogAgentTemplate %s, but the DatadogAgentTemplates aren't enabled in the operator", dda.Spec.BaseRef.Name)
+	}
+
+	template := &datadoghqv2alpha1.DatadogAgentTemplate{}
+	if err := r.client.Get(ctx, client.ObjectKey{Name: dda.Spec.BaseRef.Name}, template); err != nil {
+		return nil, fmt.Errorf("unable to get the DatadogAgentTemplate %s: %w", dda.Spec.BaseRef.Name, err)
+	}
+
+	return mergeDatadogAgentTemplate(dda, template)
+}
+
+// mergeDatadogAgentTemplate merges the spec of a DatadogAgentTemplate under the spec of the DatadogAgent
+func mergeDatadogAgentTemplate(dda *datadoghqv2alpha1.DatadogAgent, template *datadoghqv2alpha1.DatadogAgentTemplate) (*datadoghqv2alpha1.DatadogAgentTemplateStatus, error) {
+	spec, err := datadoghqv2alpha1.MergeDatadogAgentTemplate(&dda.Spec, &template.Spec)
+	if err != nil {
+		return nil, fmt.Errorf("unable to merge the DatadogAgentTemplate %s: %w", template.Name, err)
+	}
+	dda.Spec = *spec
+
+	return &datadoghqv2alpha1.DatadogAgentTemplateStatus{
+		Name:       template.Name,
+		Generation: template.Generation,
+	}, nil
+}
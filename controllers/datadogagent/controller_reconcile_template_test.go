@@ -0,0 +1,100 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package datadogagent
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	datadoghqv2alpha1 "github.com/DataDog/datadog-operator/apis/datadoghq/v2alpha1"
+	apiutils "github.com/DataDog/datadog-operator/apis/utils"
+)
+
+func Test_applyDatadogAgentTemplate(t *testing.T) {
+	template := &datadoghqv2alpha1.DatadogAgentTemplate{
+		ObjectMeta: metav1.ObjectMeta{Name: "base", Generation: 3},
+		Spec: datadoghqv2alpha1.DatadogAgentSpec{
+			Global: &datadoghqv2alpha1.GlobalConfig{
+				Registry: apiutils.NewStringPointer("registry.example.com"),
+				Tags:     []string{"team:platform"},
+			},
+		},
+	}
+	newDDA := func(baseRef string) *datadoghqv2alpha1.DatadogAgent {
+		dda := &datadoghqv2alpha1.DatadogAgent{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "bar", Name: "foo"},
+			Spec: datadoghqv2alpha1.DatadogAgentSpec{
+				Global: &datadoghqv2alpha1.GlobalConfig{ClusterName: apiutils.NewStringPointer("cluster-1")},
+			},
+		}
+		if baseRef != "" {
+			dda.Spec.BaseRef = &datadoghqv2alpha1.DatadogAgentTemplateReference{Name: baseRef}
+		}
+		return dda
+	}
+
+	tests := []struct {
+		name       string
+		dda        *datadoghqv2alpha1.DatadogAgent
+		disabled   bool
+		wantStatus *datadoghqv2alpha1.DatadogAgentTemplateStatus
+		wantSpec   *datadoghqv2alpha1.GlobalConfig
+		wantErr    string
+	}{
+		{
+			name:     "no template",
+			dda:      newDDA(""),
+			wantSpec: &datadoghqv2alpha1.GlobalConfig{ClusterName: apiutils.NewStringPointer("cluster-1")},
+		},
+		{
+			name:       "template",
+			dda:        newDDA("base"),
+			wantStatus: &datadoghqv2alpha1.DatadogAgentTemplateStatus{Name: "base", Generation: 3},
+			wantSpec: &datadoghqv2alpha1.GlobalConfig{
+				ClusterName: apiutils.NewStringPointer("cluster-1"),
+				Registry:    apiutils.NewStringPointer("registry.example.com"),
+				Tags:        []string{"team:platform"},
+			},
+		},
+		{
+			name:     "template not found",
+			dda:      newDDA("other"),
+			wantSpec: &datadoghqv2alpha1.GlobalConfig{ClusterName: apiutils.NewStringPointer("cluster-1")},
+			wantErr:  "unable to get the DatadogAgentTemplate other",
+		},
+		{
+			name:     "templates disabled",
+			dda:      newDDA("base"),
+			disabled: true,
+			wantSpec: &datadoghqv2alpha1.GlobalConfig{ClusterName: apiutils.NewStringPointer("cluster-1")},
+			wantErr:  "the DatadogAgentTemplates aren't enabled in the operator",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := runtime.NewScheme()
+			assert.NoError(t, datadoghqv2alpha1.AddToScheme(s))
+			r := &Reconciler{
+				client:  fake.NewClientBuilder().WithScheme(s).WithObjects(template).Build(),
+				options: ReconcilerOptions{DatadogAgentTemplateEnabled: !tt.disabled},
+			}
+
+			status, err := r.applyDatadogAgentTemplate(context.TODO(), tt.dda)
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+			} else if assert.Error(t, err) {
+				assert.Contains(t, err.Error(), tt.wantErr)
+			}
+			assert.Equal(t, tt.wantStatus, status)
+			assert.Equal(t, tt.wantSpec, tt.dda.Spec.Global)
+		})
+	}
+}
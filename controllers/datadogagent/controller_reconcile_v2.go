@@ -47,10 +47,6 @@ func (r *Reconciler) internalReconcileV2(ctx context.Context, request reconcile.
 		return result, err
 	}
 
-	if instance.Spec.Global == nil || instance.Spec.Global.Credentials == nil {
-		return result, fmt.Errorf("credentials not configured in the DatadogAgent, can't reconcile")
-	}
-
 	// check it the resource was properly decoded in v2
 	// if not it means it was a v1
 	/*if apiequality.Semantic.DeepEqual(instance.Spec, datadoghqv2alpha1.DatadogAgentSpec{}) {
@@ -75,26 +71,51 @@ func (r *Reconciler) internalReconcileV2(ctx context.Context, request reconcile.
 		return result, err
 	}
 
-	// TODO check if IsValideDatadogAgent function is needed for v2
-	/*
-		if err = datadoghqv2alpha1.IsValidDatadogAgent(&instance.Spec); err != nil {
-			reqLogger.V(1).Info("Invalid spec", "error", err)
-			return r.updateStatusIfNeeded(reqLogger, instance, &instance.Status, result, err)
-		}
-	*/
+	// Merge the DatadogAgentTemplate before validating the spec and setting its default values,
+	// the credentials can be set in the template
+	instanceCopy := instance.DeepCopy()
+	templateStatus, err := r.applyDatadogAgentTemplate(ctx, instanceCopy)
+	if err != nil {
+		reqLogger.V(1).Info("Unable to apply the DatadogAgentTemplate", "error", err)
+		return r.updateStatusIfNeededV2(reqLogger, instance, instance.Status.DeepCopy(), result, err)
+	}
+
+	if instanceCopy.Spec.Global == nil || instanceCopy.Spec.Global.Credentials == nil {
+		return result, fmt.Errorf("credentials not configured in the DatadogAgent, can't reconcile")
+	}
+
+	if err = datadoghqv2alpha1.IsValidDatadogAgent(&instanceCopy.Spec); err != nil {
+		reqLogger.V(1).Info("Invalid spec", "error", err)
+		return r.updateStatusIfNeededV2(reqLogger, instance, instance.Status.DeepCopy(), result, err)
+	}
 
 	// Set default values for GlobalConfig and Features
-	instanceCopy := instance.DeepCopy()
 	datadoghqv2alpha1.DefaultDatadogAgent(instanceCopy)
 
-	return r.reconcileInstanceV2(ctx, reqLogger, instanceCopy)
+	return r.reconcileInstanceV2(ctx, reqLogger, instanceCopy, templateStatus)
 }
 
-func (r *Reconciler) reconcileInstanceV2(ctx context.Context, logger logr.Logger, instance *datadoghqv2alpha1.DatadogAgent) (reconcile.Result, error) {
+func (r *Reconciler) reconcileInstanceV2(ctx context.Context, logger logr.Logger, instance *datadoghqv2alpha1.DatadogAgent, templateStatus *datadoghqv2alpha1.DatadogAgentTemplateStatus) (reconcile.Result, error) {
 	var result reconcile.Result
 	newStatus := instance.Status.DeepCopy()
+	newStatus.Template = templateStatus
 
-	features, requiredComponents := feature.BuildFeatures(instance, reconcilerOptionsToFeatureOptions(&r.options, logger))
+	featureOptions := reconcilerOptionsToFeatureOptions(&r.options, logger)
+	features, featureComponents, requiredComponents, relationErr := feature.BuildFeaturesWithComponents(instance, featureOptions)
+	ddafs, err := r.listDatadogAgentFeatures(ctx)
+	if err != nil {
+		return r.updateStatusIfNeededV2(logger, instance, newStatus, result, err)
+	}
+	features = append(features, buildDynamicFeaturesV2(logger, instance, ddafs, featureOptions)...)
+
+	featErrs := featureErrors{}
+	if relationErr != nil {
+		// Don't deploy a set of features whose relations can't be satisfied
+		logger.V(1).Info("Invalid features", "error", relationErr)
+		featErrs.recordRelationErrors(relationErr)
+		updateFeaturesStatusV2(newStatus, features, featureComponents, featErrs, metav1.NewTime(time.Now()))
+		return r.updateStatusIfNeededV2(logger, instance, newStatus, result, relationErr)
+	}
 
 	// -----------------------
 	// Manage dependencies
@@ -105,6 +126,8 @@ func (r *Reconciler) reconcileInstanceV2(ctx context.Context, logger logr.Logger
 		PlatformInfo:  r.platformInfo,
 		Logger:        logger,
 		Scheme:        r.scheme,
+		EventRecorder: r.recorder,
+		VerboseDiff:   r.options.DependenciesDiffLogEnabled,
 	}
 	depsStore := dependencies.NewStore(instance, storeOptions)
 	resourceManagers := feature.NewResourceManagers(depsStore)
@@ -115,12 +138,14 @@ func (r *Reconciler) reconcileInstanceV2(ctx context.Context, logger logr.Logger
 	for _, feat := range features {
 		logger.Info("Dependency ManageDependencies", "featureID", feat.ID())
 		if featErr := feat.ManageDependencies(resourceManagers, requiredComponents); featErr != nil {
+			featErr = newFeatureError(feat.ID(), featureManageDependenciesErrorReason, featErr)
+			featErrs.record(featErr)
 			errs = append(errs, featErr)
 		}
 	}
 
 	// Examine user configuration to override any external dependencies (e.g. RBACs)
-	errs = append(errs, override.Dependencies(logger, resourceManagers, instance)...)
+	errs = append(errs, override.Dependencies(logger, resourceManagers, instance, requiredComponents)...)
 
 	userSpecifiedClusterAgentToken := instance.Spec.Global.ClusterAgentToken != nil || instance.Spec.Global.ClusterAgentTokenSecret != nil
 	if !userSpecifiedClusterAgentToken {
@@ -131,31 +156,37 @@ func (r *Reconciler) reconcileInstanceV2(ctx context.Context, logger logr.Logger
 	// Start reconcile Components
 	// -----------------------------
 
-	var err error
-
 	result, err = r.reconcileV2ClusterAgent(logger, requiredComponents, features, instance, resourceManagers, newStatus)
+	featErrs.record(err)
 	if utils.ShouldReturn(result, err) {
+		updateFeaturesStatusV2(newStatus, features, featureComponents, featErrs, metav1.NewTime(time.Now()))
 		return r.updateStatusIfNeededV2(logger, instance, newStatus, result, err)
 	}
 
 	requiredContainers := requiredComponents.Agent.Containers
 	result, err = r.reconcileV2Agent(logger, requiredComponents, features, instance, resourceManagers, newStatus, requiredContainers)
+	featErrs.record(err)
 	if utils.ShouldReturn(result, err) {
+		updateFeaturesStatusV2(newStatus, features, featureComponents, featErrs, metav1.NewTime(time.Now()))
 		return r.updateStatusIfNeededV2(logger, instance, newStatus, result, err)
 	}
 
 	result, err = r.reconcileV2ClusterChecksRunner(logger, requiredComponents, features, instance, resourceManagers, newStatus)
+	featErrs.record(err)
 	if utils.ShouldReturn(result, err) {
+		updateFeaturesStatusV2(newStatus, features, featureComponents, featErrs, metav1.NewTime(time.Now()))
 		return r.updateStatusIfNeededV2(logger, instance, newStatus, result, err)
 	}
 
+	updateFeaturesStatusV2(newStatus, features, featureComponents, featErrs, metav1.NewTime(time.Now()))
+
 	// ------------------------------
 	// Create and update dependencies
 	// ------------------------------
 	errs = append(errs, depsStore.Apply(ctx, r.client)...)
 	if len(errs) > 0 {
 		logger.V(2).Info("Dependencies apply error", "errs", errs)
-		return result, errors.NewAggregate(errs)
+		return r.updateStatusIfNeededV2(logger, instance, newStatus, result, errors.NewAggregate(errs))
 	}
 
 	// -----------------------------
@@ -163,7 +194,7 @@ func (r *Reconciler) reconcileInstanceV2(ctx context.Context, logger logr.Logger
 	// -----------------------------
 	// Run it after the deployments reconcile
 	if errs = depsStore.Cleanup(ctx, r.client, instance.Namespace, instance.Name); len(errs) > 0 {
-		return result, errors.NewAggregate(errs)
+		return r.updateStatusIfNeededV2(logger, instance, newStatus, result, errors.NewAggregate(errs))
 	}
 
 	// Always requeue
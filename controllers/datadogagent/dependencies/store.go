@@ -8,11 +8,13 @@ package dependencies
 import (
 	"context"
 	"fmt"
+	"sort"
 	"strings"
 	"sync"
 
 	"github.com/DataDog/datadog-operator/controllers/datadogagent/component"
 	"github.com/DataDog/datadog-operator/controllers/datadogagent/object"
+	"github.com/DataDog/datadog-operator/pkg/controller/utils/datadog"
 	"github.com/DataDog/datadog-operator/pkg/equality"
 	"github.com/DataDog/datadog-operator/pkg/kubernetes"
 	"github.com/go-logr/logr"
@@ -26,12 +28,15 @@ import (
 	"k8s.io/apimachinery/pkg/selection"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/version"
+	"k8s.io/client-go/tools/record"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
 const (
 	// operatorStoreLabelKey used to identified which resource is managed by the store.
 	operatorStoreLabelKey = "operator.datadoghq.com/managed-by-store"
+	// maxEventMessageLength is the maximum length of the diff reported in an event message.
+	maxEventMessageLength = 512
 )
 
 // StoreClient dependencies store client interface
@@ -58,6 +63,8 @@ func NewStore(owner metav1.Object, options *StoreOptions) *Store {
 		store.platformInfo = options.PlatformInfo
 		store.logger = options.Logger
 		store.scheme = options.Scheme
+		store.recorder = options.EventRecorder
+		store.verboseDiff = options.VerboseDiff
 	}
 
 	return store
@@ -73,9 +80,11 @@ type Store struct {
 	versionInfo   *version.Info
 	platformInfo  kubernetes.PlatformInfo
 
-	scheme *runtime.Scheme
-	logger logr.Logger
-	owner  metav1.Object
+	scheme      *runtime.Scheme
+	logger      logr.Logger
+	owner       metav1.Object
+	recorder    record.EventRecorder
+	verboseDiff bool
 }
 
 // StoreOptions use to provide to NewStore() function some Store creation options.
@@ -86,6 +95,11 @@ type StoreOptions struct {
 
 	Scheme *runtime.Scheme
 	Logger logr.Logger
+
+	// EventRecorder is used to report the diff of each updated object as an event on the Store owner.
+	EventRecorder record.EventRecorder
+	// VerboseDiff enables logging the full diff of each updated object.
+	VerboseDiff bool
 }
 
 // AddOrUpdate used to add or update an object in the Store
@@ -196,14 +210,90 @@ func (ds *Store) Delete(kind kubernetes.ObjectKind, namespace string, name strin
 	return false
 }
 
+// Objects returns every object present in the Store.
+// Objects are sorted by kind, then by `namespace/name` identifier, to always return them in the same order.
+func (ds *Store) Objects() []client.Object {
+	ds.mutex.RLock()
+	defer ds.mutex.RUnlock()
+
+	kinds := make([]kubernetes.ObjectKind, 0, len(ds.deps))
+	for kind := range ds.deps {
+		kinds = append(kinds, kind)
+	}
+	sort.Slice(kinds, func(i, j int) bool {
+		return kinds[i] < kinds[j]
+	})
+
+	var objs []client.Object
+	for _, kind := range kinds {
+		ids := make([]string, 0, len(ds.deps[kind]))
+		for id := range ds.deps[kind] {
+			ids = append(ids, id)
+		}
+		sort.Strings(ids)
+		for _, id := range ids {
+			objs = append(objs, ds.deps[kind][id])
+		}
+	}
+	return objs
+}
+
 // Apply use to create/update resources in the api-server
 func (ds *Store) Apply(ctx context.Context, k8sClient client.Client) []error {
 	ds.mutex.RLock()
 	defer ds.mutex.RUnlock()
 
+	objsToCreate, objsToUpdate, errs := ds.listChanges(ctx, k8sClient)
+
+	ds.logger.V(2).Info("dependencies.store objsToCreate", "nb", len(objsToCreate))
+	for _, change := range objsToCreate {
+		if err := k8sClient.Create(ctx, change.obj); err != nil {
+			ds.logger.Error(err, "dependencies.store Create", "obj.namespace", change.obj.GetNamespace(), "obj.name", change.obj.GetName())
+			errs = append(errs, err)
+		}
+	}
+
+	ds.logger.V(2).Info("dependencies.store objsToUpdate", "nb", len(objsToUpdate))
+	for _, change := range objsToUpdate {
+		if err := k8sClient.Update(ctx, change.obj); err != nil {
+			ds.logger.Error(err, "dependencies.store Update", "obj.namespace", change.obj.GetNamespace(), "obj.name", change.obj.GetName())
+			errs = append(errs, err)
+			continue
+		}
+		ds.reportDiff(change.diff)
+	}
+	return errs
+}
+
+// Diff returns the pending changes between the Store and the api-server, without applying them.
+func (ds *Store) Diff(ctx context.Context, k8sClient client.Client) ([]equality.ObjectDiff, []error) {
+	ds.mutex.RLock()
+	defer ds.mutex.RUnlock()
+
+	objsToCreate, objsToUpdate, errs := ds.listChanges(ctx, k8sClient)
+
+	diffs := make([]equality.ObjectDiff, 0, len(objsToCreate)+len(objsToUpdate))
+	for _, change := range objsToCreate {
+		diffs = append(diffs, change.diff)
+	}
+	for _, change := range objsToUpdate {
+		diffs = append(diffs, change.diff)
+	}
+	return diffs, errs
+}
+
+// objectChange is an object to create or update in the api-server, with the corresponding diff.
+type objectChange struct {
+	obj  client.Object
+	diff equality.ObjectDiff
+}
+
+// listChanges returns the objects to create and the objects to update in the api-server.
+// The caller must hold the Store mutex.
+func (ds *Store) listChanges(ctx context.Context, k8sClient client.Client) ([]objectChange, []objectChange, []error) {
 	var errs []error
-	var objsToCreate []client.Object
-	var objsToUpdate []client.Object
+	var objsToCreate []objectChange
+	var objsToUpdate []objectChange
 	for kind := range ds.deps {
 		for objID, objStore := range ds.deps[kind] {
 			objNSName := buildObjectKey(objID)
@@ -211,7 +301,15 @@ func (ds *Store) Apply(ctx context.Context, k8sClient client.Client) []error {
 			err := k8sClient.Get(ctx, objNSName, objAPIServer)
 			if err != nil && apierrors.IsNotFound(err) {
 				ds.logger.V(2).Info("dependencies.store Add object to create", "obj.namespace", objStore.GetNamespace(), "obj.name", objStore.GetName(), "obj.kind", kind)
-				objsToCreate = append(objsToCreate, objStore)
+				objsToCreate = append(objsToCreate, objectChange{
+					obj: objStore,
+					diff: equality.ObjectDiff{
+						Kind:      string(kind),
+						Namespace: objStore.GetNamespace(),
+						Name:      objStore.GetName(),
+						Create:    true,
+					},
+				})
 				continue
 			} else if err != nil {
 				errs = append(errs, err)
@@ -231,28 +329,49 @@ func (ds *Store) Apply(ctx context.Context, k8sClient client.Client) []error {
 
 			if !equality.IsEqualObject(kind, objStore, objAPIServer) {
 				ds.logger.V(2).Info("dependencies.store Add object to update", "obj.namespace", objStore.GetNamespace(), "obj.name", objStore.GetName(), "obj.kind", kind)
-				objsToUpdate = append(objsToUpdate, objStore)
+				objsToUpdate = append(objsToUpdate, objectChange{
+					obj: objStore,
+					diff: equality.ObjectDiff{
+						Kind:      string(kind),
+						Namespace: objStore.GetNamespace(),
+						Name:      objStore.GetName(),
+						Fields:    equality.DiffObject(kind, objStore, objAPIServer),
+					},
+				})
 				continue
 			}
 		}
 	}
+	return objsToCreate, objsToUpdate, errs
+}
 
-	ds.logger.V(2).Info("dependencies.store objsToCreate", "nb", len(objsToCreate))
-	for _, obj := range objsToCreate {
-		if err := k8sClient.Create(ctx, obj); err != nil {
-			ds.logger.Error(err, "dependencies.store Create", "obj.namespace", obj.GetNamespace(), "obj.name", obj.GetName())
-			errs = append(errs, err)
-		}
+// reportDiff records the diff of an updated object as an event on the Store owner,
+// and logs the full diff if the verbose diff option is enabled.
+func (ds *Store) reportDiff(diff equality.ObjectDiff) {
+	// An update can be triggered without any difference on the compared fields, for instance if the
+	// owner-reference differs. No need to report it.
+	if len(diff.Fields) == 0 {
+		return
 	}
 
-	ds.logger.V(2).Info("dependencies.store objsToUpdate", "nb", len(objsToUpdate))
-	for _, obj := range objsToUpdate {
-		if err := k8sClient.Update(ctx, obj); err != nil {
-			ds.logger.Error(err, "dependencies.store Update", "obj.namespace", obj.GetNamespace(), "obj.name", obj.GetName())
-			errs = append(errs, err)
+	if ds.verboseDiff {
+		for _, field := range diff.Fields {
+			ds.logger.Info("dependencies.store Update diff", "obj.kind", diff.Kind, "obj.namespace", diff.Namespace, "obj.name", diff.Name, "field", field.Path, "current", field.Current, "desired", field.Desired)
 		}
 	}
-	return errs
+
+	if ds.recorder == nil || ds.owner == nil {
+		return
+	}
+	owner, ok := ds.owner.(runtime.Object)
+	if !ok {
+		return
+	}
+	message := diff.String()
+	if len(message) > maxEventMessageLength {
+		message = message[:maxEventMessageLength] + "..."
+	}
+	ds.recorder.Event(owner, v1.EventTypeNormal, fmt.Sprintf("%s %s", datadog.UpdateEvent, diff.Kind), message)
 }
 
 // Cleanup use to cleanup resources that are not needed anymore
@@ -12,6 +12,7 @@ import (
 
 	"github.com/DataDog/datadog-operator/apis/datadoghq/v2alpha1"
 	testutils "github.com/DataDog/datadog-operator/controllers/datadogagent/testutils"
+	"github.com/DataDog/datadog-operator/pkg/equality"
 	"github.com/DataDog/datadog-operator/pkg/kubernetes"
 	assert "github.com/stretchr/testify/require"
 	corev1 "k8s.io/api/core/v1"
@@ -20,6 +21,7 @@ import (
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
 	apiregistrationv1 "k8s.io/kube-aggregator/pkg/apis/apiregistration/v1"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
@@ -391,6 +393,120 @@ func TestStore_Apply(t *testing.T) {
 	}
 }
 
+func TestStore_Diff(t *testing.T) {
+	configMap := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "bar",
+			Name:      "foo",
+		},
+		Data: map[string]string{
+			"data1": "value1",
+		},
+	}
+	configMapUpdated := configMap.DeepCopy()
+	configMapUpdated.Data["data1"] = "value2"
+
+	tests := []struct {
+		name      string
+		deps      map[kubernetes.ObjectKind]map[string]client.Object
+		k8sClient client.Client
+		want      []equality.ObjectDiff
+	}{
+		{
+			name:      "nothing to apply",
+			deps:      map[kubernetes.ObjectKind]map[string]client.Object{},
+			k8sClient: fake.NewClientBuilder().Build(),
+			want:      []equality.ObjectDiff{},
+		},
+		{
+			name: "one ConfigMap to create",
+			deps: map[kubernetes.ObjectKind]map[string]client.Object{
+				kubernetes.ConfigMapKind: {"bar/foo": configMap.DeepCopy()},
+			},
+			k8sClient: fake.NewClientBuilder().Build(),
+			want: []equality.ObjectDiff{
+				{Kind: string(kubernetes.ConfigMapKind), Namespace: "bar", Name: "foo", Create: true},
+			},
+		},
+		{
+			name: "one ConfigMap up to date",
+			deps: map[kubernetes.ObjectKind]map[string]client.Object{
+				kubernetes.ConfigMapKind: {"bar/foo": configMap.DeepCopy()},
+			},
+			k8sClient: fake.NewClientBuilder().WithObjects(configMap.DeepCopy()).Build(),
+			want:      []equality.ObjectDiff{},
+		},
+		{
+			name: "one ConfigMap to update",
+			deps: map[kubernetes.ObjectKind]map[string]client.Object{
+				kubernetes.ConfigMapKind: {"bar/foo": configMapUpdated.DeepCopy()},
+			},
+			k8sClient: fake.NewClientBuilder().WithObjects(configMap.DeepCopy()).Build(),
+			want: []equality.ObjectDiff{
+				{
+					Kind:      string(kubernetes.ConfigMapKind),
+					Namespace: "bar",
+					Name:      "foo",
+					Fields:    []equality.FieldDiff{{Path: "data.data1", Current: "value1", Desired: "value2"}},
+				},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ds := &Store{
+				deps:   tt.deps,
+				logger: logf.Log.WithName(t.Name()),
+			}
+			got, errs := ds.Diff(context.TODO(), tt.k8sClient)
+			assert.Empty(t, errs)
+			assert.Equal(t, tt.want, got)
+
+			// Diff must not modify the api-server objects
+			assert.Empty(t, ds.Apply(context.TODO(), tt.k8sClient))
+			got, errs = ds.Diff(context.TODO(), tt.k8sClient)
+			assert.Empty(t, errs)
+			assert.Empty(t, got)
+		})
+	}
+}
+
+func TestStore_ApplyRecordsDiffEvent(t *testing.T) {
+	owner := &v2alpha1.DatadogAgent{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "bar",
+			Name:      "dda",
+		},
+	}
+	configMap := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "bar",
+			Name:      "foo",
+		},
+		Data: map[string]string{
+			"data1": "value1",
+		},
+	}
+	configMapUpdated := configMap.DeepCopy()
+	configMapUpdated.Data["data1"] = "value2"
+
+	recorder := record.NewFakeRecorder(10)
+	ds := &Store{
+		deps: map[kubernetes.ObjectKind]map[string]client.Object{
+			kubernetes.ConfigMapKind: {"bar/foo": configMapUpdated},
+		},
+		logger:      logf.Log.WithName(t.Name()),
+		owner:       owner,
+		recorder:    recorder,
+		verboseDiff: true,
+	}
+	errs := ds.Apply(context.TODO(), fake.NewClientBuilder().WithObjects(configMap).Build())
+	assert.Empty(t, errs)
+
+	assert.Len(t, recorder.Events, 1)
+	assert.Equal(t, "Normal Update configmaps configmaps bar/foo: data.data1", <-recorder.Events)
+}
+
 func TestStore_Cleanup(t *testing.T) {
 	dummyConfigMap1 := &corev1.ConfigMap{
 		TypeMeta: metav1.TypeMeta{
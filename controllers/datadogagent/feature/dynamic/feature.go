@@ -0,0 +1,207 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package dynamic
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+
+	apicommon "github.com/DataDog/datadog-operator/apis/datadoghq/common"
+	"github.com/DataDog/datadog-operator/apis/datadoghq/v1alpha1"
+	"github.com/DataDog/datadog-operator/apis/datadoghq/v2alpha1"
+	"github.com/DataDog/datadog-operator/controllers/datadogagent/feature"
+)
+
+const (
+	// IDPrefix is the prefix of the ID of the features built from a DatadogAgentFeature
+	IDPrefix = "dynamic."
+
+	// MaxNameLength is the maximum length of the name of a DatadogAgentFeature, so that the
+	// reconcile condition type of its feature is a valid qualified name
+	MaxNameLength = 63 - len(IDPrefix)
+)
+
+// ID returns the ID of the feature built from a DatadogAgentFeature
+func ID(name string) feature.IDType {
+	return feature.IDType(IDPrefix + name)
+}
+
+// Select returns the DatadogAgentFeatures applying to the DatadogAgent, sorted by name.
+// The DatadogAgentFeatures with an invalid selector are returned too, to report the error.
+func Select(dda metav1.Object, ddafs []v2alpha1.DatadogAgentFeature) []v2alpha1.DatadogAgentFeature {
+	var selected []v2alpha1.DatadogAgentFeature
+	for _, ddaf := range ddafs {
+		if ddaf.Spec.Selector == nil {
+			selected = append(selected, ddaf)
+			continue
+		}
+		selector, err := metav1.LabelSelectorAsSelector(ddaf.Spec.Selector)
+		if err != nil || selector.Matches(labels.Set(dda.GetLabels())) {
+			selected = append(selected, ddaf)
+		}
+	}
+	sort.Slice(selected, func(i, j int) bool {
+		return selected[i].Name < selected[j].Name
+	})
+
+	return selected
+}
+
+// NewFeature returns the feature built from a DatadogAgentFeature
+func NewFeature(ddaf *v2alpha1.DatadogAgentFeature, options *feature.Options) feature.Feature {
+	dynamicFeat := &dynamicFeature{
+		name: ddaf.Name,
+		spec: ddaf.Spec.DeepCopy(),
+	}
+
+	if options != nil {
+		dynamicFeat.logger = options.Logger
+	}
+
+	return dynamicFeat
+}
+
+type dynamicFeature struct {
+	name string
+	spec *v2alpha1.DatadogAgentFeatureSpec
+
+	owner          *v2alpha1.DatadogAgent
+	logger         logr.Logger
+	serviceAccount map[v2alpha1.ComponentName]string
+	// err is the validation error of the DatadogAgentFeature, reported by ManageDependencies
+	err error
+}
+
+// ID returns the ID of the Feature
+func (f *dynamicFeature) ID() feature.IDType {
+	return ID(f.name)
+}
+
+// Configure is used to configure the feature from a v2alpha1.DatadogAgent instance.
+// A DatadogAgentFeature doesn't require any component: it only adds to the components enabled by the DatadogAgent.
+func (f *dynamicFeature) Configure(dda *v2alpha1.DatadogAgent) (reqComp feature.RequiredComponents) {
+	f.owner = dda
+	f.serviceAccount = map[v2alpha1.ComponentName]string{
+		v2alpha1.NodeAgentComponentName:           v2alpha1.GetAgentServiceAccount(dda),
+		v2alpha1.ClusterAgentComponentName:        v2alpha1.GetClusterAgentServiceAccount(dda),
+		v2alpha1.ClusterChecksRunnerComponentName: v2alpha1.GetClusterChecksRunnerServiceAccount(dda),
+	}
+	if err := v2alpha1.IsValidDatadogAgentFeature(f.spec); err != nil {
+		f.err = fmt.Errorf("invalid DatadogAgentFeature %s: %w", f.name, err)
+	}
+
+	return reqComp
+}
+
+// ConfigureV1 use to configure the feature from a v1alpha1.DatadogAgent instance.
+// DatadogAgentFeatures only apply to v2alpha1.DatadogAgents.
+func (f *dynamicFeature) ConfigureV1(dda *v1alpha1.DatadogAgent) (reqComp feature.RequiredComponents) {
+	return reqComp
+}
+
+// ManageDependencies allows a feature to manage its dependencies.
+// Feature's dependencies should be added in the store.
+func (f *dynamicFeature) ManageDependencies(managers feature.ResourceManagers, components feature.RequiredComponents) error {
+	if f.err != nil {
+		return f.err
+	}
+
+	for _, configMap := range f.spec.ConfigMaps {
+		if err := managers.ConfigMapManager().AddConfigMap(configMap.Name, f.owner.GetNamespace(), configMap.Data); err != nil {
+			return err
+		}
+	}
+
+	for _, component := range []struct {
+		name    v2alpha1.ComponentName
+		suffix  string
+		enabled bool
+	}{
+		{v2alpha1.NodeAgentComponentName, apicommon.DefaultAgentResourceSuffix, components.Agent.IsEnabled()},
+		{v2alpha1.ClusterAgentComponentName, apicommon.DefaultClusterAgentResourceSuffix, components.ClusterAgent.IsEnabled()},
+		{v2alpha1.ClusterChecksRunnerComponentName, apicommon.DefaultClusterChecksRunnerResourceSuffix, components.ClusterChecksRunner.IsEnabled()},
+	} {
+		override := f.spec.Override[component.name]
+		if !component.enabled || override == nil || len(override.ClusterRules) == 0 {
+			continue
+		}
+		rbacName := getRBACResourceName(f.owner, f.name, component.suffix)
+		if err := managers.RBACManager().AddClusterPolicyRules(f.owner.GetNamespace(), rbacName, f.serviceAccount[component.name], override.ClusterRules); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// ManageClusterAgent allows a feature to configure the ClusterAgent's corev1.PodTemplateSpec
+// It should do nothing if the feature doesn't need to configure it.
+func (f *dynamicFeature) ManageClusterAgent(managers feature.PodTemplateManagers) error {
+	return f.manageComponent(managers, v2alpha1.ClusterAgentComponentName)
+}
+
+// ManageNodeAgent allows a feature to configure the Node Agent's corev1.PodTemplateSpec
+// It should do nothing if the feature doesn't need to configure it.
+func (f *dynamicFeature) ManageNodeAgent(managers feature.PodTemplateManagers) error {
+	return f.manageComponent(managers, v2alpha1.NodeAgentComponentName)
+}
+
+// ManageClusterChecksRunner allows a feature to configure the ClusterChecksRunner's corev1.PodTemplateSpec
+// It should do nothing if the feature doesn't need to configure it.
+func (f *dynamicFeature) ManageClusterChecksRunner(managers feature.PodTemplateManagers) error {
+	return f.manageComponent(managers, v2alpha1.ClusterChecksRunnerComponentName)
+}
+
+// manageComponent adds the override of a component to its corev1.PodTemplateSpec.
+// The env vars and volume mounts are added before the sidecars, so that the sidecars don't get them.
+// An invalid DatadogAgentFeature isn't applied, its error is reported by ManageDependencies.
+func (f *dynamicFeature) manageComponent(managers feature.PodTemplateManagers, component v2alpha1.ComponentName) error {
+	override := f.spec.Override[component]
+	if override == nil || f.err != nil {
+		return nil
+	}
+
+	for i := range override.Env {
+		managers.EnvVar().AddEnvVar(&override.Env[i])
+	}
+	for i := range override.VolumeMounts {
+		managers.VolumeMount().AddVolumeMount(&override.VolumeMounts[i])
+	}
+	for i := range override.Volumes {
+		managers.Volume().AddVolume(&override.Volumes[i])
+	}
+	for key, value := range override.Annotations {
+		managers.Annotation().AddAnnotation(key, value)
+	}
+
+	podSpec := &managers.PodTemplateSpec().Spec
+	for _, sidecar := range override.Sidecars {
+		addSidecar(podSpec, sidecar)
+	}
+
+	return nil
+}
+
+// addSidecar adds a container to the pod, or replaces the container with the same name
+func addSidecar(podSpec *corev1.PodSpec, sidecar corev1.Container) {
+	for i := range podSpec.Containers {
+		if podSpec.Containers[i].Name == sidecar.Name {
+			podSpec.Containers[i] = *sidecar.DeepCopy()
+			return
+		}
+	}
+	podSpec.Containers = append(podSpec.Containers, *sidecar.DeepCopy())
+}
+
+// getRBACResourceName return the RBAC resources name of a component
+func getRBACResourceName(owner metav1.Object, name, suffix string) string {
+	return fmt.Sprintf("%s-%s-%s-%s", owner.GetNamespace(), owner.GetName(), name, suffix)
+}
@@ -0,0 +1,124 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package dynamic
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	"github.com/DataDog/datadog-operator/apis/datadoghq/v2alpha1"
+	apiutils "github.com/DataDog/datadog-operator/apis/utils"
+	"github.com/DataDog/datadog-operator/controllers/datadogagent/dependencies"
+	"github.com/DataDog/datadog-operator/controllers/datadogagent/feature"
+	testutils "github.com/DataDog/datadog-operator/controllers/datadogagent/testutils"
+	"github.com/DataDog/datadog-operator/pkg/kubernetes"
+)
+
+func newDatadogAgentFeature(name string, selector *metav1.LabelSelector) v2alpha1.DatadogAgentFeature {
+	return v2alpha1.DatadogAgentFeature{
+		ObjectMeta: metav1.ObjectMeta{Name: name},
+		Spec:       v2alpha1.DatadogAgentFeatureSpec{Selector: selector},
+	}
+}
+
+func TestSelect(t *testing.T) {
+	dda := &v2alpha1.DatadogAgent{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "bar", Name: "foo", Labels: map[string]string{"team": "platform"}},
+	}
+	ddafs := []v2alpha1.DatadogAgentFeature{
+		newDatadogAgentFeature("platform", &metav1.LabelSelector{MatchLabels: map[string]string{"team": "platform"}}),
+		newDatadogAgentFeature("other", &metav1.LabelSelector{MatchLabels: map[string]string{"team": "other"}}),
+		newDatadogAgentFeature("all", nil),
+		newDatadogAgentFeature("invalid", &metav1.LabelSelector{MatchExpressions: []metav1.LabelSelectorRequirement{{Key: "team", Operator: "Foo"}}}),
+	}
+
+	var names []string
+	for _, ddaf := range Select(dda, ddafs) {
+		names = append(names, ddaf.Name)
+	}
+	assert.Equal(t, []string{"all", "invalid", "platform"}, names)
+}
+
+func Test_dynamicFeature(t *testing.T) {
+	dda := &v2alpha1.DatadogAgent{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "bar", Name: "foo"},
+	}
+	clusterRules := []rbacv1.PolicyRule{{APIGroups: []string{"platform.example.com"}, Resources: []string{"widgets"}, Verbs: []string{"get"}}}
+	ddaf := newDatadogAgentFeature("platform", nil)
+	ddaf.Spec.Override = map[v2alpha1.ComponentName]*v2alpha1.DatadogAgentFeatureComponentOverride{
+		v2alpha1.NodeAgentComponentName: {
+			Env:          []corev1.EnvVar{{Name: "DD_PLATFORM", Value: "true"}},
+			VolumeMounts: []corev1.VolumeMount{{Name: "platform", MountPath: "/etc/platform"}},
+			Volumes:      []corev1.Volume{{Name: "platform"}},
+			Sidecars:     []corev1.Container{{Name: "log-shipper", Image: "busybox:1.35"}},
+			ClusterRules: clusterRules,
+		},
+		v2alpha1.ClusterChecksRunnerComponentName: {ClusterRules: clusterRules},
+	}
+
+	feat := NewFeature(&ddaf, &feature.Options{Logger: logf.Log})
+	assert.Equal(t, feature.IDType("dynamic.platform"), feat.ID())
+	reqComp := feat.Configure(dda)
+	assert.False(t, reqComp.IsEnabled())
+
+	// The ClusterRoles are only created for the enabled components
+	store := dependencies.NewStore(dda, &dependencies.StoreOptions{Logger: logf.Log, Scheme: testutils.TestScheme(true)})
+	components := feature.RequiredComponents{Agent: feature.RequiredComponent{IsRequired: apiutils.NewBoolPointer(true)}}
+	assert.NoError(t, feat.ManageDependencies(feature.NewResourceManagers(store), components))
+	_, found := store.Get(kubernetes.ClusterRolesKind, "", "bar-foo-platform-agent")
+	assert.True(t, found)
+	_, found = store.Get(kubernetes.ClusterRolesKind, "", "bar-foo-platform-cluster-checks-runner")
+	assert.False(t, found)
+
+	// The sidecars replace the containers with the same name, and don't get the env vars and volume mounts
+	podTmpl := &corev1.PodTemplateSpec{Spec: corev1.PodSpec{Containers: []corev1.Container{
+		{Name: "agent"},
+		{Name: "log-shipper", Image: "busybox:1.34"},
+	}}}
+	assert.NoError(t, feat.ManageNodeAgent(feature.NewPodTemplateManagers(podTmpl)))
+	assert.Equal(t, []corev1.Volume{{Name: "platform"}}, podTmpl.Spec.Volumes)
+	if assert.Len(t, podTmpl.Spec.Containers, 2) {
+		assert.Equal(t, []corev1.EnvVar{{Name: "DD_PLATFORM", Value: "true"}}, podTmpl.Spec.Containers[0].Env)
+		assert.Equal(t, []corev1.VolumeMount{{Name: "platform", MountPath: "/etc/platform"}}, podTmpl.Spec.Containers[0].VolumeMounts)
+		assert.Equal(t, corev1.Container{Name: "log-shipper", Image: "busybox:1.35"}, podTmpl.Spec.Containers[1])
+	}
+
+	// The components without override are left unchanged
+	podTmpl = &corev1.PodTemplateSpec{Spec: corev1.PodSpec{Containers: []corev1.Container{{Name: "cluster-agent"}}}}
+	assert.NoError(t, feat.ManageClusterAgent(feature.NewPodTemplateManagers(podTmpl)))
+	assert.Equal(t, []corev1.Container{{Name: "cluster-agent"}}, podTmpl.Spec.Containers)
+}
+
+func Test_dynamicFeature_invalid(t *testing.T) {
+	dda := &v2alpha1.DatadogAgent{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "bar", Name: "foo"},
+	}
+	ddaf := newDatadogAgentFeature("platform", nil)
+	ddaf.Spec.Override = map[v2alpha1.ComponentName]*v2alpha1.DatadogAgentFeatureComponentOverride{
+		v2alpha1.NodeAgentComponentName: {
+			Env:      []corev1.EnvVar{{Name: "DD_PLATFORM", Value: "true"}},
+			Sidecars: []corev1.Container{{Name: "log-shipper"}},
+		},
+	}
+
+	feat := NewFeature(&ddaf, nil)
+	feat.Configure(dda)
+
+	// The error is reported by ManageDependencies, and the feature isn't applied
+	store := dependencies.NewStore(dda, &dependencies.StoreOptions{Logger: logf.Log, Scheme: testutils.TestScheme(true)})
+	err := feat.ManageDependencies(feature.NewResourceManagers(store), feature.RequiredComponents{})
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), "invalid DatadogAgentFeature platform: invalid spec.override.nodeAgent.sidecars[0]")
+	}
+	podTmpl := &corev1.PodTemplateSpec{Spec: corev1.PodSpec{Containers: []corev1.Container{{Name: "agent"}}}}
+	assert.NoError(t, feat.ManageNodeAgent(feature.NewPodTemplateManagers(podTmpl)))
+	assert.Equal(t, []corev1.Container{{Name: "agent"}}, podTmpl.Spec.Containers)
+}
@@ -29,7 +29,10 @@ import (
 )
 
 func init() {
-	err := feature.Register(feature.ExternalMetricsIDType, buildExternalMetricsFeature)
+	// The external metrics server is served by the Cluster Agent, configured by the default feature
+	err := feature.RegisterWithRelations(feature.ExternalMetricsIDType, buildExternalMetricsFeature, feature.Relations{
+		Requires: []feature.IDType{feature.DefaultIDType},
+	})
 	if err != nil {
 		panic(err)
 	}
@@ -16,6 +16,7 @@ import (
 
 func init() {
 	featureBuilders = map[IDType]BuildFunc{}
+	featureRelations = map[IDType]Relations{}
 }
 
 // Register use to register a Feature to the Feature factory.
@@ -30,12 +31,33 @@ func Register(id IDType, buildFunc BuildFunc) error {
 	return nil
 }
 
-// BuildFeatures use to build a list features depending of the v1alpha1.DatadogAgent instance
+// RegisterWithRelations use to register a Feature to the Feature factory, with its relations to the other Features.
+func RegisterWithRelations(id IDType, buildFunc BuildFunc, relations Relations) error {
+	if err := Register(id, buildFunc); err != nil {
+		return err
+	}
+
+	builderMutex.Lock()
+	defer builderMutex.Unlock()
+	featureRelations[id] = relations
+	return nil
+}
+
+// BuildFeatures use to build a list features depending of the v1alpha1.DatadogAgent instance.
+// The relations between the features are resolved, but the relation errors are ignored.
 func BuildFeatures(dda *v2alpha1.DatadogAgent, options *Options) ([]Feature, RequiredComponents) {
+	output, _, requiredComponents, _ := BuildFeaturesWithComponents(dda, options)
+
+	return output, requiredComponents
+}
+
+// BuildFeaturesWithComponents use to build a list features depending of the v2alpha1.DatadogAgent instance.
+// It also returns the RequiredComponents of each enabled feature, indexed by feature ID, and the errors of the
+// relations between the features that can't be satisfied, as *RelationError.
+func BuildFeaturesWithComponents(dda *v2alpha1.DatadogAgent, options *Options) ([]Feature, map[IDType]RequiredComponents, RequiredComponents, error) {
 	builderMutex.RLock()
 	defer builderMutex.RUnlock()
 
-	var output []Feature
 	var requiredComponents RequiredComponents
 
 	// to always return in feature in the same order we need to sort the map keys
@@ -47,17 +69,31 @@ func BuildFeatures(dda *v2alpha1.DatadogAgent, options *Options) ([]Feature, Req
 		return sortedkeys[i] < sortedkeys[j]
 	})
 
+	graph := newFeatureGraph(featureRelations)
 	for _, id := range sortedkeys {
 		feat := featureBuilders[id](options)
 		config := feat.Configure(dda)
-		// only add feat to the output if the feature is enabled
-		if config.IsEnabled() {
-			output = append(output, feat)
-		}
+		graph.add(feat, config)
 		requiredComponents.Merge(&config)
 	}
 
-	return output, requiredComponents
+	// enable the features implied by the enabled features
+	for _, config := range graph.resolveImplied(dda) {
+		requiredComponents.Merge(&config)
+	}
+	err := graph.validate()
+
+	output := make([]Feature, 0, len(graph.enabled))
+	for _, id := range sortedkeys {
+		if graph.enabled[id] {
+			output = append(output, graph.features[id])
+		}
+	}
+	if len(output) == 0 {
+		output = nil
+	}
+
+	return output, graph.components, requiredComponents, err
 }
 
 // BuildFeaturesV1 use to build a list features depending of the v1alpha1.DatadogAgent instance
@@ -91,6 +127,7 @@ func BuildFeaturesV1(dda *v1alpha1.DatadogAgent, options *Options) ([]Feature, R
 }
 
 var (
-	featureBuilders map[IDType]BuildFunc
-	builderMutex    sync.RWMutex
+	featureBuilders  map[IDType]BuildFunc
+	featureRelations map[IDType]Relations
+	builderMutex     sync.RWMutex
 )
@@ -67,6 +67,25 @@ func (f *npmFeature) Configure(dda *v2alpha1.DatadogAgent) (reqComp feature.Requ
 	return reqComp
 }
 
+// ConfigureImplied is used to configure the feature when it is implied by another feature, with the NPM settings
+// of the DatadogAgent and their default values. It isn't configured if NPM is disabled explicitly.
+func (f *npmFeature) ConfigureImplied(dda *v2alpha1.DatadogAgent) (reqComp feature.RequiredComponents) {
+	implied := dda.DeepCopy()
+	if implied.Spec.Features == nil {
+		implied.Spec.Features = &v2alpha1.DatadogFeatures{}
+	}
+	if implied.Spec.Features.NPM == nil {
+		implied.Spec.Features.NPM = &v2alpha1.NPMFeatureConfig{}
+	}
+	if implied.Spec.Features.NPM.Enabled != nil {
+		return reqComp
+	}
+	implied.Spec.Features.NPM.Enabled = apiutils.NewBoolPointer(true)
+	v2alpha1.DefaultDatadogAgent(implied)
+
+	return f.Configure(implied)
+}
+
 // ConfigureV1 use to configure the feature from a v1alpha1.DatadogAgent instance.
 func (f *npmFeature) ConfigureV1(dda *v1alpha1.DatadogAgent) (reqComp feature.RequiredComponents) {
 	if dda.Spec.Features.NetworkMonitoring != nil && *dda.Spec.Features.NetworkMonitoring.Enabled {
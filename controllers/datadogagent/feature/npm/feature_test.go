@@ -257,3 +257,26 @@ func Test_npmFeature_Configure(t *testing.T) {
 
 	tests.Run(t, buildNPMFeature)
 }
+
+func Test_npmFeature_ConfigureImplied(t *testing.T) {
+	// The NPM settings of the DatadogAgent are kept, the others are defaulted
+	dda := &v2alpha1.DatadogAgent{
+		Spec: v2alpha1.DatadogAgentSpec{
+			Features: &v2alpha1.DatadogFeatures{
+				NPM: &v2alpha1.NPMFeatureConfig{CollectDNSStats: apiutils.NewBoolPointer(false)},
+			},
+		},
+	}
+	f := buildNPMFeature(nil).(*npmFeature)
+	reqComp := f.ConfigureImplied(dda)
+	assert.True(t, reqComp.IsEnabled())
+	assert.False(t, f.collectDNSStats)
+	assert.True(t, f.enableConntrack)
+	assert.Nil(t, dda.Spec.Features.NPM.Enabled, "ConfigureImplied must not modify the DatadogAgent")
+
+	// NPM can't be implied when disabled explicitly
+	dda.Spec.Features.NPM.Enabled = apiutils.NewBoolPointer(false)
+	f = buildNPMFeature(nil).(*npmFeature)
+	reqComp = f.ConfigureImplied(dda)
+	assert.False(t, reqComp.IsEnabled())
+}
@@ -0,0 +1,171 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package feature
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/DataDog/datadog-operator/apis/datadoghq/v2alpha1"
+
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+)
+
+// Relations use to declare the relations of a Feature with the other Features, on top of the components they require.
+type Relations struct {
+	// Requires lists the Features that need to be enabled when the Feature is enabled.
+	Requires []IDType
+	// Conflicts lists the Features that can't be enabled with the Feature.
+	Conflicts []IDType
+	// Implies lists the Features enabled by the Feature, even if they are not enabled in the DatadogAgent.
+	// The implied Features need to implement ImpliedFeature.
+	Implies []IDType
+}
+
+// ImpliedFeature is implemented by the Features that can be enabled by another Feature implying them.
+type ImpliedFeature interface {
+	// ConfigureImplied use to configure the internal of a Feature that isn't enabled in the DatadogAgent,
+	// with its default settings.
+	ConfigureImplied(dda *v2alpha1.DatadogAgent) RequiredComponents
+}
+
+// RelationType is the type of a relation between two Features
+type RelationType string
+
+const (
+	// RequiresRelationType the Feature requires another Feature
+	RequiresRelationType RelationType = "requires"
+	// ConflictsRelationType the Feature conflicts with another Feature
+	ConflictsRelationType RelationType = "conflicts with"
+	// ImpliesRelationType the Feature implies another Feature
+	ImpliesRelationType RelationType = "implies"
+)
+
+// RelationError is returned when a relation between two Features can't be satisfied
+type RelationError struct {
+	// ID of the Feature declaring the relation
+	ID IDType
+	// Relation is the type of the relation
+	Relation RelationType
+	// Other is the ID of the other Feature of the relation
+	Other IDType
+	// Reason explains why the relation can't be satisfied
+	Reason string
+}
+
+func (e *RelationError) Error() string {
+	return fmt.Sprintf("feature %s %s feature %s, %s", e.ID, e.Relation, e.Other, e.Reason)
+}
+
+// featureGraph use to resolve the relations between the Features
+type featureGraph struct {
+	relations  map[IDType]Relations
+	features   map[IDType]Feature
+	enabled    map[IDType]bool
+	components map[IDType]RequiredComponents
+	errs       []error
+}
+
+func newFeatureGraph(relations map[IDType]Relations) *featureGraph {
+	return &featureGraph{
+		relations:  relations,
+		features:   map[IDType]Feature{},
+		enabled:    map[IDType]bool{},
+		components: map[IDType]RequiredComponents{},
+	}
+}
+
+// add adds a configured Feature to the graph
+func (g *featureGraph) add(feat Feature, config RequiredComponents) {
+	g.features[feat.ID()] = feat
+	if config.IsEnabled() {
+		g.enable(feat.ID(), config)
+	}
+}
+
+func (g *featureGraph) enable(id IDType, config RequiredComponents) {
+	g.enabled[id] = true
+	g.components[id] = config
+}
+
+// sortedEnabled returns the IDs of the enabled Features, sorted
+func (g *featureGraph) sortedEnabled() []IDType {
+	ids := make([]IDType, 0, len(g.enabled))
+	for id := range g.enabled {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool {
+		return ids[i] < ids[j]
+	})
+
+	return ids
+}
+
+// resolveImplied enables the Features implied by the enabled Features, until all the implications are resolved.
+// It returns the RequiredComponents of the Features it enabled.
+func (g *featureGraph) resolveImplied(dda *v2alpha1.DatadogAgent) []RequiredComponents {
+	var configs []RequiredComponents
+	failed := map[IDType]bool{}
+	for changed := true; changed; {
+		changed = false
+		for _, id := range g.sortedEnabled() {
+			for _, other := range g.relations[id].Implies {
+				if g.enabled[other] || failed[other] {
+					continue
+				}
+				feat, found := g.features[other]
+				if !found {
+					g.errs = append(g.errs, &RelationError{ID: id, Relation: ImpliesRelationType, Other: other, Reason: "which isn't registered"})
+					failed[other] = true
+					continue
+				}
+				implied, ok := feat.(ImpliedFeature)
+				if !ok {
+					g.errs = append(g.errs, &RelationError{ID: id, Relation: ImpliesRelationType, Other: other, Reason: "which needs to be enabled in the DatadogAgent"})
+					failed[other] = true
+					continue
+				}
+				config := implied.ConfigureImplied(dda)
+				if !config.IsEnabled() {
+					g.errs = append(g.errs, &RelationError{ID: id, Relation: ImpliesRelationType, Other: other, Reason: "which can't be enabled with this DatadogAgent"})
+					failed[other] = true
+					continue
+				}
+				g.enable(other, config)
+				configs = append(configs, config)
+				changed = true
+			}
+		}
+	}
+
+	return configs
+}
+
+// validate checks the requires and conflicts relations of the enabled Features
+func (g *featureGraph) validate() error {
+	errs := g.errs
+	reported := map[[2]IDType]bool{}
+	for _, id := range g.sortedEnabled() {
+		for _, other := range g.relations[id].Requires {
+			if !g.enabled[other] {
+				errs = append(errs, &RelationError{ID: id, Relation: RequiresRelationType, Other: other, Reason: "which isn't enabled"})
+			}
+		}
+		for _, other := range g.relations[id].Conflicts {
+			// Report a conflict once, even if both Features declare it
+			pair := [2]IDType{id, other}
+			if other < id {
+				pair = [2]IDType{other, id}
+			}
+			if g.enabled[other] && !reported[pair] {
+				reported[pair] = true
+				errs = append(errs, &RelationError{ID: id, Relation: ConflictsRelationType, Other: other, Reason: "which is enabled"})
+			}
+		}
+	}
+
+	return utilerrors.NewAggregate(errs)
+}
@@ -0,0 +1,179 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package feature
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/DataDog/datadog-operator/apis/datadoghq/v1alpha1"
+	"github.com/DataDog/datadog-operator/apis/datadoghq/v2alpha1"
+	apiutils "github.com/DataDog/datadog-operator/apis/utils"
+)
+
+type testFeature struct {
+	id      IDType
+	enabled bool
+}
+
+func (f *testFeature) ID() IDType { return f.id }
+
+func (f *testFeature) Configure(dda *v2alpha1.DatadogAgent) RequiredComponents {
+	if !f.enabled {
+		return RequiredComponents{}
+	}
+	return RequiredComponents{Agent: RequiredComponent{IsRequired: apiutils.NewBoolPointer(true)}}
+}
+
+func (f *testFeature) ConfigureV1(dda *v1alpha1.DatadogAgent) RequiredComponents {
+	return RequiredComponents{}
+}
+
+func (f *testFeature) ManageDependencies(managers ResourceManagers, components RequiredComponents) error {
+	return nil
+}
+
+func (f *testFeature) ManageClusterAgent(managers PodTemplateManagers) error { return nil }
+
+func (f *testFeature) ManageNodeAgent(managers PodTemplateManagers) error { return nil }
+
+func (f *testFeature) ManageClusterChecksRunner(managers PodTemplateManagers) error { return nil }
+
+type testImpliedFeature struct {
+	testFeature
+}
+
+func (f *testImpliedFeature) ConfigureImplied(dda *v2alpha1.DatadogAgent) RequiredComponents {
+	return RequiredComponents{ClusterAgent: RequiredComponent{IsRequired: apiutils.NewBoolPointer(true)}}
+}
+
+func Test_featureGraph(t *testing.T) {
+	tests := []struct {
+		name        string
+		features    []Feature
+		relations   map[IDType]Relations
+		wantEnabled []IDType
+		wantErr     []string
+	}{
+		{
+			name: "no relations",
+			features: []Feature{
+				&testFeature{id: "a", enabled: true},
+				&testFeature{id: "b"},
+			},
+			wantEnabled: []IDType{"a"},
+		},
+		{
+			name: "requirement satisfied",
+			features: []Feature{
+				&testFeature{id: "a", enabled: true},
+				&testFeature{id: "b", enabled: true},
+			},
+			relations:   map[IDType]Relations{"a": {Requires: []IDType{"b"}}},
+			wantEnabled: []IDType{"a", "b"},
+		},
+		{
+			name: "requirement not satisfied",
+			features: []Feature{
+				&testFeature{id: "a", enabled: true},
+				&testFeature{id: "b"},
+			},
+			relations:   map[IDType]Relations{"a": {Requires: []IDType{"b", "unknown"}}},
+			wantEnabled: []IDType{"a"},
+			wantErr: []string{
+				"feature a requires feature b, which isn't enabled",
+				"feature a requires feature unknown, which isn't enabled",
+			},
+		},
+		{
+			name: "requirement of a disabled feature",
+			features: []Feature{
+				&testFeature{id: "a"},
+				&testFeature{id: "b"},
+			},
+			relations: map[IDType]Relations{"a": {Requires: []IDType{"b"}}},
+		},
+		{
+			name: "conflict declared by both features",
+			features: []Feature{
+				&testFeature{id: "a", enabled: true},
+				&testFeature{id: "b", enabled: true},
+			},
+			relations: map[IDType]Relations{
+				"a": {Conflicts: []IDType{"b"}},
+				"b": {Conflicts: []IDType{"a"}},
+			},
+			wantEnabled: []IDType{"a", "b"},
+			wantErr:     []string{"feature a conflicts with feature b, which is enabled"},
+		},
+		{
+			name: "transitive implications",
+			features: []Feature{
+				&testFeature{id: "a", enabled: true},
+				&testImpliedFeature{testFeature{id: "b"}},
+				&testImpliedFeature{testFeature{id: "c"}},
+			},
+			relations: map[IDType]Relations{
+				"a": {Implies: []IDType{"b"}},
+				"b": {Implies: []IDType{"c"}},
+				"c": {Requires: []IDType{"a"}},
+			},
+			wantEnabled: []IDType{"a", "b", "c"},
+		},
+		{
+			name: "implied feature can't be enabled",
+			features: []Feature{
+				&testFeature{id: "a", enabled: true},
+				&testFeature{id: "b"},
+			},
+			relations:   map[IDType]Relations{"a": {Implies: []IDType{"b", "unknown"}}},
+			wantEnabled: []IDType{"a"},
+			wantErr: []string{
+				"feature a implies feature b, which needs to be enabled in the DatadogAgent",
+				"feature a implies feature unknown, which isn't registered",
+			},
+		},
+		{
+			name: "implied feature conflicting",
+			features: []Feature{
+				&testFeature{id: "a", enabled: true},
+				&testImpliedFeature{testFeature{id: "b"}},
+				&testFeature{id: "c", enabled: true},
+			},
+			relations: map[IDType]Relations{
+				"a": {Implies: []IDType{"b"}},
+				"c": {Conflicts: []IDType{"b"}},
+			},
+			wantEnabled: []IDType{"a", "b", "c"},
+			wantErr:     []string{"feature c conflicts with feature b, which is enabled"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			graph := newFeatureGraph(tt.relations)
+			for _, feat := range tt.features {
+				graph.add(feat, feat.Configure(nil))
+			}
+			graph.resolveImplied(nil)
+			err := graph.validate()
+
+			assert.ElementsMatch(t, tt.wantEnabled, graph.sortedEnabled())
+			for _, id := range tt.wantEnabled {
+				assert.Contains(t, graph.components, id)
+			}
+			if len(tt.wantErr) == 0 {
+				assert.NoError(t, err)
+				return
+			}
+			if assert.Error(t, err) {
+				for _, wantErr := range tt.wantErr {
+					assert.Contains(t, err.Error(), wantErr)
+				}
+			}
+		})
+	}
+}
@@ -20,7 +20,10 @@ import (
 )
 
 func init() {
-	err := feature.Register(feature.USMIDType, buildUSMFeature)
+	// USM relies on the network tracer of system-probe, enabled by NPM
+	err := feature.RegisterWithRelations(feature.USMIDType, buildUSMFeature, feature.Relations{
+		Implies: []feature.IDType{feature.NPMIDType},
+	})
 	if err != nil {
 		panic(err)
 	}
@@ -84,6 +84,9 @@ func (r *Reconciler) finalizeDadV2(reqLogger logr.Logger, obj client.Object) {
 	// We need to apply the defaults to be able to delete the resources
 	// associated with those defaults.
 	dda := obj.(*datadoghqv2alpha1.DatadogAgent).DeepCopy()
+	if _, err := r.applyDatadogAgentTemplate(context.TODO(), dda); err != nil {
+		reqLogger.Info("Error applying the DatadogAgentTemplate while finalizing the DatadogAgent", "error", err)
+	}
 	datadoghqv2alpha1.DefaultDatadogAgent(dda)
 
 	if r.options.OperatorMetricsEnabled {
@@ -94,8 +97,13 @@ func (r *Reconciler) finalizeDadV2(reqLogger logr.Logger, obj client.Object) {
 	// delete, we figure out its dependencies, store them in the dependencies
 	// store, and then call the DeleteAll function of the store.
 
-	features, requiredComponents := feature.BuildFeatures(
-		dda, reconcilerOptionsToFeatureOptions(&r.options, reqLogger))
+	featureOptions := reconcilerOptionsToFeatureOptions(&r.options, reqLogger)
+	features, requiredComponents := feature.BuildFeatures(dda, featureOptions)
+	if ddafs, err := r.listDatadogAgentFeatures(context.TODO()); err != nil {
+		reqLogger.Info("Error listing the DatadogAgentFeatures while finalizing the DatadogAgent", "error", err)
+	} else {
+		features = append(features, buildDynamicFeaturesV2(reqLogger, dda, ddafs, featureOptions)...)
+	}
 
 	storeOptions := &dependencies.StoreOptions{
 		SupportCilium: r.options.SupportCilium,
@@ -116,7 +124,7 @@ func (r *Reconciler) finalizeDadV2(reqLogger logr.Logger, obj client.Object) {
 	}
 
 	// Examine user configuration to override any external dependencies (e.g. RBACs)
-	errs = append(errs, override.Dependencies(reqLogger, resourceManagers, dda)...)
+	errs = append(errs, override.Dependencies(reqLogger, resourceManagers, dda, requiredComponents)...)
 
 	if len(errs) > 0 {
 		reqLogger.Info("Errors calculating dependencies while finalizing the DatadogAgent", "errors", errs)
@@ -16,6 +16,10 @@ func DaemonSet(daemonSet *v1.DaemonSet, override *v2alpha1.DatadogAgentComponent
 	if override.Name != nil {
 		daemonSet.Name = *override.Name
 	}
+
+	if override.UpdateStrategy != nil {
+		daemonSetUpdateStrategy(&daemonSet.Spec.UpdateStrategy, override.UpdateStrategy)
+	}
 }
 
 // ExtendedDaemonSet overrides an ExtendedDaemonSet according to the given override options
@@ -23,4 +27,59 @@ func ExtendedDaemonSet(eds *edsv1alpha1.ExtendedDaemonSet, override *v2alpha1.Da
 	if override.Name != nil {
 		eds.Name = *override.Name
 	}
+
+	if override.UpdateStrategy != nil {
+		extendedDaemonSetStrategy(&eds.Spec.Strategy, override.UpdateStrategy)
+	}
+}
+
+func daemonSetUpdateStrategy(strategy *v1.DaemonSetUpdateStrategy, override *v2alpha1.DaemonSetUpdateStrategy) {
+	if override.Type != nil {
+		strategy.Type = *override.Type
+	}
+
+	// The rolling update configuration is rejected by the API server with the OnDelete strategy.
+	if strategy.Type == v1.OnDeleteDaemonSetStrategyType {
+		strategy.RollingUpdate = nil
+		return
+	}
+
+	if override.RollingUpdate != nil && override.RollingUpdate.MaxUnavailable != nil {
+		strategy.Type = v1.RollingUpdateDaemonSetStrategyType
+		if strategy.RollingUpdate == nil {
+			strategy.RollingUpdate = &v1.RollingUpdateDaemonSet{}
+		}
+		strategy.RollingUpdate.MaxUnavailable = override.RollingUpdate.MaxUnavailable
+	}
+}
+
+func extendedDaemonSetStrategy(strategy *edsv1alpha1.ExtendedDaemonSetSpecStrategy, override *v2alpha1.DaemonSetUpdateStrategy) {
+	if override.Canary != nil {
+		strategy.Canary = edsv1alpha1.DefaultExtendedDaemonSetSpecStrategyCanary(
+			override.Canary.DeepCopy(),
+			edsv1alpha1.ExtendedDaemonSetSpecStrategyCanaryValidationModeAuto,
+		)
+	}
+
+	if override.ReconcileFrequency != nil {
+		strategy.ReconcileFrequency = override.ReconcileFrequency.DeepCopy()
+	}
+
+	if rollingUpdate := override.RollingUpdate; rollingUpdate != nil {
+		if rollingUpdate.MaxUnavailable != nil {
+			strategy.RollingUpdate.MaxUnavailable = rollingUpdate.MaxUnavailable
+		}
+		if rollingUpdate.MaxPodSchedulerFailure != nil {
+			strategy.RollingUpdate.MaxPodSchedulerFailure = rollingUpdate.MaxPodSchedulerFailure
+		}
+		if rollingUpdate.MaxParallelPodCreation != nil {
+			strategy.RollingUpdate.MaxParallelPodCreation = rollingUpdate.MaxParallelPodCreation
+		}
+		if rollingUpdate.SlowStartIntervalDuration != nil {
+			strategy.RollingUpdate.SlowStartIntervalDuration = rollingUpdate.SlowStartIntervalDuration
+		}
+		if rollingUpdate.SlowStartAdditiveIncrease != nil {
+			strategy.RollingUpdate.SlowStartAdditiveIncrease = rollingUpdate.SlowStartAdditiveIncrease
+		}
+	}
 }
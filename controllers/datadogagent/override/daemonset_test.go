@@ -0,0 +1,118 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package override
+
+import (
+	"testing"
+	"time"
+
+	"github.com/DataDog/datadog-operator/apis/datadoghq/v2alpha1"
+	apiutils "github.com/DataDog/datadog-operator/apis/utils"
+	edsv1alpha1 "github.com/DataDog/extendeddaemonset/api/v1alpha1"
+	"github.com/stretchr/testify/assert"
+	v1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+func TestDaemonSet(t *testing.T) {
+	onDelete := v1.OnDeleteDaemonSetStrategyType
+	maxUnavailable := intstr.FromString("10%")
+
+	tests := []struct {
+		name     string
+		override v2alpha1.DatadogAgentComponentOverride
+		want     v1.DaemonSetUpdateStrategy
+	}{
+		{
+			name:     "no update strategy",
+			override: v2alpha1.DatadogAgentComponentOverride{},
+			want:     v1.DaemonSetUpdateStrategy{},
+		},
+		{
+			name: "rolling update",
+			override: v2alpha1.DatadogAgentComponentOverride{
+				UpdateStrategy: &v2alpha1.DaemonSetUpdateStrategy{
+					RollingUpdate: &v2alpha1.DaemonSetRollingUpdateSpec{MaxUnavailable: &maxUnavailable},
+				},
+			},
+			want: v1.DaemonSetUpdateStrategy{
+				Type:          v1.RollingUpdateDaemonSetStrategyType,
+				RollingUpdate: &v1.RollingUpdateDaemonSet{MaxUnavailable: &maxUnavailable},
+			},
+		},
+		{
+			name: "on delete ignores the rolling update configuration",
+			override: v2alpha1.DatadogAgentComponentOverride{
+				UpdateStrategy: &v2alpha1.DaemonSetUpdateStrategy{
+					Type:          &onDelete,
+					RollingUpdate: &v2alpha1.DaemonSetRollingUpdateSpec{MaxUnavailable: &maxUnavailable},
+				},
+			},
+			want: v1.DaemonSetUpdateStrategy{
+				Type: v1.OnDeleteDaemonSetStrategyType,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			daemonSet := v1.DaemonSet{ObjectMeta: metav1.ObjectMeta{Name: "current-name"}}
+
+			DaemonSet(&daemonSet, &tt.override)
+
+			assert.Equal(t, "current-name", daemonSet.Name)
+			assert.Equal(t, tt.want, daemonSet.Spec.UpdateStrategy)
+		})
+	}
+}
+
+func TestExtendedDaemonSet(t *testing.T) {
+	maxUnavailable := intstr.FromInt(2)
+	defaultMaxPodSchedulerFailure := intstr.FromString("5%")
+
+	eds := edsv1alpha1.ExtendedDaemonSet{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "current-name",
+		},
+		Spec: edsv1alpha1.ExtendedDaemonSetSpec{
+			Strategy: edsv1alpha1.ExtendedDaemonSetSpecStrategy{
+				RollingUpdate: edsv1alpha1.ExtendedDaemonSetSpecStrategyRollingUpdate{
+					MaxPodSchedulerFailure: &defaultMaxPodSchedulerFailure,
+				},
+			},
+		},
+	}
+
+	override := v2alpha1.DatadogAgentComponentOverride{
+		Name: apiutils.NewStringPointer("new-name"),
+		UpdateStrategy: &v2alpha1.DaemonSetUpdateStrategy{
+			RollingUpdate: &v2alpha1.DaemonSetRollingUpdateSpec{
+				MaxUnavailable:         &maxUnavailable,
+				MaxParallelPodCreation: apiutils.NewInt32Pointer(10),
+			},
+			Canary: &edsv1alpha1.ExtendedDaemonSetSpecStrategyCanary{
+				Duration:       &metav1.Duration{Duration: 10 * time.Minute},
+				ValidationMode: edsv1alpha1.ExtendedDaemonSetSpecStrategyCanaryValidationModeManual,
+			},
+			ReconcileFrequency: &metav1.Duration{Duration: 5 * time.Second},
+		},
+	}
+
+	ExtendedDaemonSet(&eds, &override)
+
+	assert.Equal(t, "new-name", eds.Name)
+	assert.Equal(t, &maxUnavailable, eds.Spec.Strategy.RollingUpdate.MaxUnavailable)
+	assert.Equal(t, int32(10), *eds.Spec.Strategy.RollingUpdate.MaxParallelPodCreation)
+	// Fields not set in the override are kept
+	assert.Equal(t, &defaultMaxPodSchedulerFailure, eds.Spec.Strategy.RollingUpdate.MaxPodSchedulerFailure)
+	assert.Equal(t, 5*time.Second, eds.Spec.Strategy.ReconcileFrequency.Duration)
+
+	// The canary is defaulted, without updating the override
+	assert.Equal(t, 10*time.Minute, eds.Spec.Strategy.Canary.Duration.Duration)
+	assert.Equal(t, edsv1alpha1.ExtendedDaemonSetSpecStrategyCanaryValidationModeManual, eds.Spec.Strategy.Canary.ValidationMode)
+	assert.NotNil(t, eds.Spec.Strategy.Canary.AutoPause)
+	assert.Nil(t, override.UpdateStrategy.Canary.AutoPause)
+}
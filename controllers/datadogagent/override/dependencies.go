@@ -12,6 +12,7 @@ import (
 	"github.com/go-logr/logr"
 
 	securityv1 "github.com/openshift/api/security/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
 	"k8s.io/apimachinery/pkg/util/errors"
 
 	"github.com/DataDog/datadog-operator/apis/datadoghq/v2alpha1"
@@ -24,13 +25,17 @@ import (
 	"github.com/DataDog/datadog-operator/controllers/datadogagent/object/configmap"
 	"github.com/DataDog/datadog-operator/pkg/controller/utils/comparison"
 	"github.com/DataDog/datadog-operator/pkg/kubernetes"
+	"github.com/DataDog/datadog-operator/pkg/kubernetes/rbac"
 )
 
 // Dependencies is used to override any resource/dependency settings with a v2alpha1.DatadogAgentComponentOverride.
-func Dependencies(logger logr.Logger, manager feature.ResourceManagers, dda *v2alpha1.DatadogAgent) (errs []error) {
+func Dependencies(logger logr.Logger, manager feature.ResourceManagers, dda *v2alpha1.DatadogAgent, requiredComponents feature.RequiredComponents) (errs []error) {
 	overrides := dda.Spec.Override
 	namespace := dda.Namespace
 
+	// Grant the permissions required by the secret backend before the RBAC overrides are applied
+	errs = append(errs, secretBackendRBAC(manager, dda, requiredComponents)...)
+
 	for component, override := range overrides {
 		err := overrideRBAC(logger, manager, override, component, namespace)
 		if err != nil {
@@ -52,6 +57,14 @@ func Dependencies(logger logr.Logger, manager feature.ResourceManagers, dda *v2a
 		errs = append(errs, overrideSCC(manager, dda)...)
 	}
 
+	// Handle the custom agent configurations of the profiles, their ConfigMaps are prefixed by the profile name.
+	for _, profile := range dda.Spec.Profiles {
+		if profile.Override == nil {
+			continue
+		}
+		errs = append(errs, overrideCustomConfigs(logger, manager, profile.Override.CustomConfigurations, v2alpha1.GetProfileResourcesPrefix(dda, profile.Name), namespace)...)
+	}
+
 	return errs
 }
 
@@ -150,3 +163,105 @@ func overrideSCC(manager feature.ResourceManagers, dda *v2alpha1.DatadogAgent) (
 
 	return errs
 }
+
+// secretBackendRBAC grants the required components the permission to read the Secrets configured in the secret backend, as
+// required by a secret backend command reading Kubernetes Secrets. The components whose RBAC is not managed by the Operator
+// are skipped.
+func secretBackendRBAC(manager feature.ResourceManagers, dda *v2alpha1.DatadogAgent, requiredComponents feature.RequiredComponents) (errs []error) {
+	if dda.Spec.Global == nil || dda.Spec.Global.SecretBackend == nil {
+		return nil
+	}
+	config := dda.Spec.Global.SecretBackend
+	if !apiutils.BoolValue(config.EnableGlobalPermissions) && len(config.Roles) == 0 {
+		return nil
+	}
+
+	components := []struct {
+		name     v2alpha1.ComponentName
+		required *feature.RequiredComponent
+		saName   string
+	}{
+		{v2alpha1.NodeAgentComponentName, &requiredComponents.Agent, v2alpha1.GetAgentServiceAccount(dda)},
+		{v2alpha1.ClusterAgentComponentName, &requiredComponents.ClusterAgent, v2alpha1.GetClusterAgentServiceAccount(dda)},
+		{v2alpha1.ClusterChecksRunnerComponentName, &requiredComponents.ClusterChecksRunner, v2alpha1.GetClusterChecksRunnerServiceAccount(dda)},
+	}
+	var saNames []string
+	for _, component := range components {
+		if !component.required.IsEnabled() {
+			continue
+		}
+		if override, ok := dda.Spec.Override[component.name]; ok {
+			if apiutils.BoolValue(override.Disabled) || (override.CreateRbac != nil && !*override.CreateRbac) {
+				continue
+			}
+		}
+		saNames = append(saNames, component.saName)
+	}
+	if len(saNames) == 0 {
+		return nil
+	}
+
+	rbacName := ddacomponent.GetSecretBackendRbacResourcesName(dda)
+
+	if apiutils.BoolValue(config.EnableGlobalPermissions) {
+		obj, _ := manager.Store().GetOrCreate(kubernetes.ClusterRolesKind, "", rbacName)
+		clusterRole, ok := obj.(*rbacv1.ClusterRole)
+		if !ok {
+			return append(errs, fmt.Errorf("unable to get from the store the ClusterRole %s", rbacName))
+		}
+		clusterRole.Rules = []rbacv1.PolicyRule{
+			{
+				APIGroups: []string{rbac.CoreAPIGroup},
+				Resources: []string{rbac.SecretsResource},
+				Verbs:     []string{rbac.GetVerb},
+			},
+		}
+		if err := manager.Store().AddOrUpdate(kubernetes.ClusterRolesKind, clusterRole); err != nil {
+			errs = append(errs, err)
+		}
+
+		roleRef := rbacv1.RoleRef{
+			APIGroup: rbac.RbacAPIGroup,
+			Kind:     rbac.ClusterRoleKind,
+			Name:     rbacName,
+		}
+		for _, saName := range saNames {
+			if err := manager.RBACManager().AddClusterRoleBinding(dda.Namespace, rbacName, saName, roleRef); err != nil {
+				errs = append(errs, err)
+			}
+		}
+	}
+
+	for _, roleConfig := range config.Roles {
+		obj, _ := manager.Store().GetOrCreate(kubernetes.RolesKind, roleConfig.Namespace, rbacName)
+		role, ok := obj.(*rbacv1.Role)
+		if !ok {
+			errs = append(errs, fmt.Errorf("unable to get from the store the Role %s/%s", roleConfig.Namespace, rbacName))
+			continue
+		}
+		role.Rules = []rbacv1.PolicyRule{
+			{
+				APIGroups:     []string{rbac.CoreAPIGroup},
+				Resources:     []string{rbac.SecretsResource},
+				ResourceNames: roleConfig.Secrets,
+				Verbs:         []string{rbac.GetVerb},
+			},
+		}
+		if err := manager.Store().AddOrUpdate(kubernetes.RolesKind, role); err != nil {
+			errs = append(errs, err)
+		}
+
+		roleRef := rbacv1.RoleRef{
+			APIGroup: rbac.RbacAPIGroup,
+			Kind:     rbac.RoleKind,
+			Name:     rbacName,
+		}
+		for _, saName := range saNames {
+			if err := manager.RBACManager().AddRoleBinding(roleConfig.Namespace, rbacName, dda.Namespace, saName, roleRef); err != nil {
+				errs = append(errs, err)
+			}
+		}
+	}
+
+	return errs
+}
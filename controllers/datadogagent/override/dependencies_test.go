@@ -14,7 +14,11 @@ import (
 	"github.com/DataDog/datadog-operator/controllers/datadogagent/dependencies"
 	"github.com/DataDog/datadog-operator/controllers/datadogagent/feature"
 
+	"github.com/DataDog/datadog-operator/pkg/kubernetes"
+
 	"github.com/stretchr/testify/assert"
+	rbacv1 "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
 )
@@ -152,7 +156,7 @@ func TestDependencies(t *testing.T) {
 			store := dependencies.NewStore(&test.dda, storeOptions)
 			manager := feature.NewResourceManagers(store)
 
-			errs := Dependencies(testLogger, manager, &test.dda)
+			errs := Dependencies(testLogger, manager, &test.dda, feature.RequiredComponents{})
 
 			if test.expectsErrors {
 				assert.NotEmpty(t, errs)
@@ -162,3 +166,88 @@ func TestDependencies(t *testing.T) {
 		})
 	}
 }
+
+func TestSecretBackendRBAC(t *testing.T) {
+	testLogger := logf.Log.WithName("TestSecretBackendRBAC")
+
+	testScheme := runtime.NewScheme()
+	testScheme.AddKnownTypes(v2alpha1.GroupVersion, &v2alpha1.DatadogAgent{})
+	storeOptions := &dependencies.StoreOptions{
+		Scheme: testScheme,
+	}
+
+	dda := v2alpha1.DatadogAgent{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "bar",
+			Name:      "foo",
+		},
+		Spec: v2alpha1.DatadogAgentSpec{
+			Global: &v2alpha1.GlobalConfig{
+				SecretBackend: &v2alpha1.SecretBackendConfig{
+					Command:                 apiutils.NewStringPointer("/readsecret_multiple_providers.sh"),
+					EnableGlobalPermissions: apiutils.NewBoolPointer(true),
+					Roles: []v2alpha1.SecretBackendRolesConfig{
+						{Namespace: "secrets-ns", Secrets: []string{"api-key"}},
+					},
+				},
+			},
+			Override: map[v2alpha1.ComponentName]*v2alpha1.DatadogAgentComponentOverride{
+				v2alpha1.ClusterChecksRunnerComponentName: {
+					CreateRbac: apiutils.NewBoolPointer(false),
+				},
+			},
+		},
+	}
+
+	store := dependencies.NewStore(&dda, storeOptions)
+	manager := feature.NewResourceManagers(store)
+
+	requiredComponents := feature.RequiredComponents{
+		Agent:               feature.RequiredComponent{IsRequired: apiutils.NewBoolPointer(true)},
+		ClusterAgent:        feature.RequiredComponent{IsRequired: apiutils.NewBoolPointer(true)},
+		ClusterChecksRunner: feature.RequiredComponent{IsRequired: apiutils.NewBoolPointer(true)},
+	}
+	errs := Dependencies(testLogger, manager, &dda, requiredComponents)
+	assert.Empty(t, errs)
+
+	// The ServiceAccount of the Cluster Checks Runner is skipped, its RBAC is not managed by the Operator.
+	wantSubjects := []rbacv1.Subject{
+		{Kind: "ServiceAccount", Namespace: "bar", Name: "foo-agent"},
+		{Kind: "ServiceAccount", Namespace: "bar", Name: "foo-cluster-agent"},
+	}
+
+	obj, found := store.Get(kubernetes.ClusterRolesKind, "", "foo-secret-backend")
+	assert.True(t, found)
+	assert.Equal(t, []string{"secrets"}, obj.(*rbacv1.ClusterRole).Rules[0].Resources)
+	assert.Empty(t, obj.(*rbacv1.ClusterRole).Rules[0].ResourceNames)
+
+	obj, found = store.Get(kubernetes.ClusterRoleBindingKind, "", "foo-secret-backend")
+	assert.True(t, found)
+	assert.Equal(t, wantSubjects, obj.(*rbacv1.ClusterRoleBinding).Subjects)
+
+	obj, found = store.Get(kubernetes.RolesKind, "secrets-ns", "foo-secret-backend")
+	assert.True(t, found)
+	assert.Equal(t, []string{"api-key"}, obj.(*rbacv1.Role).Rules[0].ResourceNames)
+
+	obj, found = store.Get(kubernetes.RoleBindingKind, "secrets-ns", "foo-secret-backend")
+	assert.True(t, found)
+	assert.Equal(t, wantSubjects, obj.(*rbacv1.RoleBinding).Subjects)
+
+	// Computing the dependencies again doesn't duplicate the rules.
+	errs = Dependencies(testLogger, manager, &dda, requiredComponents)
+	assert.Empty(t, errs)
+	obj, _ = store.Get(kubernetes.RolesKind, "secrets-ns", "foo-secret-backend")
+	assert.Len(t, obj.(*rbacv1.Role).Rules, 1)
+
+	// The components that are not required are skipped.
+	delete(dda.Spec.Override, v2alpha1.ClusterChecksRunnerComponentName)
+	requiredComponents.ClusterChecksRunner = feature.RequiredComponent{}
+	requiredComponents.ClusterAgent = feature.RequiredComponent{IsRequired: apiutils.NewBoolPointer(false)}
+	store = dependencies.NewStore(&dda, storeOptions)
+	manager = feature.NewResourceManagers(store)
+	errs = Dependencies(testLogger, manager, &dda, requiredComponents)
+	assert.Empty(t, errs)
+	obj, found = store.Get(kubernetes.ClusterRoleBindingKind, "", "foo-secret-backend")
+	assert.True(t, found)
+	assert.Equal(t, []rbacv1.Subject{{Kind: "ServiceAccount", Namespace: "bar", Name: "foo-agent"}}, obj.(*rbacv1.ClusterRoleBinding).Subjects)
+}
@@ -9,6 +9,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"path/filepath"
+	"strings"
 
 	apicommon "github.com/DataDog/datadog-operator/apis/datadoghq/common"
 	apicommonv1 "github.com/DataDog/datadog-operator/apis/datadoghq/common/v1"
@@ -75,6 +76,21 @@ func ApplyGlobalSettings(logger logr.Logger, manager feature.PodTemplateManagers
 		Value: *config.LogLevel,
 	})
 
+	// SecretBackend configures the command used by the Agents to resolve `ENC[]` secret handles.
+	if config.SecretBackend != nil && config.SecretBackend.Command != nil {
+		manager.EnvVar().AddEnvVar(&corev1.EnvVar{
+			Name:  apicommon.DDSecretBackendCommand,
+			Value: *config.SecretBackend.Command,
+		})
+
+		if len(config.SecretBackend.Args) > 0 {
+			manager.EnvVar().AddEnvVar(&corev1.EnvVar{
+				Name:  apicommon.DDSecretBackendArguments,
+				Value: strings.Join(config.SecretBackend.Args, " "),
+			})
+		}
+	}
+
 	// NetworkPolicy contains the network configuration.
 	if config.NetworkPolicy != nil {
 		if apiutils.BoolValue(config.NetworkPolicy.Create) {
@@ -0,0 +1,297 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package datadogagent
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/go-logr/logr"
+	appsv1 "k8s.io/api/apps/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/errors"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/apimachinery/pkg/version"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	apiregistrationv1 "k8s.io/kube-aggregator/pkg/apis/apiregistration/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	edsv1alpha1 "github.com/DataDog/extendeddaemonset/api/v1alpha1"
+
+	apicommon "github.com/DataDog/datadog-operator/apis/datadoghq/common"
+	datadoghqv2alpha1 "github.com/DataDog/datadog-operator/apis/datadoghq/v2alpha1"
+	"github.com/DataDog/datadog-operator/controllers/datadogagent/dependencies"
+	"github.com/DataDog/datadog-operator/controllers/datadogagent/feature"
+	"github.com/DataDog/datadog-operator/controllers/datadogagent/override"
+	"github.com/DataDog/datadog-operator/pkg/controller/utils/comparison"
+	"github.com/DataDog/datadog-operator/pkg/equality"
+	"github.com/DataDog/datadog-operator/pkg/kubernetes"
+)
+
+// RenderOptions provides options to the Render function.
+type RenderOptions struct {
+	SupportExtendedDaemonset bool
+	SupportCilium            bool
+
+	// VersionInfo and PlatformInfo describe the targeted cluster.
+	// If not set, the objects are rendered as if the cluster version is unknown.
+	VersionInfo  *version.Info
+	PlatformInfo kubernetes.PlatformInfo
+
+	// Scheme is used to set owner references and the objects TypeMeta.
+	// If not set, a Scheme containing every type managed by the operator is used.
+	Scheme *runtime.Scheme
+	// Logger is optional, logs are discarded if not set.
+	Logger logr.Logger
+	// DatadogAgentFeatures are applied to the DatadogAgent if they select it.
+	DatadogAgentFeatures []datadoghqv2alpha1.DatadogAgentFeature
+	// DatadogAgentTemplates contains the DatadogAgentTemplate referenced in the DatadogAgent spec.baseRef, if any.
+	DatadogAgentTemplates []datadoghqv2alpha1.DatadogAgentTemplate
+}
+
+// NewRenderScheme returns a runtime.Scheme that knows every type the DatadogAgent reconciler can create.
+func NewRenderScheme() *runtime.Scheme {
+	s := runtime.NewScheme()
+	utilruntime.Must(clientgoscheme.AddToScheme(s))
+	utilruntime.Must(apiregistrationv1.AddToScheme(s))
+	utilruntime.Must(edsv1alpha1.AddToScheme(s))
+	utilruntime.Must(datadoghqv2alpha1.AddToScheme(s))
+	return s
+}
+
+// Render runs the v2alpha1 reconcile pipeline offline and returns every object the operator would
+// create for the given DatadogAgent: the agent workloads and their dependencies.
+// It doesn't need access to a Kubernetes cluster, and the DatadogAgent instance isn't modified.
+func Render(dda *datadoghqv2alpha1.DatadogAgent, options *RenderOptions) ([]client.Object, error) {
+	options, scheme := defaultRenderOptions(options)
+
+	workloads, depsStore, err := render(dda, options, scheme)
+	if err != nil {
+		return nil, err
+	}
+
+	objs := append(workloads, depsStore.Objects()...)
+	for _, obj := range objs {
+		if err := setTypeMeta(obj, scheme); err != nil {
+			return nil, err
+		}
+	}
+
+	return objs, nil
+}
+
+// Diff renders the objects for the given DatadogAgent, and returns the pending changes compared to
+// the objects present in the api-server. Nothing is created or updated.
+// As in the reconciler, a workload is reported as changed if its spec hash changed.
+func Diff(ctx context.Context, k8sClient client.Client, dda *datadoghqv2alpha1.DatadogAgent, options *RenderOptions) ([]equality.ObjectDiff, error) {
+	options, scheme := defaultRenderOptions(options)
+
+	workloads, depsStore, err := render(dda, options, scheme)
+	if err != nil {
+		return nil, err
+	}
+
+	var diffs []equality.ObjectDiff
+	var errs []error
+	for _, obj := range workloads {
+		if err = setTypeMeta(obj, scheme); err != nil {
+			return nil, err
+		}
+		diff := equality.ObjectDiff{
+			Kind:      obj.GetObjectKind().GroupVersionKind().Kind,
+			Namespace: obj.GetNamespace(),
+			Name:      obj.GetName(),
+		}
+
+		current := obj.DeepCopyObject().(client.Object)
+		if err = k8sClient.Get(ctx, client.ObjectKeyFromObject(obj), current); err != nil {
+			if apierrors.IsNotFound(err) {
+				diff.Create = true
+				diffs = append(diffs, diff)
+			} else {
+				errs = append(errs, err)
+			}
+			continue
+		}
+		if comparison.IsSameSpecMD5Hash(obj.GetAnnotations()[apicommon.MD5AgentDeploymentAnnotationKey], current.GetAnnotations()) {
+			continue
+		}
+		diff.Fields = equality.DiffDesiredFields(obj, current, "spec")
+		diffs = append(diffs, diff)
+	}
+
+	depsDiffs, depsErrs := depsStore.Diff(ctx, k8sClient)
+	diffs = append(diffs, depsDiffs...)
+	errs = append(errs, depsErrs...)
+
+	return diffs, errors.NewAggregate(errs)
+}
+
+func defaultRenderOptions(options *RenderOptions) (*RenderOptions, *runtime.Scheme) {
+	if options == nil {
+		options = &RenderOptions{}
+	}
+	scheme := options.Scheme
+	if scheme == nil {
+		scheme = NewRenderScheme()
+	}
+	return options, scheme
+}
+
+// renderDatadogAgentTemplate merges the DatadogAgentTemplate referenced in the DatadogAgent spec.baseRef, if any
+func renderDatadogAgentTemplate(dda *datadoghqv2alpha1.DatadogAgent, templates []datadoghqv2alpha1.DatadogAgentTemplate) error {
+	if dda.Spec.BaseRef == nil {
+		return nil
+	}
+	for i := range templates {
+		if templates[i].Name == dda.Spec.BaseRef.Name {
+			_, err := mergeDatadogAgentTemplate(dda, &templates[i])
+			return err
+		}
+	}
+
+	return fmt.Errorf("the DatadogAgentTemplate %s referenced in spec.baseRef isn't provided", dda.Spec.BaseRef.Name)
+}
+
+// render builds the agent workloads and the Store containing their dependencies.
+func render(dda *datadoghqv2alpha1.DatadogAgent, options *RenderOptions, scheme *runtime.Scheme) ([]client.Object, *dependencies.Store, error) {
+	logger := options.Logger
+	if logger.GetSink() == nil {
+		logger = logr.Discard()
+	}
+
+	instance := dda.DeepCopy()
+	if err := renderDatadogAgentTemplate(instance, options.DatadogAgentTemplates); err != nil {
+		return nil, nil, err
+	}
+
+	// Set default values for GlobalConfig and Features
+	if instance.Spec.Global == nil || instance.Spec.Global.Credentials == nil {
+		return nil, nil, fmt.Errorf("credentials not configured in the DatadogAgent, can't render")
+	}
+	if err := datadoghqv2alpha1.IsValidDatadogAgent(&instance.Spec); err != nil {
+		return nil, nil, fmt.Errorf("invalid DatadogAgent: %w", err)
+	}
+	datadoghqv2alpha1.DefaultDatadogAgent(instance)
+
+	reconcilerOptions := &ReconcilerOptions{
+		SupportExtendedDaemonset: options.SupportExtendedDaemonset,
+		SupportCilium:            options.SupportCilium,
+	}
+	featureOptions := reconcilerOptionsToFeatureOptions(reconcilerOptions, logger)
+	features, _, requiredComponents, err := feature.BuildFeaturesWithComponents(instance, featureOptions)
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid DatadogAgent features: %w", err)
+	}
+	features = append(features, buildDynamicFeaturesV2(logger, instance, options.DatadogAgentFeatures, featureOptions)...)
+
+	storeOptions := &dependencies.StoreOptions{
+		SupportCilium: options.SupportCilium,
+		VersionInfo:   options.VersionInfo,
+		PlatformInfo:  options.PlatformInfo,
+		Logger:        logger,
+		Scheme:        scheme,
+	}
+	depsStore := dependencies.NewStore(instance, storeOptions)
+	resourceManagers := feature.NewResourceManagers(depsStore)
+
+	var errs []error
+	for _, feat := range features {
+		if featErr := feat.ManageDependencies(resourceManagers, requiredComponents); featErr != nil {
+			errs = append(errs, featErr)
+		}
+	}
+	errs = append(errs, override.Dependencies(logger, resourceManagers, instance, requiredComponents)...)
+	if len(errs) > 0 {
+		return nil, nil, errors.NewAggregate(errs)
+	}
+
+	var workloads []client.Object
+
+	if isV2ClusterAgentEnabled(requiredComponents, instance) {
+		deployment, err := buildV2ClusterAgentDeployment(logger, features, instance, resourceManagers)
+		if err != nil {
+			return nil, nil, err
+		}
+		workloads = append(workloads, deployment)
+	}
+
+	// The default node Agent, then the node Agent of each profile
+	agentProfiles := []*datadoghqv2alpha1.DatadogAgentProfile{nil}
+	for i := range instance.Spec.Profiles {
+		agentProfiles = append(agentProfiles, &instance.Spec.Profiles[i])
+	}
+	requiredContainers := requiredComponents.Agent.Containers
+	for _, profile := range agentProfiles {
+		if !isV2AgentEnabled(instance, profile) {
+			continue
+		}
+		if options.SupportExtendedDaemonset {
+			eds, err := buildV2AgentExtendedDaemonSet(logger, features, instance, resourceManagers, requiredContainers, profile)
+			if err != nil {
+				return nil, nil, err
+			}
+			workloads = append(workloads, eds)
+		} else {
+			daemonset, err := buildV2AgentDaemonSet(logger, features, instance, resourceManagers, requiredContainers, profile)
+			if err != nil {
+				return nil, nil, err
+			}
+			workloads = append(workloads, daemonset)
+		}
+	}
+
+	if isV2ClusterChecksRunnerEnabled(requiredComponents, instance) {
+		deployment, err := buildV2ClusterChecksRunnerDeployment(logger, features, instance, resourceManagers)
+		if err != nil {
+			return nil, nil, err
+		}
+		workloads = append(workloads, deployment)
+	}
+
+	for _, obj := range workloads {
+		// Set DatadogAgent instance as the owner and controller
+		if err := controllerutil.SetControllerReference(instance, obj, scheme); err != nil {
+			return nil, nil, err
+		}
+		if err := setWorkloadHashAnnotation(obj); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	return workloads, depsStore, nil
+}
+
+// setWorkloadHashAnnotation sets the spec hash annotation, as done by the reconciler before creating or updating a workload.
+func setWorkloadHashAnnotation(obj client.Object) error {
+	var err error
+	switch workload := obj.(type) {
+	case *appsv1.Deployment:
+		_, err = comparison.SetMD5DatadogAgentGenerationAnnotation(&workload.ObjectMeta, workload.Spec)
+	case *appsv1.DaemonSet:
+		_, err = comparison.SetMD5DatadogAgentGenerationAnnotation(&workload.ObjectMeta, workload.Spec)
+	case *edsv1alpha1.ExtendedDaemonSet:
+		_, err = comparison.SetMD5DatadogAgentGenerationAnnotation(&workload.ObjectMeta, workload.Spec)
+	}
+	return err
+}
+
+// setTypeMeta sets the object apiVersion and kind from the scheme,
+// typed objects built by the reconciler don't set them.
+func setTypeMeta(obj client.Object, scheme *runtime.Scheme) error {
+	if !obj.GetObjectKind().GroupVersionKind().Empty() {
+		return nil
+	}
+	gvk, err := apiutil.GVKForObject(obj, scheme)
+	if err != nil {
+		return fmt.Errorf("unable to get the GroupVersionKind of %s: %w", obj.GetName(), err)
+	}
+	obj.GetObjectKind().SetGroupVersionKind(gvk)
+	return nil
+}
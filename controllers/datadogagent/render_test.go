@@ -0,0 +1,361 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package datadogagent
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+
+	apicommon "github.com/DataDog/datadog-operator/apis/datadoghq/common"
+	datadoghqv2alpha1 "github.com/DataDog/datadog-operator/apis/datadoghq/v2alpha1"
+	v2alpha1test "github.com/DataDog/datadog-operator/apis/datadoghq/v2alpha1/test"
+	apiutils "github.com/DataDog/datadog-operator/apis/utils"
+
+	edsv1alpha1 "github.com/DataDog/extendeddaemonset/api/v1alpha1"
+)
+
+func TestRender(t *testing.T) {
+	tests := []struct {
+		name      string
+		dda       func() *datadoghqv2alpha1.DatadogAgent
+		options   *RenderOptions
+		wantKinds []string
+		wantErr   bool
+	}{
+		{
+			name: "no credentials",
+			dda: func() *datadoghqv2alpha1.DatadogAgent {
+				dda := v2alpha1test.NewDatadogAgent("bar", "foo", nil)
+				dda.Spec.Global.Credentials = nil
+				return dda
+			},
+			wantErr: true,
+		},
+		{
+			name: "default DatadogAgent",
+			dda: func() *datadoghqv2alpha1.DatadogAgent {
+				return v2alpha1test.NewDatadogAgent("bar", "foo", nil)
+			},
+			wantKinds: []string{"Deployment", "DaemonSet"},
+		},
+		{
+			name: "default DatadogAgent with ExtendedDaemonSet",
+			dda: func() *datadoghqv2alpha1.DatadogAgent {
+				return v2alpha1test.NewDatadogAgent("bar", "foo", nil)
+			},
+			options:   &RenderOptions{SupportExtendedDaemonset: true},
+			wantKinds: []string{"Deployment", "ExtendedDaemonSet"},
+		},
+		{
+			name: "node agent disabled by override",
+			dda: func() *datadoghqv2alpha1.DatadogAgent {
+				dda := v2alpha1test.NewDatadogAgent("bar", "foo", nil)
+				dda.Spec.Override = map[datadoghqv2alpha1.ComponentName]*datadoghqv2alpha1.DatadogAgentComponentOverride{
+					datadoghqv2alpha1.NodeAgentComponentName: {Disabled: apiutils.NewBoolPointer(true)},
+				}
+				return dda
+			},
+			wantKinds: []string{"Deployment"},
+		},
+		{
+			name: "cluster checks runner enabled by override",
+			dda: func() *datadoghqv2alpha1.DatadogAgent {
+				dda := v2alpha1test.NewDatadogAgent("bar", "foo", nil)
+				dda.Spec.Override = map[datadoghqv2alpha1.ComponentName]*datadoghqv2alpha1.DatadogAgentComponentOverride{
+					datadoghqv2alpha1.ClusterChecksRunnerComponentName: {},
+				}
+				return dda
+			},
+			wantKinds: []string{"Deployment", "DaemonSet", "Deployment"},
+		},
+		{
+			name: "node agent profiles",
+			dda: func() *datadoghqv2alpha1.DatadogAgent {
+				dda := v2alpha1test.NewDatadogAgent("bar", "foo", nil)
+				dda.Spec.Profiles = []datadoghqv2alpha1.DatadogAgentProfile{
+					newProfile("gpu", nil),
+					newProfile("spot", &datadoghqv2alpha1.DatadogAgentComponentOverride{Disabled: apiutils.NewBoolPointer(true)}),
+					newProfile("infra", nil),
+				}
+				return dda
+			},
+			wantKinds: []string{"Deployment", "DaemonSet", "DaemonSet", "DaemonSet"},
+		},
+		{
+			name: "invalid profile",
+			dda: func() *datadoghqv2alpha1.DatadogAgent {
+				dda := v2alpha1test.NewDatadogAgent("bar", "foo", nil)
+				dda.Spec.Profiles = []datadoghqv2alpha1.DatadogAgentProfile{{Name: "gpu"}}
+				return dda
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dda := tt.dda()
+			original := dda.DeepCopy()
+
+			objs, err := Render(dda, tt.options)
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, original, dda, "Render must not modify the DatadogAgent")
+
+			// Workloads are returned first, followed by their dependencies
+			require.GreaterOrEqual(t, len(objs), len(tt.wantKinds))
+			for i, kind := range tt.wantKinds {
+				assert.Equal(t, kind, objs[i].GetObjectKind().GroupVersionKind().Kind)
+				owner := metav1.GetControllerOf(objs[i])
+				require.NotNil(t, owner)
+				assert.Equal(t, "foo", owner.Name)
+			}
+
+			for _, obj := range objs {
+				assert.False(t, obj.GetObjectKind().GroupVersionKind().Empty(), "TypeMeta must be set on %s", obj.GetName())
+			}
+		})
+	}
+}
+
+func TestRenderProfiles(t *testing.T) {
+	dda := v2alpha1test.NewDatadogAgent("bar", "foo", nil)
+
+	// Without profiles, the selector of the default node Agent is unchanged
+	objs, err := Render(dda, nil)
+	require.NoError(t, err)
+	ds, ok := objs[1].(*appsv1.DaemonSet)
+	require.True(t, ok)
+	assert.Empty(t, ds.Spec.Selector.MatchExpressions)
+
+	dda.Spec.Profiles = []datadoghqv2alpha1.DatadogAgentProfile{
+		newProfile("gpu", &datadoghqv2alpha1.DatadogAgentComponentOverride{
+			Tolerations: []corev1.Toleration{{Key: "nvidia.com/gpu", Operator: corev1.TolerationOpExists}},
+		}),
+	}
+
+	objs, err = Render(dda, nil)
+	require.NoError(t, err)
+
+	var daemonsets []*appsv1.DaemonSet
+	for _, obj := range objs {
+		if ds, ok := obj.(*appsv1.DaemonSet); ok {
+			daemonsets = append(daemonsets, ds)
+		}
+	}
+	require.Len(t, daemonsets, 2)
+	defaultDS, gpuDS := daemonsets[0], daemonsets[1]
+
+	assert.Equal(t, "foo-agent", defaultDS.Name)
+	assert.NotContains(t, defaultDS.Spec.Selector.MatchLabels, apicommon.AgentDeploymentProfileLabelKey)
+	// The selector of the default node Agent doesn't select the pods of the profiles
+	defaultSelector, err := metav1.LabelSelectorAsSelector(defaultDS.Spec.Selector)
+	require.NoError(t, err)
+	assert.True(t, defaultSelector.Matches(labels.Set(defaultDS.Spec.Template.Labels)))
+	assert.False(t, defaultSelector.Matches(labels.Set(gpuDS.Spec.Template.Labels)))
+	assert.Empty(t, defaultDS.Spec.Template.Spec.Tolerations)
+	assert.Equal(t, []corev1.NodeSelectorTerm{
+		{MatchExpressions: []corev1.NodeSelectorRequirement{{Key: "pool", Operator: corev1.NodeSelectorOpNotIn, Values: []string{"gpu"}}}},
+	}, defaultDS.Spec.Template.Spec.Affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution.NodeSelectorTerms)
+
+	assert.Equal(t, "foo-agent-gpu", gpuDS.Name)
+	assert.Equal(t, "gpu", gpuDS.Labels[apicommon.AgentDeploymentProfileLabelKey])
+	assert.Equal(t, "gpu", gpuDS.Spec.Selector.MatchLabels[apicommon.AgentDeploymentProfileLabelKey])
+	assert.Equal(t, "gpu", gpuDS.Spec.Template.Labels[apicommon.AgentDeploymentProfileLabelKey])
+	assert.Len(t, gpuDS.Spec.Template.Spec.Tolerations, 1)
+	assert.Equal(t, []corev1.NodeSelectorTerm{
+		{MatchExpressions: []corev1.NodeSelectorRequirement{{Key: "pool", Operator: corev1.NodeSelectorOpIn, Values: []string{"gpu"}}}},
+	}, gpuDS.Spec.Template.Spec.Affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution.NodeSelectorTerms)
+}
+
+func TestRenderProfilesExtendedDaemonSet(t *testing.T) {
+	dda := v2alpha1test.NewDatadogAgent("bar", "foo", nil)
+
+	// Without profiles, the default node Agent pods keep their labels
+	objs, err := Render(dda, &RenderOptions{SupportExtendedDaemonset: true})
+	require.NoError(t, err)
+	eds, ok := objs[1].(*edsv1alpha1.ExtendedDaemonSet)
+	require.True(t, ok)
+	assert.NotContains(t, eds.Spec.Template.Labels, apicommon.AgentDeploymentProfileLabelKey)
+
+	dda.Spec.Profiles = []datadoghqv2alpha1.DatadogAgentProfile{newProfile("gpu", nil)}
+	objs, err = Render(dda, &RenderOptions{SupportExtendedDaemonset: true})
+	require.NoError(t, err)
+
+	var edss []*edsv1alpha1.ExtendedDaemonSet
+	for _, obj := range objs {
+		if eds, ok := obj.(*edsv1alpha1.ExtendedDaemonSet); ok {
+			edss = append(edss, eds)
+		}
+	}
+	require.Len(t, edss, 2)
+	defaultEDS, gpuEDS := edss[0], edss[1]
+
+	assert.Equal(t, "foo-agent", defaultEDS.Name)
+	// The default node Agent isn't a profile node Agent, but its pods are distinct from the pods of the profiles
+	assert.NotContains(t, defaultEDS.Labels, apicommon.AgentDeploymentProfileLabelKey)
+	assert.Equal(t, apicommon.DefaultAgentProfileName, defaultEDS.Spec.Template.Labels[apicommon.AgentDeploymentProfileLabelKey])
+
+	assert.Equal(t, "foo-agent-gpu", gpuEDS.Name)
+	assert.Equal(t, "gpu", gpuEDS.Labels[apicommon.AgentDeploymentProfileLabelKey])
+	assert.Equal(t, "gpu", gpuEDS.Spec.Template.Labels[apicommon.AgentDeploymentProfileLabelKey])
+}
+
+func TestRenderSecretBackend(t *testing.T) {
+	dda := v2alpha1test.NewDatadogAgent("bar", "foo", nil)
+	dda.Spec.Global.SecretBackend = &datadoghqv2alpha1.SecretBackendConfig{
+		Command:                 apiutils.NewStringPointer("/readsecret_multiple_providers.sh"),
+		Args:                    []string{"--timeout", "10"},
+		EnableGlobalPermissions: apiutils.NewBoolPointer(true),
+	}
+
+	objs, err := Render(dda, nil)
+	require.NoError(t, err)
+
+	var clusterRoleFound bool
+	for _, obj := range objs {
+		switch o := obj.(type) {
+		case *appsv1.DaemonSet:
+			for _, container := range o.Spec.Template.Spec.Containers {
+				assert.Contains(t, container.Env, corev1.EnvVar{Name: apicommon.DDSecretBackendCommand, Value: "/readsecret_multiple_providers.sh"}, "container %s", container.Name)
+				assert.Contains(t, container.Env, corev1.EnvVar{Name: apicommon.DDSecretBackendArguments, Value: "--timeout 10"}, "container %s", container.Name)
+			}
+		case *appsv1.Deployment:
+			for _, container := range o.Spec.Template.Spec.Containers {
+				assert.Contains(t, container.Env, corev1.EnvVar{Name: apicommon.DDSecretBackendCommand, Value: "/readsecret_multiple_providers.sh"}, "container %s", container.Name)
+			}
+		case *rbacv1.ClusterRole:
+			if o.Name == "foo-secret-backend" {
+				clusterRoleFound = true
+			}
+		}
+	}
+	assert.True(t, clusterRoleFound)
+}
+
+func TestRenderDatadogAgentFeatures(t *testing.T) {
+	dda := v2alpha1test.NewDatadogAgent("bar", "foo", nil)
+	dda.Labels = map[string]string{"team": "platform"}
+	clusterRules := []rbacv1.PolicyRule{{APIGroups: []string{"platform.example.com"}, Resources: []string{"widgets"}, Verbs: []string{"get"}}}
+	options := &RenderOptions{
+		DatadogAgentFeatures: []datadoghqv2alpha1.DatadogAgentFeature{
+			{
+				ObjectMeta: metav1.ObjectMeta{Name: "platform"},
+				Spec: datadoghqv2alpha1.DatadogAgentFeatureSpec{
+					Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"team": "platform"}},
+					Override: map[datadoghqv2alpha1.ComponentName]*datadoghqv2alpha1.DatadogAgentFeatureComponentOverride{
+						datadoghqv2alpha1.NodeAgentComponentName: {
+							Env:         []corev1.EnvVar{{Name: "DD_PLATFORM", Value: "true"}},
+							Annotations: map[string]string{"platform.example.com/audited": "true"},
+							Sidecars:    []corev1.Container{{Name: "log-shipper", Image: "busybox"}},
+						},
+						datadoghqv2alpha1.ClusterAgentComponentName: {ClusterRules: clusterRules},
+					},
+					ConfigMaps: []datadoghqv2alpha1.DatadogAgentFeatureConfigMap{{Name: "platform-config", Data: map[string]string{"platform.yaml": "team: platform"}}},
+				},
+			},
+			{
+				ObjectMeta: metav1.ObjectMeta{Name: "other"},
+				Spec: datadoghqv2alpha1.DatadogAgentFeatureSpec{
+					Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"team": "other"}},
+					Override: map[datadoghqv2alpha1.ComponentName]*datadoghqv2alpha1.DatadogAgentFeatureComponentOverride{
+						datadoghqv2alpha1.NodeAgentComponentName: {Env: []corev1.EnvVar{{Name: "DD_OTHER", Value: "true"}}},
+					},
+				},
+			},
+		},
+	}
+
+	objs, err := Render(dda, options)
+	require.NoError(t, err)
+
+	var daemonSetFound, configMapFound, clusterRoleFound bool
+	for _, obj := range objs {
+		switch o := obj.(type) {
+		case *appsv1.DaemonSet:
+			daemonSetFound = true
+			assert.Equal(t, "true", o.Spec.Template.Annotations["platform.example.com/audited"])
+			var sidecarFound bool
+			for _, container := range o.Spec.Template.Spec.Containers {
+				assert.NotContains(t, container.Env, corev1.EnvVar{Name: "DD_OTHER", Value: "true"}, "container %s", container.Name)
+				if container.Name == "log-shipper" {
+					sidecarFound = true
+					assert.Empty(t, container.Env)
+					continue
+				}
+				assert.Contains(t, container.Env, corev1.EnvVar{Name: "DD_PLATFORM", Value: "true"}, "container %s", container.Name)
+			}
+			assert.True(t, sidecarFound)
+		case *corev1.ConfigMap:
+			if o.Name == "platform-config" {
+				configMapFound = true
+				assert.Equal(t, "bar", o.Namespace)
+				assert.Equal(t, map[string]string{"platform.yaml": "team: platform"}, o.Data)
+			}
+		case *rbacv1.ClusterRole:
+			if o.Name == "bar-foo-platform-cluster-agent" {
+				clusterRoleFound = true
+				assert.Equal(t, clusterRules, o.Rules)
+			}
+		}
+	}
+	assert.True(t, daemonSetFound)
+	assert.True(t, configMapFound)
+	assert.True(t, clusterRoleFound)
+}
+
+func newProfile(name string, override *datadoghqv2alpha1.DatadogAgentComponentOverride) datadoghqv2alpha1.DatadogAgentProfile {
+	return datadoghqv2alpha1.DatadogAgentProfile{
+		Name: name,
+		NodeSelector: []corev1.NodeSelectorRequirement{
+			{Key: "pool", Operator: corev1.NodeSelectorOpIn, Values: []string{name}},
+		},
+		Override: override,
+	}
+}
+
+func TestRenderDatadogAgentTemplate(t *testing.T) {
+	base := v2alpha1test.NewDatadogAgent("", "", nil)
+	template := datadoghqv2alpha1.DatadogAgentTemplate{
+		ObjectMeta: metav1.ObjectMeta{Name: "base"},
+		Spec:       base.Spec,
+	}
+	template.Spec.Override = map[datadoghqv2alpha1.ComponentName]*datadoghqv2alpha1.DatadogAgentComponentOverride{
+		datadoghqv2alpha1.ClusterAgentComponentName: {Disabled: apiutils.NewBoolPointer(true)},
+	}
+
+	// The credentials and the overrides are inherited from the template
+	dda := v2alpha1test.NewDatadogAgent("bar", "foo", &datadoghqv2alpha1.GlobalConfig{ClusterName: apiutils.NewStringPointer("cluster-1")})
+	dda.Spec.BaseRef = &datadoghqv2alpha1.DatadogAgentTemplateReference{Name: "base"}
+
+	objs, err := Render(dda, &RenderOptions{DatadogAgentTemplates: []datadoghqv2alpha1.DatadogAgentTemplate{template}})
+	require.NoError(t, err)
+	var daemonSetFound bool
+	for _, obj := range objs {
+		assert.NotEqual(t, "Deployment", obj.GetObjectKind().GroupVersionKind().Kind)
+		if ds, ok := obj.(*appsv1.DaemonSet); ok {
+			daemonSetFound = true
+			for _, container := range ds.Spec.Template.Spec.Containers {
+				assert.Contains(t, container.Env, corev1.EnvVar{Name: apicommon.DDClusterName, Value: "cluster-1"}, "container %s", container.Name)
+			}
+		}
+	}
+	assert.True(t, daemonSetFound)
+	assert.Nil(t, dda.Spec.Global.Credentials)
+
+	// The referenced template must be provided
+	_, err = Render(dda, nil)
+	assert.EqualError(t, err, "the DatadogAgentTemplate base referenced in spec.baseRef isn't provided")
+}
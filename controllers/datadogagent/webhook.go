@@ -0,0 +1,130 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package datadogagent
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/go-logr/logr"
+	"k8s.io/apimachinery/pkg/runtime"
+	utilserrors "k8s.io/apimachinery/pkg/util/errors"
+
+	datadoghqv2alpha1 "github.com/DataDog/datadog-operator/apis/datadoghq/v2alpha1"
+	apiutils "github.com/DataDog/datadog-operator/apis/utils"
+	"github.com/DataDog/datadog-operator/controllers/datadogagent/feature"
+)
+
+// Validator validates the v2alpha1 DatadogAgents when they are created or updated, so that invalid combinations of
+// settings are rejected by the API server instead of failing asynchronously in the DatadogAgent status
+type Validator struct {
+	log logr.Logger
+}
+
+// NewValidator returns a new Validator
+func NewValidator(log logr.Logger) *Validator {
+	return &Validator{log: log.WithName("webhook")}
+}
+
+// ValidateCreate implements admission.CustomValidator
+func (v *Validator) ValidateCreate(ctx context.Context, obj runtime.Object) error {
+	dda, ok := obj.(*datadoghqv2alpha1.DatadogAgent)
+	if !ok {
+		return fmt.Errorf("expected a DatadogAgent but got a %T", obj)
+	}
+
+	return v.validate(dda)
+}
+
+// ValidateUpdate implements admission.CustomValidator
+func (v *Validator) ValidateUpdate(ctx context.Context, oldObj, newObj runtime.Object) error {
+	dda, ok := newObj.(*datadoghqv2alpha1.DatadogAgent)
+	if !ok {
+		return fmt.Errorf("expected a DatadogAgent but got a %T", newObj)
+	}
+	// Don't block the removal of the finalizer of a deleted DatadogAgent
+	if dda.DeletionTimestamp != nil {
+		return nil
+	}
+
+	return v.validate(dda)
+}
+
+// ValidateDelete implements admission.CustomValidator
+func (v *Validator) ValidateDelete(ctx context.Context, obj runtime.Object) error {
+	return nil
+}
+
+func (v *Validator) validate(dda *datadoghqv2alpha1.DatadogAgent) error {
+	if err := datadoghqv2alpha1.IsValidDatadogAgent(&dda.Spec); err != nil {
+		return fmt.Errorf("invalid DatadogAgent spec: %w", err)
+	}
+	// The features and components also depend on the DatadogAgentTemplate, they are checked during the reconcile
+	if dda.Spec.BaseRef != nil {
+		return nil
+	}
+
+	return validateRequiredComponents(dda, v.log)
+}
+
+// validateRequiredComponents rejects the enabled features requiring a component disabled in spec.override
+func validateRequiredComponents(dda *datadoghqv2alpha1.DatadogAgent, logger logr.Logger) error {
+	defaulted := dda.DeepCopy()
+	datadoghqv2alpha1.DefaultDatadogAgent(defaulted)
+	features, featureComponents, _, relationErr := feature.BuildFeaturesWithComponents(defaulted, &feature.Options{Logger: logger})
+
+	var errs []error
+	if relationErr != nil {
+		errs = append(errs, relationErr)
+	}
+	for _, feat := range features {
+		// The default feature requires all the components, but doesn't prevent from disabling them
+		if feat.ID() == feature.DefaultIDType {
+			continue
+		}
+		required := featureComponents[feat.ID()]
+		for _, component := range []struct {
+			name     datadoghqv2alpha1.ComponentName
+			required feature.RequiredComponent
+		}{
+			{datadoghqv2alpha1.NodeAgentComponentName, required.Agent},
+			{datadoghqv2alpha1.ClusterAgentComponentName, required.ClusterAgent},
+			{datadoghqv2alpha1.ClusterChecksRunnerComponentName, required.ClusterChecksRunner},
+		} {
+			if !component.required.IsEnabled() || !isComponentDisabled(dda, component.name) {
+				continue
+			}
+			errs = append(errs, fmt.Errorf("feature %s requires the %s component, disabled by spec.override.%s.disabled", feat.ID(), component.name, component.name))
+		}
+	}
+
+	return utilserrors.NewAggregate(errs)
+}
+
+func isComponentDisabled(dda *datadoghqv2alpha1.DatadogAgent, name datadoghqv2alpha1.ComponentName) bool {
+	override, found := dda.Spec.Override[name]
+	return found && override != nil && apiutils.BoolValue(override.Disabled)
+}
+
+// Defaulter sets the default values of the v2alpha1 DatadogAgents in their spec when they are created or updated, so
+// that the effective configuration is visible in the DatadogAgent.
+// The DatadogAgents referencing a DatadogAgentTemplate aren't defaulted: the default values would take precedence
+// over the template.
+type Defaulter struct{}
+
+// Default implements admission.CustomDefaulter
+func (d *Defaulter) Default(ctx context.Context, obj runtime.Object) error {
+	dda, ok := obj.(*datadoghqv2alpha1.DatadogAgent)
+	if !ok {
+		return fmt.Errorf("expected a DatadogAgent but got a %T", obj)
+	}
+	if dda.Spec.BaseRef != nil {
+		return nil
+	}
+	datadoghqv2alpha1.DefaultDatadogAgent(dda)
+
+	return nil
+}
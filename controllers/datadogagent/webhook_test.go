@@ -0,0 +1,123 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package datadogagent
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	datadoghqv2alpha1 "github.com/DataDog/datadog-operator/apis/datadoghq/v2alpha1"
+	apiutils "github.com/DataDog/datadog-operator/apis/utils"
+)
+
+func TestValidator(t *testing.T) {
+	disabled := func(components ...datadoghqv2alpha1.ComponentName) map[datadoghqv2alpha1.ComponentName]*datadoghqv2alpha1.DatadogAgentComponentOverride {
+		override := map[datadoghqv2alpha1.ComponentName]*datadoghqv2alpha1.DatadogAgentComponentOverride{}
+		for _, component := range components {
+			override[component] = &datadoghqv2alpha1.DatadogAgentComponentOverride{Disabled: apiutils.NewBoolPointer(true)}
+		}
+		return override
+	}
+
+	tests := []struct {
+		name    string
+		spec    datadoghqv2alpha1.DatadogAgentSpec
+		wantErr []string
+	}{
+		{
+			name: "default spec",
+		},
+		{
+			name: "cluster agent disabled without cluster agent feature",
+			spec: datadoghqv2alpha1.DatadogAgentSpec{
+				Features: &datadoghqv2alpha1.DatadogFeatures{
+					EventCollection:       &datadoghqv2alpha1.EventCollectionFeatureConfig{CollectKubernetesEvents: apiutils.NewBoolPointer(false)},
+					OrchestratorExplorer:  &datadoghqv2alpha1.OrchestratorExplorerFeatureConfig{Enabled: apiutils.NewBoolPointer(false)},
+					KubeStateMetricsCore:  &datadoghqv2alpha1.KubeStateMetricsCoreFeatureConfig{Enabled: apiutils.NewBoolPointer(false)},
+					AdmissionController:   &datadoghqv2alpha1.AdmissionControllerFeatureConfig{Enabled: apiutils.NewBoolPointer(false)},
+					ExternalMetricsServer: &datadoghqv2alpha1.ExternalMetricsServerFeatureConfig{Enabled: apiutils.NewBoolPointer(false)},
+					ClusterChecks:         &datadoghqv2alpha1.ClusterChecksFeatureConfig{Enabled: apiutils.NewBoolPointer(false)},
+				},
+				Override: disabled(datadoghqv2alpha1.ClusterAgentComponentName),
+			},
+		},
+		{
+			name: "cluster checks runners disabled",
+			spec: datadoghqv2alpha1.DatadogAgentSpec{
+				Features: &datadoghqv2alpha1.DatadogFeatures{
+					ClusterChecks: &datadoghqv2alpha1.ClusterChecksFeatureConfig{Enabled: apiutils.NewBoolPointer(true), UseClusterChecksRunners: apiutils.NewBoolPointer(true)},
+				},
+				Override: disabled(datadoghqv2alpha1.ClusterChecksRunnerComponentName),
+			},
+			wantErr: []string{"feature cluster_checks requires the clusterChecksRunner component, disabled by spec.override.clusterChecksRunner.disabled"},
+		},
+		{
+			name: "node agent disabled",
+			spec: datadoghqv2alpha1.DatadogAgentSpec{
+				Features: &datadoghqv2alpha1.DatadogFeatures{
+					APM: &datadoghqv2alpha1.APMFeatureConfig{Enabled: apiutils.NewBoolPointer(true)},
+				},
+				Override: disabled(datadoghqv2alpha1.NodeAgentComponentName),
+			},
+			wantErr: []string{"feature apm requires the nodeAgent component"},
+		},
+		{
+			name: "cluster agent disabled with a template",
+			spec: datadoghqv2alpha1.DatadogAgentSpec{
+				BaseRef:  &datadoghqv2alpha1.DatadogAgentTemplateReference{Name: "base"},
+				Override: disabled(datadoghqv2alpha1.ClusterAgentComponentName),
+			},
+		},
+		{
+			name: "invalid spec",
+			spec: datadoghqv2alpha1.DatadogAgentSpec{
+				Profiles: []datadoghqv2alpha1.DatadogAgentProfile{{Name: "gpu"}},
+			},
+			wantErr: []string{"invalid DatadogAgent spec", "'nodeSelector' should contain at least one requirement"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dda := &datadoghqv2alpha1.DatadogAgent{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "foo", Name: "bar"},
+				Spec:       tt.spec,
+			}
+
+			err := NewValidator(logf.Log).ValidateCreate(context.TODO(), dda)
+			if len(tt.wantErr) == 0 {
+				assert.NoError(t, err)
+				return
+			}
+			assert.Error(t, err)
+			for _, wantErr := range tt.wantErr {
+				assert.Contains(t, err.Error(), wantErr)
+			}
+		})
+	}
+}
+
+func TestDefaulter(t *testing.T) {
+	dda := &datadoghqv2alpha1.DatadogAgent{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "foo", Name: "bar"},
+	}
+
+	assert.NoError(t, (&Defaulter{}).Default(context.TODO(), dda))
+	assert.Equal(t, "datadoghq.com", *dda.Spec.Global.Site)
+	assert.NotNil(t, dda.Spec.Features.Dogstatsd.HostPortConfig)
+
+	// The default values aren't set with a template, they would take precedence over it
+	dda = &datadoghqv2alpha1.DatadogAgent{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "foo", Name: "bar"},
+		Spec:       datadoghqv2alpha1.DatadogAgentSpec{BaseRef: &datadoghqv2alpha1.DatadogAgentTemplateReference{Name: "base"}},
+	}
+	assert.NoError(t, (&Defaulter{}).Default(context.TODO(), dda))
+	assert.Nil(t, dda.Spec.Global)
+	assert.Nil(t, dda.Spec.Features)
+}
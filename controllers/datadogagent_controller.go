@@ -52,6 +52,8 @@ type DatadogAgentReconciler struct {
 // +kubebuilder:rbac:groups=datadoghq.com,resources=datadogagents,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=datadoghq.com,resources=datadogagents/status,verbs=get;update;patch
 // +kubebuilder:rbac:groups=datadoghq.com,resources=datadogagents/finalizers,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=datadoghq.com,resources=datadogagentfeatures,verbs=get;list;watch
+// +kubebuilder:rbac:groups=datadoghq.com,resources=datadogagenttemplates,verbs=get;list;watch
 
 // RBAC Management
 // +kubebuilder:rbac:groups=rbac.authorization.k8s.io,resources=clusterroles,verbs=get;list;watch;create;update;patch;delete
@@ -173,6 +175,18 @@ func (r *DatadogAgentReconciler) Reconcile(ctx context.Context, req ctrl.Request
 	return r.internal.Reconcile(ctx, req)
 }
 
+// SetupWebhookWithManager starts the v2alpha1 DatadogAgent validating webhook, and the defaulting webhook if enabled
+func (r *DatadogAgentReconciler) SetupWebhookWithManager(mgr ctrl.Manager, defaulting bool) error {
+	builder := ctrl.NewWebhookManagedBy(mgr).
+		For(&datadoghqv2alpha1.DatadogAgent{}).
+		WithValidator(datadogagent.NewValidator(r.Log))
+	if defaulting {
+		builder = builder.WithDefaulter(&datadogagent.Defaulter{})
+	}
+
+	return builder.Complete()
+}
+
 // SetupWithManager creates a new DatadogAgent controller.
 func (r *DatadogAgentReconciler) SetupWithManager(mgr ctrl.Manager) error {
 	builder := ctrl.NewControllerManagedBy(mgr).
@@ -194,6 +208,18 @@ func (r *DatadogAgentReconciler) SetupWithManager(mgr ctrl.Manager) error {
 	builder.Watches(&source.Kind{Type: &rbacv1.ClusterRole{}}, handlerEnqueue)
 	builder.Watches(&source.Kind{Type: &rbacv1.ClusterRoleBinding{}}, handlerEnqueue)
 
+	// DatadogAgentFeatures are cluster-scoped and select the DatadogAgents by label,
+	// all the DatadogAgents are reconciled when one of them changes.
+	if r.Options.DatadogAgentFeatureEnabled {
+		builder.Watches(&source.Kind{Type: &datadoghqv2alpha1.DatadogAgentFeature{}}, handler.EnqueueRequestsFromMapFunc(r.enqueueAllDatadogAgents))
+	}
+
+	// DatadogAgentTemplates are cluster-scoped, the DatadogAgents referencing a template
+	// in their spec.baseRef are reconciled when it changes.
+	if r.Options.DatadogAgentTemplateEnabled {
+		builder.Watches(&source.Kind{Type: &datadoghqv2alpha1.DatadogAgentTemplate{}}, handler.EnqueueRequestsFromMapFunc(r.enqueueDatadogAgentsFromTemplate))
+	}
+
 	if r.Options.SupportExtendedDaemonset {
 		builder = builder.Owns(&edsdatadoghqv1alpha1.ExtendedDaemonSet{})
 	}
@@ -211,7 +237,7 @@ func (r *DatadogAgentReconciler) SetupWithManager(mgr ctrl.Manager) error {
 	var metricForwarder datadog.MetricForwardersManager
 	var builderOptions []ctrlbuilder.ForOption
 	if r.Options.OperatorMetricsEnabled {
-		metricForwarder = datadog.NewForwardersManager(r.Client, r.Options.V2Enabled, &r.PlatformInfo)
+		metricForwarder = datadog.NewForwardersManager(r.Client, r.Options.V2Enabled, &r.PlatformInfo, r.Options.CredentialManager)
 		builderOptions = append(builderOptions, ctrlbuilder.WithPredicates(predicate.Funcs{
 			// On `DatadogAgent` object creation, we register a metrics forwarder for it.
 			CreateFunc: func(e event.CreateEvent) bool {
@@ -252,3 +278,35 @@ func enqueueIfOwnedByDatadogAgent(obj client.Object) []reconcile.Request {
 
 	return []reconcile.Request{{NamespacedName: owner}}
 }
+
+func (r *DatadogAgentReconciler) enqueueAllDatadogAgents(obj client.Object) []reconcile.Request {
+	ddaList := &datadoghqv2alpha1.DatadogAgentList{}
+	if err := r.List(context.TODO(), ddaList); err != nil {
+		r.Log.Error(err, "Unable to list the DatadogAgents", "datadogagentfeature", obj.GetName())
+		return nil
+	}
+
+	requests := make([]reconcile.Request, 0, len(ddaList.Items))
+	for i := range ddaList.Items {
+		requests = append(requests, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(&ddaList.Items[i])})
+	}
+
+	return requests
+}
+
+func (r *DatadogAgentReconciler) enqueueDatadogAgentsFromTemplate(obj client.Object) []reconcile.Request {
+	ddaList := &datadoghqv2alpha1.DatadogAgentList{}
+	if err := r.List(context.TODO(), ddaList); err != nil {
+		r.Log.Error(err, "Unable to list the DatadogAgents", "datadogagenttemplate", obj.GetName())
+		return nil
+	}
+
+	var requests []reconcile.Request
+	for i := range ddaList.Items {
+		if baseRef := ddaList.Items[i].Spec.BaseRef; baseRef != nil && baseRef.Name == obj.GetName() {
+			requests = append(requests, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(&ddaList.Items[i])})
+		}
+	}
+
+	return requests
+}
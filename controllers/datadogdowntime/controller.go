@@ -0,0 +1,384 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package datadogdowntime
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/go-logr/logr"
+	apiequality "k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	utilserrors "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	datadogapiclientv1 "github.com/DataDog/datadog-api-client-go/api/v1/datadog"
+	datadoghqv1alpha1 "github.com/DataDog/datadog-operator/apis/datadoghq/v1alpha1"
+	ctrUtils "github.com/DataDog/datadog-operator/pkg/controller/utils"
+	"github.com/DataDog/datadog-operator/pkg/controller/utils/comparison"
+	"github.com/DataDog/datadog-operator/pkg/controller/utils/condition"
+	"github.com/DataDog/datadog-operator/pkg/controller/utils/datadog"
+	"github.com/DataDog/datadog-operator/pkg/datadogclient"
+)
+
+const (
+	defaultRequeuePeriod    = 60 * time.Second
+	defaultErrRequeuePeriod = 5 * time.Second
+
+	// conditionTypeMonitorFound means the DatadogMonitor referenced by spec.monitorRef exists
+	conditionTypeMonitorFound = "MonitorFound"
+	monitorFoundReason        = "MonitorFound"
+	monitorNotFoundReason     = "MonitorNotFound"
+)
+
+// Reconciler reconciles a DatadogDowntime object
+type Reconciler struct {
+	client        client.Client
+	apiReader     client.Reader
+	datadogClient *datadogapiclientv1.APIClient
+	datadogAuth   context.Context
+	datadogMutex  sync.RWMutex
+	clients       datadogclient.ClientCache
+	log           logr.Logger
+	scheme        *runtime.Scheme
+	recorder      record.EventRecorder
+}
+
+// NewReconciler returns a new Reconciler object
+func NewReconciler(client client.Client, apiReader client.Reader, ddClient datadogclient.DatadogClient, scheme *runtime.Scheme, log logr.Logger, recorder record.EventRecorder) (*Reconciler, error) {
+	return &Reconciler{
+		client:        client,
+		apiReader:     apiReader,
+		datadogClient: ddClient.Client,
+		datadogAuth:   ddClient.Auth,
+		scheme:        scheme,
+		log:           log,
+		recorder:      recorder,
+	}, nil
+}
+
+// UpdateDatadogClient replaces the Datadog API client, e.g. when the operator credentials are rotated
+func (r *Reconciler) UpdateDatadogClient(ddClient datadogclient.DatadogClient) {
+	r.datadogMutex.Lock()
+	defer r.datadogMutex.Unlock()
+	r.datadogClient = ddClient.Client
+	r.datadogAuth = ddClient.Auth
+}
+
+// getDatadogClient returns the Datadog API authentication context and client of a DatadogDowntime:
+// the ones of its credentials Secret if referenced, the operator ones otherwise
+func (r *Reconciler) getDatadogClient(dd *datadoghqv1alpha1.DatadogDowntime) (context.Context, *datadogapiclientv1.APIClient, error) {
+	if dd.Spec.CredentialsSecretRef != nil {
+		ddClient, err := r.clients.GetFromSecret(r.apiReader, dd.Namespace, dd.Spec.CredentialsSecretRef)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		return ddClient.Auth, ddClient.Client, nil
+	}
+
+	r.datadogMutex.RLock()
+	defer r.datadogMutex.RUnlock()
+	return r.datadogAuth, r.datadogClient, nil
+}
+
+// Reconcile is similar to reconciler.Reconcile interface, but taking a context
+func (r *Reconciler) Reconcile(ctx context.Context, request reconcile.Request) (reconcile.Result, error) {
+	return r.internalReconcile(ctx, request)
+}
+
+// Reconcile loop for DatadogDowntime
+func (r *Reconciler) internalReconcile(ctx context.Context, req reconcile.Request) (reconcile.Result, error) {
+	logger := r.log.WithValues("datadogdowntime", req.NamespacedName)
+	logger.Info("Reconciling DatadogDowntime")
+
+	// Get instance
+	instance := &datadoghqv1alpha1.DatadogDowntime{}
+	var result ctrl.Result
+	err := r.client.Get(ctx, req.NamespacedName, instance)
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			// Request object not found, could have been deleted after reconcile request.
+			// Owned objects are automatically garbage collected. For additional cleanup logic use finalizers.
+			// Return and don't requeue
+			return result, nil
+		}
+		// Error reading the object - requeue the request
+		return ctrl.Result{RequeueAfter: defaultErrRequeuePeriod}, err
+	}
+
+	newStatus := instance.Status.DeepCopy()
+
+	if result, err = r.handleFinalizer(logger, instance); ctrUtils.ShouldReturn(result, err) {
+		return result, err
+	}
+
+	// Requeue periodically to retry the monitors not created in Datadog yet
+	result.RequeueAfter = defaultRequeuePeriod
+
+	// Validate the DatadogDowntime spec
+	if err = datadoghqv1alpha1.IsValidDatadogDowntime(&instance.Spec); err != nil {
+		logger.Error(err, "invalid DatadogDowntime spec")
+
+		return r.updateStatusIfNeeded(logger, instance, newStatus, err, result)
+	}
+
+	instanceSpecHash, err := comparison.GenerateMD5ForSpec(&instance.Spec)
+	if err != nil {
+		logger.Error(err, "error generating hash")
+
+		return r.updateStatusIfNeeded(logger, instance, newStatus, err, result)
+	}
+
+	monitorIDs, err := r.getMonitorIDs(ctx, instance)
+	if apierrors.IsNotFound(err) {
+		// The downtimes of a deleted DatadogMonitor are canceled, and created again if it is recreated
+		logger.Info("Referenced DatadogMonitor not found, canceling its downtimes", "DatadogMonitor", instance.Spec.MonitorRef.Name)
+		setMonitorFoundCondition(instance, newStatus, false)
+		monitorIDs, err = nil, nil
+	} else if err == nil && instance.Spec.MonitorRef != nil {
+		setMonitorFoundCondition(instance, newStatus, true)
+	}
+	if err != nil {
+		logger.Error(err, "error getting the monitors to silence")
+
+		return r.updateStatusIfNeeded(logger, instance, newStatus, err, result)
+	}
+
+	if err = r.sync(logger, instance, newStatus, monitorIDs, instanceSpecHash); err != nil {
+		logger.Error(err, "error syncing downtimes")
+	} else {
+		newStatus.CurrentHash = instanceSpecHash
+	}
+
+	// Update the status
+	return r.updateStatusIfNeeded(logger, instance, newStatus, err, result)
+}
+
+// getMonitorIDs returns the sorted IDs of the monitors silenced by a DatadogDowntime,
+// or a single 0 ID if the DatadogDowntime silences monitors by tags
+func (r *Reconciler) getMonitorIDs(ctx context.Context, dd *datadoghqv1alpha1.DatadogDowntime) ([]int, error) {
+	if len(dd.Spec.MonitorTags) > 0 {
+		return []int{0}, nil
+	}
+
+	var monitors []datadoghqv1alpha1.DatadogMonitor
+	if dd.Spec.MonitorRef != nil {
+		dm := &datadoghqv1alpha1.DatadogMonitor{}
+		if err := r.client.Get(ctx, types.NamespacedName{Namespace: dd.Namespace, Name: dd.Spec.MonitorRef.Name}, dm); err != nil {
+			return nil, fmt.Errorf("unable to get DatadogMonitor %s: %w", dd.Spec.MonitorRef.Name, err)
+		}
+		if dm.Status.ID == 0 {
+			return nil, fmt.Errorf("DatadogMonitor %s is not created in Datadog yet", dm.Name)
+		}
+		monitors = append(monitors, *dm)
+	} else {
+		selector, err := metav1.LabelSelectorAsSelector(dd.Spec.MonitorSelector)
+		if err != nil {
+			return nil, fmt.Errorf("invalid monitor selector: %w", err)
+		}
+		dmList := &datadoghqv1alpha1.DatadogMonitorList{}
+		if err = r.client.List(ctx, dmList, client.InNamespace(dd.Namespace), client.MatchingLabelsSelector{Selector: selector}); err != nil {
+			return nil, fmt.Errorf("unable to list DatadogMonitors: %w", err)
+		}
+		for _, dm := range dmList.Items {
+			// The DatadogMonitors not created in Datadog yet are silenced once created
+			if dm.Status.ID != 0 {
+				monitors = append(monitors, dm)
+			}
+		}
+	}
+
+	ids := make([]int, 0, len(monitors))
+	for _, dm := range monitors {
+		if !apiequality.Semantic.DeepEqual(dm.Spec.CredentialsSecretRef, dd.Spec.CredentialsSecretRef) {
+			return nil, fmt.Errorf("DatadogMonitor %s doesn't use the credentials of the DatadogDowntime", dm.Name)
+		}
+		ids = append(ids, dm.Status.ID)
+	}
+	sort.Ints(ids)
+
+	return ids, nil
+}
+
+// setMonitorFoundCondition sets the MonitorFound condition reporting whether the DatadogMonitor referenced by
+// spec.monitorRef exists
+func setMonitorFoundCondition(dd *datadoghqv1alpha1.DatadogDowntime, status *datadoghqv1alpha1.DatadogDowntimeStatus, found bool) {
+	cond := metav1.Condition{
+		Type:               conditionTypeMonitorFound,
+		Status:             metav1.ConditionTrue,
+		ObservedGeneration: dd.Generation,
+		Reason:             monitorFoundReason,
+	}
+	if !found {
+		cond.Status = metav1.ConditionFalse
+		cond.Reason = monitorNotFoundReason
+		cond.Message = fmt.Sprintf("DatadogMonitor %s not found, its downtimes are canceled", dd.Spec.MonitorRef.Name)
+	}
+	apimeta.SetStatusCondition(&status.Conditions, cond)
+}
+
+// sync creates the missing downtimes, updates the existing ones if the spec changed, and cancels the ones
+// of the monitors not silenced anymore
+func (r *Reconciler) sync(logger logr.Logger, dd *datadoghqv1alpha1.DatadogDowntime, status *datadoghqv1alpha1.DatadogDowntimeStatus, monitorIDs []int, specHash string) error {
+	datadogAuth, datadogClient, err := r.getDatadogClient(dd)
+	if err != nil {
+		return err
+	}
+
+	existing := make(map[int]datadoghqv1alpha1.DatadogDowntimeStatusDowntime, len(status.Downtimes))
+	for _, downtime := range status.Downtimes {
+		existing[downtime.MonitorID] = downtime
+	}
+
+	var errs []error
+	var created, updated bool
+	downtimes := make([]datadoghqv1alpha1.DatadogDowntimeStatusDowntime, 0, len(monitorIDs))
+	for _, monitorID := range monitorIDs {
+		if downtime, found := existing[monitorID]; found {
+			delete(existing, monitorID)
+			downtimes = append(downtimes, downtime)
+			if dd.Status.CurrentHash == specHash {
+				continue
+			}
+
+			if err = updateDowntime(datadogAuth, datadogClient, downtime.ID, buildDowntime(dd, monitorID)); err != nil {
+				errs = append(errs, err)
+				continue
+			}
+			updated = true
+			logger.Info("Updated downtime", "Downtime ID", downtime.ID, "Monitor ID", monitorID)
+			continue
+		}
+
+		dt, err := createDowntime(datadogAuth, datadogClient, buildDowntime(dd, monitorID))
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		created = true
+		downtimes = append(downtimes, datadoghqv1alpha1.DatadogDowntimeStatusDowntime{ID: int(dt.GetId()), MonitorID: monitorID})
+		logger.Info("Created downtime", "Downtime ID", dt.GetId(), "Monitor ID", monitorID)
+	}
+
+	// Cancel the downtimes of the monitors not silenced anymore
+	for _, downtime := range status.Downtimes {
+		if _, found := existing[downtime.MonitorID]; !found {
+			continue
+		}
+		if err = cancelDowntime(datadogAuth, datadogClient, downtime.ID); err != nil {
+			// Keep track of the downtime to retry
+			downtimes = append(downtimes, downtime)
+			errs = append(errs, err)
+			continue
+		}
+		logger.Info("Canceled downtime", "Downtime ID", downtime.ID, "Monitor ID", downtime.MonitorID)
+	}
+
+	if created {
+		r.recordEvent(dd, buildEventInfo(dd.Name, dd.Namespace, datadog.CreationEvent))
+	}
+	if updated {
+		r.recordEvent(dd, buildEventInfo(dd.Name, dd.Namespace, datadog.UpdateEvent))
+	}
+
+	sort.Slice(downtimes, func(i, j int) bool { return downtimes[i].MonitorID < downtimes[j].MonitorID })
+	status.Downtimes = downtimes
+
+	return utilserrors.NewAggregate(errs)
+}
+
+func (r *Reconciler) updateStatusIfNeeded(logger logr.Logger, dd *datadoghqv1alpha1.DatadogDowntime, status *datadoghqv1alpha1.DatadogDowntimeStatus, currentErr error, result ctrl.Result) (ctrl.Result, error) {
+	// Update Error and Active conditions
+	condition.SetErrorActiveStatusConditions(&status.Conditions, dd.Generation, datadogDowntimeKind, currentErr)
+
+	// Retry once the Datadog API rate limit resets
+	if reset, rateLimited := datadogclient.GetRateLimitReset(currentErr); rateLimited {
+		result.RequeueAfter = reset
+	}
+
+	if !apiequality.Semantic.DeepEqual(&dd.Status, status) {
+		dd.Status = *status
+		if err := r.client.Status().Update(context.TODO(), dd); err != nil {
+			if apierrors.IsConflict(err) {
+				logger.Error(err, "unable to update DatadogDowntime status due to update conflict")
+
+				return ctrl.Result{Requeue: true, RequeueAfter: defaultErrRequeuePeriod}, nil
+			}
+			logger.Error(err, "unable to update DatadogDowntime status")
+
+			return ctrl.Result{}, err
+		}
+	}
+
+	return result, nil
+}
+
+// RequestsForMonitor returns the reconcile requests of the DatadogDowntimes silencing a DatadogMonitor,
+// so that they are updated when the DatadogMonitor is created in Datadog or its labels change
+func (r *Reconciler) RequestsForMonitor(obj client.Object) []reconcile.Request {
+	ddList := &datadoghqv1alpha1.DatadogDowntimeList{}
+	if err := r.client.List(context.TODO(), ddList, client.InNamespace(obj.GetNamespace())); err != nil {
+		r.log.Error(err, "unable to list DatadogDowntimes")
+		return nil
+	}
+
+	var requests []reconcile.Request
+	for _, dd := range ddList.Items {
+		switch {
+		case dd.Spec.MonitorRef != nil:
+			if dd.Spec.MonitorRef.Name != obj.GetName() {
+				continue
+			}
+		case dd.Spec.MonitorSelector != nil:
+			// Also reconcile the DatadogDowntimes that silenced the monitor before a label change
+			if !silences(&dd, obj) && !hasMonitorDowntime(&dd, obj) {
+				continue
+			}
+		default:
+			continue
+		}
+		requests = append(requests, reconcile.Request{NamespacedName: types.NamespacedName{Namespace: dd.Namespace, Name: dd.Name}})
+	}
+
+	return requests
+}
+
+// silences returns true if the DatadogDowntime monitor selector matches the labels of obj
+func silences(dd *datadoghqv1alpha1.DatadogDowntime, obj client.Object) bool {
+	selector, err := metav1.LabelSelectorAsSelector(dd.Spec.MonitorSelector)
+	if err != nil {
+		return false
+	}
+
+	return selector.Matches(labels.Set(obj.GetLabels()))
+}
+
+// hasMonitorDowntime returns true if the DatadogDowntime has a downtime for the DatadogMonitor obj
+func hasMonitorDowntime(dd *datadoghqv1alpha1.DatadogDowntime, obj client.Object) bool {
+	dm, ok := obj.(*datadoghqv1alpha1.DatadogMonitor)
+	if !ok || dm.Status.ID == 0 {
+		return false
+	}
+	for _, downtime := range dd.Status.Downtimes {
+		if downtime.MonitorID == dm.Status.ID {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,403 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package datadogdowntime
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	datadogapiclientv1 "github.com/DataDog/datadog-api-client-go/api/v1/datadog"
+	datadoghqv1alpha1 "github.com/DataDog/datadog-operator/apis/datadoghq/v1alpha1"
+	"github.com/DataDog/datadog-operator/pkg/controller/utils/condition"
+)
+
+const (
+	resourcesName      = "foo"
+	resourcesNamespace = "bar"
+)
+
+// fakeDowntimesAPI is a minimal implementation of the Datadog downtimes API
+type fakeDowntimesAPI struct {
+	sync.Mutex
+	nextID    int64
+	downtimes map[int64]datadogapiclientv1.Downtime
+	// failCancel makes the downtime cancellations fail
+	failCancel bool
+}
+
+func newFakeDowntimesAPI() *fakeDowntimesAPI {
+	return &fakeDowntimesAPI{nextID: 100, downtimes: map[int64]datadogapiclientv1.Downtime{}}
+}
+
+func (f *fakeDowntimesAPI) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.Lock()
+	defer f.Unlock()
+	w.Header().Set("Content-Type", "application/json")
+
+	id, _ := strconv.ParseInt(strings.TrimPrefix(r.URL.Path, "/api/v1/downtime/"), 10, 64)
+	switch r.Method {
+	case http.MethodPost:
+		downtime := datadogapiclientv1.Downtime{}
+		_ = json.NewDecoder(r.Body).Decode(&downtime)
+		downtime.SetId(f.nextID)
+		f.downtimes[f.nextID] = downtime
+		f.nextID++
+		_ = json.NewEncoder(w).Encode(downtime)
+	case http.MethodPut:
+		if _, found := f.downtimes[id]; !found {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		downtime := datadogapiclientv1.Downtime{}
+		_ = json.NewDecoder(r.Body).Decode(&downtime)
+		downtime.SetId(id)
+		f.downtimes[id] = downtime
+		_ = json.NewEncoder(w).Encode(downtime)
+	case http.MethodDelete:
+		if f.failCancel {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		if _, found := f.downtimes[id]; !found {
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"errors": ["Downtime not found"]}`))
+			return
+		}
+		delete(f.downtimes, id)
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+func TestReconciler_Reconcile(t *testing.T) {
+	s := runtime.NewScheme()
+	_ = clientgoscheme.AddToScheme(s)
+	_ = datadoghqv1alpha1.AddToScheme(s)
+
+	monitorA := newMonitor("monitor-a", map[string]string{"team": "a"}, 1)
+	monitorB := newMonitor("monitor-b", map[string]string{"team": "a"}, 2)
+	monitorNotCreated := newMonitor("monitor-c", map[string]string{"team": "a"}, 0)
+	monitorOtherTeam := newMonitor("monitor-d", map[string]string{"team": "b"}, 3)
+
+	tests := []struct {
+		name          string
+		downtime      *datadoghqv1alpha1.DatadogDowntime
+		action        func(t *testing.T, c client.Client)
+		wantActive    metav1.ConditionStatus
+		wantDowntimes []datadoghqv1alpha1.DatadogDowntimeStatusDowntime
+		wantAPI       map[int64]int64
+		// wantMonitorFound is the expected MonitorFound condition status, if any
+		wantMonitorFound metav1.ConditionStatus
+	}{
+		{
+			name:             "silence by monitor reference",
+			downtime:         newDowntime(datadoghqv1alpha1.DatadogDowntimeSpec{MonitorRef: &corev1.LocalObjectReference{Name: "monitor-a"}}),
+			wantActive:       metav1.ConditionTrue,
+			wantDowntimes:    []datadoghqv1alpha1.DatadogDowntimeStatusDowntime{{ID: 100, MonitorID: 1}},
+			wantAPI:          map[int64]int64{100: 1},
+			wantMonitorFound: metav1.ConditionTrue,
+		},
+		{
+			name:          "silence by monitor tags",
+			downtime:      newDowntime(datadoghqv1alpha1.DatadogDowntimeSpec{MonitorTags: []string{"team:a"}}),
+			wantActive:    metav1.ConditionTrue,
+			wantDowntimes: []datadoghqv1alpha1.DatadogDowntimeStatusDowntime{{ID: 100}},
+			wantAPI:       map[int64]int64{100: 0},
+		},
+		{
+			name: "silence by monitor selector",
+			downtime: newDowntime(datadoghqv1alpha1.DatadogDowntimeSpec{
+				MonitorSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"team": "a"}},
+			}),
+			wantActive:    metav1.ConditionTrue,
+			wantDowntimes: []datadoghqv1alpha1.DatadogDowntimeStatusDowntime{{ID: 100, MonitorID: 1}, {ID: 101, MonitorID: 2}},
+			wantAPI:       map[int64]int64{100: 1, 101: 2},
+		},
+		{
+			name: "monitor not selected anymore",
+			downtime: newDowntime(datadoghqv1alpha1.DatadogDowntimeSpec{
+				MonitorSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"team": "a"}},
+			}),
+			action: func(t *testing.T, c client.Client) {
+				dm := &datadoghqv1alpha1.DatadogMonitor{}
+				assert.NoError(t, c.Get(context.TODO(), types.NamespacedName{Namespace: resourcesNamespace, Name: "monitor-b"}, dm))
+				dm.Labels["team"] = "b"
+				assert.NoError(t, c.Update(context.TODO(), dm))
+			},
+			wantActive:    metav1.ConditionTrue,
+			wantDowntimes: []datadoghqv1alpha1.DatadogDowntimeStatusDowntime{{ID: 100, MonitorID: 1}},
+			wantAPI:       map[int64]int64{100: 1},
+		},
+		{
+			name:          "spec updated",
+			downtime:      newDowntime(datadoghqv1alpha1.DatadogDowntimeSpec{MonitorRef: &corev1.LocalObjectReference{Name: "monitor-a"}}),
+			action:        updateDowntimeSpec(func(spec *datadoghqv1alpha1.DatadogDowntimeSpec) { spec.Message = "new message" }),
+			wantActive:    metav1.ConditionTrue,
+			wantDowntimes: []datadoghqv1alpha1.DatadogDowntimeStatusDowntime{{ID: 100, MonitorID: 1}},
+			wantAPI:       map[int64]int64{100: 1},
+		},
+		{
+			name:     "target changed from tags to reference",
+			downtime: newDowntime(datadoghqv1alpha1.DatadogDowntimeSpec{MonitorTags: []string{"team:a"}}),
+			action: updateDowntimeSpec(func(spec *datadoghqv1alpha1.DatadogDowntimeSpec) {
+				spec.MonitorTags = nil
+				spec.MonitorRef = &corev1.LocalObjectReference{Name: "monitor-b"}
+			}),
+			wantActive:    metav1.ConditionTrue,
+			wantDowntimes: []datadoghqv1alpha1.DatadogDowntimeStatusDowntime{{ID: 101, MonitorID: 2}},
+			wantAPI:       map[int64]int64{101: 2},
+		},
+		{
+			name:     "referenced monitor deleted",
+			downtime: newDowntime(datadoghqv1alpha1.DatadogDowntimeSpec{MonitorRef: &corev1.LocalObjectReference{Name: "monitor-a"}}),
+			action: func(t *testing.T, c client.Client) {
+				assert.NoError(t, c.Delete(context.TODO(), monitorA.DeepCopy()))
+			},
+			wantActive:       metav1.ConditionTrue,
+			wantAPI:          map[int64]int64{},
+			wantMonitorFound: metav1.ConditionFalse,
+		},
+		{
+			name:       "referenced monitor not created in Datadog",
+			downtime:   newDowntime(datadoghqv1alpha1.DatadogDowntimeSpec{MonitorRef: &corev1.LocalObjectReference{Name: "monitor-c"}}),
+			wantActive: metav1.ConditionFalse,
+			wantAPI:    map[int64]int64{},
+		},
+		{
+			name:       "invalid spec",
+			downtime:   newDowntime(datadoghqv1alpha1.DatadogDowntimeSpec{}),
+			wantActive: metav1.ConditionFalse,
+			wantAPI:    map[int64]int64{},
+		},
+		{
+			name: "monitor using other credentials",
+			downtime: newDowntime(datadoghqv1alpha1.DatadogDowntimeSpec{
+				MonitorRef:           &corev1.LocalObjectReference{Name: "monitor-a"},
+				CredentialsSecretRef: &datadoghqv1alpha1.DatadogCredentialsSecretRef{Name: "creds"},
+			}),
+			wantActive: metav1.ConditionFalse,
+			wantAPI:    map[int64]int64{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			api := newFakeDowntimesAPI()
+			httpServer := httptest.NewServer(api)
+			defer httpServer.Close()
+
+			testConfig := datadogapiclientv1.NewConfiguration()
+			testConfig.HTTPClient = httpServer.Client()
+
+			k8sClient := fake.NewClientBuilder().WithScheme(s).WithObjects(
+				monitorA.DeepCopy(), monitorB.DeepCopy(), monitorNotCreated.DeepCopy(), monitorOtherTeam.DeepCopy(), tt.downtime,
+			).Build()
+			r := &Reconciler{
+				client:        k8sClient,
+				apiReader:     k8sClient,
+				datadogClient: datadogapiclientv1.NewAPIClient(testConfig),
+				datadogAuth:   setupTestAuth(httpServer.URL),
+				scheme:        s,
+				recorder:      record.NewFakeRecorder(10),
+				log:           logf.Log.WithName(tt.name),
+			}
+			req := newRequest(resourcesNamespace, resourcesName)
+
+			// Add the finalizer, then sync the downtimes
+			for i := 0; i < 2; i++ {
+				_, err := r.Reconcile(context.TODO(), req)
+				assert.NoError(t, err)
+			}
+			if tt.action != nil {
+				tt.action(t, k8sClient)
+				_, err := r.Reconcile(context.TODO(), req)
+				assert.NoError(t, err)
+			}
+
+			dd := &datadoghqv1alpha1.DatadogDowntime{}
+			assert.NoError(t, k8sClient.Get(context.TODO(), req.NamespacedName, dd))
+			assert.Contains(t, dd.GetFinalizers(), datadogDowntimeFinalizer)
+			assert.True(t, apimeta.IsStatusConditionPresentAndEqual(dd.Status.Conditions, condition.ConditionTypeActive, tt.wantActive))
+			assert.Equal(t, tt.wantDowntimes, dd.Status.Downtimes)
+			if tt.wantMonitorFound != "" {
+				assert.True(t, apimeta.IsStatusConditionPresentAndEqual(dd.Status.Conditions, conditionTypeMonitorFound, tt.wantMonitorFound))
+			}
+
+			gotAPI := map[int64]int64{}
+			for id, downtime := range api.downtimes {
+				gotAPI[id] = downtime.GetMonitorId()
+				assert.Equal(t, dd.Spec.Message, downtime.GetMessage())
+			}
+			assert.Equal(t, tt.wantAPI, gotAPI)
+
+			// The finalizer cancels all the downtimes
+			assert.NoError(t, k8sClient.Delete(context.TODO(), dd))
+			_, err := r.Reconcile(context.TODO(), req)
+			assert.NoError(t, err)
+			assert.Empty(t, api.downtimes)
+		})
+	}
+}
+
+func TestReconciler_finalizerCancelError(t *testing.T) {
+	s := runtime.NewScheme()
+	_ = clientgoscheme.AddToScheme(s)
+	_ = datadoghqv1alpha1.AddToScheme(s)
+
+	api := newFakeDowntimesAPI()
+	httpServer := httptest.NewServer(api)
+	defer httpServer.Close()
+	testConfig := datadogapiclientv1.NewConfiguration()
+	testConfig.HTTPClient = httpServer.Client()
+
+	k8sClient := fake.NewClientBuilder().WithScheme(s).WithObjects(
+		newMonitor("monitor-a", map[string]string{"team": "a"}, 1),
+		newDowntime(datadoghqv1alpha1.DatadogDowntimeSpec{MonitorRef: &corev1.LocalObjectReference{Name: "monitor-a"}}),
+	).Build()
+	r := &Reconciler{
+		client:        k8sClient,
+		apiReader:     k8sClient,
+		datadogClient: datadogapiclientv1.NewAPIClient(testConfig),
+		datadogAuth:   setupTestAuth(httpServer.URL),
+		scheme:        s,
+		recorder:      record.NewFakeRecorder(10),
+		log:           logf.Log.WithName(t.Name()),
+	}
+	req := newRequest(resourcesNamespace, resourcesName)
+	for i := 0; i < 2; i++ {
+		_, err := r.Reconcile(context.TODO(), req)
+		assert.NoError(t, err)
+	}
+	assert.Len(t, api.downtimes, 1)
+
+	dd := &datadoghqv1alpha1.DatadogDowntime{}
+	assert.NoError(t, k8sClient.Get(context.TODO(), req.NamespacedName, dd))
+	assert.NoError(t, k8sClient.Delete(context.TODO(), dd))
+
+	// The finalizer is kept while the downtimes can't be canceled
+	api.Lock()
+	api.failCancel = true
+	api.Unlock()
+	_, err := r.Reconcile(context.TODO(), req)
+	assert.Error(t, err)
+	assert.NoError(t, k8sClient.Get(context.TODO(), req.NamespacedName, dd))
+	assert.Contains(t, dd.GetFinalizers(), datadogDowntimeFinalizer)
+	assert.Len(t, api.downtimes, 1)
+
+	api.Lock()
+	api.failCancel = false
+	api.Unlock()
+	_, err = r.Reconcile(context.TODO(), req)
+	assert.NoError(t, err)
+	assert.Empty(t, api.downtimes)
+}
+
+func TestReconciler_RequestsForMonitor(t *testing.T) {
+	s := runtime.NewScheme()
+	_ = datadoghqv1alpha1.AddToScheme(s)
+
+	byRef := newDowntime(datadoghqv1alpha1.DatadogDowntimeSpec{MonitorRef: &corev1.LocalObjectReference{Name: "monitor-a"}})
+	byRef.Name = "by-ref"
+	bySelector := newDowntime(datadoghqv1alpha1.DatadogDowntimeSpec{
+		MonitorSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"team": "a"}},
+	})
+	bySelector.Name = "by-selector"
+	bySelector.Status.Downtimes = []datadoghqv1alpha1.DatadogDowntimeStatusDowntime{{ID: 100, MonitorID: 2}}
+	byTags := newDowntime(datadoghqv1alpha1.DatadogDowntimeSpec{MonitorTags: []string{"team:a"}})
+	byTags.Name = "by-tags"
+	otherNamespace := byRef.DeepCopy()
+	otherNamespace.Namespace = "other"
+
+	r := &Reconciler{
+		client: fake.NewClientBuilder().WithScheme(s).WithObjects(byRef, bySelector, byTags, otherNamespace).Build(),
+		log:    logf.Log.WithName(t.Name()),
+	}
+	request := func(name string) reconcile.Request {
+		return newRequest(resourcesNamespace, name)
+	}
+
+	assert.ElementsMatch(t, []reconcile.Request{request("by-ref"), request("by-selector")}, r.RequestsForMonitor(newMonitor("monitor-a", map[string]string{"team": "a"}, 1)))
+	assert.ElementsMatch(t, []reconcile.Request{request("by-selector")}, r.RequestsForMonitor(newMonitor("monitor-b", map[string]string{"team": "b"}, 2)))
+	assert.Empty(t, r.RequestsForMonitor(newMonitor("monitor-c", map[string]string{"team": "b"}, 3)))
+}
+
+func newDowntime(spec datadoghqv1alpha1.DatadogDowntimeSpec) *datadoghqv1alpha1.DatadogDowntime {
+	start := metav1.NewTime(time.Date(2022, 6, 6, 22, 0, 0, 0, time.UTC))
+	spec.Start = &start
+	spec.Message = "maintenance"
+
+	return &datadoghqv1alpha1.DatadogDowntime{
+		ObjectMeta: metav1.ObjectMeta{Namespace: resourcesNamespace, Name: resourcesName},
+		Spec:       spec,
+	}
+}
+
+func updateDowntimeSpec(update func(spec *datadoghqv1alpha1.DatadogDowntimeSpec)) func(t *testing.T, c client.Client) {
+	return func(t *testing.T, c client.Client) {
+		dd := &datadoghqv1alpha1.DatadogDowntime{}
+		assert.NoError(t, c.Get(context.TODO(), types.NamespacedName{Namespace: resourcesNamespace, Name: resourcesName}, dd))
+		update(&dd.Spec)
+		assert.NoError(t, c.Update(context.TODO(), dd))
+	}
+}
+
+func newMonitor(name string, labels map[string]string, id int) *datadoghqv1alpha1.DatadogMonitor {
+	return &datadoghqv1alpha1.DatadogMonitor{
+		ObjectMeta: metav1.ObjectMeta{Namespace: resourcesNamespace, Name: name, Labels: labels},
+		Status:     datadoghqv1alpha1.DatadogMonitorStatus{ID: id},
+	}
+}
+
+func newRequest(ns, name string) reconcile.Request {
+	return reconcile.Request{
+		NamespacedName: types.NamespacedName{
+			Namespace: ns,
+			Name:      name,
+		},
+	}
+}
+
+func setupTestAuth(apiURL string) context.Context {
+	testAuth := context.WithValue(
+		context.Background(),
+		datadogapiclientv1.ContextAPIKeys,
+		map[string]datadogapiclientv1.APIKey{
+			"apiKeyAuth": {
+				Key: "DUMMY_API_KEY",
+			},
+			"appKeyAuth": {
+				Key: "DUMMY_APP_KEY",
+			},
+		},
+	)
+	parsedAPIURL, _ := url.Parse(apiURL)
+	testAuth = context.WithValue(testAuth, datadogapiclientv1.ContextServerIndex, 1)
+	testAuth = context.WithValue(testAuth, datadogapiclientv1.ContextServerVariables, map[string]string{
+		"name":     parsedAPIURL.Host,
+		"protocol": parsedAPIURL.Scheme,
+	})
+
+	return testAuth
+}
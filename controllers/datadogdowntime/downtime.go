@@ -0,0 +1,100 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package datadogdowntime
+
+import (
+	"context"
+	"net/http"
+
+	datadogapiclientv1 "github.com/DataDog/datadog-api-client-go/api/v1/datadog"
+	datadoghqv1alpha1 "github.com/DataDog/datadog-operator/apis/datadoghq/v1alpha1"
+	"github.com/DataDog/datadog-operator/pkg/datadogclient"
+)
+
+// buildDowntime builds the Datadog downtime of a DatadogDowntime silencing a monitor,
+// or the monitors matching the DatadogDowntime monitor tags if monitorID is 0
+func buildDowntime(dd *datadoghqv1alpha1.DatadogDowntime, monitorID int) datadogapiclientv1.Downtime {
+	spec := dd.Spec
+	downtime := datadogapiclientv1.NewDowntime()
+
+	scope := spec.Scope
+	if len(scope) == 0 {
+		scope = []string{"*"}
+	}
+	downtime.SetScope(scope)
+
+	if monitorID != 0 {
+		downtime.SetMonitorId(int64(monitorID))
+	} else {
+		downtime.SetMonitorTags(spec.MonitorTags)
+	}
+
+	if spec.Message != "" {
+		downtime.SetMessage(spec.Message)
+	}
+	if spec.Start != nil {
+		downtime.SetStart(spec.Start.Unix())
+	}
+	if spec.End != nil {
+		downtime.SetEnd(spec.End.Unix())
+	}
+	if spec.Timezone != "" {
+		downtime.SetTimezone(spec.Timezone)
+	}
+
+	if r := spec.Recurrence; r != nil {
+		recurrence := datadogapiclientv1.NewDowntimeRecurrence()
+		recurrence.SetType(string(r.Type))
+		if r.Period != 0 {
+			recurrence.SetPeriod(r.Period)
+		}
+		if len(r.WeekDays) > 0 {
+			recurrence.SetWeekDays(r.WeekDays)
+		}
+		if r.RRule != "" {
+			recurrence.SetRrule(r.RRule)
+		}
+		if r.UntilDate != nil {
+			recurrence.SetUntilDate(r.UntilDate.Unix())
+		}
+		if r.UntilOccurrences != nil {
+			recurrence.SetUntilOccurrences(*r.UntilOccurrences)
+		}
+		downtime.SetRecurrence(*recurrence)
+	}
+
+	return *downtime
+}
+
+func createDowntime(auth context.Context, client *datadogapiclientv1.APIClient, downtime datadogapiclientv1.Downtime) (datadogapiclientv1.Downtime, error) {
+	dtCreated, _, err := client.DowntimesApi.CreateDowntime(auth, downtime)
+	if err != nil {
+		return datadogapiclientv1.Downtime{}, datadogclient.TranslateClientError(err, "error creating downtime")
+	}
+
+	return dtCreated, nil
+}
+
+func updateDowntime(auth context.Context, client *datadogapiclientv1.APIClient, downtimeID int, downtime datadogapiclientv1.Downtime) error {
+	if _, _, err := client.DowntimesApi.UpdateDowntime(auth, int64(downtimeID), downtime); err != nil {
+		return datadogclient.TranslateClientError(err, "error updating downtime")
+	}
+
+	return nil
+}
+
+// cancelDowntime cancels a downtime, ignoring the downtimes that don't exist anymore
+func cancelDowntime(auth context.Context, client *datadogapiclientv1.APIClient, downtimeID int) error {
+	resp, err := client.DowntimesApi.CancelDowntime(auth, int64(downtimeID))
+	if err != nil {
+		if resp != nil && resp.StatusCode == http.StatusNotFound {
+			return nil
+		}
+		return datadogclient.TranslateClientError(err, "error canceling downtime")
+	}
+
+	return nil
+}
@@ -0,0 +1,64 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package datadogdowntime
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	datadoghqv1alpha1 "github.com/DataDog/datadog-operator/apis/datadoghq/v1alpha1"
+)
+
+func Test_buildDowntime(t *testing.T) {
+	start := metav1.NewTime(time.Date(2022, 6, 6, 22, 0, 0, 0, time.UTC))
+	end := metav1.NewTime(start.Add(time.Hour))
+	untilDate := metav1.NewTime(start.Add(30 * 24 * time.Hour))
+
+	dd := &datadoghqv1alpha1.DatadogDowntime{
+		Spec: datadoghqv1alpha1.DatadogDowntimeSpec{
+			Message:     "maintenance",
+			Scope:       []string{"env:staging"},
+			MonitorTags: []string{"team:a"},
+			Start:       &start,
+			End:         &end,
+			Timezone:    "Europe/Paris",
+			Recurrence: &datadoghqv1alpha1.DatadogDowntimeRecurrence{
+				Type:      datadoghqv1alpha1.DatadogDowntimeRecurrenceTypeWeeks,
+				Period:    2,
+				WeekDays:  []string{"Mon", "Thu"},
+				UntilDate: &untilDate,
+			},
+		},
+	}
+
+	// Silence by monitor tags
+	downtime := buildDowntime(dd, 0)
+	assert.Equal(t, "maintenance", downtime.GetMessage())
+	assert.Equal(t, []string{"env:staging"}, downtime.GetScope())
+	assert.Equal(t, []string{"team:a"}, downtime.GetMonitorTags())
+	assert.False(t, downtime.HasMonitorId())
+	assert.Equal(t, start.Unix(), downtime.GetStart())
+	assert.Equal(t, end.Unix(), downtime.GetEnd())
+	assert.Equal(t, "Europe/Paris", downtime.GetTimezone())
+
+	recurrence := downtime.GetRecurrence()
+	assert.Equal(t, "weeks", recurrence.GetType())
+	assert.Equal(t, int32(2), recurrence.GetPeriod())
+	assert.Equal(t, []string{"Mon", "Thu"}, recurrence.GetWeekDays())
+	assert.Equal(t, untilDate.Unix(), recurrence.GetUntilDate())
+	assert.False(t, recurrence.HasUntilOccurrences())
+	assert.False(t, recurrence.HasRrule())
+
+	// Silence a monitor, on all scopes by default
+	dd.Spec.Scope = nil
+	downtime = buildDowntime(dd, 42)
+	assert.Equal(t, int64(42), downtime.GetMonitorId())
+	assert.False(t, downtime.HasMonitorTags())
+	assert.Equal(t, []string{"*"}, downtime.GetScope())
+}
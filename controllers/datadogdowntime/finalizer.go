@@ -0,0 +1,98 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package datadogdowntime
+
+import (
+	"context"
+
+	"github.com/go-logr/logr"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	datadoghqv1alpha1 "github.com/DataDog/datadog-operator/apis/datadoghq/v1alpha1"
+	"github.com/DataDog/datadog-operator/pkg/controller/utils"
+	"github.com/DataDog/datadog-operator/pkg/controller/utils/datadog"
+)
+
+const (
+	datadogDowntimeFinalizer = "finalizer.downtime.datadoghq.com"
+)
+
+func (r *Reconciler) handleFinalizer(logger logr.Logger, dd *datadoghqv1alpha1.DatadogDowntime) (ctrl.Result, error) {
+	// Check if the DatadogDowntime instance is marked to be deleted, which is indicated by the deletion timestamp being set.
+	if dd.GetDeletionTimestamp() != nil {
+		if utils.ContainsString(dd.GetFinalizers(), datadogDowntimeFinalizer) {
+			// Keep the finalizer until all the downtimes are canceled, so that the monitors aren't silenced forever
+			if err := r.finalizeDatadogDowntime(logger, dd); err != nil {
+				return ctrl.Result{Requeue: true, RequeueAfter: defaultErrRequeuePeriod}, err
+			}
+
+			dd.SetFinalizers(utils.RemoveString(dd.GetFinalizers(), datadogDowntimeFinalizer))
+			err := r.client.Update(context.TODO(), dd)
+			if err != nil {
+				return ctrl.Result{Requeue: true, RequeueAfter: defaultErrRequeuePeriod}, err
+			}
+		}
+
+		// Requeue until the object was properly deleted by Kuberentes
+		return ctrl.Result{Requeue: true, RequeueAfter: defaultRequeuePeriod}, nil
+	}
+
+	// Add finalizer for this resource if it doesn't already exist.
+	if !utils.ContainsString(dd.GetFinalizers(), datadogDowntimeFinalizer) {
+		if err := r.addFinalizer(logger, dd); err != nil {
+			return ctrl.Result{Requeue: true, RequeueAfter: defaultErrRequeuePeriod}, err
+		}
+
+		return ctrl.Result{Requeue: true, RequeueAfter: defaultRequeuePeriod}, nil
+	}
+
+	// Proceed in reconcile loop.
+	return ctrl.Result{}, nil
+}
+
+func (r *Reconciler) finalizeDatadogDowntime(logger logr.Logger, dd *datadoghqv1alpha1.DatadogDowntime) error {
+	if len(dd.Status.Downtimes) == 0 {
+		return nil
+	}
+
+	datadogAuth, datadogClient, err := r.getDatadogClient(dd)
+	if err != nil {
+		logger.Error(err, "failed to finalize downtimes")
+
+		return err
+	}
+
+	var errs []error
+	for _, downtime := range dd.Status.Downtimes {
+		if err = cancelDowntime(datadogAuth, datadogClient, downtime.ID); err != nil {
+			logger.Error(err, "failed to finalize downtime", "Downtime ID", downtime.ID)
+			errs = append(errs, err)
+		}
+	}
+	if len(errs) > 0 {
+		return utilerrors.NewAggregate(errs)
+	}
+	logger.Info("Successfully finalized DatadogDowntime")
+	event := buildEventInfo(dd.Name, dd.Namespace, datadog.DeletionEvent)
+	r.recordEvent(dd, event)
+
+	return nil
+}
+
+func (r *Reconciler) addFinalizer(logger logr.Logger, dd *datadoghqv1alpha1.DatadogDowntime) error {
+	logger.Info("Adding Finalizer for the DatadogDowntime")
+
+	dd.SetFinalizers(append(dd.GetFinalizers(), datadogDowntimeFinalizer))
+
+	err := r.client.Update(context.TODO(), dd)
+	if err != nil {
+		logger.Error(err, "failed to update DatadogDowntime with finalizer")
+		return err
+	}
+
+	return nil
+}
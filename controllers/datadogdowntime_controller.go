@@ -0,0 +1,68 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package controllers
+
+import (
+	"context"
+
+	"github.com/go-logr/logr"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+
+	datadoghqv1alpha1 "github.com/DataDog/datadog-operator/apis/datadoghq/v1alpha1"
+	"github.com/DataDog/datadog-operator/controllers/datadogdowntime"
+	"github.com/DataDog/datadog-operator/pkg/datadogclient"
+)
+
+// DatadogDowntimeReconciler reconciles a DatadogDowntime object.
+type DatadogDowntimeReconciler struct {
+	Client   client.Client
+	DDClient datadogclient.DatadogClient
+	Log      logr.Logger
+	Scheme   *runtime.Scheme
+	Recorder record.EventRecorder
+	internal *datadogdowntime.Reconciler
+}
+
+// +kubebuilder:rbac:groups=datadoghq.com,resources=datadogdowntimes,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=datadoghq.com,resources=datadogdowntimes/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=datadoghq.com,resources=datadogdowntimes/finalizers,verbs=get;list;watch;create;update;patch;delete
+
+// Reconcile loop for DatadogDowntime.
+func (r *DatadogDowntimeReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	return r.internal.Reconcile(ctx, req)
+}
+
+// UpdateDatadogClient replaces the Datadog API Client used by the controller.
+func (r *DatadogDowntimeReconciler) UpdateDatadogClient(ddClient datadogclient.DatadogClient) {
+	if r.internal != nil {
+		r.internal.UpdateDatadogClient(ddClient)
+	}
+}
+
+// SetupWithManager creates a new DatadogDowntime controller.
+func (r *DatadogDowntimeReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	internal, err := datadogdowntime.NewReconciler(r.Client, mgr.GetAPIReader(), r.DDClient, r.Scheme, r.Log, r.Recorder)
+	if err != nil {
+		return err
+	}
+	r.internal = internal
+
+	builder := ctrl.NewControllerManagedBy(mgr).
+		For(&datadoghqv1alpha1.DatadogDowntime{}).
+		Watches(&source.Kind{Type: &datadoghqv1alpha1.DatadogMonitor{}}, handler.EnqueueRequestsFromMapFunc(internal.RequestsForMonitor))
+
+	err = builder.Complete(r)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,121 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package datadogmonitor
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strconv"
+
+	apiequality "k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	datadoghqv1alpha1 "github.com/DataDog/datadog-operator/apis/datadoghq/v1alpha1"
+)
+
+// compositeReferenceRegexp matches the references to DatadogMonitors in a composite monitor query, e.g. `${monitor-a}`
+var compositeReferenceRegexp = regexp.MustCompile(`\$\{([a-z0-9]([-a-z0-9.]*[a-z0-9])?)\}`)
+
+// getCompositeReferences returns the names of the DatadogMonitors referenced by a composite monitor, without duplicates
+func getCompositeReferences(dm *datadoghqv1alpha1.DatadogMonitor) []string {
+	if dm.Spec.Type != datadoghqv1alpha1.DatadogMonitorTypeComposite {
+		return nil
+	}
+
+	var names []string
+	seen := map[string]bool{}
+	for _, match := range compositeReferenceRegexp.FindAllStringSubmatch(dm.Spec.Query, -1) {
+		if !seen[match[1]] {
+			seen[match[1]] = true
+			names = append(names, match[1])
+		}
+	}
+
+	return names
+}
+
+// renderCompositeQuery replaces the DatadogMonitor references of a composite monitor query by the monitor IDs
+func renderCompositeQuery(query string, ids map[string]int) string {
+	return compositeReferenceRegexp.ReplaceAllStringFunc(query, func(reference string) string {
+		name := compositeReferenceRegexp.FindStringSubmatch(reference)[1]
+		return strconv.Itoa(ids[name])
+	})
+}
+
+// resolveCompositeQuery returns the query of a composite DatadogMonitor with its references to other DatadogMonitors
+// replaced by their IDs. The referenced DatadogMonitors must be created in Datadog first.
+func (r *Reconciler) resolveCompositeQuery(ctx context.Context, dm *datadoghqv1alpha1.DatadogMonitor) (string, error) {
+	names := getCompositeReferences(dm)
+	ids := make(map[string]int, len(names))
+	for _, name := range names {
+		if name == dm.Name {
+			return "", fmt.Errorf("composite DatadogMonitor %s can't reference itself", dm.Name)
+		}
+
+		ref := &datadoghqv1alpha1.DatadogMonitor{}
+		if err := r.client.Get(ctx, types.NamespacedName{Namespace: dm.Namespace, Name: name}, ref); err != nil {
+			return "", fmt.Errorf("unable to get DatadogMonitor %s: %w", name, err)
+		}
+		if ref.Status.ID == 0 {
+			return "", fmt.Errorf("DatadogMonitor %s is not created in Datadog yet", name)
+		}
+		if !apiequality.Semantic.DeepEqual(ref.Spec.CredentialsSecretRef, dm.Spec.CredentialsSecretRef) {
+			return "", fmt.Errorf("DatadogMonitor %s doesn't use the credentials of the composite DatadogMonitor", name)
+		}
+		ids[name] = ref.Status.ID
+	}
+
+	return renderCompositeQuery(dm.Spec.Query, ids), nil
+}
+
+// getReferencingComposites returns the names of the composite DatadogMonitors referencing a DatadogMonitor
+func (r *Reconciler) getReferencingComposites(ctx context.Context, dm *datadoghqv1alpha1.DatadogMonitor) ([]string, error) {
+	dmList := &datadoghqv1alpha1.DatadogMonitorList{}
+	if err := r.client.List(ctx, dmList, client.InNamespace(dm.Namespace)); err != nil {
+		return nil, fmt.Errorf("unable to list DatadogMonitors: %w", err)
+	}
+
+	var composites []string
+	for i := range dmList.Items {
+		for _, name := range getCompositeReferences(&dmList.Items[i]) {
+			if name == dm.Name {
+				composites = append(composites, dmList.Items[i].Name)
+				break
+			}
+		}
+	}
+
+	return composites, nil
+}
+
+// RequestsForRelatedMonitors returns the reconcile requests of the composite DatadogMonitors referencing a DatadogMonitor,
+// so that their query is rendered again when the monitor is created or recreated in Datadog, and of the DatadogMonitors
+// referenced by a composite DatadogMonitor, so that their deletion is unblocked when the composite is deleted
+func (r *Reconciler) RequestsForRelatedMonitors(obj client.Object) []reconcile.Request {
+	dm, ok := obj.(*datadoghqv1alpha1.DatadogMonitor)
+	if !ok {
+		return nil
+	}
+
+	var requests []reconcile.Request
+	for _, name := range getCompositeReferences(dm) {
+		requests = append(requests, reconcile.Request{NamespacedName: types.NamespacedName{Namespace: dm.Namespace, Name: name}})
+	}
+
+	composites, err := r.getReferencingComposites(context.TODO(), dm)
+	if err != nil {
+		r.log.Error(err, "unable to get the composite DatadogMonitors")
+		return requests
+	}
+	for _, name := range composites {
+		requests = append(requests, reconcile.Request{NamespacedName: types.NamespacedName{Namespace: dm.Namespace, Name: name}})
+	}
+
+	return requests
+}
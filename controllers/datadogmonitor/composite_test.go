@@ -0,0 +1,219 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package datadogmonitor
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	datadogapiclientv1 "github.com/DataDog/datadog-api-client-go/api/v1/datadog"
+	datadoghqv1alpha1 "github.com/DataDog/datadog-operator/apis/datadoghq/v1alpha1"
+	"github.com/DataDog/datadog-operator/pkg/controller/utils"
+)
+
+func newCompositeTestMonitor(name string, id int, monitorType datadoghqv1alpha1.DatadogMonitorType, query string) *datadoghqv1alpha1.DatadogMonitor {
+	return &datadoghqv1alpha1.DatadogMonitor{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace:  resourcesNamespace,
+			Name:       name,
+			Finalizers: []string{datadogMonitorFinalizer},
+		},
+		Spec: datadoghqv1alpha1.DatadogMonitorSpec{
+			Name:    name,
+			Message: "something is wrong",
+			Type:    monitorType,
+			Query:   query,
+			Tags:    getRequiredTags(),
+		},
+		Status: datadoghqv1alpha1.DatadogMonitorStatus{ID: id, Primary: true},
+	}
+}
+
+func Test_getCompositeReferences(t *testing.T) {
+	composite := newCompositeTestMonitor("composite", 0, datadoghqv1alpha1.DatadogMonitorTypeComposite, "${monitor-a} && (${monitor-b} || !${monitor-a}) && 1234")
+	assert.Equal(t, []string{"monitor-a", "monitor-b"}, getCompositeReferences(composite))
+
+	composite.Spec.Query = "1234 && 5678"
+	assert.Empty(t, getCompositeReferences(composite))
+
+	metric := newCompositeTestMonitor("metric", 0, datadoghqv1alpha1.DatadogMonitorTypeMetric, "${monitor-a}")
+	assert.Empty(t, getCompositeReferences(metric))
+}
+
+func Test_renderCompositeQuery(t *testing.T) {
+	query := renderCompositeQuery("${monitor-a} && (${monitor-b} || !${monitor-a}) && 1234", map[string]int{"monitor-a": 1, "monitor-b": 2})
+	assert.Equal(t, "1 && (2 || !1) && 1234", query)
+}
+
+func TestReconciler_resolveCompositeQuery(t *testing.T) {
+	ref := &datadoghqv1alpha1.DatadogCredentialsSecretRef{Name: "creds"}
+	monitorA := newCompositeTestMonitor("monitor-a", 1, datadoghqv1alpha1.DatadogMonitorTypeMetric, "")
+	monitorB := newCompositeTestMonitor("monitor-b", 0, datadoghqv1alpha1.DatadogMonitorTypeMetric, "")
+	monitorC := newCompositeTestMonitor("monitor-c", 3, datadoghqv1alpha1.DatadogMonitorTypeMetric, "")
+	monitorC.Spec.CredentialsSecretRef = ref
+
+	r := &Reconciler{client: newTestClient(monitorA, monitorB, monitorC)}
+
+	tests := []struct {
+		name    string
+		query   string
+		want    string
+		wantErr string
+	}{
+		{
+			name:  "resolved",
+			query: "${monitor-a} && 1234",
+			want:  "1 && 1234",
+		},
+		{
+			name:    "missing monitor",
+			query:   "${monitor-a} && ${monitor-d}",
+			wantErr: "unable to get DatadogMonitor monitor-d",
+		},
+		{
+			name:    "monitor not created yet",
+			query:   "${monitor-a} && ${monitor-b}",
+			wantErr: "DatadogMonitor monitor-b is not created in Datadog yet",
+		},
+		{
+			name:    "different credentials",
+			query:   "${monitor-a} && ${monitor-c}",
+			wantErr: "DatadogMonitor monitor-c doesn't use the credentials of the composite DatadogMonitor",
+		},
+		{
+			name:    "self reference",
+			query:   "${monitor-a} && ${composite}",
+			wantErr: "composite DatadogMonitor composite can't reference itself",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			composite := newCompositeTestMonitor("composite", 0, datadoghqv1alpha1.DatadogMonitorTypeComposite, tt.query)
+			got, err := r.resolveCompositeQuery(context.TODO(), composite)
+			if tt.wantErr != "" {
+				assert.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestReconciler_RequestsForRelatedMonitors(t *testing.T) {
+	monitorA := newCompositeTestMonitor("monitor-a", 1, datadoghqv1alpha1.DatadogMonitorTypeMetric, "")
+	monitorB := newCompositeTestMonitor("monitor-b", 2, datadoghqv1alpha1.DatadogMonitorTypeMetric, "")
+	composite := newCompositeTestMonitor("composite", 3, datadoghqv1alpha1.DatadogMonitorTypeComposite, "${monitor-a} && ${monitor-b}")
+
+	r := &Reconciler{
+		client: newTestClient(monitorA, monitorB, composite),
+		log:    testLogger,
+	}
+
+	request := func(name string) reconcile.Request {
+		return reconcile.Request{NamespacedName: types.NamespacedName{Namespace: resourcesNamespace, Name: name}}
+	}
+	assert.Equal(t, []reconcile.Request{request("composite")}, r.RequestsForRelatedMonitors(monitorA))
+	assert.Equal(t, []reconcile.Request{request("monitor-a"), request("monitor-b")}, r.RequestsForRelatedMonitors(composite))
+}
+
+func Test_handleFinalizer_referencedByComposite(t *testing.T) {
+	metaNow := metav1.NewTime(time.Now())
+	monitorA := newCompositeTestMonitor("monitor-a", 1, datadoghqv1alpha1.DatadogMonitorTypeMetric, "")
+	monitorA.Status.Primary = false
+	monitorA.DeletionTimestamp = &metaNow
+	composite := newCompositeTestMonitor("composite", 2, datadoghqv1alpha1.DatadogMonitorTypeComposite, "${monitor-a} && 1234")
+	composite.Finalizers = nil
+
+	r := &Reconciler{
+		client:   newTestClient(monitorA, composite),
+		log:      testLogger,
+		recorder: record.NewFakeRecorder(10),
+	}
+
+	// The deletion is blocked while the composite monitor references the monitor
+	result, err := r.handleFinalizer(testLogger, monitorA)
+	assert.NoError(t, err)
+	assert.Equal(t, defaultRequeuePeriod, result.RequeueAfter)
+	assert.True(t, utils.ContainsString(monitorA.GetFinalizers(), datadogMonitorFinalizer))
+	assert.Equal(t, "Warning DeletionBlocked DatadogMonitor is referenced by composite DatadogMonitors: composite", <-r.recorder.(*record.FakeRecorder).Events)
+
+	// The monitor is finalized once the composite monitor is deleted
+	assert.NoError(t, r.client.Delete(context.TODO(), composite))
+	_, err = r.handleFinalizer(testLogger, monitorA)
+	assert.NoError(t, err)
+	assert.False(t, utils.ContainsString(monitorA.GetFinalizers(), datadogMonitorFinalizer))
+}
+
+func TestReconciler_Reconcile_composite(t *testing.T) {
+	var sentQueries []string
+	httpServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.Path == "/api/v1/monitor/validate" {
+			_, _ = w.Write([]byte("{}"))
+			return
+		}
+		body := map[string]interface{}{}
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		sentQueries = append(sentQueries, body["query"].(string))
+		body["id"] = 3
+		_ = json.NewEncoder(w).Encode(body)
+	}))
+	defer httpServer.Close()
+
+	testConfig := datadogapiclientv1.NewConfiguration()
+	testConfig.HTTPClient = httpServer.Client()
+
+	monitorA := newCompositeTestMonitor("monitor-a", 1, datadoghqv1alpha1.DatadogMonitorTypeMetric, "")
+	monitorB := newCompositeTestMonitor("monitor-b", 2, datadoghqv1alpha1.DatadogMonitorTypeMetric, "")
+	composite := newCompositeTestMonitor("composite", 0, datadoghqv1alpha1.DatadogMonitorTypeComposite, "${monitor-a} && ${monitor-b}")
+	composite.Status = datadoghqv1alpha1.DatadogMonitorStatus{}
+
+	r := &Reconciler{
+		client:        newTestClient(monitorA, monitorB, composite),
+		datadogClient: datadogapiclientv1.NewAPIClient(testConfig),
+		datadogAuth:   setupTestAuth(httpServer.URL),
+		recorder:      record.NewFakeRecorder(10),
+		log:           testLogger,
+	}
+	getComposite := func() *datadoghqv1alpha1.DatadogMonitor {
+		dm := &datadoghqv1alpha1.DatadogMonitor{}
+		assert.NoError(t, r.client.Get(context.TODO(), types.NamespacedName{Namespace: resourcesNamespace, Name: "composite"}, dm))
+		return dm
+	}
+	request := reconcile.Request{NamespacedName: types.NamespacedName{Namespace: resourcesNamespace, Name: "composite"}}
+
+	// The composite monitor is created with the IDs of the referenced monitors
+	_, err := r.Reconcile(context.TODO(), request)
+	assert.NoError(t, err)
+	dm := getComposite()
+	assert.Equal(t, []string{"1 && 2"}, sentQueries)
+	assert.Equal(t, 3, dm.Status.ID)
+	assert.Equal(t, "1 && 2", dm.Status.ResolvedQuery)
+	assert.Equal(t, "${monitor-a} && ${monitor-b}", dm.Spec.Query)
+
+	// The composite monitor is updated when a referenced monitor is recreated
+	monitorA.Status.ID = 10
+	assert.NoError(t, r.client.Status().Update(context.TODO(), monitorA))
+	_, err = r.Reconcile(context.TODO(), request)
+	assert.NoError(t, err)
+	dm = getComposite()
+	assert.Equal(t, []string{"1 && 2", "10 && 2"}, sentQueries)
+	assert.Equal(t, "10 && 2", dm.Status.ResolvedQuery)
+	assert.Equal(t, corev1.ConditionTrue, dm.Status.Conditions[0].Status)
+}
@@ -9,6 +9,8 @@ import (
 	"context"
 	"fmt"
 	"sort"
+	"strconv"
+	"sync"
 	"time"
 
 	"github.com/go-logr/logr"
@@ -36,6 +38,7 @@ import (
 const (
 	defaultRequeuePeriod    = 60 * time.Second
 	defaultErrRequeuePeriod = 5 * time.Second
+	clusterNameTagKey       = "kube_cluster_name"
 	maxTriggeredStateGroups = 10
 )
 
@@ -51,32 +54,81 @@ var supportedMonitorTypes = map[string]bool{
 	string(datadogapiclientv1.MONITORTYPE_SLO_ALERT):             true,
 	string(datadogapiclientv1.MONITORTYPE_EVENT_V2_ALERT):        true,
 	string(datadogapiclientv1.MONITORTYPE_AUDIT_ALERT):           true,
+	string(datadogapiclientv1.MONITORTYPE_COMPOSITE):             true,
 }
 
 // Reconciler reconciles a DatadogMonitor object
 type Reconciler struct {
 	client        client.Client
+	apiReader     client.Reader
 	datadogClient *datadogapiclientv1.APIClient
 	datadogAuth   context.Context
+	datadogMutex  sync.RWMutex
+	clients       datadogclient.ClientCache
 	versionInfo   *version.Info
 	log           logr.Logger
 	scheme        *runtime.Scheme
 	recorder      record.EventRecorder
+	options       ReconcilerOptions
+}
+
+// ReconcilerOptions provides options read from command line
+type ReconcilerOptions struct {
+	// DefaultDeletionPolicy applies to the DatadogMonitors without deletion policy
+	DefaultDeletionPolicy datadoghqv1alpha1.DatadogMonitorDeletionPolicy
+	// ClusterName identifies the monitors managed by the operator in this cluster, with the kube_cluster_name tag
+	ClusterName string
+	// OrphanGCEnabled enables the deletion of the monitors of this cluster without DatadogMonitor
+	OrphanGCEnabled bool
+	// OrphanGCGracePeriod is the time an orphan monitor is reported before being deleted
+	OrphanGCGracePeriod time.Duration
+	// WebhookReceiverAddress is the address of the endpoint receiving the Datadog webhook notifications, the
+	// endpoint is disabled if empty
+	WebhookReceiverAddress string
+	// WebhookReceiverToken is the bearer token authenticating the Datadog webhook notifications
+	WebhookReceiverToken string
 }
 
 // NewReconciler returns a new Reconciler object
-func NewReconciler(client client.Client, ddClient datadogclient.DatadogClient, versionInfo *version.Info, scheme *runtime.Scheme, log logr.Logger, recorder record.EventRecorder) (*Reconciler, error) {
+func NewReconciler(client client.Client, apiReader client.Reader, ddClient datadogclient.DatadogClient, versionInfo *version.Info, scheme *runtime.Scheme, log logr.Logger, recorder record.EventRecorder, options ReconcilerOptions) (*Reconciler, error) {
 	return &Reconciler{
 		client:        client,
+		apiReader:     apiReader,
 		datadogClient: ddClient.Client,
 		datadogAuth:   ddClient.Auth,
 		versionInfo:   versionInfo,
 		scheme:        scheme,
 		log:           log,
 		recorder:      recorder,
+		options:       options,
 	}, nil
 }
 
+// UpdateDatadogClient replaces the Datadog API client, e.g. when the operator credentials are rotated
+func (r *Reconciler) UpdateDatadogClient(ddClient datadogclient.DatadogClient) {
+	r.datadogMutex.Lock()
+	defer r.datadogMutex.Unlock()
+	r.datadogClient = ddClient.Client
+	r.datadogAuth = ddClient.Auth
+}
+
+// getDatadogClient returns the Datadog API authentication context and client of a DatadogMonitor:
+// the ones of its credentials Secret if referenced, the operator ones otherwise
+func (r *Reconciler) getDatadogClient(dm *datadoghqv1alpha1.DatadogMonitor) (context.Context, *datadogapiclientv1.APIClient, error) {
+	if dm.Spec.CredentialsSecretRef != nil {
+		ddClient, err := r.clients.GetFromSecret(r.apiReader, dm.Namespace, dm.Spec.CredentialsSecretRef)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		return ddClient.Auth, ddClient.Client, nil
+	}
+
+	r.datadogMutex.RLock()
+	defer r.datadogMutex.RUnlock()
+	return r.datadogAuth, r.datadogClient, nil
+}
+
 // Reconcile is similar to reconciler.Reconcile interface, but taking a context
 func (r *Reconciler) Reconcile(ctx context.Context, request reconcile.Request) (reconcile.Result, error) {
 	return r.internalReconcile(ctx, request)
@@ -125,6 +177,21 @@ func (r *Reconciler) internalReconcile(ctx context.Context, req reconcile.Reques
 
 	statusSpecHash := instance.Status.CurrentHash
 
+	// Render the query of a composite monitor with the IDs of the DatadogMonitors it references. The monitor synced
+	// with Datadog is a copy, as the rendered query must not be persisted in the DatadogMonitor spec
+	toSync := instance
+	var resolvedQuery string
+	if instance.Spec.Type == datadoghqv1alpha1.DatadogMonitorTypeComposite {
+		if resolvedQuery, err = r.resolveCompositeQuery(ctx, instance); err != nil {
+			logger.Error(err, "error resolving composite monitor query")
+			result.RequeueAfter = defaultRequeuePeriod
+
+			return r.updateStatusIfNeeded(logger, instance, now, newStatus, err, result)
+		}
+		toSync = instance.DeepCopy()
+		toSync.Spec.Query = resolvedQuery
+	}
+
 	// Create or update monitor, or check monitor state. Fall through this block (without returning)
 	// if the result should be requeued with the default period
 	if instance.Status.ID == 0 {
@@ -136,8 +203,17 @@ func (r *Reconciler) internalReconcile(ctx context.Context, req reconcile.Reques
 				return r.updateStatusIfNeeded(logger, instance, now, newStatus, err, result)
 			}
 
-			if err = r.create(logger, instance, newStatus, now); err != nil {
+			if monitorID, found := instance.Annotations[datadoghqv1alpha1.DatadogMonitorAdoptAnnotationKey]; found {
+				// Take ownership of an existing monitor instead of creating a new one
+				if err = r.adopt(ctx, logger, toSync, monitorID, newStatus, now); err != nil {
+					logger.Error(err, "error adopting monitor", "Monitor ID", monitorID)
+				} else {
+					newStatus.ResolvedQuery = resolvedQuery
+				}
+			} else if err = r.create(logger, toSync, newStatus, now); err != nil {
 				logger.Error(err, "error creating monitor")
+			} else {
+				newStatus.ResolvedQuery = resolvedQuery
 			}
 			newStatus.CurrentHash = instanceSpecHash
 		} else {
@@ -147,23 +223,25 @@ func (r *Reconciler) internalReconcile(ctx context.Context, req reconcile.Reques
 			return r.updateStatusIfNeeded(logger, instance, now, newStatus, err, result)
 		}
 	} else {
-		// Check if instance needs to be updated
-		if instanceSpecHash != statusSpecHash {
-			// Make sure required tags are present
-			if result, err = r.checkRequiredTags(logger, instance); err != nil || result.Requeue {
-				return r.updateStatusIfNeeded(logger, instance, now, newStatus, err, result)
-			}
+		// Make sure required tags are present, e.g. the cluster name tag added after the monitor creation
+		if result, err = r.checkRequiredTags(logger, instance); err != nil || result.Requeue {
+			return r.updateStatusIfNeeded(logger, instance, now, newStatus, err, result)
+		}
+		// Check if instance needs to be updated, or if a monitor referenced by a composite monitor was recreated
+		if instanceSpecHash != statusSpecHash || resolvedQuery != instance.Status.ResolvedQuery {
 			// Update action
-			if err = r.update(logger, instance, newStatus, now); err != nil {
+			if err = r.update(logger, toSync, newStatus, now); err != nil {
 				logger.Error(err, "error updating monitor", "Monitor ID", instance.Status.ID)
 			} else {
 				newStatus.CurrentHash = instanceSpecHash
+				newStatus.ResolvedQuery = resolvedQuery
 			}
 		} else {
 			// Spec has not changed, just check if monitor state has changed (alert, warn, OK, etc.)
 			// We only do it every defaultRequeuePeriod to avoid overloading APIServer and DD
 			// controller-runtime does not support Watch with Resync per controller, so doing it manually
 			// see https://github.com/kubernetes-sigs/controller-runtime/blob/master/pkg/manager/manager.go#L108-L133
+			// The StateSyncer usually syncs the state first, the monitor is only fetched if it wasn't synced in time
 			if instance.Status.MonitorStateLastUpdateTime != nil {
 				nextUpdateIn := defaultRequeuePeriod - now.Sub(instance.Status.MonitorStateLastUpdateTime.Time)
 				if nextUpdateIn > 0 {
@@ -171,7 +249,7 @@ func (r *Reconciler) internalReconcile(ctx context.Context, req reconcile.Reques
 				}
 			}
 
-			if err = r.get(logger, instance, newStatus, now); err != nil {
+			if err = r.get(logger, toSync, newStatus, now); err != nil {
 				logger.Error(err, "error getting monitor", "Monitor ID", instance.Status.ID)
 			}
 		}
@@ -187,13 +265,19 @@ func (r *Reconciler) internalReconcile(ctx context.Context, req reconcile.Reques
 }
 
 func (r *Reconciler) create(logger logr.Logger, datadogMonitor *datadoghqv1alpha1.DatadogMonitor, status *datadoghqv1alpha1.DatadogMonitorStatus, now metav1.Time) error {
+	datadogAuth, datadogClient, err := r.getDatadogClient(datadogMonitor)
+	if err != nil {
+		status.SyncStatus = datadoghqv1alpha1.SyncStatusCredentialsError
+		return err
+	}
+
 	// Validate monitor in Datadog
-	if err := validateMonitor(r.datadogAuth, logger, r.datadogClient, datadogMonitor); err != nil {
+	if err = validateMonitor(datadogAuth, logger, datadogClient, datadogMonitor); err != nil {
 		return err
 	}
 
 	// Create monitor in Datadog
-	m, err := createMonitor(r.datadogAuth, logger, r.datadogClient, datadogMonitor)
+	m, err := createMonitor(datadogAuth, logger, datadogClient, datadogMonitor)
 	if err != nil {
 		return err
 	}
@@ -207,6 +291,7 @@ func (r *Reconciler) create(logger logr.Logger, datadogMonitor *datadoghqv1alpha
 	createdTime := metav1.NewTime(m.GetCreated())
 	status.Created = &createdTime
 	status.Primary = true
+	status.SyncedQuery = m.GetQuery()
 	status.SyncStatus = ""
 
 	// Set Created Condition
@@ -216,18 +301,112 @@ func (r *Reconciler) create(logger logr.Logger, datadogMonitor *datadoghqv1alpha
 	return nil
 }
 
+// adopt takes ownership of the existing monitor monitorID, and overwrites it with the DatadogMonitor spec
+func (r *Reconciler) adopt(ctx context.Context, logger logr.Logger, datadogMonitor *datadoghqv1alpha1.DatadogMonitor, monitorID string, status *datadoghqv1alpha1.DatadogMonitorStatus, now metav1.Time) error {
+	id, err := strconv.Atoi(monitorID)
+	if err != nil || id <= 0 {
+		status.SyncStatus = datadoghqv1alpha1.SyncStatusAdoptError
+		return fmt.Errorf("invalid monitor ID %q in annotation %s", monitorID, datadoghqv1alpha1.DatadogMonitorAdoptAnnotationKey)
+	}
+
+	// A monitor is managed by a single DatadogMonitor
+	if owner, err := r.getMonitorOwner(ctx, datadogMonitor, id); err != nil {
+		return err
+	} else if owner != "" {
+		status.SyncStatus = datadoghqv1alpha1.SyncStatusAdoptError
+		return fmt.Errorf("monitor %d is already managed by DatadogMonitor %s", id, owner)
+	}
+
+	datadogAuth, datadogClient, err := r.getDatadogClient(datadogMonitor)
+	if err != nil {
+		status.SyncStatus = datadoghqv1alpha1.SyncStatusCredentialsError
+		return err
+	}
+
+	// Make sure the monitor exists in Datadog
+	m, err := getMonitor(datadogAuth, datadogClient, id)
+	if err != nil {
+		status.SyncStatus = datadoghqv1alpha1.SyncStatusAdoptError
+		return err
+	}
+
+	// Validate monitor in Datadog
+	if err = validateMonitor(datadogAuth, logger, datadogClient, datadogMonitor); err != nil {
+		status.SyncStatus = datadoghqv1alpha1.SyncStatusValidateError
+		return err
+	}
+
+	// Overwrite the monitor with the DatadogMonitor spec
+	adopted := datadogMonitor.DeepCopy()
+	adopted.Status.ID = id
+	updated, err := updateMonitor(datadogAuth, logger, datadogClient, adopted)
+	if err != nil {
+		status.SyncStatus = datadoghqv1alpha1.SyncStatusUpdateError
+		return err
+	}
+
+	event := buildEventInfo(datadogMonitor.Name, datadogMonitor.Namespace, datadog.AdoptionEvent)
+	r.recordEvent(datadogMonitor, event)
+
+	// The monitor is now owned by the DatadogMonitor, and deleted with it
+	status.ID = id
+	creator := m.GetCreator()
+	status.Creator = creator.GetEmail()
+	createdTime := metav1.NewTime(m.GetCreated())
+	status.Created = &createdTime
+	status.Primary = true
+	status.SyncedQuery = updated.GetQuery()
+	status.SyncStatus = datadoghqv1alpha1.SyncStatusOK
+
+	// Set Created Condition
+	condition.UpdateDatadogMonitorConditions(status, now, datadoghqv1alpha1.DatadogMonitorConditionTypeCreated, corev1.ConditionTrue, "DatadogMonitor Adopted")
+	logger.Info("Adopted an existing monitor", "Monitor Namespace", datadogMonitor.Namespace, "Monitor Name", datadogMonitor.Name, "Monitor ID", id)
+
+	return nil
+}
+
+// getMonitorOwner returns the namespaced name of the other DatadogMonitor managing the monitor monitorID with the same
+// credentials, if any
+func (r *Reconciler) getMonitorOwner(ctx context.Context, datadogMonitor *datadoghqv1alpha1.DatadogMonitor, monitorID int) (string, error) {
+	dmList := &datadoghqv1alpha1.DatadogMonitorList{}
+	if err := r.client.List(ctx, dmList); err != nil {
+		return "", fmt.Errorf("unable to list DatadogMonitors: %w", err)
+	}
+
+	for _, dm := range dmList.Items {
+		if dm.Status.ID != monitorID || (dm.Namespace == datadogMonitor.Namespace && dm.Name == datadogMonitor.Name) {
+			continue
+		}
+		// Monitors of different organizations may have the same ID
+		if dm.Spec.CredentialsSecretRef == nil && datadogMonitor.Spec.CredentialsSecretRef == nil ||
+			dm.Namespace == datadogMonitor.Namespace && apiequality.Semantic.DeepEqual(dm.Spec.CredentialsSecretRef, datadogMonitor.Spec.CredentialsSecretRef) {
+			return fmt.Sprintf("%s/%s", dm.Namespace, dm.Name), nil
+		}
+	}
+
+	return "", nil
+}
+
 func (r *Reconciler) update(logger logr.Logger, datadogMonitor *datadoghqv1alpha1.DatadogMonitor, status *datadoghqv1alpha1.DatadogMonitorStatus, now metav1.Time) error {
+	datadogAuth, datadogClient, err := r.getDatadogClient(datadogMonitor)
+	if err != nil {
+		status.SyncStatus = datadoghqv1alpha1.SyncStatusCredentialsError
+		return err
+	}
+
 	// Validate monitor in Datadog
-	if err := validateMonitor(r.datadogAuth, logger, r.datadogClient, datadogMonitor); err != nil {
+	if err = validateMonitor(datadogAuth, logger, datadogClient, datadogMonitor); err != nil {
 		status.SyncStatus = datadoghqv1alpha1.SyncStatusValidateError
 		return err
 	}
 
 	// Update monitor in Datadog
-	if _, err := updateMonitor(r.datadogAuth, logger, r.datadogClient, datadogMonitor); err != nil {
+	m, err := updateMonitor(datadogAuth, logger, datadogClient, datadogMonitor)
+	if err != nil {
 		status.SyncStatus = datadoghqv1alpha1.SyncStatusUpdateError
 		return err
 	}
+	status.SyncedQuery = m.GetQuery()
 
 	event := buildEventInfo(datadogMonitor.Name, datadogMonitor.Namespace, datadog.UpdateEvent)
 	r.recordEvent(datadogMonitor, event)
@@ -242,14 +421,29 @@ func (r *Reconciler) update(logger logr.Logger, datadogMonitor *datadoghqv1alpha
 
 func (r *Reconciler) get(logger logr.Logger, datadogMonitor *datadoghqv1alpha1.DatadogMonitor, status *datadoghqv1alpha1.DatadogMonitorStatus, now metav1.Time) error {
 	// Get monitor from Datadog and update resource status if needed
-	m, err := getMonitor(r.datadogAuth, r.datadogClient, datadogMonitor.Status.ID)
+	datadogAuth, datadogClient, err := r.getDatadogClient(datadogMonitor)
+	if err != nil {
+		status.SyncStatus = datadoghqv1alpha1.SyncStatusCredentialsError
+		return err
+	}
+
+	m, err := getMonitor(datadogAuth, datadogClient, datadogMonitor.Status.ID)
 	if err != nil {
 		status.SyncStatus = datadoghqv1alpha1.SyncStatusGetError
 		return err
 	}
 
+	oldState := status.MonitorState
 	convertStateToStatus(m, status, now)
 	status.MonitorStateLastUpdateTime = &now
+	r.recordStateTransition(datadogMonitor, oldState, status.MonitorState)
+	r.syncTarget(context.TODO(), logger, datadogMonitor, status)
+
+	// Detect the changes made to the monitor outside of the DatadogMonitor
+	if err = r.handleDrift(datadogAuth, logger, datadogClient, datadogMonitor, m, status, now); err != nil {
+		status.SyncStatus = datadoghqv1alpha1.SyncStatusUpdateError
+		return err
+	}
 	status.SyncStatus = datadoghqv1alpha1.SyncStatusOK
 	logger.V(1).Info("Synced DatadogMonitor state", "Monitor Namespace", datadogMonitor.Namespace, "Monitor Name", datadogMonitor.Name, "Monitor ID", datadogMonitor.Status.ID)
 
@@ -260,6 +454,13 @@ func (r *Reconciler) updateStatusIfNeeded(logger logr.Logger, datadogMonitor *da
 	// Update Error and Active conditions
 	condition.SetErrorActiveConditions(status, now, currentErr)
 
+	// Retry once the Datadog API rate limit resets
+	requeueAfter := defaultRequeuePeriod
+	if reset, rateLimited := datadogclient.GetRateLimitReset(currentErr); rateLimited {
+		requeueAfter = reset
+		result.RequeueAfter = reset
+	}
+
 	if !apiequality.Semantic.DeepEqual(&datadogMonitor.Status, status) {
 		datadogMonitor.Status = *status
 		if err := r.client.Status().Update(context.TODO(), datadogMonitor); err != nil {
@@ -279,7 +480,7 @@ func (r *Reconciler) updateStatusIfNeeded(logger logr.Logger, datadogMonitor *da
 		// not an issue, but if a monitor has many groups and is "flapping", then it can cause a flood of updates to
 		// the Status.TriggeredState and put pressure on the controller. As a safeguard against this, the maximum number
 		// of groups stored in Status.TriggeredState should be conservative.
-		return ctrl.Result{Requeue: true, RequeueAfter: defaultRequeuePeriod}, nil
+		return ctrl.Result{Requeue: true, RequeueAfter: requeueAfter}, nil
 	}
 
 	return result, nil
@@ -289,7 +490,7 @@ func (r *Reconciler) checkRequiredTags(logger logr.Logger, datadogMonitor *datad
 	tagsToAdd := []string{}
 	var found bool
 	tags := datadogMonitor.Spec.Tags
-	for _, rT := range getRequiredTags() {
+	for _, rT := range r.requiredTags() {
 		found = false
 		for _, t := range tags {
 			if t == rT {
@@ -324,6 +525,16 @@ func getRequiredTags() []string {
 	return []string{"generated:kubernetes"}
 }
 
+// requiredTags returns the tags required on the monitors managed by the operator, including the tag identifying
+// the cluster if the operator cluster name is set
+func (r *Reconciler) requiredTags() []string {
+	if r.options.ClusterName == "" {
+		return getRequiredTags()
+	}
+
+	return append(getRequiredTags(), clusterNameTagKey+":"+r.options.ClusterName)
+}
+
 // convertStateToStatus updates status.MonitorState, status.TriggeredState, and status.DowntimeStatus according to the current state of the monitor
 func convertStateToStatus(monitor datadogapiclientv1.Monitor, newStatus *datadoghqv1alpha1.DatadogMonitorStatus, now metav1.Time) {
 	// If monitor group is in Alert, Warn or No Data, then add its info to the TriggeredState
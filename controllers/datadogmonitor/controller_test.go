@@ -11,11 +11,13 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/kubernetes/scheme"
 	"k8s.io/client-go/tools/record"
@@ -29,6 +31,7 @@ import (
 	datadogapiclientv1 "github.com/DataDog/datadog-api-client-go/api/v1/datadog"
 	datadoghqv1alpha1 "github.com/DataDog/datadog-operator/apis/datadoghq/v1alpha1"
 	"github.com/DataDog/datadog-operator/pkg/controller/utils/comparison"
+	"github.com/DataDog/datadog-operator/pkg/datadogclient"
 )
 
 const (
@@ -43,7 +46,7 @@ func TestReconcileDatadogMonitor_Reconcile(t *testing.T) {
 	logf.SetLogger(zap.New(zap.UseDevMode(true)))
 
 	s := scheme.Scheme
-	s.AddKnownTypes(datadoghqv1alpha1.GroupVersion, &datadoghqv1alpha1.DatadogMonitor{})
+	s.AddKnownTypes(datadoghqv1alpha1.GroupVersion, &datadoghqv1alpha1.DatadogMonitor{}, &datadoghqv1alpha1.DatadogMonitorList{})
 
 	type args struct {
 		request              reconcile.Request
@@ -398,7 +401,7 @@ func TestReconcileDatadogMonitor_Reconcile(t *testing.T) {
 			},
 		},
 		{
-			name: "DatadogMonitor of unsupported type (composite)",
+			name: "DatadogMonitor of type composite, referenced DatadogMonitor missing",
 			args: args{
 				request: newRequest(resourcesNamespace, resourcesName),
 				firstAction: func(c client.Client) {
@@ -412,7 +415,7 @@ func TestReconcileDatadogMonitor_Reconcile(t *testing.T) {
 							Name:      resourcesName,
 						},
 						Spec: datadoghqv1alpha1.DatadogMonitorSpec{
-							Query:   "avg(last_10m):avg:system.disk.in_use{*} by {host} > 0.1",
+							Query:   "${monitor-a} && ${monitor-b}",
 							Type:    datadoghqv1alpha1.DatadogMonitorTypeComposite,
 							Name:    "test monitor",
 							Message: "something is wrong",
@@ -725,6 +728,113 @@ func Test_convertStateToStatus(t *testing.T) {
 	}
 }
 
+func TestReconciler_adopt(t *testing.T) {
+	var updatedIDs []string
+	jsonMonitor, _ := genericMonitor(1234).MarshalJSON()
+	httpServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case r.URL.Path == "/api/v1/monitor/validate":
+			_, _ = w.Write([]byte("{}"))
+		case r.URL.Path != "/api/v1/monitor/1234":
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"errors": ["Monitor not found"]}`))
+		case r.Method == http.MethodPut:
+			updatedIDs = append(updatedIDs, "1234")
+			_, _ = w.Write(jsonMonitor)
+		default:
+			_, _ = w.Write(jsonMonitor)
+		}
+	}))
+	defer httpServer.Close()
+
+	testConfig := datadogapiclientv1.NewConfiguration()
+	testConfig.HTTPClient = httpServer.Client()
+
+	owner := genericDatadogMonitor()
+	owner.Name = "owner"
+	owner.Namespace = "other"
+	owner.Status.ID = 5678
+
+	r := &Reconciler{
+		client:        newTestClient(owner),
+		datadogClient: datadogapiclientv1.NewAPIClient(testConfig),
+		datadogAuth:   setupTestAuth(httpServer.URL),
+		recorder:      record.NewFakeRecorder(10),
+		log:           testLogger,
+	}
+
+	tests := []struct {
+		name           string
+		monitorID      string
+		wantErr        string
+		wantSyncStatus datadoghqv1alpha1.SyncStatusMessage
+	}{
+		{
+			name:           "invalid ID",
+			monitorID:      "monitor",
+			wantErr:        `invalid monitor ID "monitor" in annotation datadoghq.com/adopt-monitor-id`,
+			wantSyncStatus: datadoghqv1alpha1.SyncStatusAdoptError,
+		},
+		{
+			name:           "monitor already managed",
+			monitorID:      "5678",
+			wantErr:        "monitor 5678 is already managed by DatadogMonitor other/owner",
+			wantSyncStatus: datadoghqv1alpha1.SyncStatusAdoptError,
+		},
+		{
+			name:           "monitor not found",
+			monitorID:      "4321",
+			wantErr:        "Monitor not found",
+			wantSyncStatus: datadoghqv1alpha1.SyncStatusAdoptError,
+		},
+		{
+			name:           "monitor adopted",
+			monitorID:      "1234",
+			wantSyncStatus: datadoghqv1alpha1.SyncStatusOK,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			status := &datadoghqv1alpha1.DatadogMonitorStatus{}
+			err := r.adopt(context.TODO(), testLogger, genericDatadogMonitor(), tt.monitorID, status, metav1.Now())
+			assert.Equal(t, tt.wantSyncStatus, status.SyncStatus)
+			if tt.wantErr != "" {
+				assert.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantErr)
+				assert.Equal(t, 0, status.ID)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, 1234, status.ID)
+			assert.True(t, status.Primary)
+			assert.Equal(t, []string{"1234"}, updatedIDs)
+		})
+	}
+}
+
+func TestReconciler_updateStatusIfNeeded_rateLimited(t *testing.T) {
+	dm := genericDatadogMonitor()
+	r := &Reconciler{
+		client: newTestClient(dm),
+		log:    testLogger,
+	}
+
+	rateLimitErr := datadogclient.TranslateClientError(&datadogclient.RateLimitError{Endpoint: "GET /api/v1/monitor/{id}", ResetIn: 42 * time.Second}, "error getting monitor")
+	result, err := r.updateStatusIfNeeded(testLogger, dm, metav1.Now(), dm.Status.DeepCopy(), rateLimitErr, ctrl.Result{RequeueAfter: defaultRequeuePeriod})
+	assert.NoError(t, err)
+	// The reconcile is requeued when the rate limit resets
+	assert.Equal(t, 42*time.Second, result.RequeueAfter)
+}
+
+func newTestClient(objs ...client.Object) client.Client {
+	s := runtime.NewScheme()
+	_ = scheme.AddToScheme(s)
+	_ = datadoghqv1alpha1.AddToScheme(s)
+
+	return fake.NewClientBuilder().WithScheme(s).WithObjects(objs...).Build()
+}
+
 func genericDatadogMonitor() *datadoghqv1alpha1.DatadogMonitor {
 	return &datadoghqv1alpha1.DatadogMonitor{
 		TypeMeta: metav1.TypeMeta{
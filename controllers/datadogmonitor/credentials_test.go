@@ -0,0 +1,69 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package datadogmonitor
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	datadogapiclientv1 "github.com/DataDog/datadog-api-client-go/api/v1/datadog"
+	datadoghqv1alpha1 "github.com/DataDog/datadog-operator/apis/datadoghq/v1alpha1"
+)
+
+func TestReconciler_getDatadogClient(t *testing.T) {
+	secretTeamA := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "team-a", Name: "creds"},
+		Data:       map[string][]byte{"api_key": []byte("api"), "app_key": []byte("app")},
+	}
+	secretTeamB := secretTeamA.DeepCopy()
+	secretTeamB.Namespace = "team-b"
+	secretTeamC := secretTeamA.DeepCopy()
+	secretTeamC.Namespace = "team-c"
+	secretTeamC.Data["site"] = []byte("datadoghq.eu")
+
+	operatorClient := datadogapiclientv1.NewAPIClient(datadogapiclientv1.NewConfiguration())
+	r := &Reconciler{
+		apiReader:     fake.NewClientBuilder().WithObjects(secretTeamA, secretTeamB, secretTeamC).Build(),
+		datadogClient: operatorClient,
+		datadogAuth:   context.TODO(),
+	}
+
+	newMonitor := func(namespace string, ref *datadoghqv1alpha1.DatadogCredentialsSecretRef) *datadoghqv1alpha1.DatadogMonitor {
+		return &datadoghqv1alpha1.DatadogMonitor{
+			ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: resourcesName},
+			Spec:       datadoghqv1alpha1.DatadogMonitorSpec{CredentialsSecretRef: ref},
+		}
+	}
+	ref := &datadoghqv1alpha1.DatadogCredentialsSecretRef{Name: "creds"}
+
+	// The operator client is used without credentials Secret
+	_, ddClient, err := r.getDatadogClient(newMonitor("team-a", nil))
+	assert.NoError(t, err)
+	assert.Same(t, operatorClient, ddClient)
+
+	// The same credentials share a client
+	_, clientA, err := r.getDatadogClient(newMonitor("team-a", ref))
+	assert.NoError(t, err)
+	assert.NotSame(t, operatorClient, clientA)
+	_, clientB, err := r.getDatadogClient(newMonitor("team-b", ref))
+	assert.NoError(t, err)
+	assert.Same(t, clientA, clientB)
+
+	// A different site uses another client
+	authC, clientC, err := r.getDatadogClient(newMonitor("team-c", ref))
+	assert.NoError(t, err)
+	assert.NotSame(t, clientA, clientC)
+	assert.Equal(t, map[string]string{"site": "datadoghq.eu"}, authC.Value(datadogapiclientv1.ContextServerVariables))
+
+	// Missing credentials Secret
+	_, _, err = r.getDatadogClient(newMonitor("team-d", ref))
+	assert.Error(t, err)
+}
@@ -0,0 +1,140 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package datadogmonitor
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	datadogapiclientv1 "github.com/DataDog/datadog-api-client-go/api/v1/datadog"
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	datadoghqv1alpha1 "github.com/DataDog/datadog-operator/apis/datadoghq/v1alpha1"
+	"github.com/DataDog/datadog-operator/pkg/controller/utils/condition"
+)
+
+// handleDrift detects the changes made to the monitor m outside of the DatadogMonitor, and reverts, reports or
+// ignores them depending on the DatadogMonitor drift policy
+func (r *Reconciler) handleDrift(auth context.Context, logger logr.Logger, client *datadogapiclientv1.APIClient, dm *datadoghqv1alpha1.DatadogMonitor, m datadogapiclientv1.Monitor, status *datadoghqv1alpha1.DatadogMonitorStatus, now metav1.Time) error {
+	if dm.Spec.DriftPolicy == datadoghqv1alpha1.DatadogMonitorDriftPolicyIgnore {
+		return nil
+	}
+
+	fields := getDriftedFields(logger, dm, m)
+	if len(fields) == 0 {
+		condition.UpdateDatadogMonitorConditions(status, now, datadoghqv1alpha1.DatadogMonitorConditionTypeDrifted, corev1.ConditionFalse, "")
+		return nil
+	}
+
+	if dm.Spec.DriftPolicy == datadoghqv1alpha1.DatadogMonitorDriftPolicyRevert {
+		updated, err := updateMonitor(auth, logger, client, dm)
+		if err != nil {
+			return err
+		}
+		status.SyncedQuery = updated.GetQuery()
+		message := fmt.Sprintf("Reverted the changes of the monitor fields: %s", strings.Join(fields, ", "))
+		r.recorder.Event(dm, corev1.EventTypeNormal, "DriftReverted", message)
+		condition.UpdateDatadogMonitorConditions(status, now, datadoghqv1alpha1.DatadogMonitorConditionTypeDrifted, corev1.ConditionFalse, message)
+		logger.Info("Reverted monitor drift", "Monitor ID", dm.Status.ID, "Fields", fields)
+
+		return nil
+	}
+
+	// Only report a drift once, the monitor state is synced every defaultRequeuePeriod
+	message := fmt.Sprintf("The monitor fields differ from the DatadogMonitor spec: %s", strings.Join(fields, ", "))
+	if !isDriftReported(status, message) {
+		r.recorder.Event(dm, corev1.EventTypeWarning, "DriftDetected", message)
+		logger.Info("Detected monitor drift", "Monitor ID", dm.Status.ID, "Fields", fields)
+	}
+	condition.UpdateDatadogMonitorConditions(status, now, datadoghqv1alpha1.DatadogMonitorConditionTypeDrifted, corev1.ConditionTrue, message)
+
+	return nil
+}
+
+func isDriftReported(status *datadoghqv1alpha1.DatadogMonitorStatus, message string) bool {
+	for _, c := range status.Conditions {
+		if c.Type == datadoghqv1alpha1.DatadogMonitorConditionTypeDrifted {
+			return c.Status == corev1.ConditionTrue && c.Message == message
+		}
+	}
+
+	return false
+}
+
+// getDriftedFields returns the fields of the monitor m that differ from the DatadogMonitor spec. Only the options set
+// in the spec are compared, as Datadog sets default values for the other ones. The query is compared with the query
+// returned by Datadog at the last creation or update of the monitor, if known, as Datadog normalizes it.
+func getDriftedFields(logger logr.Logger, dm *datadoghqv1alpha1.DatadogMonitor, m datadogapiclientv1.Monitor) []string {
+	expected, _ := buildMonitor(logger, dm)
+	expectedQuery := expected.GetQuery()
+	if dm.Status.SyncedQuery != "" {
+		expectedQuery = dm.Status.SyncedQuery
+	}
+	var fields []string
+	check := func(field string, drifted bool) {
+		if drifted {
+			fields = append(fields, field)
+		}
+	}
+
+	check("name", m.GetName() != expected.GetName())
+	check("message", m.GetMessage() != expected.GetMessage())
+	check("priority", m.GetPriority() != expected.GetPriority())
+	check("query", m.GetQuery() != expectedQuery)
+	check("tags", !equalTags(m.GetTags(), expected.GetTags()))
+
+	options := dm.Spec.Options
+	remote := m.GetOptions()
+	check("options.escalationMessage", options.EscalationMessage != nil && *options.EscalationMessage != remote.GetEscalationMessage())
+	check("options.evaluationDelay", options.EvaluationDelay != nil && *options.EvaluationDelay != remote.GetEvaluationDelay())
+	check("options.includeTags", options.IncludeTags != nil && *options.IncludeTags != remote.GetIncludeTags())
+	check("options.locked", options.Locked != nil && *options.Locked != remote.GetLocked())
+	check("options.newGroupDelay", options.NewGroupDelay != nil && *options.NewGroupDelay != remote.GetNewGroupDelay())
+	check("options.noDataTimeframe", options.NoDataTimeframe != nil && *options.NoDataTimeframe != remote.GetNoDataTimeframe())
+	check("options.notifyAudit", options.NotifyAudit != nil && *options.NotifyAudit != remote.GetNotifyAudit())
+	check("options.notifyNoData", options.NotifyNoData != nil && *options.NotifyNoData != remote.GetNotifyNoData())
+	check("options.renotifyInterval", options.RenotifyInterval != nil && *options.RenotifyInterval != remote.GetRenotifyInterval())
+	check("options.requireFullWindow", options.RequireFullWindow != nil && *options.RequireFullWindow != remote.GetRequireFullWindow())
+	check("options.timeoutH", options.TimeoutH != nil && *options.TimeoutH != remote.GetTimeoutH())
+
+	expectedOptions := expected.GetOptions()
+	thresholds := expectedOptions.GetThresholds()
+	remoteThresholds := remote.GetThresholds()
+	check("options.thresholds.critical", thresholds.HasCritical() && thresholds.GetCritical() != remoteThresholds.GetCritical())
+	check("options.thresholds.criticalRecovery", thresholds.HasCriticalRecovery() && thresholds.GetCriticalRecovery() != remoteThresholds.GetCriticalRecovery())
+	check("options.thresholds.ok", thresholds.HasOk() && thresholds.GetOk() != remoteThresholds.GetOk())
+	check("options.thresholds.unknown", thresholds.HasUnknown() && thresholds.GetUnknown() != remoteThresholds.GetUnknown())
+	check("options.thresholds.warning", thresholds.HasWarning() && thresholds.GetWarning() != remoteThresholds.GetWarning())
+	check("options.thresholds.warningRecovery", thresholds.HasWarningRecovery() && thresholds.GetWarningRecovery() != remoteThresholds.GetWarningRecovery())
+
+	windows := expectedOptions.GetThresholdWindows()
+	remoteWindows := remote.GetThresholdWindows()
+	check("options.thresholdWindows.recoveryWindow", windows.HasRecoveryWindow() && windows.GetRecoveryWindow() != remoteWindows.GetRecoveryWindow())
+	check("options.thresholdWindows.triggerWindow", windows.HasTriggerWindow() && windows.GetTriggerWindow() != remoteWindows.GetTriggerWindow())
+
+	return fields
+}
+
+func equalTags(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	sortedA := append([]string{}, a...)
+	sortedB := append([]string{}, b...)
+	sort.Strings(sortedA)
+	sort.Strings(sortedB)
+	for i := range sortedA {
+		if sortedA[i] != sortedB[i] {
+			return false
+		}
+	}
+
+	return true
+}
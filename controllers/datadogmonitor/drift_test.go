@@ -0,0 +1,204 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package datadogmonitor
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+
+	datadogapiclientv1 "github.com/DataDog/datadog-api-client-go/api/v1/datadog"
+	datadoghqv1alpha1 "github.com/DataDog/datadog-operator/apis/datadoghq/v1alpha1"
+)
+
+func newDriftTestMonitor() *datadoghqv1alpha1.DatadogMonitor {
+	critical := "0.1"
+	notifyNoData := true
+	dm := genericDatadogMonitor()
+	dm.Spec.Tags = []string{"generated:kubernetes", "env:prod"}
+	dm.Spec.Options = datadoghqv1alpha1.DatadogMonitorOptions{
+		NotifyNoData: &notifyNoData,
+		Thresholds:   &datadoghqv1alpha1.DatadogMonitorOptionsThresholds{Critical: &critical},
+	}
+	dm.Status.ID = 1234
+
+	return dm
+}
+
+func Test_getDriftedFields(t *testing.T) {
+	upToDate := func() datadogapiclientv1.Monitor {
+		m, _ := buildMonitor(testLogger, newDriftTestMonitor())
+		options := m.GetOptions()
+		// Options set by Datadog
+		options.SetEvaluationDelay(300)
+		options.SetNotifyAudit(true)
+		m.SetOptions(options)
+		m.SetTags([]string{"env:prod", "generated:kubernetes"})
+		return *m
+	}
+
+	// The query as normalized by Datadog
+	const normalizedQuery = "avg(last_10m):avg:system.disk.in_use{*} by {host} > 0.100"
+
+	tests := []struct {
+		name        string
+		syncedQuery string
+		update      func(m *datadogapiclientv1.Monitor)
+		want        []string
+	}{
+		{
+			name:   "no drift",
+			update: func(m *datadogapiclientv1.Monitor) {},
+		},
+		{
+			name:        "query normalized by Datadog",
+			syncedQuery: normalizedQuery,
+			update: func(m *datadogapiclientv1.Monitor) {
+				m.SetQuery(normalizedQuery)
+			},
+		},
+		{
+			name:        "query changed since the last sync",
+			syncedQuery: normalizedQuery,
+			update: func(m *datadogapiclientv1.Monitor) {
+				m.SetQuery("avg(last_10m):avg:system.disk.in_use{*} by {host} > 0.5")
+			},
+			want: []string{"query"},
+		},
+		{
+			name: "query and message changed",
+			update: func(m *datadogapiclientv1.Monitor) {
+				m.SetQuery("avg(last_10m):avg:system.disk.in_use{*} by {host} > 0.5")
+				m.SetMessage("changed in the UI")
+			},
+			want: []string{"message", "query"},
+		},
+		{
+			name: "tag added",
+			update: func(m *datadogapiclientv1.Monitor) {
+				m.SetTags([]string{"env:prod", "generated:kubernetes", "team:a"})
+			},
+			want: []string{"tags"},
+		},
+		{
+			name: "options changed",
+			update: func(m *datadogapiclientv1.Monitor) {
+				options := m.GetOptions()
+				options.SetNotifyNoData(false)
+				thresholds := options.GetThresholds()
+				thresholds.SetCritical(0.5)
+				options.SetThresholds(thresholds)
+				m.SetOptions(options)
+			},
+			want: []string{"options.notifyNoData", "options.thresholds.critical"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := upToDate()
+			tt.update(&m)
+			dm := newDriftTestMonitor()
+			dm.Status.SyncedQuery = tt.syncedQuery
+			assert.Equal(t, tt.want, getDriftedFields(testLogger, dm, m))
+		})
+	}
+}
+
+func TestReconciler_handleDrift(t *testing.T) {
+	drifted, _ := buildMonitor(testLogger, newDriftTestMonitor())
+	drifted.SetQuery("avg(last_10m):avg:system.disk.in_use{*} by {host} > 0.5")
+	jsonMonitor, _ := drifted.MarshalJSON()
+
+	tests := []struct {
+		name            string
+		policy          datadoghqv1alpha1.DatadogMonitorDriftPolicy
+		reported        bool
+		wantUpdate      bool
+		wantEvent       string
+		wantCondition   corev1.ConditionStatus
+		wantNoCondition bool
+	}{
+		{
+			name:          "report by default",
+			wantEvent:     "Warning DriftDetected The monitor fields differ from the DatadogMonitor spec: query",
+			wantCondition: corev1.ConditionTrue,
+		},
+		{
+			name:          "drift already reported",
+			policy:        datadoghqv1alpha1.DatadogMonitorDriftPolicyReport,
+			reported:      true,
+			wantCondition: corev1.ConditionTrue,
+		},
+		{
+			name:            "revert",
+			policy:          datadoghqv1alpha1.DatadogMonitorDriftPolicyRevert,
+			wantUpdate:      true,
+			wantEvent:       "Normal DriftReverted Reverted the changes of the monitor fields: query",
+			wantNoCondition: true,
+		},
+		{
+			name:            "ignore",
+			policy:          datadoghqv1alpha1.DatadogMonitorDriftPolicyIgnore,
+			wantNoCondition: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var updated bool
+			httpServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "application/json")
+				if r.Method == http.MethodPut && r.URL.Path == "/api/v1/monitor/1234" {
+					updated = true
+				}
+				_, _ = w.Write(jsonMonitor)
+			}))
+			defer httpServer.Close()
+
+			testConfig := datadogapiclientv1.NewConfiguration()
+			testConfig.HTTPClient = httpServer.Client()
+			recorder := record.NewFakeRecorder(10)
+			r := &Reconciler{recorder: recorder}
+
+			dm := newDriftTestMonitor()
+			dm.Spec.DriftPolicy = tt.policy
+			status := &datadoghqv1alpha1.DatadogMonitorStatus{}
+			if tt.reported {
+				status.Conditions = []datadoghqv1alpha1.DatadogMonitorCondition{{
+					Type:    datadoghqv1alpha1.DatadogMonitorConditionTypeDrifted,
+					Status:  corev1.ConditionTrue,
+					Message: "The monitor fields differ from the DatadogMonitor spec: query",
+				}}
+			}
+
+			err := r.handleDrift(setupTestAuth(httpServer.URL), testLogger, datadogapiclientv1.NewAPIClient(testConfig), dm, *drifted, status, metav1.Now())
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantUpdate, updated)
+
+			if tt.wantEvent != "" {
+				assert.Equal(t, tt.wantEvent, <-recorder.Events)
+			}
+			assert.Empty(t, recorder.Events)
+
+			if tt.wantUpdate {
+				// The query returned by Datadog is the reference of the next drift detections
+				assert.Equal(t, drifted.GetQuery(), status.SyncedQuery)
+			}
+
+			if tt.wantNoCondition {
+				assert.Empty(t, status.Conditions)
+				return
+			}
+			assert.Len(t, status.Conditions, 1)
+			assert.Equal(t, datadoghqv1alpha1.DatadogMonitorConditionTypeDrifted, status.Conditions[0].Type)
+			assert.Equal(t, tt.wantCondition, status.Conditions[0].Status)
+		})
+	}
+}
@@ -8,9 +8,11 @@ package datadogmonitor
 import (
 	"context"
 	"fmt"
+	"strings"
 
 	datadoghqv1alpha1 "github.com/DataDog/datadog-operator/apis/datadoghq/v1alpha1"
 	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
 	ctrl "sigs.k8s.io/controller-runtime"
 
 	"github.com/DataDog/datadog-operator/pkg/controller/utils"
@@ -25,10 +27,23 @@ func (r *Reconciler) handleFinalizer(logger logr.Logger, dm *datadoghqv1alpha1.D
 	// Check if the DatadogMonitor instance is marked to be deleted, which is indicated by the deletion timestamp being set.
 	if dm.GetDeletionTimestamp() != nil {
 		if utils.ContainsString(dm.GetFinalizers(), datadogMonitorFinalizer) {
+			// Keep the monitor as long as a composite monitor references it, as Datadog refuses to delete it
+			composites, err := r.getReferencingComposites(context.TODO(), dm)
+			if err != nil {
+				return ctrl.Result{Requeue: true, RequeueAfter: defaultErrRequeuePeriod}, err
+			}
+			if len(composites) > 0 {
+				logger.Info("DatadogMonitor deletion blocked by composite monitors", "Composite Monitors", composites)
+				r.recorder.Event(dm, corev1.EventTypeWarning, "DeletionBlocked", fmt.Sprintf("DatadogMonitor is referenced by composite DatadogMonitors: %s", strings.Join(composites, ", ")))
+
+				return ctrl.Result{Requeue: true, RequeueAfter: defaultRequeuePeriod}, nil
+			}
+
 			r.finalizeDatadogMonitor(logger, dm)
+			r.cleanupTarget(context.TODO(), logger, dm)
 
 			dm.SetFinalizers(utils.RemoveString(dm.GetFinalizers(), datadogMonitorFinalizer))
-			err := r.client.Update(context.TODO(), dm)
+			err = r.client.Update(context.TODO(), dm)
 			if err != nil {
 				return ctrl.Result{Requeue: true, RequeueAfter: defaultErrRequeuePeriod}, err
 			}
@@ -53,7 +68,16 @@ func (r *Reconciler) handleFinalizer(logger logr.Logger, dm *datadoghqv1alpha1.D
 
 func (r *Reconciler) finalizeDatadogMonitor(logger logr.Logger, dm *datadoghqv1alpha1.DatadogMonitor) {
 	if dm.Status.Primary {
-		err := deleteMonitor(r.datadogAuth, r.datadogClient, dm.Status.ID)
+		if r.getDeletionPolicy(dm) == datadoghqv1alpha1.DatadogMonitorDeletionPolicyRetain {
+			r.retainDatadogMonitor(logger, dm)
+
+			return
+		}
+
+		datadogAuth, datadogClient, err := r.getDatadogClient(dm)
+		if err == nil {
+			err = deleteMonitor(datadogAuth, datadogClient, dm.Status.ID)
+		}
 		if err != nil {
 			logger.Error(err, "failed to finalize monitor", "Monitor ID", fmt.Sprint(dm.Status.ID))
 
@@ -65,6 +89,33 @@ func (r *Reconciler) finalizeDatadogMonitor(logger logr.Logger, dm *datadoghqv1a
 	}
 }
 
+// retainDatadogMonitor keeps the monitor of a deleted DatadogMonitor in Datadog, without the tags marking it as
+// managed by the operator, so that it can be adopted by another DatadogMonitor
+func (r *Reconciler) retainDatadogMonitor(logger logr.Logger, dm *datadoghqv1alpha1.DatadogMonitor) {
+	datadogAuth, datadogClient, err := r.getDatadogClient(dm)
+	if err == nil {
+		err = removeMonitorTags(datadogAuth, datadogClient, dm.Status.ID, r.requiredTags())
+	}
+	if err != nil {
+		// The monitor is retained anyway, only its tags are not cleaned up
+		logger.Error(err, "failed to remove the required tags of the retained monitor", "Monitor ID", fmt.Sprint(dm.Status.ID))
+	}
+	logger.Info("Retained the monitor of the deleted DatadogMonitor", "Monitor ID", fmt.Sprint(dm.Status.ID))
+	r.recorder.Event(dm, corev1.EventTypeNormal, "MonitorRetained", fmt.Sprintf("Monitor %d retained in Datadog, it can be adopted with the %s annotation", dm.Status.ID, datadoghqv1alpha1.DatadogMonitorAdoptAnnotationKey))
+}
+
+// getDeletionPolicy returns the deletion policy of a DatadogMonitor, or the operator default one if not set
+func (r *Reconciler) getDeletionPolicy(dm *datadoghqv1alpha1.DatadogMonitor) datadoghqv1alpha1.DatadogMonitorDeletionPolicy {
+	if dm.Spec.DeletionPolicy != "" {
+		return dm.Spec.DeletionPolicy
+	}
+	if r.options.DefaultDeletionPolicy != "" {
+		return r.options.DefaultDeletionPolicy
+	}
+
+	return datadoghqv1alpha1.DatadogMonitorDeletionPolicyDelete
+}
+
 func (r *Reconciler) addFinalizer(logger logr.Logger, dm *datadoghqv1alpha1.DatadogMonitor) error {
 	logger.Info("Adding Finalizer for the DatadogMonitor")
 
@@ -7,6 +7,9 @@ package datadogmonitor
 
 import (
 	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
 	"testing"
 	"time"
 
@@ -14,9 +17,11 @@ import (
 	"github.com/stretchr/testify/assert"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 	"sigs.k8s.io/controller-runtime/pkg/log/zap"
 
+	datadogapiclientv1 "github.com/DataDog/datadog-api-client-go/api/v1/datadog"
 	datadoghqv1alpha1 "github.com/DataDog/datadog-operator/apis/datadoghq/v1alpha1"
 	"github.com/DataDog/datadog-operator/pkg/controller/utils"
 )
@@ -27,7 +32,7 @@ var (
 
 func Test_handleFinalizer(t *testing.T) {
 	s := scheme.Scheme
-	s.AddKnownTypes(datadoghqv1alpha1.GroupVersion, &datadoghqv1alpha1.DatadogMonitor{})
+	s.AddKnownTypes(datadoghqv1alpha1.GroupVersion, &datadoghqv1alpha1.DatadogMonitor{}, &datadoghqv1alpha1.DatadogMonitorList{})
 	metaNow := metav1.NewTime(time.Now())
 
 	r := &Reconciler{
@@ -85,3 +90,85 @@ func Test_handleFinalizer(t *testing.T) {
 		})
 	}
 }
+
+func TestReconciler_finalizeDatadogMonitor(t *testing.T) {
+	tests := []struct {
+		name          string
+		policy        datadoghqv1alpha1.DatadogMonitorDeletionPolicy
+		defaultPolicy datadoghqv1alpha1.DatadogMonitorDeletionPolicy
+		wantDeleted   bool
+		wantTags      []string
+		wantEvent     string
+	}{
+		{
+			name:        "delete by default",
+			wantDeleted: true,
+			wantEvent:   "Normal Delete DatadogMonitor",
+		},
+		{
+			name:      "retain",
+			policy:    datadoghqv1alpha1.DatadogMonitorDeletionPolicyRetain,
+			wantTags:  []string{"env:staging", "kube_cluster:test.staging", "kube_namespace:test"},
+			wantEvent: "Normal MonitorRetained Monitor 1234 retained in Datadog, it can be adopted with the datadoghq.com/adopt-monitor-id annotation",
+		},
+		{
+			name:          "retain by default",
+			defaultPolicy: datadoghqv1alpha1.DatadogMonitorDeletionPolicyRetain,
+			wantTags:      []string{"env:staging", "kube_cluster:test.staging", "kube_namespace:test"},
+			wantEvent:     "Normal MonitorRetained Monitor 1234 retained in Datadog, it can be adopted with the datadoghq.com/adopt-monitor-id annotation",
+		},
+		{
+			name:          "delete overrides the default policy",
+			policy:        datadoghqv1alpha1.DatadogMonitorDeletionPolicyDelete,
+			defaultPolicy: datadoghqv1alpha1.DatadogMonitorDeletionPolicyRetain,
+			wantDeleted:   true,
+			wantEvent:     "Normal Delete DatadogMonitor",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var deleted bool
+			var updatedTags []string
+			m := genericMonitor(1234)
+			m.SetTags(append(m.GetTags(), "generated:kubernetes"))
+			jsonMonitor, _ := m.MarshalJSON()
+			httpServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "application/json")
+				switch r.Method {
+				case http.MethodDelete:
+					deleted = true
+					_, _ = w.Write([]byte(`{"deleted_monitor_id": 1234}`))
+				case http.MethodPut:
+					u := datadogapiclientv1.MonitorUpdateRequest{}
+					_ = json.NewDecoder(r.Body).Decode(&u)
+					updatedTags = u.GetTags()
+					_, _ = w.Write(jsonMonitor)
+				default:
+					_, _ = w.Write(jsonMonitor)
+				}
+			}))
+			defer httpServer.Close()
+
+			testConfig := datadogapiclientv1.NewConfiguration()
+			testConfig.HTTPClient = httpServer.Client()
+			recorder := record.NewFakeRecorder(10)
+			r := &Reconciler{
+				datadogClient: datadogapiclientv1.NewAPIClient(testConfig),
+				datadogAuth:   setupTestAuth(httpServer.URL),
+				recorder:      recorder,
+				log:           testLogger,
+				options:       ReconcilerOptions{DefaultDeletionPolicy: tt.defaultPolicy},
+			}
+
+			dm := genericDatadogMonitor()
+			dm.Spec.DeletionPolicy = tt.policy
+			dm.Status.ID = 1234
+			dm.Status.Primary = true
+			r.finalizeDatadogMonitor(testLogger, dm)
+
+			assert.Equal(t, tt.wantDeleted, deleted)
+			assert.Equal(t, tt.wantTags, updatedTags)
+			assert.Contains(t, <-recorder.Events, tt.wantEvent)
+		})
+	}
+}
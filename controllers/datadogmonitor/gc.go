@@ -0,0 +1,143 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package datadogmonitor
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+
+	datadoghqv1alpha1 "github.com/DataDog/datadog-operator/apis/datadoghq/v1alpha1"
+)
+
+const (
+	orphanGCPeriod = 10 * time.Minute
+	// DefaultOrphanGCGracePeriod is the default time an orphan monitor is reported before being deleted
+	DefaultOrphanGCGracePeriod = 24 * time.Hour
+)
+
+var (
+	orphanMonitors = prometheus.NewGauge(prometheus.GaugeOpts{
+		Name: "datadog_operator_orphan_monitors",
+		Help: "Number of monitors of the cluster without DatadogMonitor",
+	})
+	orphanMonitorsDeleted = prometheus.NewCounter(prometheus.CounterOpts{
+		Name: "datadog_operator_orphan_monitors_deleted_total",
+		Help: "Number of orphan monitors deleted by the operator",
+	})
+)
+
+func init() {
+	metrics.Registry.MustRegister(orphanMonitors, orphanMonitorsDeleted)
+}
+
+// OrphanCollector periodically deletes the monitors of the cluster left behind without DatadogMonitor, e.g. when a
+// finalizer was removed by hand. The monitors of the cluster are identified by the kube_cluster_name tag, and are
+// deleted once they have been orphaned for the grace period.
+type OrphanCollector struct {
+	reconciler *Reconciler
+	period     time.Duration
+	// orphans holds the time the orphan monitors were first seen. It is not persisted, so the grace period starts
+	// over when the operator restarts.
+	orphans map[int64]time.Time
+	sync.Mutex
+}
+
+// NewOrphanCollector returns an OrphanCollector deleting the orphan monitors of the cluster of r
+func NewOrphanCollector(r *Reconciler) *OrphanCollector {
+	return &OrphanCollector{
+		reconciler: r,
+		period:     orphanGCPeriod,
+		orphans:    map[int64]time.Time{},
+	}
+}
+
+// Start implements manager.Runnable
+func (c *OrphanCollector) Start(ctx context.Context) error {
+	wait.UntilWithContext(ctx, c.collect, c.period)
+	return nil
+}
+
+// NeedLeaderElection implements manager.LeaderElectionRunnable
+func (c *OrphanCollector) NeedLeaderElection() bool {
+	return true
+}
+
+// collect reports the orphan monitors, and deletes the ones orphaned for longer than the grace period
+func (c *OrphanCollector) collect(ctx context.Context) {
+	c.Lock()
+	defer c.Unlock()
+
+	r := c.reconciler
+	logger := r.log.WithName("gc")
+
+	dmList := &datadoghqv1alpha1.DatadogMonitorList{}
+	if err := r.client.List(ctx, dmList); err != nil {
+		logger.Error(err, "unable to list DatadogMonitors")
+		return
+	}
+	managed := map[int64]bool{}
+	for _, dm := range dmList.Items {
+		if dm.Status.ID != 0 {
+			managed[int64(dm.Status.ID)] = true
+		}
+	}
+
+	// Only the monitors of the operator organization are collected
+	auth, client, err := r.getDatadogClient(&datadoghqv1alpha1.DatadogMonitor{})
+	if err != nil {
+		logger.Error(err, "unable to get the Datadog API client")
+		return
+	}
+
+	now := time.Now()
+	orphans := map[int64]time.Time{}
+	monitorTags := strings.Join(r.requiredTags(), ",")
+	for page := int64(0); ; page++ {
+		monitors, err := listMonitors(auth, client, monitorTags, page, listMonitorsPageSize)
+		if err != nil {
+			logger.Error(err, "unable to list the monitors of the cluster")
+			return
+		}
+
+		for _, m := range monitors {
+			if managed[m.GetId()] {
+				continue
+			}
+			firstSeen, found := c.orphans[m.GetId()]
+			if !found {
+				firstSeen = now
+				logger.Info("Found orphan monitor", "Monitor ID", m.GetId(), "Monitor Name", m.GetName(), "Deletion Time", now.Add(r.options.OrphanGCGracePeriod))
+			}
+			orphans[m.GetId()] = firstSeen
+		}
+
+		if len(monitors) < listMonitorsPageSize {
+			break
+		}
+	}
+
+	for id, firstSeen := range orphans {
+		if now.Sub(firstSeen) < r.options.OrphanGCGracePeriod {
+			continue
+		}
+		if err := deleteMonitor(auth, client, int(id)); err != nil {
+			logger.Error(err, "unable to delete orphan monitor", "Monitor ID", id)
+			continue
+		}
+		logger.Info("Deleted orphan monitor", "Monitor ID", id, "Orphan Since", firstSeen)
+		orphanMonitorsDeleted.Inc()
+		delete(orphans, id)
+	}
+
+	c.orphans = orphans
+	orphanMonitors.Set(float64(len(orphans)))
+}
@@ -0,0 +1,73 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package datadogmonitor
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	datadogapiclientv1 "github.com/DataDog/datadog-api-client-go/api/v1/datadog"
+)
+
+func TestOrphanCollector_collect(t *testing.T) {
+	var deletedPaths []string
+	httpServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.Method {
+		case http.MethodDelete:
+			deletedPaths = append(deletedPaths, r.URL.Path)
+			_, _ = w.Write([]byte(`{"deleted_monitor_id": 5678}`))
+		default:
+			assert.Equal(t, "generated:kubernetes,kube_cluster_name:test-cluster", r.URL.Query().Get("monitor_tags"))
+			_ = json.NewEncoder(w).Encode([]datadogapiclientv1.Monitor{genericMonitor(1234), genericMonitor(5678)})
+		}
+	}))
+	defer httpServer.Close()
+
+	managed := genericDatadogMonitor()
+	managed.Status.ID = 1234
+
+	testConfig := datadogapiclientv1.NewConfiguration()
+	testConfig.HTTPClient = httpServer.Client()
+	r := &Reconciler{
+		client:        newTestClient(managed),
+		datadogClient: datadogapiclientv1.NewAPIClient(testConfig),
+		datadogAuth:   setupTestAuth(httpServer.URL),
+		log:           testLogger,
+		options: ReconcilerOptions{
+			ClusterName:         "test-cluster",
+			OrphanGCEnabled:     true,
+			OrphanGCGracePeriod: time.Hour,
+		},
+	}
+	c := NewOrphanCollector(r)
+
+	// The orphan monitor is reported
+	c.collect(context.TODO())
+	assert.Empty(t, deletedPaths)
+	assert.Len(t, c.orphans, 1)
+	assert.Contains(t, c.orphans, int64(5678))
+
+	// The orphan monitor is deleted after the grace period
+	c.orphans[5678] = time.Now().Add(-2 * time.Hour)
+	c.collect(context.TODO())
+	assert.Equal(t, []string{"/api/v1/monitor/5678"}, deletedPaths)
+	assert.Empty(t, c.orphans)
+}
+
+func TestReconciler_requiredTags(t *testing.T) {
+	r := &Reconciler{}
+	assert.Equal(t, []string{"generated:kubernetes"}, r.requiredTags())
+
+	r.options.ClusterName = "test-cluster"
+	assert.Equal(t, []string{"generated:kubernetes", "kube_cluster_name:test-cluster"}, r.requiredTags())
+}
@@ -7,9 +7,6 @@ package datadogmonitor
 
 import (
 	"context"
-	"errors"
-	"fmt"
-	"net/url"
 	"sort"
 	"strconv"
 
@@ -17,6 +14,7 @@ import (
 
 	datadogapiclientv1 "github.com/DataDog/datadog-api-client-go/api/v1/datadog"
 	datadoghqv1alpha1 "github.com/DataDog/datadog-operator/apis/datadoghq/v1alpha1"
+	"github.com/DataDog/datadog-operator/pkg/datadogclient"
 )
 
 func buildMonitor(logger logr.Logger, dm *datadoghqv1alpha1.DatadogMonitor) (*datadogapiclientv1.Monitor, *datadogapiclientv1.MonitorUpdateRequest) {
@@ -172,16 +170,42 @@ func getMonitor(auth context.Context, client *datadogapiclientv1.APIClient, moni
 	}
 	m, _, err := client.MonitorsApi.GetMonitor(auth, int64(monitorID), optionalParams)
 	if err != nil {
-		return datadogapiclientv1.Monitor{}, translateClientError(err, "error getting monitor")
+		return datadogapiclientv1.Monitor{}, datadogclient.TranslateClientError(err, "error getting monitor")
 	}
 
 	return m, nil
 }
 
+// listMonitors returns a page of the monitors having all the monitorTags, with the state of their groups
+func listMonitors(auth context.Context, client *datadogapiclientv1.APIClient, monitorTags string, page int64, pageSize int32) ([]datadogapiclientv1.Monitor, error) {
+	optionalParams := datadogapiclientv1.NewListMonitorsOptionalParameters().
+		WithMonitorTags(monitorTags).
+		WithGroupStates("all").
+		WithPage(page).
+		WithPageSize(pageSize)
+	monitors, _, err := client.MonitorsApi.ListMonitors(auth, *optionalParams)
+	if err != nil {
+		return nil, datadogclient.TranslateClientError(err, "error listing monitors")
+	}
+
+	return monitors, nil
+}
+
+// listActiveDowntimes returns the downtimes currently silencing monitors
+func listActiveDowntimes(auth context.Context, client *datadogapiclientv1.APIClient) ([]datadogapiclientv1.Downtime, error) {
+	optionalParams := datadogapiclientv1.NewListDowntimesOptionalParameters().WithCurrentOnly(true)
+	downtimes, _, err := client.DowntimesApi.ListDowntimes(auth, *optionalParams)
+	if err != nil {
+		return nil, datadogclient.TranslateClientError(err, "error listing downtimes")
+	}
+
+	return downtimes, nil
+}
+
 func validateMonitor(auth context.Context, logger logr.Logger, client *datadogapiclientv1.APIClient, dm *datadoghqv1alpha1.DatadogMonitor) error {
 	m, _ := buildMonitor(logger, dm)
 	if _, _, err := client.MonitorsApi.ValidateMonitor(auth, *m); err != nil {
-		return translateClientError(err, "error validating monitor")
+		return datadogclient.TranslateClientError(err, "error validating monitor")
 	}
 
 	return nil
@@ -191,7 +215,7 @@ func createMonitor(auth context.Context, logger logr.Logger, client *datadogapic
 	m, _ := buildMonitor(logger, dm)
 	mCreated, _, err := client.MonitorsApi.CreateMonitor(auth, *m)
 	if err != nil {
-		return datadogapiclientv1.Monitor{}, translateClientError(err, "error creating monitor")
+		return datadogapiclientv1.Monitor{}, datadogclient.TranslateClientError(err, "error creating monitor")
 	}
 
 	return mCreated, nil
@@ -202,7 +226,7 @@ func updateMonitor(auth context.Context, logger logr.Logger, client *datadogapic
 
 	mUpdated, _, err := client.MonitorsApi.UpdateMonitor(auth, int64(dm.Status.ID), *u)
 	if err != nil {
-		return datadogapiclientv1.Monitor{}, translateClientError(err, "error updating monitor")
+		return datadogapiclientv1.Monitor{}, datadogclient.TranslateClientError(err, "error updating monitor")
 	}
 
 	// TODO additional logic to handle downtimes (and silenced param if needed)
@@ -210,32 +234,41 @@ func updateMonitor(auth context.Context, logger logr.Logger, client *datadogapic
 	return mUpdated, nil
 }
 
-func deleteMonitor(auth context.Context, client *datadogapiclientv1.APIClient, monitorID int) error {
-	force := "false"
-	optionalParams := datadogapiclientv1.DeleteMonitorOptionalParameters{
-		Force: &force,
+// removeMonitorTags removes tagsToRemove from the tags of a monitor
+func removeMonitorTags(auth context.Context, client *datadogapiclientv1.APIClient, monitorID int, tagsToRemove []string) error {
+	m, err := getMonitor(auth, client, monitorID)
+	if err != nil {
+		return err
 	}
-	if _, _, err := client.MonitorsApi.DeleteMonitor(auth, int64(monitorID), optionalParams); err != nil {
-		return translateClientError(err, "error deleting monitor")
+
+	toRemove := map[string]bool{}
+	for _, tag := range tagsToRemove {
+		toRemove[tag] = true
+	}
+	tags := []string{}
+	for _, tag := range m.GetTags() {
+		if !toRemove[tag] {
+			tags = append(tags, tag)
+		}
+	}
+
+	u := datadogapiclientv1.MonitorUpdateRequest{}
+	u.SetTags(tags)
+	if _, _, err = client.MonitorsApi.UpdateMonitor(auth, int64(monitorID), u); err != nil {
+		return datadogclient.TranslateClientError(err, "error updating monitor tags")
 	}
 
 	return nil
 }
 
-func translateClientError(err error, msg string) error {
-	if msg == "" {
-		msg = "an error occurred"
-	}
-
-	var apiErr datadogapiclientv1.GenericOpenAPIError
-	var errURL *url.Error
-	if errors.As(err, &apiErr) {
-		return fmt.Errorf(msg+": %w: %s", err, apiErr.Body())
+func deleteMonitor(auth context.Context, client *datadogapiclientv1.APIClient, monitorID int) error {
+	force := "false"
+	optionalParams := datadogapiclientv1.DeleteMonitorOptionalParameters{
+		Force: &force,
 	}
-
-	if errors.As(err, &errURL) {
-		return fmt.Errorf(msg+" (url.Error): %s", errURL)
+	if _, _, err := client.MonitorsApi.DeleteMonitor(auth, int64(monitorID), optionalParams); err != nil {
+		return datadogclient.TranslateClientError(err, "error deleting monitor")
 	}
 
-	return fmt.Errorf(msg+": %w", err)
+	return nil
 }
@@ -7,8 +7,6 @@ package datadogmonitor
 
 import (
 	"context"
-	"errors"
-	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"net/url"
@@ -320,58 +318,3 @@ func setupTestAuth(apiURL string) context.Context {
 
 	return testAuth
 }
-
-func Test_translateClientError(t *testing.T) {
-	var ErrGeneric = errors.New("generic error")
-
-	testCases := []struct {
-		name                   string
-		error                  error
-		message                string
-		expectedErrorType      error
-		expectedError          error
-		expectedErrorInterface interface{}
-	}{
-		{
-			name:              "no message, generic error",
-			error:             ErrGeneric,
-			message:           "",
-			expectedErrorType: ErrGeneric,
-		},
-		{
-			name:              "generic message, generic error",
-			error:             ErrGeneric,
-			message:           "generic message",
-			expectedErrorType: ErrGeneric,
-		},
-		{
-			name:                   "generic message, error type datadogapiclientv1.GenericOpenAPIError",
-			error:                  datadogapiclientv1.GenericOpenAPIError{},
-			message:                "generic message",
-			expectedErrorInterface: &datadogapiclientv1.GenericOpenAPIError{},
-		},
-		{
-			name:          "generic message, error type *url.Error",
-			error:         &url.Error{Err: fmt.Errorf("generic url error")},
-			message:       "generic message",
-			expectedError: fmt.Errorf("generic message (url.Error):  \"\": generic url error"),
-		},
-	}
-	for _, test := range testCases {
-		t.Run(test.name, func(t *testing.T) {
-			result := translateClientError(test.error, test.message)
-
-			if test.expectedErrorType != nil {
-				assert.True(t, errors.Is(result, test.expectedErrorType))
-			}
-
-			if test.expectedErrorInterface != nil {
-				assert.True(t, errors.As(result, test.expectedErrorInterface))
-			}
-
-			if test.expectedError != nil {
-				assert.Equal(t, test.expectedError, result)
-			}
-		})
-	}
-}
@@ -0,0 +1,295 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package datadogmonitor
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/util/retry"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+
+	datadoghqv1alpha1 "github.com/DataDog/datadog-operator/apis/datadoghq/v1alpha1"
+)
+
+const (
+	// WebhookReceiverPath is the path of the endpoint receiving the Datadog webhook notifications
+	WebhookReceiverPath = "/datadogmonitor/webhook"
+
+	maxWebhookPayloadSize = 1 << 20
+	receiverShutdownDelay = 5 * time.Second
+)
+
+var webhookNotifications = prometheus.NewCounterVec(prometheus.CounterOpts{
+	Name: "datadog_operator_monitor_webhook_notifications_total",
+	Help: "Number of Datadog webhook notifications received, by result",
+}, []string{"result"})
+
+func init() {
+	metrics.Registry.MustRegister(webhookNotifications)
+}
+
+// webhookPayload is the payload of the Datadog webhook notifications, see the Datadog webhooks integration variables.
+// The values are strings, as the variables are rendered in a JSON template.
+type webhookPayload struct {
+	// AlertID is the ID of the monitor ($ALERT_ID)
+	AlertID string `json:"alert_id"`
+	// AlertTransition is the type of notification, e.g. Triggered or Recovered ($ALERT_TRANSITION)
+	AlertTransition string `json:"alert_transition"`
+	// AlertScope is the monitor group, empty for a simple monitor ($ALERT_SCOPE)
+	AlertScope string `json:"alert_scope"`
+	// Date is the time of the notification, as a Unix timestamp in milliseconds ($DATE)
+	Date string `json:"date"`
+}
+
+// Receiver is an HTTP endpoint receiving the Datadog webhook notifications of the monitors, so that their state is
+// updated in the DatadogMonitors without waiting for the next poll
+type Receiver struct {
+	reconciler *Reconciler
+	address    string
+	token      string
+}
+
+// NewReceiver returns a Receiver listening on the webhook receiver address of the options of r
+func NewReceiver(r *Reconciler) *Receiver {
+	return &Receiver{
+		reconciler: r,
+		address:    r.options.WebhookReceiverAddress,
+		token:      r.options.WebhookReceiverToken,
+	}
+}
+
+// Start implements manager.Runnable
+func (rc *Receiver) Start(ctx context.Context) error {
+	mux := http.NewServeMux()
+	mux.Handle(WebhookReceiverPath, rc)
+	server := &http.Server{
+		Addr:              rc.address,
+		Handler:           mux,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	errCh := make(chan error, 1)
+	go func() {
+		rc.reconciler.log.Info("Starting the Datadog webhook receiver", "address", rc.address, "path", WebhookReceiverPath)
+		if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			errCh <- err
+		}
+		close(errCh)
+	}()
+
+	select {
+	case err := <-errCh:
+		return err
+	case <-ctx.Done():
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), receiverShutdownDelay)
+		defer cancel()
+		return server.Shutdown(shutdownCtx)
+	}
+}
+
+// NeedLeaderElection implements manager.LeaderElectionRunnable. All the replicas receive notifications, as the
+// endpoint is usually exposed with a Service.
+func (rc *Receiver) NeedLeaderElection() bool {
+	return false
+}
+
+// ServeHTTP handles a Datadog webhook notification
+func (rc *Receiver) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	logger := rc.reconciler.log.WithName("receiver")
+
+	if req.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if !rc.isAuthorized(req) {
+		webhookNotifications.WithLabelValues("unauthorized").Inc()
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	payload := webhookPayload{}
+	if err := json.NewDecoder(http.MaxBytesReader(w, req.Body, maxWebhookPayloadSize)).Decode(&payload); err != nil {
+		webhookNotifications.WithLabelValues("invalid").Inc()
+		http.Error(w, fmt.Sprintf("invalid payload: %v", err), http.StatusBadRequest)
+		return
+	}
+	monitorID, err := strconv.Atoi(payload.AlertID)
+	if err != nil {
+		webhookNotifications.WithLabelValues("invalid").Inc()
+		http.Error(w, fmt.Sprintf("invalid alert_id: %s", payload.AlertID), http.StatusBadRequest)
+		return
+	}
+	state, found := transitionToState(payload.AlertTransition)
+	if !found {
+		// e.g. a renotification, which doesn't change the state
+		webhookNotifications.WithLabelValues("ignored").Inc()
+		w.WriteHeader(http.StatusAccepted)
+		return
+	}
+
+	now := metav1.Now()
+	at := now
+	if ms, err := strconv.ParseInt(payload.Date, 10, 64); err == nil {
+		at = metav1.NewTime(time.UnixMilli(ms))
+	}
+
+	updated, err := rc.reconciler.applyNotification(req.Context(), monitorID, notificationGroup(payload.AlertScope), state, at, now)
+	if err != nil {
+		logger.Error(err, "unable to apply the Datadog webhook notification", "Monitor ID", monitorID)
+		webhookNotifications.WithLabelValues("error").Inc()
+		http.Error(w, "unable to update the DatadogMonitor", http.StatusInternalServerError)
+		return
+	}
+	if updated == 0 {
+		webhookNotifications.WithLabelValues("unknown_monitor").Inc()
+		http.Error(w, fmt.Sprintf("no DatadogMonitor found for monitor %d", monitorID), http.StatusNotFound)
+		return
+	}
+
+	logger.V(1).Info("Applied Datadog webhook notification", "Monitor ID", monitorID, "Transition", payload.AlertTransition, "Group", payload.AlertScope)
+	webhookNotifications.WithLabelValues("applied").Inc()
+	w.WriteHeader(http.StatusOK)
+}
+
+// isAuthorized checks the bearer token of a request, set in the custom headers of the Datadog webhook
+func (rc *Receiver) isAuthorized(req *http.Request) bool {
+	token := strings.TrimPrefix(req.Header.Get("Authorization"), "Bearer ")
+
+	return rc.token != "" && subtle.ConstantTimeCompare([]byte(token), []byte(rc.token)) == 1
+}
+
+// applyNotification updates the state of the DatadogMonitors of a monitor with the transition of one of its groups,
+// and returns the number of DatadogMonitors of the monitor
+// Only the DatadogMonitors using the operator credentials are updated, as the webhook notifications are sent by the
+// organization of the operator: the monitor IDs of the other organizations can collide with its monitor IDs.
+func (r *Reconciler) applyNotification(ctx context.Context, monitorID int, group string, state datadoghqv1alpha1.DatadogMonitorState, at, now metav1.Time) (int, error) {
+	dmList := &datadoghqv1alpha1.DatadogMonitorList{}
+	if err := r.client.List(ctx, dmList); err != nil {
+		return 0, err
+	}
+
+	matched := 0
+	for i := range dmList.Items {
+		if dmList.Items[i].Status.ID != monitorID || dmList.Items[i].Spec.CredentialsSecretRef != nil {
+			continue
+		}
+		matched++
+		key := client.ObjectKeyFromObject(&dmList.Items[i])
+		dm := &datadoghqv1alpha1.DatadogMonitor{}
+		var oldState datadoghqv1alpha1.DatadogMonitorState
+		applied := false
+		err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+			if err := r.client.Get(ctx, key, dm); err != nil {
+				return err
+			}
+			oldState = dm.Status.MonitorState
+			if applied = applyTransition(&dm.Status, group, state, at, now); !applied {
+				return nil
+			}
+
+			return r.client.Status().Update(ctx, dm)
+		})
+		if err != nil {
+			return matched, err
+		}
+		if !applied {
+			r.log.V(1).Info("Ignored an outdated Datadog webhook notification", "datadogmonitor", key, "Group", group)
+			continue
+		}
+
+		r.recordStateTransition(dm, oldState, dm.Status.MonitorState)
+		r.syncTarget(ctx, r.log.WithValues("datadogmonitor", key), dm, &dm.Status)
+	}
+
+	return matched, nil
+}
+
+// applyTransition updates status.TriggeredState with the new state of a monitor group, and status.MonitorState with
+// the most severe state of the triggered groups
+// The transition isn't applied, and false is returned, if it is older than the last transition of the group, as the
+// webhook notifications can be delivered out of order.
+func applyTransition(status *datadoghqv1alpha1.DatadogMonitorStatus, group string, state datadoghqv1alpha1.DatadogMonitorState, at, now metav1.Time) bool {
+	triggeredStates := []datadoghqv1alpha1.DatadogMonitorTriggeredState{}
+	for _, ts := range status.TriggeredState {
+		if ts.MonitorGroup == group && at.Before(&ts.LastTransitionTime) {
+			return false
+		}
+		if ts.MonitorGroup != group {
+			triggeredStates = append(triggeredStates, ts)
+		}
+	}
+	if isTriggered(string(state)) {
+		triggeredStates = append(triggeredStates, datadoghqv1alpha1.DatadogMonitorTriggeredState{
+			MonitorGroup:       group,
+			State:              state,
+			LastTransitionTime: at,
+		})
+	}
+	sort.SliceStable(triggeredStates, func(i, j int) bool { return triggeredStates[i].MonitorGroup < triggeredStates[j].MonitorGroup })
+	if len(triggeredStates) > maxTriggeredStateGroups {
+		triggeredStates = triggeredStates[0:maxTriggeredStateGroups]
+	}
+	status.TriggeredState = triggeredStates
+
+	overallState := datadoghqv1alpha1.DatadogMonitorStateOK
+	for _, ts := range triggeredStates {
+		if stateSeverity[ts.State] > stateSeverity[overallState] {
+			overallState = ts.State
+		}
+	}
+	if status.MonitorState != overallState {
+		status.MonitorState = overallState
+		status.MonitorStateLastTransitionTime = &now
+	}
+
+	return true
+}
+
+var stateSeverity = map[datadoghqv1alpha1.DatadogMonitorState]int{
+	datadoghqv1alpha1.DatadogMonitorStateOK:     0,
+	datadoghqv1alpha1.DatadogMonitorStateNoData: 1,
+	datadoghqv1alpha1.DatadogMonitorStateWarn:   2,
+	datadoghqv1alpha1.DatadogMonitorStateAlert:  3,
+}
+
+// transitionToState maps an $ALERT_TRANSITION value to a monitor state
+func transitionToState(transition string) (datadoghqv1alpha1.DatadogMonitorState, bool) {
+	if strings.HasSuffix(transition, "Recovered") {
+		return datadoghqv1alpha1.DatadogMonitorStateOK, true
+	}
+	switch strings.TrimPrefix(transition, "Re-") {
+	case "Triggered":
+		return datadoghqv1alpha1.DatadogMonitorStateAlert, true
+	case "Warn":
+		return datadoghqv1alpha1.DatadogMonitorStateWarn, true
+	case "No Data":
+		return datadoghqv1alpha1.DatadogMonitorStateNoData, true
+	}
+
+	return "", false
+}
+
+// notificationGroup returns the monitor group of an $ALERT_SCOPE value, "*" for a simple monitor like in the
+// monitor state returned by the Datadog API
+func notificationGroup(scope string) string {
+	if scope == "" {
+		return "*"
+	}
+
+	return scope
+}
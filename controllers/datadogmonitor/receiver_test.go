@@ -0,0 +1,108 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package datadogmonitor
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	datadoghqv1alpha1 "github.com/DataDog/datadog-operator/apis/datadoghq/v1alpha1"
+)
+
+func TestReceiver(t *testing.T) {
+	dm := genericDatadogMonitor()
+	dm.Status.ID = 1234
+	dm.Status.MonitorState = datadoghqv1alpha1.DatadogMonitorStateOK
+	// A monitor of another organization, with the same ID
+	otherOrg := dm.DeepCopy()
+	otherOrg.Name = "other-org"
+	otherOrg.Spec.CredentialsSecretRef = &datadoghqv1alpha1.DatadogCredentialsSecretRef{Name: "creds"}
+	recorder := record.NewFakeRecorder(10)
+	r := &Reconciler{
+		client:   newTestClient(dm, otherOrg),
+		recorder: recorder,
+		log:      testLogger,
+		options:  ReconcilerOptions{WebhookReceiverToken: "secret"},
+	}
+	server := httptest.NewServer(NewReceiver(r))
+	defer server.Close()
+
+	post := func(token, payload string) int {
+		req, _ := http.NewRequest(http.MethodPost, server.URL, strings.NewReader(payload))
+		req.Header.Set("Authorization", "Bearer "+token)
+		resp, err := server.Client().Do(req)
+		assert.NoError(t, err)
+		defer resp.Body.Close()
+		return resp.StatusCode
+	}
+	getStatus := func(obj client.Object) datadoghqv1alpha1.DatadogMonitorStatus {
+		updated := &datadoghqv1alpha1.DatadogMonitor{}
+		_ = r.client.Get(context.TODO(), client.ObjectKeyFromObject(obj), updated)
+		return updated.Status
+	}
+
+	assert.Equal(t, http.StatusUnauthorized, post("wrong", `{"alert_id": "1234", "alert_transition": "Triggered"}`))
+	assert.Equal(t, http.StatusBadRequest, post("secret", `{"alert_id": "$ALERT_ID"}`))
+	assert.Equal(t, http.StatusNotFound, post("secret", `{"alert_id": "5678", "alert_transition": "Triggered"}`))
+	assert.Equal(t, http.StatusAccepted, post("secret", `{"alert_id": "1234", "alert_transition": "Renotify"}`))
+
+	// A group is triggered
+	assert.Equal(t, http.StatusOK, post("secret", `{"alert_id": "1234", "alert_transition": "Triggered", "alert_scope": "host:a", "date": "1700000000000"}`))
+	status := getStatus(dm)
+	assert.Equal(t, datadoghqv1alpha1.DatadogMonitorStateAlert, status.MonitorState)
+	assert.Equal(t, []datadoghqv1alpha1.DatadogMonitorTriggeredState{{
+		MonitorGroup:       "host:a",
+		State:              datadoghqv1alpha1.DatadogMonitorStateAlert,
+		LastTransitionTime: metav1.NewTime(time.UnixMilli(1700000000000)),
+	}}, status.TriggeredState)
+	assert.Equal(t, "Warning MonitorStateChanged Monitor 1234 state changed from OK to Alert", <-recorder.Events)
+	// The monitor of the other organization is unchanged
+	assert.Equal(t, datadoghqv1alpha1.DatadogMonitorStateOK, getStatus(otherOrg).MonitorState)
+
+	// An outdated notification of the group is ignored
+	assert.Equal(t, http.StatusOK, post("secret", `{"alert_id": "1234", "alert_transition": "Recovered", "alert_scope": "host:a", "date": "1699999999000"}`))
+	assert.Equal(t, datadoghqv1alpha1.DatadogMonitorStateAlert, getStatus(dm).MonitorState)
+
+	// Another group warns
+	assert.Equal(t, http.StatusOK, post("secret", `{"alert_id": "1234", "alert_transition": "Warn", "alert_scope": "host:b"}`))
+	status = getStatus(dm)
+	assert.Equal(t, datadoghqv1alpha1.DatadogMonitorStateAlert, status.MonitorState)
+	assert.Len(t, status.TriggeredState, 2)
+
+	// The alerting group recovers
+	assert.Equal(t, http.StatusOK, post("secret", `{"alert_id": "1234", "alert_transition": "Recovered", "alert_scope": "host:a"}`))
+	status = getStatus(dm)
+	assert.Equal(t, datadoghqv1alpha1.DatadogMonitorStateWarn, status.MonitorState)
+	assert.Len(t, status.TriggeredState, 1)
+	assert.Equal(t, "host:b", status.TriggeredState[0].MonitorGroup)
+	assert.Equal(t, "Warning MonitorStateChanged Monitor 1234 state changed from Alert to Warn", <-recorder.Events)
+}
+
+func Test_transitionToState(t *testing.T) {
+	tests := map[string]datadoghqv1alpha1.DatadogMonitorState{
+		"Triggered":         datadoghqv1alpha1.DatadogMonitorStateAlert,
+		"Re-Triggered":      datadoghqv1alpha1.DatadogMonitorStateAlert,
+		"Warn":              datadoghqv1alpha1.DatadogMonitorStateWarn,
+		"No Data":           datadoghqv1alpha1.DatadogMonitorStateNoData,
+		"Recovered":         datadoghqv1alpha1.DatadogMonitorStateOK,
+		"No Data Recovered": datadoghqv1alpha1.DatadogMonitorStateOK,
+		"Renotify":          "",
+	}
+	for transition, want := range tests {
+		state, found := transitionToState(transition)
+		assert.Equal(t, want, state, transition)
+		assert.Equal(t, want != "", found, transition)
+	}
+}
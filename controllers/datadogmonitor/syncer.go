@@ -0,0 +1,215 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package datadogmonitor
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	datadogapiclientv1 "github.com/DataDog/datadog-api-client-go/api/v1/datadog"
+	apiequality "k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+
+	datadoghqv1alpha1 "github.com/DataDog/datadog-operator/apis/datadoghq/v1alpha1"
+	"github.com/DataDog/datadog-operator/pkg/controller/utils/comparison"
+)
+
+const (
+	// stateSyncPeriod is shorter than defaultRequeuePeriod, so that the monitor states are synced by the StateSyncer
+	// before the reconciles fall back to getting each monitor
+	stateSyncPeriod = 45 * time.Second
+	// listMonitorsPageSize is the maximum page size of the monitors list API
+	listMonitorsPageSize = 1000
+)
+
+// StateSyncer periodically syncs the state of all the monitors managed by the DatadogMonitors, by listing them page
+// by page instead of getting each monitor. The number of Datadog API calls depends on the number of pages of monitors
+// and of Datadog organizations, instead of the number of DatadogMonitors.
+type StateSyncer struct {
+	reconciler *Reconciler
+	period     time.Duration
+}
+
+// NewStateSyncer returns a StateSyncer updating the status of the DatadogMonitors managed by r
+func NewStateSyncer(r *Reconciler) *StateSyncer {
+	return &StateSyncer{
+		reconciler: r,
+		period:     stateSyncPeriod,
+	}
+}
+
+// Start implements manager.Runnable
+func (s *StateSyncer) Start(ctx context.Context) error {
+	wait.UntilWithContext(ctx, s.reconciler.syncMonitorStates, s.period)
+	return nil
+}
+
+// NeedLeaderElection implements manager.LeaderElectionRunnable
+func (s *StateSyncer) NeedLeaderElection() bool {
+	return true
+}
+
+// syncGroup holds the DatadogMonitors sharing a Datadog API client
+type syncGroup struct {
+	auth     context.Context
+	client   *datadogapiclientv1.APIClient
+	monitors map[int]*datadoghqv1alpha1.DatadogMonitor
+}
+
+// syncMonitorStates lists the monitors of each Datadog organization used by the DatadogMonitors, and updates the
+// state of the matching DatadogMonitors
+func (r *Reconciler) syncMonitorStates(ctx context.Context) {
+	dmList := &datadoghqv1alpha1.DatadogMonitorList{}
+	if err := r.client.List(ctx, dmList); err != nil {
+		r.log.Error(err, "unable to list DatadogMonitors")
+		return
+	}
+
+	// The DatadogMonitors using the same credentials share their client, see datadogclient.ClientCache
+	groups := map[*datadogapiclientv1.APIClient]*syncGroup{}
+	for i := range dmList.Items {
+		dm := &dmList.Items[i]
+		if !isStateSyncable(dm) {
+			continue
+		}
+		auth, client, err := r.getDatadogClient(dm)
+		if err != nil {
+			// The reconcile reports the credentials error
+			continue
+		}
+		group, found := groups[client]
+		if !found {
+			group = &syncGroup{auth: auth, client: client, monitors: map[int]*datadoghqv1alpha1.DatadogMonitor{}}
+			groups[client] = group
+		}
+		group.monitors[dm.Status.ID] = dm
+	}
+
+	for _, group := range groups {
+		if err := r.syncGroupStates(ctx, group); err != nil {
+			r.log.Error(err, "unable to sync monitor states")
+		}
+	}
+}
+
+func (r *Reconciler) syncGroupStates(ctx context.Context, group *syncGroup) error {
+	downtimes, err := listActiveDowntimes(group.auth, group.client)
+	if err != nil {
+		return err
+	}
+
+	monitorTags := strings.Join(getRequiredTags(), ",")
+	for page := int64(0); ; page++ {
+		monitors, err := listMonitors(group.auth, group.client, monitorTags, page, listMonitorsPageSize)
+		if err != nil {
+			return err
+		}
+
+		for _, m := range monitors {
+			if dm, found := group.monitors[int(m.GetId())]; found {
+				r.syncState(ctx, group, dm, m, downtimes)
+			}
+		}
+
+		if len(monitors) < listMonitorsPageSize {
+			return nil
+		}
+	}
+}
+
+// syncState updates the status of a DatadogMonitor with the state of its monitor m, like Reconciler.get
+func (r *Reconciler) syncState(ctx context.Context, group *syncGroup, dm *datadoghqv1alpha1.DatadogMonitor, m datadogapiclientv1.Monitor, downtimes []datadogapiclientv1.Downtime) {
+	logger := r.log.WithValues("datadogmonitor", dm.Namespace+"/"+dm.Name)
+	now := metav1.Now()
+	status := dm.Status.DeepCopy()
+
+	convertStateToStatus(m, status, now)
+	status.DowntimeStatus = getDowntimeStatus(m, downtimes)
+	status.MonitorStateLastUpdateTime = &now
+	r.recordStateTransition(dm, dm.Status.MonitorState, status.MonitorState)
+	r.syncTarget(ctx, logger, dm, status)
+
+	// The drift is detected against the query sent to Datadog
+	toSync := dm
+	if dm.Spec.Type == datadoghqv1alpha1.DatadogMonitorTypeComposite {
+		toSync = dm.DeepCopy()
+		toSync.Spec.Query = dm.Status.ResolvedQuery
+	}
+	if err := r.handleDrift(group.auth, logger, group.client, toSync, m, status, now); err != nil {
+		logger.Error(err, "error handling monitor drift", "Monitor ID", dm.Status.ID)
+		status.SyncStatus = datadoghqv1alpha1.SyncStatusUpdateError
+	} else {
+		status.SyncStatus = datadoghqv1alpha1.SyncStatusOK
+	}
+
+	if apiequality.Semantic.DeepEqual(&dm.Status, status) {
+		return
+	}
+	dm.Status = *status
+	if err := r.client.Status().Update(ctx, dm); err != nil {
+		if apierrors.IsConflict(err) {
+			// The DatadogMonitor is being reconciled, its state is synced at the next period
+			logger.V(1).Info("unable to update DatadogMonitor status due to update conflict")
+			return
+		}
+		logger.Error(err, "unable to update DatadogMonitor status")
+	}
+}
+
+// isStateSyncable returns whether the state of a DatadogMonitor can be synced by the StateSyncer: its monitor must be
+// created, and up to date with its spec so that drifts are not mistaken for pending updates
+func isStateSyncable(dm *datadoghqv1alpha1.DatadogMonitor) bool {
+	if dm.Status.ID == 0 || dm.DeletionTimestamp != nil || dm.Status.SyncStatus != datadoghqv1alpha1.SyncStatusOK {
+		return false
+	}
+	hash, err := comparison.GenerateMD5ForSpec(&dm.Spec)
+	if err != nil {
+		return false
+	}
+
+	return hash == dm.Status.CurrentHash
+}
+
+// getDowntimeStatus returns the first active downtime silencing the monitor m, by ID or by tags, whatever its scope
+func getDowntimeStatus(m datadogapiclientv1.Monitor, downtimes []datadogapiclientv1.Downtime) datadoghqv1alpha1.DatadogMonitorDowntimeStatus {
+	for _, d := range downtimes {
+		if !d.GetActive() || d.GetDisabled() {
+			continue
+		}
+		if monitorID, ok := d.GetMonitorIdOk(); ok && monitorID != nil {
+			if *monitorID != m.GetId() {
+				continue
+			}
+		} else if !hasTags(m.GetTags(), d.GetMonitorTags()) {
+			continue
+		}
+
+		return datadoghqv1alpha1.DatadogMonitorDowntimeStatus{
+			IsDowntimed: true,
+			DowntimeID:  int(d.GetId()),
+		}
+	}
+
+	return datadoghqv1alpha1.DatadogMonitorDowntimeStatus{}
+}
+
+// hasTags returns whether tags contains all the downtime monitor tags, `*` matching all the monitors
+func hasTags(tags, monitorTags []string) bool {
+	set := map[string]bool{}
+	for _, tag := range tags {
+		set[tag] = true
+	}
+	for _, tag := range monitorTags {
+		if tag != "*" && !set[tag] {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,150 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package datadogmonitor
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+
+	datadogapiclientv1 "github.com/DataDog/datadog-api-client-go/api/v1/datadog"
+	datadoghqv1alpha1 "github.com/DataDog/datadog-operator/apis/datadoghq/v1alpha1"
+	"github.com/DataDog/datadog-operator/pkg/controller/utils/comparison"
+)
+
+func TestReconciler_syncMonitorStates(t *testing.T) {
+	synced := genericDatadogMonitor()
+	synced.Name = "synced"
+	synced.Spec.Tags = []string{"generated:kubernetes"}
+	synced.Status.ID = 1234
+	synced.Status.SyncStatus = datadoghqv1alpha1.SyncStatusOK
+	synced.Status.CurrentHash, _ = comparison.GenerateMD5ForSpec(&synced.Spec)
+
+	// The spec of this DatadogMonitor is not applied yet, its state is synced by the reconcile
+	pending := synced.DeepCopy()
+	pending.Name = "pending"
+	pending.Status.ID = 5678
+	pending.Status.CurrentHash = "outdated"
+
+	alert, _ := buildMonitor(testLogger, synced)
+	alert.SetId(1234)
+	alert.SetOverallState(datadogapiclientv1.MONITOROVERALLSTATES_ALERT)
+	other, _ := buildMonitor(testLogger, pending)
+	other.SetId(5678)
+	other.SetOverallState(datadogapiclientv1.MONITOROVERALLSTATES_ALERT)
+
+	downtime := datadogapiclientv1.NewDowntime()
+	downtime.SetId(42)
+	downtime.SetActive(true)
+	downtime.SetMonitorTags([]string{"generated:kubernetes"})
+
+	var calls []string
+	httpServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls = append(calls, r.URL.Path)
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/api/v1/downtime":
+			assert.Equal(t, "true", r.URL.Query().Get("current_only"))
+			_ = json.NewEncoder(w).Encode([]datadogapiclientv1.Downtime{*downtime})
+		case "/api/v1/monitor":
+			assert.Equal(t, "generated:kubernetes", r.URL.Query().Get("monitor_tags"))
+			assert.Equal(t, "all", r.URL.Query().Get("group_states"))
+			_ = json.NewEncoder(w).Encode([]datadogapiclientv1.Monitor{*alert, *other})
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer httpServer.Close()
+
+	testConfig := datadogapiclientv1.NewConfiguration()
+	testConfig.HTTPClient = httpServer.Client()
+	r := &Reconciler{
+		client:        newTestClient(synced, pending),
+		datadogClient: datadogapiclientv1.NewAPIClient(testConfig),
+		datadogAuth:   setupTestAuth(httpServer.URL),
+		recorder:      record.NewFakeRecorder(10),
+		log:           testLogger,
+	}
+
+	r.syncMonitorStates(context.TODO())
+	// A single page of monitors is listed for both DatadogMonitors
+	assert.Equal(t, []string{"/api/v1/downtime", "/api/v1/monitor"}, calls)
+
+	dm := &datadoghqv1alpha1.DatadogMonitor{}
+	assert.NoError(t, r.client.Get(context.TODO(), types.NamespacedName{Namespace: synced.Namespace, Name: "synced"}, dm))
+	assert.Equal(t, datadoghqv1alpha1.DatadogMonitorStateAlert, dm.Status.MonitorState)
+	assert.NotNil(t, dm.Status.MonitorStateLastUpdateTime)
+	assert.Equal(t, datadoghqv1alpha1.DatadogMonitorDowntimeStatus{IsDowntimed: true, DowntimeID: 42}, dm.Status.DowntimeStatus)
+
+	assert.NoError(t, r.client.Get(context.TODO(), types.NamespacedName{Namespace: pending.Namespace, Name: "pending"}, dm))
+	assert.Empty(t, dm.Status.MonitorState)
+	assert.Nil(t, dm.Status.MonitorStateLastUpdateTime)
+}
+
+func Test_getDowntimeStatus(t *testing.T) {
+	m := genericMonitor(1234)
+	m.SetTags([]string{"env:prod", "generated:kubernetes"})
+
+	newDowntime := func(id int64, update func(d *datadogapiclientv1.Downtime)) datadogapiclientv1.Downtime {
+		d := datadogapiclientv1.NewDowntime()
+		d.SetId(id)
+		d.SetActive(true)
+		update(d)
+		return *d
+	}
+
+	tests := []struct {
+		name      string
+		downtimes []datadogapiclientv1.Downtime
+		want      datadoghqv1alpha1.DatadogMonitorDowntimeStatus
+	}{
+		{
+			name: "no downtime",
+		},
+		{
+			name: "downtime of another monitor",
+			downtimes: []datadogapiclientv1.Downtime{
+				newDowntime(1, func(d *datadogapiclientv1.Downtime) { d.SetMonitorId(5678) }),
+			},
+		},
+		{
+			name: "downtime by ID",
+			downtimes: []datadogapiclientv1.Downtime{
+				newDowntime(1, func(d *datadogapiclientv1.Downtime) { d.SetMonitorId(5678) }),
+				newDowntime(2, func(d *datadogapiclientv1.Downtime) { d.SetMonitorId(1234) }),
+			},
+			want: datadoghqv1alpha1.DatadogMonitorDowntimeStatus{IsDowntimed: true, DowntimeID: 2},
+		},
+		{
+			name: "downtime by tags",
+			downtimes: []datadogapiclientv1.Downtime{
+				newDowntime(1, func(d *datadogapiclientv1.Downtime) { d.SetMonitorTags([]string{"env:prod", "team:a"}) }),
+				newDowntime(2, func(d *datadogapiclientv1.Downtime) { d.SetMonitorTags([]string{"env:prod"}) }),
+			},
+			want: datadoghqv1alpha1.DatadogMonitorDowntimeStatus{IsDowntimed: true, DowntimeID: 2},
+		},
+		{
+			name: "inactive downtime",
+			downtimes: []datadogapiclientv1.Downtime{
+				newDowntime(1, func(d *datadogapiclientv1.Downtime) {
+					d.SetActive(false)
+					d.SetMonitorTags([]string{"*"})
+				}),
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, getDowntimeStatus(m, tt.downtimes))
+		})
+	}
+}
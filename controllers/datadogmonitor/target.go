@@ -0,0 +1,271 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package datadogmonitor
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/go-logr/logr"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	apiequality "k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	datadoghqv1alpha1 "github.com/DataDog/datadog-operator/apis/datadoghq/v1alpha1"
+)
+
+// recordStateTransition emits an event when the overall state of the monitor of a DatadogMonitor changes. The first
+// state of a monitor is only reported if it is triggered.
+func (r *Reconciler) recordStateTransition(dm *datadoghqv1alpha1.DatadogMonitor, oldState, newState datadoghqv1alpha1.DatadogMonitorState) {
+	if oldState == newState || (oldState == "" && !isTriggered(string(newState))) {
+		return
+	}
+
+	eventType := corev1.EventTypeNormal
+	if isTriggered(string(newState)) {
+		eventType = corev1.EventTypeWarning
+	}
+	from := string(oldState)
+	if from == "" {
+		from = "Unknown"
+	}
+	r.recorder.Event(dm, eventType, "MonitorStateChanged", fmt.Sprintf("Monitor %d state changed from %s to %s", dm.Status.ID, from, newState))
+}
+
+// syncTarget reflects the monitor state of a DatadogMonitor on the workload referenced by its spec.targetRef, and
+// records it in status.syncedTargetRef. The workload previously recorded is cleaned up if spec.targetRef changed.
+// Errors are reported with an event, as they must not fail the sync of the monitor state.
+func (r *Reconciler) syncTarget(ctx context.Context, logger logr.Logger, dm *datadoghqv1alpha1.DatadogMonitor, status *datadoghqv1alpha1.DatadogMonitorStatus) {
+	if synced := status.SyncedTargetRef; synced != nil && !apiequality.Semantic.DeepEqual(synced, dm.Spec.TargetRef) {
+		// Only the condition is removed if the workload didn't change
+		removeAnnotation := dm.Spec.TargetRef == nil || synced.Kind != dm.Spec.TargetRef.Kind || synced.Name != dm.Spec.TargetRef.Name
+		if !r.removeTargetState(ctx, logger, dm, synced, removeAnnotation) {
+			// Keep the previous workload to retry its cleanup at the next sync
+			return
+		}
+		status.SyncedTargetRef = nil
+	}
+	if dm.Spec.TargetRef == nil || status.MonitorState == "" {
+		return
+	}
+
+	status.SyncedTargetRef = dm.Spec.TargetRef.DeepCopy()
+	if err := r.updateTarget(ctx, dm, status.MonitorState); err != nil {
+		logger.Error(err, "unable to update the target workload", "Kind", dm.Spec.TargetRef.Kind, "Name", dm.Spec.TargetRef.Name)
+		r.recorder.Event(dm, corev1.EventTypeWarning, "TargetUpdateError", fmt.Sprintf("Unable to update %s %s: %v", dm.Spec.TargetRef.Kind, dm.Spec.TargetRef.Name, err))
+	}
+}
+
+// updateTarget sets the state annotation, and optionally the state condition, on the target workload if they changed
+func (r *Reconciler) updateTarget(ctx context.Context, dm *datadoghqv1alpha1.DatadogMonitor, state datadoghqv1alpha1.DatadogMonitorState) error {
+	key, err := targetKey(dm)
+	if err != nil {
+		return err
+	}
+	obj, err := r.getTarget(ctx, dm.Namespace, dm.Spec.TargetRef)
+	if err != nil {
+		return err
+	}
+
+	if obj.GetAnnotations()[key] != string(state) {
+		patch := client.MergeFrom(obj.DeepCopyObject().(client.Object))
+		annotations := obj.GetAnnotations()
+		if annotations == nil {
+			annotations = map[string]string{}
+		}
+		annotations[key] = string(state)
+		obj.SetAnnotations(annotations)
+		if err = r.client.Patch(ctx, obj, patch); err != nil {
+			return err
+		}
+	}
+
+	if !dm.Spec.TargetRef.Conditions {
+		return nil
+	}
+	// The conditions are a list: the optimistic lock prevents overwriting the conditions set by the workload controller
+	patch := client.MergeFromWithOptions(obj.DeepCopyObject().(client.Object), client.MergeFromWithOptimisticLock{})
+	if !setTargetCondition(obj, key, dm.Status.ID, state, metav1.Now()) {
+		return nil
+	}
+
+	return r.client.Status().Patch(ctx, obj, patch)
+}
+
+// cleanupTarget removes the state annotation and condition of a deleted DatadogMonitor from its target workload, and
+// from the workload previously recorded in its status if spec.targetRef changed
+func (r *Reconciler) cleanupTarget(ctx context.Context, logger logr.Logger, dm *datadoghqv1alpha1.DatadogMonitor) {
+	if synced := dm.Status.SyncedTargetRef; synced != nil && !apiequality.Semantic.DeepEqual(synced, dm.Spec.TargetRef) {
+		r.removeTargetState(ctx, logger, dm, synced, true)
+	}
+	if dm.Spec.TargetRef != nil {
+		r.removeTargetState(ctx, logger, dm, dm.Spec.TargetRef, true)
+	}
+}
+
+// removeTargetState removes the state condition, and optionally the state annotation, of a DatadogMonitor from the
+// workload ref, and returns whether they are removed. A missing workload has nothing to clean up.
+func (r *Reconciler) removeTargetState(ctx context.Context, logger logr.Logger, dm *datadoghqv1alpha1.DatadogMonitor, ref *datadoghqv1alpha1.DatadogMonitorTargetRef, removeAnnotation bool) bool {
+	key, err := targetKey(dm)
+	if err != nil {
+		return true
+	}
+	obj, err := r.getTarget(ctx, dm.Namespace, ref)
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return true
+		}
+		logger.Error(err, "unable to clean up the target workload", "Kind", ref.Kind, "Name", ref.Name)
+		return false
+	}
+
+	removed := true
+	if _, found := obj.GetAnnotations()[key]; found && removeAnnotation {
+		patch := client.MergeFrom(obj.DeepCopyObject().(client.Object))
+		annotations := obj.GetAnnotations()
+		delete(annotations, key)
+		obj.SetAnnotations(annotations)
+		if err = r.client.Patch(ctx, obj, patch); err != nil {
+			logger.Error(err, "unable to remove the monitor state annotation of the target workload", "Kind", ref.Kind, "Name", ref.Name)
+			removed = false
+		}
+	}
+
+	patch := client.MergeFromWithOptions(obj.DeepCopyObject().(client.Object), client.MergeFromWithOptimisticLock{})
+	if removeTargetCondition(obj, key) {
+		if err = r.client.Status().Patch(ctx, obj, patch); err != nil {
+			logger.Error(err, "unable to remove the monitor state condition of the target workload", "Kind", ref.Kind, "Name", ref.Name)
+			removed = false
+		}
+	}
+
+	return removed
+}
+
+func (r *Reconciler) getTarget(ctx context.Context, namespace string, ref *datadoghqv1alpha1.DatadogMonitorTargetRef) (client.Object, error) {
+	var obj client.Object
+	switch ref.Kind {
+	case "Deployment":
+		obj = &appsv1.Deployment{}
+	case "StatefulSet":
+		obj = &appsv1.StatefulSet{}
+	default:
+		return nil, fmt.Errorf("target kind %s not supported", ref.Kind)
+	}
+
+	if err := r.client.Get(ctx, types.NamespacedName{Namespace: namespace, Name: ref.Name}, obj); err != nil {
+		return nil, err
+	}
+
+	return obj, nil
+}
+
+// targetKey returns the annotation key and condition type holding the monitor state of a DatadogMonitor
+func targetKey(dm *datadoghqv1alpha1.DatadogMonitor) (string, error) {
+	key := datadoghqv1alpha1.DatadogMonitorStateKeyPrefix + dm.Name
+	if errs := validation.IsQualifiedName(key); len(errs) > 0 {
+		return "", fmt.Errorf("invalid target annotation key %s: %s", key, strings.Join(errs, ", "))
+	}
+
+	return key, nil
+}
+
+// setTargetCondition sets the monitor state condition in the status of a workload, and returns whether it changed
+func setTargetCondition(obj client.Object, conditionType string, monitorID int, state datadoghqv1alpha1.DatadogMonitorState, now metav1.Time) bool {
+	status := corev1.ConditionFalse
+	if isTriggered(string(state)) {
+		status = corev1.ConditionTrue
+	}
+	reason := strings.ReplaceAll(string(state), " ", "")
+	message := fmt.Sprintf("Monitor %d is in %s state", monitorID, state)
+
+	switch workload := obj.(type) {
+	case *appsv1.Deployment:
+		for i, c := range workload.Status.Conditions {
+			if string(c.Type) != conditionType {
+				continue
+			}
+			if c.Status == status && c.Reason == reason && c.Message == message {
+				return false
+			}
+			if c.Status != status {
+				c.LastTransitionTime = now
+			}
+			c.Status, c.Reason, c.Message, c.LastUpdateTime = status, reason, message, now
+			workload.Status.Conditions[i] = c
+			return true
+		}
+		workload.Status.Conditions = append(workload.Status.Conditions, appsv1.DeploymentCondition{
+			Type:               appsv1.DeploymentConditionType(conditionType),
+			Status:             status,
+			LastUpdateTime:     now,
+			LastTransitionTime: now,
+			Reason:             reason,
+			Message:            message,
+		})
+	case *appsv1.StatefulSet:
+		for i, c := range workload.Status.Conditions {
+			if string(c.Type) != conditionType {
+				continue
+			}
+			if c.Status == status && c.Reason == reason && c.Message == message {
+				return false
+			}
+			if c.Status != status {
+				c.LastTransitionTime = now
+			}
+			c.Status, c.Reason, c.Message = status, reason, message
+			workload.Status.Conditions[i] = c
+			return true
+		}
+		workload.Status.Conditions = append(workload.Status.Conditions, appsv1.StatefulSetCondition{
+			Type:               appsv1.StatefulSetConditionType(conditionType),
+			Status:             status,
+			LastTransitionTime: now,
+			Reason:             reason,
+			Message:            message,
+		})
+	default:
+		return false
+	}
+
+	return true
+}
+
+// removeTargetCondition removes the monitor state condition from the status of a workload, and returns whether it was found
+func removeTargetCondition(obj client.Object, conditionType string) bool {
+	found := false
+	switch workload := obj.(type) {
+	case *appsv1.Deployment:
+		conditions := []appsv1.DeploymentCondition{}
+		for _, c := range workload.Status.Conditions {
+			if string(c.Type) == conditionType {
+				found = true
+				continue
+			}
+			conditions = append(conditions, c)
+		}
+		workload.Status.Conditions = conditions
+	case *appsv1.StatefulSet:
+		conditions := []appsv1.StatefulSetCondition{}
+		for _, c := range workload.Status.Conditions {
+			if string(c.Type) == conditionType {
+				found = true
+				continue
+			}
+			conditions = append(conditions, c)
+		}
+		workload.Status.Conditions = conditions
+	}
+
+	return found
+}
@@ -0,0 +1,184 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package datadogmonitor
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+
+	datadoghqv1alpha1 "github.com/DataDog/datadog-operator/apis/datadoghq/v1alpha1"
+)
+
+func TestReconciler_recordStateTransition(t *testing.T) {
+	tests := []struct {
+		name      string
+		oldState  datadoghqv1alpha1.DatadogMonitorState
+		newState  datadoghqv1alpha1.DatadogMonitorState
+		wantEvent string
+	}{
+		{
+			name:     "unchanged",
+			oldState: datadoghqv1alpha1.DatadogMonitorStateAlert,
+			newState: datadoghqv1alpha1.DatadogMonitorStateAlert,
+		},
+		{
+			name:     "first state OK",
+			newState: datadoghqv1alpha1.DatadogMonitorStateOK,
+		},
+		{
+			name:      "first state triggered",
+			newState:  datadoghqv1alpha1.DatadogMonitorStateNoData,
+			wantEvent: "Warning MonitorStateChanged Monitor 1234 state changed from Unknown to No Data",
+		},
+		{
+			name:      "triggered",
+			oldState:  datadoghqv1alpha1.DatadogMonitorStateOK,
+			newState:  datadoghqv1alpha1.DatadogMonitorStateAlert,
+			wantEvent: "Warning MonitorStateChanged Monitor 1234 state changed from OK to Alert",
+		},
+		{
+			name:      "recovered",
+			oldState:  datadoghqv1alpha1.DatadogMonitorStateWarn,
+			newState:  datadoghqv1alpha1.DatadogMonitorStateOK,
+			wantEvent: "Normal MonitorStateChanged Monitor 1234 state changed from Warn to OK",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			recorder := record.NewFakeRecorder(10)
+			r := &Reconciler{recorder: recorder}
+			dm := genericDatadogMonitor()
+			dm.Status.ID = 1234
+
+			r.recordStateTransition(dm, tt.oldState, tt.newState)
+			if tt.wantEvent == "" {
+				assert.Empty(t, recorder.Events)
+				return
+			}
+			assert.Equal(t, tt.wantEvent, <-recorder.Events)
+		})
+	}
+}
+
+func TestReconciler_syncTarget(t *testing.T) {
+	deployment := &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{Namespace: resourcesNamespace, Name: "app"},
+		Status: appsv1.DeploymentStatus{
+			Conditions: []appsv1.DeploymentCondition{{Type: appsv1.DeploymentAvailable, Status: corev1.ConditionTrue}},
+		},
+	}
+	recorder := record.NewFakeRecorder(10)
+	r := &Reconciler{
+		client:   newTestClient(deployment),
+		recorder: recorder,
+		log:      testLogger,
+	}
+	dm := genericDatadogMonitor()
+	dm.Status.ID = 1234
+	dm.Spec.TargetRef = &datadoghqv1alpha1.DatadogMonitorTargetRef{Kind: "Deployment", Name: "app", Conditions: true}
+	key := datadoghqv1alpha1.DatadogMonitorStateKeyPrefix + dm.Name
+
+	dm.Status.MonitorState = datadoghqv1alpha1.DatadogMonitorStateAlert
+	r.syncTarget(context.TODO(), testLogger, dm, &dm.Status)
+	updated := &appsv1.Deployment{}
+	_ = r.client.Get(context.TODO(), types.NamespacedName{Namespace: resourcesNamespace, Name: "app"}, updated)
+	assert.Equal(t, "Alert", updated.Annotations[key])
+	assert.Len(t, updated.Status.Conditions, 2)
+	assert.Equal(t, appsv1.DeploymentConditionType(key), updated.Status.Conditions[1].Type)
+	assert.Equal(t, corev1.ConditionTrue, updated.Status.Conditions[1].Status)
+	assert.Equal(t, "Alert", updated.Status.Conditions[1].Reason)
+	assert.Equal(t, dm.Spec.TargetRef, dm.Status.SyncedTargetRef)
+
+	dm.Status.MonitorState = datadoghqv1alpha1.DatadogMonitorStateOK
+	r.syncTarget(context.TODO(), testLogger, dm, &dm.Status)
+	_ = r.client.Get(context.TODO(), types.NamespacedName{Namespace: resourcesNamespace, Name: "app"}, updated)
+	assert.Equal(t, "OK", updated.Annotations[key])
+	assert.Len(t, updated.Status.Conditions, 2)
+	assert.Equal(t, corev1.ConditionFalse, updated.Status.Conditions[1].Status)
+
+	// The annotation and condition are removed with the DatadogMonitor
+	r.cleanupTarget(context.TODO(), testLogger, dm)
+	_ = r.client.Get(context.TODO(), types.NamespacedName{Namespace: resourcesNamespace, Name: "app"}, updated)
+	assert.NotContains(t, updated.Annotations, key)
+	assert.Equal(t, []appsv1.DeploymentCondition{{Type: appsv1.DeploymentAvailable, Status: corev1.ConditionTrue}}, updated.Status.Conditions)
+
+	// A missing target doesn't fail the sync
+	dm.Spec.TargetRef = &datadoghqv1alpha1.DatadogMonitorTargetRef{Kind: "StatefulSet", Name: "missing"}
+	dm.Status.MonitorState = datadoghqv1alpha1.DatadogMonitorStateAlert
+	r.syncTarget(context.TODO(), testLogger, dm, &dm.Status)
+	assert.Contains(t, <-recorder.Events, "Warning TargetUpdateError Unable to update StatefulSet missing")
+}
+
+func TestReconciler_syncTarget_targetRefChanged(t *testing.T) {
+	available := appsv1.DeploymentCondition{Type: appsv1.DeploymentAvailable, Status: corev1.ConditionTrue}
+	r := &Reconciler{
+		client: newTestClient(
+			&appsv1.Deployment{
+				ObjectMeta: metav1.ObjectMeta{Namespace: resourcesNamespace, Name: "app"},
+				Status:     appsv1.DeploymentStatus{Conditions: []appsv1.DeploymentCondition{available}},
+			},
+			&appsv1.StatefulSet{ObjectMeta: metav1.ObjectMeta{Namespace: resourcesNamespace, Name: "db"}},
+		),
+		recorder: record.NewFakeRecorder(10),
+		log:      testLogger,
+	}
+	dm := genericDatadogMonitor()
+	dm.Status.ID = 1234
+	dm.Status.MonitorState = datadoghqv1alpha1.DatadogMonitorStateAlert
+	dm.Spec.TargetRef = &datadoghqv1alpha1.DatadogMonitorTargetRef{Kind: "Deployment", Name: "app", Conditions: true}
+	key := datadoghqv1alpha1.DatadogMonitorStateKeyPrefix + dm.Name
+	getDeployment := func() *appsv1.Deployment {
+		deployment := &appsv1.Deployment{}
+		assert.NoError(t, r.client.Get(context.TODO(), types.NamespacedName{Namespace: resourcesNamespace, Name: "app"}, deployment))
+		return deployment
+	}
+
+	r.syncTarget(context.TODO(), testLogger, dm, &dm.Status)
+	assert.Equal(t, "Alert", getDeployment().Annotations[key])
+	assert.Len(t, getDeployment().Status.Conditions, 2)
+
+	// Disabling the conditions only removes the condition
+	dm.Spec.TargetRef.Conditions = false
+	r.syncTarget(context.TODO(), testLogger, dm, &dm.Status)
+	assert.Equal(t, "Alert", getDeployment().Annotations[key])
+	assert.Equal(t, []appsv1.DeploymentCondition{available}, getDeployment().Status.Conditions)
+	assert.Equal(t, dm.Spec.TargetRef, dm.Status.SyncedTargetRef)
+
+	// Changing the workload moves the annotation
+	dm.Spec.TargetRef = &datadoghqv1alpha1.DatadogMonitorTargetRef{Kind: "StatefulSet", Name: "db"}
+	r.syncTarget(context.TODO(), testLogger, dm, &dm.Status)
+	assert.NotContains(t, getDeployment().Annotations, key)
+	statefulSet := &appsv1.StatefulSet{}
+	assert.NoError(t, r.client.Get(context.TODO(), types.NamespacedName{Namespace: resourcesNamespace, Name: "db"}, statefulSet))
+	assert.Equal(t, "Alert", statefulSet.Annotations[key])
+	assert.Equal(t, dm.Spec.TargetRef, dm.Status.SyncedTargetRef)
+
+	// Removing the target cleans up the previous workload
+	dm.Spec.TargetRef = nil
+	r.syncTarget(context.TODO(), testLogger, dm, &dm.Status)
+	assert.NoError(t, r.client.Get(context.TODO(), types.NamespacedName{Namespace: resourcesNamespace, Name: "db"}, statefulSet))
+	assert.NotContains(t, statefulSet.Annotations, key)
+	assert.Nil(t, dm.Status.SyncedTargetRef)
+}
+
+func Test_targetKey(t *testing.T) {
+	dm := genericDatadogMonitor()
+	key, err := targetKey(dm)
+	assert.NoError(t, err)
+	assert.Equal(t, "monitor.datadoghq.com/"+dm.Name, key)
+
+	dm.Name = strings.Repeat("a", 64)
+	_, err = targetKey(dm)
+	assert.Error(t, err)
+}
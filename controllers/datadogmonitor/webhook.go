@@ -0,0 +1,138 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package datadogmonitor
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/go-logr/logr"
+	apiequality "k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	datadoghqv1alpha1 "github.com/DataDog/datadog-operator/apis/datadoghq/v1alpha1"
+	"github.com/DataDog/datadog-operator/pkg/datadogclient"
+)
+
+// DefaultValidationTimeout is the default timeout of the Datadog monitor validate endpoint calls of the Validator
+const DefaultValidationTimeout = 5 * time.Second
+
+// ValidatorOptions configures the validation of the DatadogMonitors by the validating webhook
+type ValidatorOptions struct {
+	// RemoteValidation enables the validation of the monitors with the Datadog monitor validate endpoint, in addition
+	// to the local validation of the DatadogMonitor spec
+	RemoteValidation bool
+	// Timeout of the Datadog monitor validate endpoint calls
+	Timeout time.Duration
+	// FailOpen accepts the DatadogMonitors that can't be validated remotely, e.g. when the Datadog API is unreachable
+	FailOpen bool
+}
+
+// Validator validates the DatadogMonitors when they are created or updated, so that invalid monitors are rejected by
+// the API server instead of failing asynchronously in the DatadogMonitor status
+type Validator struct {
+	reconciler *Reconciler
+	options    ValidatorOptions
+	log        logr.Logger
+}
+
+// NewValidator returns a Validator using the Datadog API clients of r
+func NewValidator(r *Reconciler, options ValidatorOptions) *Validator {
+	if options.Timeout <= 0 {
+		options.Timeout = DefaultValidationTimeout
+	}
+
+	return &Validator{
+		reconciler: r,
+		options:    options,
+		log:        r.log.WithName("webhook"),
+	}
+}
+
+// ValidateCreate implements admission.CustomValidator
+func (v *Validator) ValidateCreate(ctx context.Context, obj runtime.Object) error {
+	dm, ok := obj.(*datadoghqv1alpha1.DatadogMonitor)
+	if !ok {
+		return fmt.Errorf("expected a DatadogMonitor but got a %T", obj)
+	}
+
+	return v.validate(dm)
+}
+
+// ValidateUpdate implements admission.CustomValidator
+func (v *Validator) ValidateUpdate(ctx context.Context, oldObj, newObj runtime.Object) error {
+	dm, ok := newObj.(*datadoghqv1alpha1.DatadogMonitor)
+	if !ok {
+		return fmt.Errorf("expected a DatadogMonitor but got a %T", newObj)
+	}
+	// Don't block the removal of the finalizer of a deleted DatadogMonitor
+	if dm.DeletionTimestamp != nil {
+		return nil
+	}
+	// Don't validate the metadata updates, e.g. the finalizer or label updates, the spec was validated when it changed
+	if oldDM, ok := oldObj.(*datadoghqv1alpha1.DatadogMonitor); ok && apiequality.Semantic.DeepEqual(oldDM.Spec, dm.Spec) {
+		return nil
+	}
+
+	return v.validate(dm)
+}
+
+// ValidateDelete implements admission.CustomValidator
+func (v *Validator) ValidateDelete(ctx context.Context, obj runtime.Object) error {
+	return nil
+}
+
+func (v *Validator) validate(dm *datadoghqv1alpha1.DatadogMonitor) error {
+	if err := datadoghqv1alpha1.IsValidDatadogMonitor(&dm.Spec); err != nil {
+		return fmt.Errorf("invalid DatadogMonitor spec: %w", err)
+	}
+	if !isSupportedMonitorType(dm.Spec.Type) {
+		return fmt.Errorf("monitor type %v not supported", dm.Spec.Type)
+	}
+
+	// The query of a composite monitor referencing DatadogMonitors by name is only valid once they are created
+	if !v.options.RemoteValidation || len(getCompositeReferences(dm)) > 0 {
+		return nil
+	}
+
+	invalid, err := v.validateRemote(dm)
+	if invalid {
+		return err
+	}
+	if err != nil {
+		if v.options.FailOpen {
+			v.log.Info("Unable to validate the monitor with the Datadog API, accepting it", "datadogmonitor", dm.Namespace+"/"+dm.Name, "error", err.Error())
+			return nil
+		}
+		return fmt.Errorf("unable to validate the monitor with the Datadog API: %w", err)
+	}
+
+	return nil
+}
+
+// validateRemote validates the monitor with the Datadog monitor validate endpoint. invalid is true if the monitor is
+// rejected by the endpoint, false if it is valid or couldn't be validated.
+func (v *Validator) validateRemote(dm *datadoghqv1alpha1.DatadogMonitor) (invalid bool, err error) {
+	auth, client, err := v.reconciler.getDatadogClient(dm)
+	if err != nil {
+		return false, err
+	}
+
+	// The authentication context holds the Datadog API keys and site
+	auth, cancel := context.WithTimeout(auth, v.options.Timeout)
+	defer cancel()
+
+	m, _ := buildMonitor(v.log, dm)
+	_, resp, err := client.MonitorsApi.ValidateMonitor(auth, *m)
+	if err != nil {
+		err = datadogclient.TranslateClientError(err, "invalid monitor")
+		return resp != nil && resp.StatusCode == http.StatusBadRequest, err
+	}
+
+	return false, nil
+}
@@ -0,0 +1,145 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package datadogmonitor
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	datadogapiclientv1 "github.com/DataDog/datadog-api-client-go/api/v1/datadog"
+	datadoghqv1alpha1 "github.com/DataDog/datadog-operator/apis/datadoghq/v1alpha1"
+)
+
+func TestValidator(t *testing.T) {
+	var validateCalls int
+	var statusCode int
+	httpServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		validateCalls++
+		assert.Equal(t, "/api/v1/monitor/validate", r.URL.Path)
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(statusCode)
+		if statusCode == http.StatusBadRequest {
+			_, _ = w.Write([]byte(`{"errors": ["The value provided for parameter 'query' is invalid"]}`))
+			return
+		}
+		_, _ = w.Write([]byte("{}"))
+	}))
+	defer httpServer.Close()
+
+	testConfig := datadogapiclientv1.NewConfiguration()
+	testConfig.HTTPClient = httpServer.Client()
+	r := &Reconciler{
+		datadogClient: datadogapiclientv1.NewAPIClient(testConfig),
+		datadogAuth:   setupTestAuth(httpServer.URL),
+		log:           testLogger,
+	}
+
+	tests := []struct {
+		name              string
+		update            func(dm *datadoghqv1alpha1.DatadogMonitor)
+		options           ValidatorOptions
+		statusCode        int
+		wantErr           string
+		wantValidateCalls int
+	}{
+		{
+			name:   "invalid spec",
+			update: func(dm *datadoghqv1alpha1.DatadogMonitor) { dm.Spec.Query = "" },
+			options: ValidatorOptions{
+				RemoteValidation: true,
+			},
+			wantErr: "invalid DatadogMonitor spec: spec.Query must be defined",
+		},
+		{
+			name:    "unsupported type",
+			update:  func(dm *datadoghqv1alpha1.DatadogMonitor) { dm.Spec.Type = "synthetics alert" },
+			wantErr: "monitor type synthetics alert not supported",
+		},
+		{
+			name: "valid spec, local validation only",
+		},
+		{
+			name:              "valid monitor",
+			options:           ValidatorOptions{RemoteValidation: true},
+			statusCode:        http.StatusOK,
+			wantValidateCalls: 1,
+		},
+		{
+			name:              "monitor rejected by Datadog",
+			options:           ValidatorOptions{RemoteValidation: true, FailOpen: true},
+			statusCode:        http.StatusBadRequest,
+			wantErr:           "The value provided for parameter 'query' is invalid",
+			wantValidateCalls: 1,
+		},
+		{
+			name:              "Datadog API error, fail open",
+			options:           ValidatorOptions{RemoteValidation: true, FailOpen: true},
+			statusCode:        http.StatusInternalServerError,
+			wantValidateCalls: 1,
+		},
+		{
+			name:              "Datadog API error, fail closed",
+			options:           ValidatorOptions{RemoteValidation: true},
+			statusCode:        http.StatusInternalServerError,
+			wantErr:           "unable to validate the monitor with the Datadog API",
+			wantValidateCalls: 1,
+		},
+		{
+			name: "composite monitor referencing DatadogMonitors",
+			update: func(dm *datadoghqv1alpha1.DatadogMonitor) {
+				dm.Spec.Type = datadoghqv1alpha1.DatadogMonitorTypeComposite
+				dm.Spec.Query = "${disk} && ${cpu}"
+			},
+			options: ValidatorOptions{RemoteValidation: true},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			validateCalls = 0
+			statusCode = tt.statusCode
+			dm := genericDatadogMonitor()
+			if tt.update != nil {
+				tt.update(dm)
+			}
+
+			err := NewValidator(r, tt.options).ValidateCreate(context.TODO(), dm)
+			assert.Equal(t, tt.wantValidateCalls, validateCalls)
+			if tt.wantErr != "" {
+				assert.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestValidator_ValidateUpdate(t *testing.T) {
+	v := NewValidator(&Reconciler{log: testLogger}, ValidatorOptions{})
+	old := genericDatadogMonitor()
+	dm := genericDatadogMonitor()
+	dm.Spec.Query = ""
+	assert.Error(t, v.ValidateUpdate(context.TODO(), old, dm))
+
+	// The finalizer of a deleted DatadogMonitor can be removed
+	now := metav1.Now()
+	dm.DeletionTimestamp = &now
+	assert.NoError(t, v.ValidateUpdate(context.TODO(), old, dm))
+
+	// The spec isn't validated, neither locally nor remotely, if it is unchanged
+	remote := NewValidator(&Reconciler{log: testLogger}, ValidatorOptions{RemoteValidation: true})
+	invalid := genericDatadogMonitor()
+	invalid.Spec.Query = ""
+	relabeled := invalid.DeepCopy()
+	relabeled.Labels = map[string]string{"team": "a"}
+	relabeled.Finalizers = []string{datadogMonitorFinalizer}
+	assert.NoError(t, remote.ValidateUpdate(context.TODO(), invalid, relabeled))
+}
@@ -7,6 +7,7 @@ package controllers
 
 import (
 	"context"
+	"errors"
 
 	"github.com/go-logr/logr"
 	"k8s.io/apimachinery/pkg/runtime"
@@ -14,6 +15,8 @@ import (
 	"k8s.io/client-go/tools/record"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/source"
 
 	datadoghqv1alpha1 "github.com/DataDog/datadog-operator/apis/datadoghq/v1alpha1"
 	"github.com/DataDog/datadog-operator/controllers/datadogmonitor"
@@ -28,33 +31,75 @@ type DatadogMonitorReconciler struct {
 	Log         logr.Logger
 	Scheme      *runtime.Scheme
 	Recorder    record.EventRecorder
+	Options     datadogmonitor.ReconcilerOptions
 	internal    *datadogmonitor.Reconciler
 }
 
 // +kubebuilder:rbac:groups=datadoghq.com,resources=datadogmonitors,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=datadoghq.com,resources=datadogmonitors/status,verbs=get;update;patch
 // +kubebuilder:rbac:groups=datadoghq.com,resources=datadogmonitors/finalizers,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=apps,resources=deployments;statefulsets,verbs=get;list;watch;patch
+// +kubebuilder:rbac:groups=apps,resources=deployments/status;statefulsets/status,verbs=patch
 
 // Reconcile loop for DatadogMonitor.
 func (r *DatadogMonitorReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
 	return r.internal.Reconcile(ctx, req)
 }
 
+// UpdateDatadogClient replaces the Datadog API Client used by the controller.
+func (r *DatadogMonitorReconciler) UpdateDatadogClient(ddClient datadogclient.DatadogClient) {
+	if r.internal != nil {
+		r.internal.UpdateDatadogClient(ddClient)
+	}
+}
+
+// SetupWebhookWithManager starts the DatadogMonitor validating webhook, once the controller is set up
+func (r *DatadogMonitorReconciler) SetupWebhookWithManager(mgr ctrl.Manager, options datadogmonitor.ValidatorOptions) error {
+	return ctrl.NewWebhookManagedBy(mgr).
+		For(&datadoghqv1alpha1.DatadogMonitor{}).
+		WithValidator(datadogmonitor.NewValidator(r.internal, options)).
+		Complete()
+}
+
 // SetupWithManager creates a new DatadogMonitor controller.
 func (r *DatadogMonitorReconciler) SetupWithManager(mgr ctrl.Manager) error {
-	internal, err := datadogmonitor.NewReconciler(r.Client, r.DDClient, r.VersionInfo, r.Scheme, r.Log, r.Recorder)
+	internal, err := datadogmonitor.NewReconciler(r.Client, mgr.GetAPIReader(), r.DDClient, r.VersionInfo, r.Scheme, r.Log, r.Recorder, r.Options)
 	if err != nil {
 		return err
 	}
 	r.internal = internal
 
 	builder := ctrl.NewControllerManagedBy(mgr).
-		For(&datadoghqv1alpha1.DatadogMonitor{})
+		For(&datadoghqv1alpha1.DatadogMonitor{}).
+		Watches(&source.Kind{Type: &datadoghqv1alpha1.DatadogMonitor{}}, handler.EnqueueRequestsFromMapFunc(internal.RequestsForRelatedMonitors))
 
 	err = builder.Complete(r)
 	if err != nil {
 		return err
 	}
 
+	// Sync the monitor states in batches rather than with a Datadog API call per DatadogMonitor
+	if err = mgr.Add(datadogmonitor.NewStateSyncer(internal)); err != nil {
+		return err
+	}
+
+	if r.Options.OrphanGCEnabled {
+		if r.Options.ClusterName == "" {
+			return errors.New("the cluster name is required to collect the orphan monitors")
+		}
+		if err = mgr.Add(datadogmonitor.NewOrphanCollector(internal)); err != nil {
+			return err
+		}
+	}
+
+	if r.Options.WebhookReceiverAddress != "" {
+		if r.Options.WebhookReceiverToken == "" {
+			return errors.New("a token is required to receive the Datadog webhook notifications")
+		}
+		if err = mgr.Add(datadogmonitor.NewReceiver(internal)); err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
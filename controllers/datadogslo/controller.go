@@ -0,0 +1,329 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package datadogslo
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/go-logr/logr"
+	apiequality "k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	datadogapiclientv1 "github.com/DataDog/datadog-api-client-go/api/v1/datadog"
+	datadoghqv1alpha1 "github.com/DataDog/datadog-operator/apis/datadoghq/v1alpha1"
+	ctrUtils "github.com/DataDog/datadog-operator/pkg/controller/utils"
+	"github.com/DataDog/datadog-operator/pkg/controller/utils/comparison"
+	"github.com/DataDog/datadog-operator/pkg/controller/utils/condition"
+	"github.com/DataDog/datadog-operator/pkg/controller/utils/datadog"
+	"github.com/DataDog/datadog-operator/pkg/datadogclient"
+)
+
+const (
+	defaultRequeuePeriod    = 60 * time.Second
+	defaultErrRequeuePeriod = 5 * time.Second
+)
+
+// Reconciler reconciles a DatadogSLO object
+type Reconciler struct {
+	client        client.Client
+	apiReader     client.Reader
+	datadogClient *datadogapiclientv1.APIClient
+	datadogAuth   context.Context
+	datadogMutex  sync.RWMutex
+	clients       datadogclient.ClientCache
+	log           logr.Logger
+	scheme        *runtime.Scheme
+	recorder      record.EventRecorder
+}
+
+// NewReconciler returns a new Reconciler object
+func NewReconciler(client client.Client, apiReader client.Reader, ddClient datadogclient.DatadogClient, scheme *runtime.Scheme, log logr.Logger, recorder record.EventRecorder) (*Reconciler, error) {
+	return &Reconciler{
+		client:        client,
+		apiReader:     apiReader,
+		datadogClient: ddClient.Client,
+		datadogAuth:   ddClient.Auth,
+		scheme:        scheme,
+		log:           log,
+		recorder:      recorder,
+	}, nil
+}
+
+// UpdateDatadogClient replaces the Datadog API client, e.g. when the operator credentials are rotated
+func (r *Reconciler) UpdateDatadogClient(ddClient datadogclient.DatadogClient) {
+	r.datadogMutex.Lock()
+	defer r.datadogMutex.Unlock()
+	r.datadogClient = ddClient.Client
+	r.datadogAuth = ddClient.Auth
+}
+
+// getDatadogClient returns the Datadog API authentication context and client of a DatadogSLO:
+// the ones of its credentials Secret if referenced, the operator ones otherwise
+func (r *Reconciler) getDatadogClient(slo *datadoghqv1alpha1.DatadogSLO) (context.Context, *datadogapiclientv1.APIClient, error) {
+	if slo.Spec.CredentialsSecretRef != nil {
+		ddClient, err := r.clients.GetFromSecret(r.apiReader, slo.Namespace, slo.Spec.CredentialsSecretRef)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		return ddClient.Auth, ddClient.Client, nil
+	}
+
+	r.datadogMutex.RLock()
+	defer r.datadogMutex.RUnlock()
+	return r.datadogAuth, r.datadogClient, nil
+}
+
+// Reconcile is similar to reconciler.Reconcile interface, but taking a context
+func (r *Reconciler) Reconcile(ctx context.Context, request reconcile.Request) (reconcile.Result, error) {
+	return r.internalReconcile(ctx, request)
+}
+
+// Reconcile loop for DatadogSLO
+func (r *Reconciler) internalReconcile(ctx context.Context, req reconcile.Request) (reconcile.Result, error) {
+	logger := r.log.WithValues("datadogslo", req.NamespacedName)
+	logger.Info("Reconciling DatadogSLO")
+	now := metav1.NewTime(time.Now())
+
+	// Get instance
+	instance := &datadoghqv1alpha1.DatadogSLO{}
+	var result ctrl.Result
+	err := r.client.Get(ctx, req.NamespacedName, instance)
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			// Request object not found, could have been deleted after reconcile request.
+			// Owned objects are automatically garbage collected. For additional cleanup logic use finalizers.
+			// Return and don't requeue
+			return result, nil
+		}
+		// Error reading the object - requeue the request
+		return ctrl.Result{RequeueAfter: defaultErrRequeuePeriod}, err
+	}
+
+	newStatus := instance.Status.DeepCopy()
+
+	if result, err = r.handleFinalizer(logger, instance); ctrUtils.ShouldReturn(result, err) {
+		return result, err
+	}
+
+	// Requeue periodically to retry the monitors not created in Datadog yet and refresh the SLO state
+	result.RequeueAfter = defaultRequeuePeriod
+
+	// Validate the DatadogSLO spec
+	if err = datadoghqv1alpha1.IsValidDatadogSLO(&instance.Spec); err != nil {
+		logger.Error(err, "invalid DatadogSLO spec")
+
+		return r.updateStatusIfNeeded(logger, instance, newStatus, err, result)
+	}
+
+	instanceSpecHash, err := comparison.GenerateMD5ForSpec(&instance.Spec)
+	if err != nil {
+		logger.Error(err, "error generating hash")
+
+		return r.updateStatusIfNeeded(logger, instance, newStatus, err, result)
+	}
+
+	monitorIDs, err := r.getMonitorIDs(ctx, instance)
+	if err != nil {
+		logger.Error(err, "error resolving the SLO monitors")
+
+		return r.updateStatusIfNeeded(logger, instance, newStatus, err, result)
+	}
+
+	switch {
+	case instance.Status.ID == "":
+		logger.V(1).Info("SLO ID is not set; creating SLO in Datadog")
+		if err = r.create(logger, instance, newStatus, monitorIDs); err != nil {
+			logger.Error(err, "error creating SLO")
+		} else {
+			newStatus.CurrentHash = instanceSpecHash
+		}
+	case instanceSpecHash != instance.Status.CurrentHash || !equalIDs(monitorIDs, instance.Status.MonitorIDs):
+		if err = r.update(logger, instance, newStatus, monitorIDs); err != nil {
+			logger.Error(err, "error updating SLO", "SLO ID", instance.Status.ID)
+		} else {
+			newStatus.CurrentHash = instanceSpecHash
+		}
+	default:
+		// Spec has not changed, just refresh the SLO state every defaultRequeuePeriod
+		// to avoid overloading APIServer and DD
+		if instance.Status.LastStateUpdateTime != nil {
+			nextUpdateIn := defaultRequeuePeriod - now.Sub(instance.Status.LastStateUpdateTime.Time)
+			if nextUpdateIn > 0 {
+				return ctrl.Result{RequeueAfter: nextUpdateIn}, nil
+			}
+		}
+
+		if err = r.updateState(instance, newStatus, now); err != nil {
+			logger.Error(err, "error getting SLO state", "SLO ID", instance.Status.ID)
+		}
+	}
+
+	// Update the status
+	return r.updateStatusIfNeeded(logger, instance, newStatus, err, result)
+}
+
+// getMonitorIDs returns the sorted IDs of the monitors of a monitor-based DatadogSLO, resolving its DatadogMonitor references
+func (r *Reconciler) getMonitorIDs(ctx context.Context, slo *datadoghqv1alpha1.DatadogSLO) ([]int, error) {
+	if slo.Spec.Type != datadoghqv1alpha1.DatadogSLOTypeMonitor {
+		return nil, nil
+	}
+
+	ids := make([]int, 0, len(slo.Spec.MonitorIDs)+len(slo.Spec.MonitorRefs))
+	ids = append(ids, slo.Spec.MonitorIDs...)
+	for _, ref := range slo.Spec.MonitorRefs {
+		dm := &datadoghqv1alpha1.DatadogMonitor{}
+		if err := r.client.Get(ctx, types.NamespacedName{Namespace: slo.Namespace, Name: ref.Name}, dm); err != nil {
+			return nil, fmt.Errorf("unable to get DatadogMonitor %s: %w", ref.Name, err)
+		}
+		if dm.Status.ID == 0 {
+			return nil, fmt.Errorf("DatadogMonitor %s is not created in Datadog yet", dm.Name)
+		}
+		if !apiequality.Semantic.DeepEqual(dm.Spec.CredentialsSecretRef, slo.Spec.CredentialsSecretRef) {
+			return nil, fmt.Errorf("DatadogMonitor %s doesn't use the credentials of the DatadogSLO", dm.Name)
+		}
+		ids = append(ids, dm.Status.ID)
+	}
+	sort.Ints(ids)
+
+	return ids, nil
+}
+
+func (r *Reconciler) create(logger logr.Logger, slo *datadoghqv1alpha1.DatadogSLO, status *datadoghqv1alpha1.DatadogSLOStatus, monitorIDs []int) error {
+	datadogAuth, datadogClient, err := r.getDatadogClient(slo)
+	if err != nil {
+		return err
+	}
+
+	created, err := createSLO(datadogAuth, datadogClient, buildSLORequest(slo, monitorIDs))
+	if err != nil {
+		return err
+	}
+	r.recordEvent(slo, buildEventInfo(slo.Name, slo.Namespace, datadog.CreationEvent))
+
+	status.ID = created.GetId()
+	creator := created.GetCreator()
+	status.Creator = creator.GetEmail()
+	createdTime := metav1.NewTime(time.Unix(created.GetCreatedAt(), 0))
+	status.Created = &createdTime
+	status.MonitorIDs = monitorIDs
+	logger.Info("Created a new DatadogSLO", "SLO Namespace", slo.Namespace, "SLO Name", slo.Name, "SLO ID", status.ID)
+
+	return nil
+}
+
+func (r *Reconciler) update(logger logr.Logger, slo *datadoghqv1alpha1.DatadogSLO, status *datadoghqv1alpha1.DatadogSLOStatus, monitorIDs []int) error {
+	datadogAuth, datadogClient, err := r.getDatadogClient(slo)
+	if err != nil {
+		return err
+	}
+
+	if err = updateSLO(datadogAuth, datadogClient, status.ID, buildSLO(slo, monitorIDs)); err != nil {
+		return err
+	}
+	r.recordEvent(slo, buildEventInfo(slo.Name, slo.Namespace, datadog.UpdateEvent))
+
+	status.MonitorIDs = monitorIDs
+	// Refresh the state with the new thresholds at the next reconcile
+	status.LastStateUpdateTime = nil
+	logger.Info("Updated DatadogSLO", "SLO Namespace", slo.Namespace, "SLO Name", slo.Name, "SLO ID", status.ID)
+
+	return nil
+}
+
+// updateState refreshes the SLI value, error budget and state of the SLO for each threshold timeframe
+func (r *Reconciler) updateState(slo *datadoghqv1alpha1.DatadogSLO, status *datadoghqv1alpha1.DatadogSLOStatus, now metav1.Time) error {
+	datadogAuth, datadogClient, err := r.getDatadogClient(slo)
+	if err != nil {
+		return err
+	}
+
+	timeframes := make([]datadoghqv1alpha1.DatadogSLOTimeframeStatus, 0, len(slo.Spec.Thresholds))
+	for _, threshold := range slo.Spec.Thresholds {
+		sli, err := getSLIValue(datadogAuth, datadogClient, status.ID, threshold.Timeframe, now.Time)
+		if err != nil {
+			return err
+		}
+		timeframes = append(timeframes, buildTimeframeStatus(threshold, sli))
+	}
+	status.Timeframes = timeframes
+	status.LastStateUpdateTime = &now
+
+	return nil
+}
+
+func (r *Reconciler) updateStatusIfNeeded(logger logr.Logger, slo *datadoghqv1alpha1.DatadogSLO, status *datadoghqv1alpha1.DatadogSLOStatus, currentErr error, result ctrl.Result) (ctrl.Result, error) {
+	// Update Error and Active conditions
+	condition.SetErrorActiveStatusConditions(&status.Conditions, slo.Generation, datadogSLOKind, currentErr)
+
+	// Retry once the Datadog API rate limit resets
+	if reset, rateLimited := datadogclient.GetRateLimitReset(currentErr); rateLimited {
+		result.RequeueAfter = reset
+	}
+
+	if !apiequality.Semantic.DeepEqual(&slo.Status, status) {
+		slo.Status = *status
+		if err := r.client.Status().Update(context.TODO(), slo); err != nil {
+			if apierrors.IsConflict(err) {
+				logger.Error(err, "unable to update DatadogSLO status due to update conflict")
+
+				return ctrl.Result{Requeue: true, RequeueAfter: defaultErrRequeuePeriod}, nil
+			}
+			logger.Error(err, "unable to update DatadogSLO status")
+
+			return ctrl.Result{}, err
+		}
+	}
+
+	return result, nil
+}
+
+// RequestsForMonitor returns the reconcile requests of the DatadogSLOs referencing a DatadogMonitor,
+// so that they are updated when the DatadogMonitor is created in Datadog
+func (r *Reconciler) RequestsForMonitor(obj client.Object) []reconcile.Request {
+	sloList := &datadoghqv1alpha1.DatadogSLOList{}
+	if err := r.client.List(context.TODO(), sloList, client.InNamespace(obj.GetNamespace())); err != nil {
+		r.log.Error(err, "unable to list DatadogSLOs")
+		return nil
+	}
+
+	var requests []reconcile.Request
+	for _, slo := range sloList.Items {
+		for _, ref := range slo.Spec.MonitorRefs {
+			if ref.Name == obj.GetName() {
+				requests = append(requests, reconcile.Request{NamespacedName: types.NamespacedName{Namespace: slo.Namespace, Name: slo.Name}})
+				break
+			}
+		}
+	}
+
+	return requests
+}
+
+// equalIDs returns true if the two sorted lists of IDs are equal
+func equalIDs(a, b []int) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,301 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package datadogslo
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	datadogapiclientv1 "github.com/DataDog/datadog-api-client-go/api/v1/datadog"
+	datadoghqv1alpha1 "github.com/DataDog/datadog-operator/apis/datadoghq/v1alpha1"
+	"github.com/DataDog/datadog-operator/pkg/controller/utils/condition"
+)
+
+const (
+	resourcesName      = "foo"
+	resourcesNamespace = "bar"
+)
+
+// fakeSLOAPI is a minimal implementation of the Datadog SLOs API
+type fakeSLOAPI struct {
+	sync.Mutex
+	nextID int
+	sli    *float64
+	slos   map[string]datadogapiclientv1.ServiceLevelObjective
+}
+
+func newFakeSLOAPI(sli *float64) *fakeSLOAPI {
+	return &fakeSLOAPI{nextID: 1, sli: sli, slos: map[string]datadogapiclientv1.ServiceLevelObjective{}}
+}
+
+func (f *fakeSLOAPI) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.Lock()
+	defer f.Unlock()
+	w.Header().Set("Content-Type", "application/json")
+
+	path := strings.TrimPrefix(r.URL.Path, "/api/v1/slo")
+	id := strings.TrimSuffix(strings.TrimPrefix(path, "/"), "/history")
+	if _, found := f.slos[id]; id != "" && !found {
+		w.WriteHeader(http.StatusNotFound)
+		_, _ = w.Write([]byte(`{"errors": ["SLO not found"]}`))
+		return
+	}
+
+	switch {
+	case r.Method == http.MethodPost:
+		slo := datadogapiclientv1.ServiceLevelObjective{}
+		_ = json.NewDecoder(r.Body).Decode(&slo)
+		slo.SetId(fmt.Sprintf("slo%d", f.nextID))
+		slo.SetCreatedAt(1654000000)
+		f.slos[slo.GetId()] = slo
+		f.nextID++
+		_ = json.NewEncoder(w).Encode(datadogapiclientv1.SLOListResponse{Data: &[]datadogapiclientv1.ServiceLevelObjective{slo}})
+	case r.Method == http.MethodPut:
+		slo := datadogapiclientv1.ServiceLevelObjective{}
+		_ = json.NewDecoder(r.Body).Decode(&slo)
+		slo.SetId(id)
+		f.slos[id] = slo
+		_ = json.NewEncoder(w).Encode(datadogapiclientv1.SLOListResponse{Data: &[]datadogapiclientv1.ServiceLevelObjective{slo}})
+	case r.Method == http.MethodDelete:
+		delete(f.slos, id)
+		_ = json.NewEncoder(w).Encode(datadogapiclientv1.SLODeleteResponse{Data: &[]string{id}})
+	case strings.HasSuffix(path, "/history"):
+		_ = json.NewEncoder(w).Encode(datadogapiclientv1.SLOHistoryResponse{
+			Data: &datadogapiclientv1.SLOHistoryResponseData{Overall: &datadogapiclientv1.SLOHistorySLIData{SliValue: f.sli}},
+		})
+	}
+}
+
+func TestReconciler_Reconcile(t *testing.T) {
+	s := runtime.NewScheme()
+	_ = clientgoscheme.AddToScheme(s)
+	_ = datadoghqv1alpha1.AddToScheme(s)
+
+	monitorA := newMonitor("monitor-a", 1)
+	monitorB := newMonitor("monitor-b", 2)
+	monitorNotCreated := newMonitor("monitor-c", 0)
+	sli := 99.95
+
+	tests := []struct {
+		name           string
+		slo            *datadoghqv1alpha1.DatadogSLO
+		action         func(t *testing.T, c client.Client)
+		wantActive     metav1.ConditionStatus
+		wantMonitorIDs []int
+		wantAPI        map[string][]int64
+		wantTimeframes []datadoghqv1alpha1.DatadogSLOTimeframeStatus
+	}{
+		{
+			name:           "monitor SLO",
+			slo:            newSLO(datadoghqv1alpha1.DatadogSLOTypeMonitor, []string{"monitor-b", "monitor-a"}, 12345),
+			wantActive:     metav1.ConditionTrue,
+			wantMonitorIDs: []int{1, 2, 12345},
+			wantAPI:        map[string][]int64{"slo1": {1, 2, 12345}},
+			wantTimeframes: []datadoghqv1alpha1.DatadogSLOTimeframeStatus{
+				{Timeframe: datadoghqv1alpha1.DatadogSLOTimeframe7d, State: datadoghqv1alpha1.DatadogSLOStateWarning, SLIValue: "99.95", ErrorBudgetRemaining: "50"},
+			},
+		},
+		{
+			name:       "metric SLO",
+			slo:        newSLO(datadoghqv1alpha1.DatadogSLOTypeMetric, nil),
+			wantActive: metav1.ConditionTrue,
+			wantAPI:    map[string][]int64{"slo1": nil},
+			wantTimeframes: []datadoghqv1alpha1.DatadogSLOTimeframeStatus{
+				{Timeframe: datadoghqv1alpha1.DatadogSLOTimeframe7d, State: datadoghqv1alpha1.DatadogSLOStateWarning, SLIValue: "99.95", ErrorBudgetRemaining: "50"},
+			},
+		},
+		{
+			name: "monitor reference added",
+			slo:  newSLO(datadoghqv1alpha1.DatadogSLOTypeMonitor, []string{"monitor-a"}),
+			action: func(t *testing.T, c client.Client) {
+				slo := &datadoghqv1alpha1.DatadogSLO{}
+				assert.NoError(t, c.Get(context.TODO(), types.NamespacedName{Namespace: resourcesNamespace, Name: resourcesName}, slo))
+				slo.Spec.MonitorRefs = append(slo.Spec.MonitorRefs, corev1.LocalObjectReference{Name: "monitor-b"})
+				assert.NoError(t, c.Update(context.TODO(), slo))
+			},
+			wantActive:     metav1.ConditionTrue,
+			wantMonitorIDs: []int{1, 2},
+			wantAPI:        map[string][]int64{"slo1": {1, 2}},
+		},
+		{
+			name:       "referenced monitor not created in Datadog",
+			slo:        newSLO(datadoghqv1alpha1.DatadogSLOTypeMonitor, []string{"monitor-c"}),
+			wantActive: metav1.ConditionFalse,
+			wantAPI:    map[string][]int64{},
+		},
+		{
+			name:       "invalid spec",
+			slo:        newSLO(datadoghqv1alpha1.DatadogSLOTypeMonitor, nil),
+			wantActive: metav1.ConditionFalse,
+			wantAPI:    map[string][]int64{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			api := newFakeSLOAPI(&sli)
+			httpServer := httptest.NewServer(api)
+			defer httpServer.Close()
+
+			testConfig := datadogapiclientv1.NewConfiguration()
+			testConfig.HTTPClient = httpServer.Client()
+			testConfig.SetUnstableOperationEnabled("GetSLOHistory", true)
+
+			k8sClient := fake.NewClientBuilder().WithScheme(s).WithObjects(
+				monitorA.DeepCopy(), monitorB.DeepCopy(), monitorNotCreated.DeepCopy(), tt.slo,
+			).Build()
+			r := &Reconciler{
+				client:        k8sClient,
+				datadogClient: datadogapiclientv1.NewAPIClient(testConfig),
+				datadogAuth:   setupTestAuth(httpServer.URL),
+				scheme:        s,
+				recorder:      record.NewFakeRecorder(10),
+				log:           logf.Log.WithName(tt.name),
+			}
+			req := newRequest(resourcesNamespace, resourcesName)
+
+			// Add the finalizer, create the SLO, then get its state
+			for i := 0; i < 3; i++ {
+				_, err := r.Reconcile(context.TODO(), req)
+				assert.NoError(t, err)
+			}
+			if tt.action != nil {
+				tt.action(t, k8sClient)
+				_, err := r.Reconcile(context.TODO(), req)
+				assert.NoError(t, err)
+			}
+
+			slo := &datadoghqv1alpha1.DatadogSLO{}
+			assert.NoError(t, k8sClient.Get(context.TODO(), req.NamespacedName, slo))
+			assert.Contains(t, slo.GetFinalizers(), datadogSLOFinalizer)
+			assert.True(t, apimeta.IsStatusConditionPresentAndEqual(slo.Status.Conditions, condition.ConditionTypeActive, tt.wantActive))
+			assert.Equal(t, tt.wantMonitorIDs, slo.Status.MonitorIDs)
+			if tt.wantTimeframes != nil {
+				assert.Equal(t, tt.wantTimeframes, slo.Status.Timeframes)
+				assert.NotNil(t, slo.Status.LastStateUpdateTime)
+			}
+
+			gotAPI := map[string][]int64{}
+			for id, ddSLO := range api.slos {
+				var monitorIDs []int64
+				if ddSLO.MonitorIds != nil {
+					monitorIDs = *ddSLO.MonitorIds
+				}
+				gotAPI[id] = monitorIDs
+				assert.Equal(t, slo.Spec.Name, ddSLO.GetName())
+			}
+			assert.Equal(t, tt.wantAPI, gotAPI)
+
+			// The finalizer deletes the SLO
+			assert.NoError(t, k8sClient.Delete(context.TODO(), slo))
+			_, err := r.Reconcile(context.TODO(), req)
+			assert.NoError(t, err)
+			assert.Empty(t, api.slos)
+		})
+	}
+}
+
+func TestReconciler_RequestsForMonitor(t *testing.T) {
+	s := runtime.NewScheme()
+	_ = datadoghqv1alpha1.AddToScheme(s)
+
+	byRef := newSLO(datadoghqv1alpha1.DatadogSLOTypeMonitor, []string{"monitor-a", "monitor-b"})
+	byRef.Name = "by-ref"
+	byID := newSLO(datadoghqv1alpha1.DatadogSLOTypeMonitor, nil, 1)
+	byID.Name = "by-id"
+	otherNamespace := byRef.DeepCopy()
+	otherNamespace.Namespace = "other"
+
+	r := &Reconciler{
+		client: fake.NewClientBuilder().WithScheme(s).WithObjects(byRef, byID, otherNamespace).Build(),
+		log:    logf.Log.WithName(t.Name()),
+	}
+
+	assert.Equal(t, []reconcile.Request{newRequest(resourcesNamespace, "by-ref")}, r.RequestsForMonitor(newMonitor("monitor-b", 2)))
+	assert.Empty(t, r.RequestsForMonitor(newMonitor("monitor-c", 3)))
+}
+
+func newSLO(sloType datadoghqv1alpha1.DatadogSLOType, monitorRefs []string, monitorIDs ...int) *datadoghqv1alpha1.DatadogSLO {
+	warning := "99.99"
+	slo := &datadoghqv1alpha1.DatadogSLO{
+		ObjectMeta: metav1.ObjectMeta{Namespace: resourcesNamespace, Name: resourcesName},
+		Spec: datadoghqv1alpha1.DatadogSLOSpec{
+			Name:       "checkout availability",
+			Type:       sloType,
+			MonitorIDs: monitorIDs,
+			Thresholds: []datadoghqv1alpha1.DatadogSLOThreshold{{Timeframe: datadoghqv1alpha1.DatadogSLOTimeframe7d, Target: "99.9", Warning: &warning}},
+		},
+	}
+	for _, ref := range monitorRefs {
+		slo.Spec.MonitorRefs = append(slo.Spec.MonitorRefs, corev1.LocalObjectReference{Name: ref})
+	}
+	if sloType == datadoghqv1alpha1.DatadogSLOTypeMetric {
+		slo.Spec.Query = &datadoghqv1alpha1.DatadogSLOQuery{
+			Numerator:   "sum:requests.success{service:checkout}.as_count()",
+			Denominator: "sum:requests.total{service:checkout}.as_count()",
+		}
+	}
+
+	return slo
+}
+
+func newMonitor(name string, id int) *datadoghqv1alpha1.DatadogMonitor {
+	return &datadoghqv1alpha1.DatadogMonitor{
+		ObjectMeta: metav1.ObjectMeta{Namespace: resourcesNamespace, Name: name},
+		Status:     datadoghqv1alpha1.DatadogMonitorStatus{ID: id},
+	}
+}
+
+func newRequest(ns, name string) reconcile.Request {
+	return reconcile.Request{
+		NamespacedName: types.NamespacedName{
+			Namespace: ns,
+			Name:      name,
+		},
+	}
+}
+
+func setupTestAuth(apiURL string) context.Context {
+	testAuth := context.WithValue(
+		context.Background(),
+		datadogapiclientv1.ContextAPIKeys,
+		map[string]datadogapiclientv1.APIKey{
+			"apiKeyAuth": {
+				Key: "DUMMY_API_KEY",
+			},
+			"appKeyAuth": {
+				Key: "DUMMY_APP_KEY",
+			},
+		},
+	)
+	parsedAPIURL, _ := url.Parse(apiURL)
+	testAuth = context.WithValue(testAuth, datadogapiclientv1.ContextServerIndex, 1)
+	testAuth = context.WithValue(testAuth, datadogapiclientv1.ContextServerVariables, map[string]string{
+		"name":     parsedAPIURL.Host,
+		"protocol": parsedAPIURL.Scheme,
+	})
+
+	return testAuth
+}
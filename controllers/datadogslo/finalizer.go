@@ -0,0 +1,87 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package datadogslo
+
+import (
+	"context"
+
+	"github.com/go-logr/logr"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	datadoghqv1alpha1 "github.com/DataDog/datadog-operator/apis/datadoghq/v1alpha1"
+	"github.com/DataDog/datadog-operator/pkg/controller/utils"
+	"github.com/DataDog/datadog-operator/pkg/controller/utils/datadog"
+)
+
+const (
+	datadogSLOFinalizer = "finalizer.slo.datadoghq.com"
+)
+
+func (r *Reconciler) handleFinalizer(logger logr.Logger, slo *datadoghqv1alpha1.DatadogSLO) (ctrl.Result, error) {
+	// Check if the DatadogSLO instance is marked to be deleted, which is indicated by the deletion timestamp being set.
+	if slo.GetDeletionTimestamp() != nil {
+		if utils.ContainsString(slo.GetFinalizers(), datadogSLOFinalizer) {
+			r.finalizeDatadogSLO(logger, slo)
+
+			slo.SetFinalizers(utils.RemoveString(slo.GetFinalizers(), datadogSLOFinalizer))
+			err := r.client.Update(context.TODO(), slo)
+			if err != nil {
+				return ctrl.Result{Requeue: true, RequeueAfter: defaultErrRequeuePeriod}, err
+			}
+		}
+
+		// Requeue until the object was properly deleted by Kuberentes
+		return ctrl.Result{Requeue: true, RequeueAfter: defaultRequeuePeriod}, nil
+	}
+
+	// Add finalizer for this resource if it doesn't already exist.
+	if !utils.ContainsString(slo.GetFinalizers(), datadogSLOFinalizer) {
+		if err := r.addFinalizer(logger, slo); err != nil {
+			return ctrl.Result{Requeue: true, RequeueAfter: defaultErrRequeuePeriod}, err
+		}
+
+		return ctrl.Result{Requeue: true, RequeueAfter: defaultRequeuePeriod}, nil
+	}
+
+	// Proceed in reconcile loop.
+	return ctrl.Result{}, nil
+}
+
+func (r *Reconciler) finalizeDatadogSLO(logger logr.Logger, slo *datadoghqv1alpha1.DatadogSLO) {
+	if slo.Status.ID == "" {
+		return
+	}
+
+	datadogAuth, datadogClient, err := r.getDatadogClient(slo)
+	if err != nil {
+		logger.Error(err, "failed to finalize SLO", "SLO ID", slo.Status.ID)
+
+		return
+	}
+
+	if err = deleteSLO(datadogAuth, datadogClient, slo.Status.ID); err != nil {
+		logger.Error(err, "failed to finalize SLO", "SLO ID", slo.Status.ID)
+
+		return
+	}
+	logger.Info("Successfully finalized DatadogSLO", "SLO ID", slo.Status.ID)
+	event := buildEventInfo(slo.Name, slo.Namespace, datadog.DeletionEvent)
+	r.recordEvent(slo, event)
+}
+
+func (r *Reconciler) addFinalizer(logger logr.Logger, slo *datadoghqv1alpha1.DatadogSLO) error {
+	logger.Info("Adding Finalizer for the DatadogSLO")
+
+	slo.SetFinalizers(append(slo.GetFinalizers(), datadogSLOFinalizer))
+
+	err := r.client.Update(context.TODO(), slo)
+	if err != nil {
+		logger.Error(err, "failed to update DatadogSLO with finalizer")
+		return err
+	}
+
+	return nil
+}
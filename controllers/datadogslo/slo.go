@@ -0,0 +1,165 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package datadogslo
+
+import (
+	"context"
+	"errors"
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+
+	datadogapiclientv1 "github.com/DataDog/datadog-api-client-go/api/v1/datadog"
+	datadoghqv1alpha1 "github.com/DataDog/datadog-operator/apis/datadoghq/v1alpha1"
+	"github.com/DataDog/datadog-operator/pkg/datadogclient"
+)
+
+var timeframeDurations = map[datadoghqv1alpha1.DatadogSLOTimeframe]time.Duration{
+	datadoghqv1alpha1.DatadogSLOTimeframe7d:  7 * 24 * time.Hour,
+	datadoghqv1alpha1.DatadogSLOTimeframe30d: 30 * 24 * time.Hour,
+	datadoghqv1alpha1.DatadogSLOTimeframe90d: 90 * 24 * time.Hour,
+}
+
+// buildSLO builds the Datadog SLO of a DatadogSLO, based on the resolved monitorIDs for monitor-based SLOs
+func buildSLO(slo *datadoghqv1alpha1.DatadogSLO, monitorIDs []int) datadogapiclientv1.ServiceLevelObjective {
+	spec := slo.Spec
+	s := datadogapiclientv1.NewServiceLevelObjective(spec.Name, buildThresholds(spec.Thresholds), datadogapiclientv1.SLOType(spec.Type))
+
+	if spec.Description != "" {
+		s.SetDescription(spec.Description)
+	}
+	if len(spec.Tags) > 0 {
+		s.SetTags(spec.Tags)
+	}
+	if len(monitorIDs) > 0 {
+		s.SetMonitorIds(toInt64s(monitorIDs))
+	}
+	if len(spec.Groups) > 0 {
+		s.SetGroups(spec.Groups)
+	}
+	if spec.Query != nil {
+		s.SetQuery(*datadogapiclientv1.NewServiceLevelObjectiveQuery(spec.Query.Denominator, spec.Query.Numerator))
+	}
+
+	return *s
+}
+
+// buildSLORequest builds the Datadog SLO creation request of a DatadogSLO
+func buildSLORequest(slo *datadoghqv1alpha1.DatadogSLO, monitorIDs []int) datadogapiclientv1.ServiceLevelObjectiveRequest {
+	s := buildSLO(slo, monitorIDs)
+	request := datadogapiclientv1.NewServiceLevelObjectiveRequest(s.Name, s.Thresholds, s.Type)
+	request.Description = s.Description
+	request.Tags = s.Tags
+	request.MonitorIds = s.MonitorIds
+	request.Groups = s.Groups
+	request.Query = s.Query
+
+	return *request
+}
+
+func buildThresholds(thresholds []datadoghqv1alpha1.DatadogSLOThreshold) []datadogapiclientv1.SLOThreshold {
+	result := make([]datadogapiclientv1.SLOThreshold, 0, len(thresholds))
+	for _, threshold := range thresholds {
+		t := datadogapiclientv1.NewSLOThreshold(parseFloat(threshold.Target), datadogapiclientv1.SLOTimeframe(threshold.Timeframe))
+		if threshold.Warning != nil {
+			t.SetWarning(parseFloat(*threshold.Warning))
+		}
+		result = append(result, *t)
+	}
+
+	return result
+}
+
+// buildTimeframeStatus computes the error budget remaining and the state of a SLO threshold from its SLI value, nil if unknown
+func buildTimeframeStatus(threshold datadoghqv1alpha1.DatadogSLOThreshold, sli *float64) datadoghqv1alpha1.DatadogSLOTimeframeStatus {
+	status := datadoghqv1alpha1.DatadogSLOTimeframeStatus{Timeframe: threshold.Timeframe}
+	if sli == nil {
+		status.State = datadoghqv1alpha1.DatadogSLOStateNoData
+		return status
+	}
+
+	target := parseFloat(threshold.Target)
+	status.SLIValue = formatPercentage(*sli)
+	status.ErrorBudgetRemaining = formatPercentage(100 * (*sli - target) / (100 - target))
+
+	switch {
+	case *sli < target:
+		status.State = datadoghqv1alpha1.DatadogSLOStateBreached
+	case threshold.Warning != nil && *sli < parseFloat(*threshold.Warning):
+		status.State = datadoghqv1alpha1.DatadogSLOStateWarning
+	default:
+		status.State = datadoghqv1alpha1.DatadogSLOStateOK
+	}
+
+	return status
+}
+
+func createSLO(auth context.Context, client *datadogapiclientv1.APIClient, slo datadogapiclientv1.ServiceLevelObjectiveRequest) (datadogapiclientv1.ServiceLevelObjective, error) {
+	resp, _, err := client.ServiceLevelObjectivesApi.CreateSLO(auth, slo)
+	if err != nil {
+		return datadogapiclientv1.ServiceLevelObjective{}, datadogclient.TranslateClientError(err, "error creating SLO")
+	}
+	if len(resp.GetData()) == 0 {
+		return datadogapiclientv1.ServiceLevelObjective{}, errors.New("error creating SLO: empty response")
+	}
+
+	return resp.GetData()[0], nil
+}
+
+func updateSLO(auth context.Context, client *datadogapiclientv1.APIClient, sloID string, slo datadogapiclientv1.ServiceLevelObjective) error {
+	if _, _, err := client.ServiceLevelObjectivesApi.UpdateSLO(auth, sloID, slo); err != nil {
+		return datadogclient.TranslateClientError(err, "error updating SLO")
+	}
+
+	return nil
+}
+
+// deleteSLO deletes a SLO, ignoring the SLOs that don't exist anymore
+func deleteSLO(auth context.Context, client *datadogapiclientv1.APIClient, sloID string) error {
+	_, resp, err := client.ServiceLevelObjectivesApi.DeleteSLO(auth, sloID)
+	if err != nil {
+		if resp != nil && resp.StatusCode == http.StatusNotFound {
+			return nil
+		}
+		return datadogclient.TranslateClientError(err, "error deleting SLO")
+	}
+
+	return nil
+}
+
+// getSLIValue returns the SLI value of a SLO over the timeframe ending now, nil if Datadog has no data
+func getSLIValue(auth context.Context, client *datadogapiclientv1.APIClient, sloID string, timeframe datadoghqv1alpha1.DatadogSLOTimeframe, now time.Time) (*float64, error) {
+	from := now.Add(-timeframeDurations[timeframe])
+	history, _, err := client.ServiceLevelObjectivesApi.GetSLOHistory(auth, sloID, from.Unix(), now.Unix())
+	if err != nil {
+		return nil, datadogclient.TranslateClientError(err, "error getting SLO history")
+	}
+
+	data := history.GetData()
+	overall := data.GetOverall()
+
+	return overall.SliValue, nil
+}
+
+func formatPercentage(value float64) string {
+	return strconv.FormatFloat(math.Round(value*1000)/1000, 'f', -1, 64)
+}
+
+// parseFloat parses a threshold validated by IsValidDatadogSLO
+func parseFloat(value string) float64 {
+	f, _ := strconv.ParseFloat(value, 64)
+	return f
+}
+
+func toInt64s(ids []int) []int64 {
+	result := make([]int64, 0, len(ids))
+	for _, id := range ids {
+		result = append(result, int64(id))
+	}
+
+	return result
+}
@@ -0,0 +1,90 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package datadogslo
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	datadogapiclientv1 "github.com/DataDog/datadog-api-client-go/api/v1/datadog"
+	datadoghqv1alpha1 "github.com/DataDog/datadog-operator/apis/datadoghq/v1alpha1"
+)
+
+func Test_buildSLORequest(t *testing.T) {
+	slo := newSLO(datadoghqv1alpha1.DatadogSLOTypeMonitor, []string{"monitor-a"})
+	slo.Spec.Description = "checkout availability"
+	slo.Spec.Tags = []string{"service:checkout"}
+	slo.Spec.Groups = []string{"env:prod"}
+
+	request := buildSLORequest(slo, []int{1})
+
+	assert.Equal(t, "checkout availability", request.GetName())
+	assert.Equal(t, "checkout availability", request.GetDescription())
+	assert.Equal(t, datadogapiclientv1.SLOTYPE_MONITOR, request.GetType())
+	assert.Equal(t, []string{"service:checkout"}, request.GetTags())
+	assert.Equal(t, []int64{1}, request.GetMonitorIds())
+	assert.Equal(t, []string{"env:prod"}, request.GetGroups())
+	assert.False(t, request.HasQuery())
+	assert.Len(t, request.GetThresholds(), 1)
+	assert.Equal(t, 99.9, request.GetThresholds()[0].Target)
+	assert.Equal(t, 99.99, request.GetThresholds()[0].GetWarning())
+	assert.Equal(t, datadogapiclientv1.SLOTIMEFRAME_SEVEN_DAYS, request.GetThresholds()[0].Timeframe)
+
+	metricRequest := buildSLORequest(newSLO(datadoghqv1alpha1.DatadogSLOTypeMetric, nil), nil)
+	assert.False(t, metricRequest.HasMonitorIds())
+	assert.Equal(t, "sum:requests.success{service:checkout}.as_count()", metricRequest.Query.Numerator)
+	assert.Equal(t, "sum:requests.total{service:checkout}.as_count()", metricRequest.Query.Denominator)
+}
+
+func Test_buildTimeframeStatus(t *testing.T) {
+	warning := "99.5"
+	withWarning := datadoghqv1alpha1.DatadogSLOThreshold{Timeframe: datadoghqv1alpha1.DatadogSLOTimeframe30d, Target: "99", Warning: &warning}
+	withoutWarning := datadoghqv1alpha1.DatadogSLOThreshold{Timeframe: datadoghqv1alpha1.DatadogSLOTimeframe30d, Target: "99"}
+	value := func(v float64) *float64 { return &v }
+
+	tests := []struct {
+		name      string
+		threshold datadoghqv1alpha1.DatadogSLOThreshold
+		sli       *float64
+		want      datadoghqv1alpha1.DatadogSLOTimeframeStatus
+	}{
+		{
+			name:      "no data",
+			threshold: withWarning,
+			want:      datadoghqv1alpha1.DatadogSLOTimeframeStatus{Timeframe: "30d", State: datadoghqv1alpha1.DatadogSLOStateNoData},
+		},
+		{
+			name:      "ok",
+			threshold: withWarning,
+			sli:       value(99.8),
+			want:      datadoghqv1alpha1.DatadogSLOTimeframeStatus{Timeframe: "30d", State: datadoghqv1alpha1.DatadogSLOStateOK, SLIValue: "99.8", ErrorBudgetRemaining: "80"},
+		},
+		{
+			name:      "warning",
+			threshold: withWarning,
+			sli:       value(99.25),
+			want:      datadoghqv1alpha1.DatadogSLOTimeframeStatus{Timeframe: "30d", State: datadoghqv1alpha1.DatadogSLOStateWarning, SLIValue: "99.25", ErrorBudgetRemaining: "25"},
+		},
+		{
+			name:      "ok without warning",
+			threshold: withoutWarning,
+			sli:       value(99.25),
+			want:      datadoghqv1alpha1.DatadogSLOTimeframeStatus{Timeframe: "30d", State: datadoghqv1alpha1.DatadogSLOStateOK, SLIValue: "99.25", ErrorBudgetRemaining: "25"},
+		},
+		{
+			name:      "breached",
+			threshold: withoutWarning,
+			sli:       value(98.5),
+			want:      datadoghqv1alpha1.DatadogSLOTimeframeStatus{Timeframe: "30d", State: datadoghqv1alpha1.DatadogSLOStateBreached, SLIValue: "98.5", ErrorBudgetRemaining: "-50"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, buildTimeframeStatus(tt.threshold, tt.sli))
+		})
+	}
+}
@@ -0,0 +1,68 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package controllers
+
+import (
+	"context"
+
+	"github.com/go-logr/logr"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+
+	datadoghqv1alpha1 "github.com/DataDog/datadog-operator/apis/datadoghq/v1alpha1"
+	"github.com/DataDog/datadog-operator/controllers/datadogslo"
+	"github.com/DataDog/datadog-operator/pkg/datadogclient"
+)
+
+// DatadogSLOReconciler reconciles a DatadogSLO object.
+type DatadogSLOReconciler struct {
+	Client   client.Client
+	DDClient datadogclient.DatadogClient
+	Log      logr.Logger
+	Scheme   *runtime.Scheme
+	Recorder record.EventRecorder
+	internal *datadogslo.Reconciler
+}
+
+// +kubebuilder:rbac:groups=datadoghq.com,resources=datadogslos,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=datadoghq.com,resources=datadogslos/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=datadoghq.com,resources=datadogslos/finalizers,verbs=get;list;watch;create;update;patch;delete
+
+// Reconcile loop for DatadogSLO.
+func (r *DatadogSLOReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	return r.internal.Reconcile(ctx, req)
+}
+
+// UpdateDatadogClient replaces the Datadog API Client used by the controller.
+func (r *DatadogSLOReconciler) UpdateDatadogClient(ddClient datadogclient.DatadogClient) {
+	if r.internal != nil {
+		r.internal.UpdateDatadogClient(ddClient)
+	}
+}
+
+// SetupWithManager creates a new DatadogSLO controller.
+func (r *DatadogSLOReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	internal, err := datadogslo.NewReconciler(r.Client, mgr.GetAPIReader(), r.DDClient, r.Scheme, r.Log, r.Recorder)
+	if err != nil {
+		return err
+	}
+	r.internal = internal
+
+	builder := ctrl.NewControllerManagedBy(mgr).
+		For(&datadoghqv1alpha1.DatadogSLO{}).
+		Watches(&source.Kind{Type: &datadoghqv1alpha1.DatadogMonitor{}}, handler.EnqueueRequestsFromMapFunc(internal.RequestsForMonitor))
+
+	err = builder.Complete(r)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
@@ -7,12 +7,14 @@ package controllers
 
 import (
 	"fmt"
+	"sync"
 
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/manager"
 
 	"github.com/DataDog/datadog-operator/controllers/datadogagent"
+	"github.com/DataDog/datadog-operator/controllers/datadogmonitor"
 	"github.com/DataDog/datadog-operator/pkg/config"
 	"github.com/DataDog/datadog-operator/pkg/datadogclient"
 	"github.com/DataDog/datadog-operator/pkg/kubernetes"
@@ -24,26 +26,42 @@ import (
 )
 
 const (
-	agentControllerName   = "DatadogAgent"
-	monitorControllerName = "DatadogMonitor"
+	agentControllerName       = "DatadogAgent"
+	monitorControllerName     = "DatadogMonitor"
+	downtimeControllerName    = "DatadogDowntime"
+	sloControllerName         = "DatadogSLO"
+	credentialsControllerName = "Credentials"
 )
 
 // SetupOptions defines options for setting up controllers to ease testing
 type SetupOptions struct {
-	SupportExtendedDaemonset bool
-	SupportCilium            bool
-	Creds                    config.Creds
-	DatadogAgentEnabled      bool
-	DatadogMonitorEnabled    bool
-	OperatorMetricsEnabled   bool
-	V2APIEnabled             bool
+	SupportExtendedDaemonset    bool
+	SupportCilium               bool
+	Creds                       config.Creds
+	CredentialManager           *config.CredentialManager
+	DatadogAgentEnabled         bool
+	DatadogMonitorEnabled       bool
+	DatadogDowntimeEnabled      bool
+	DatadogSLOEnabled           bool
+	OperatorMetricsEnabled      bool
+	V2APIEnabled                bool
+	DependenciesDiffLogEnabled  bool
+	DatadogAgentFeatureEnabled  bool
+	DatadogAgentTemplateEnabled bool
+	AdmissionWebhookEnabled     bool
+	AgentDefaultingWebhook      bool
+	MonitorValidatorOptions     datadogmonitor.ValidatorOptions
+	MonitorOptions              datadogmonitor.ReconcilerOptions
 }
 
 type starterFunc func(logr.Logger, manager.Manager, *version.Info, kubernetes.PlatformInfo, SetupOptions) error
 
 var controllerStarters = map[string]starterFunc{
-	agentControllerName:   startDatadogAgent,
-	monitorControllerName: startDatadogMonitor,
+	agentControllerName:       startDatadogAgent,
+	monitorControllerName:     startDatadogMonitor,
+	downtimeControllerName:    startDatadogDowntime,
+	sloControllerName:         startDatadogSLO,
+	credentialsControllerName: startCredentials,
 }
 
 // SetupControllers starts all controllers (also used by e2e tests)
@@ -94,7 +112,7 @@ func startDatadogAgent(logger logr.Logger, mgr manager.Manager, vInfo *version.I
 		return nil
 	}
 
-	return (&DatadogAgentReconciler{
+	reconciler := &DatadogAgentReconciler{
 		Client:       mgr.GetClient(),
 		VersionInfo:  vInfo,
 		PlatformInfo: pInfo,
@@ -102,12 +120,27 @@ func startDatadogAgent(logger logr.Logger, mgr manager.Manager, vInfo *version.I
 		Scheme:       mgr.GetScheme(),
 		Recorder:     mgr.GetEventRecorderFor(agentControllerName),
 		Options: datadogagent.ReconcilerOptions{
-			SupportExtendedDaemonset: options.SupportExtendedDaemonset,
-			SupportCilium:            options.SupportCilium,
-			OperatorMetricsEnabled:   options.OperatorMetricsEnabled,
-			V2Enabled:                options.V2APIEnabled,
+			SupportExtendedDaemonset:    options.SupportExtendedDaemonset,
+			SupportCilium:               options.SupportCilium,
+			OperatorMetricsEnabled:      options.OperatorMetricsEnabled,
+			V2Enabled:                   options.V2APIEnabled,
+			DependenciesDiffLogEnabled:  options.DependenciesDiffLogEnabled,
+			DatadogAgentFeatureEnabled:  options.DatadogAgentFeatureEnabled && options.V2APIEnabled,
+			DatadogAgentTemplateEnabled: options.DatadogAgentTemplateEnabled && options.V2APIEnabled,
+			CredentialManager:           options.CredentialManager,
 		},
-	}).SetupWithManager(mgr)
+	}
+	if err := reconciler.SetupWithManager(mgr); err != nil {
+		return err
+	}
+
+	if options.AdmissionWebhookEnabled && options.V2APIEnabled {
+		if err := reconciler.SetupWebhookWithManager(mgr, options.AgentDefaultingWebhook); err != nil {
+			return fmt.Errorf("unable to create DatadogAgent webhook: %w", err)
+		}
+	}
+
+	return nil
 }
 
 func startDatadogMonitor(logger logr.Logger, mgr manager.Manager, vInfo *version.Info, pInfo kubernetes.PlatformInfo, options SetupOptions) error {
@@ -122,12 +155,121 @@ func startDatadogMonitor(logger logr.Logger, mgr manager.Manager, vInfo *version
 		return fmt.Errorf("unable to create Datadog API Client: %w", err)
 	}
 
-	return (&DatadogMonitorReconciler{
+	reconciler := &DatadogMonitorReconciler{
 		Client:      mgr.GetClient(),
 		DDClient:    ddClient,
 		VersionInfo: vInfo,
 		Log:         ctrl.Log.WithName("controllers").WithName(monitorControllerName),
 		Scheme:      mgr.GetScheme(),
 		Recorder:    mgr.GetEventRecorderFor(monitorControllerName),
+		Options:     options.MonitorOptions,
+	}
+	if err = reconciler.SetupWithManager(mgr); err != nil {
+		return err
+	}
+
+	if options.AdmissionWebhookEnabled {
+		if err = reconciler.SetupWebhookWithManager(mgr, options.MonitorValidatorOptions); err != nil {
+			return fmt.Errorf("unable to create DatadogMonitor webhook: %w", err)
+		}
+	}
+
+	registerCredentialsRotation(options, reconciler.Log, ddClient, reconciler.UpdateDatadogClient)
+
+	return nil
+}
+
+func startDatadogDowntime(logger logr.Logger, mgr manager.Manager, vInfo *version.Info, pInfo kubernetes.PlatformInfo, options SetupOptions) error {
+	if !options.DatadogDowntimeEnabled {
+		logger.Info("Feature disabled, not starting the controller", "controller", downtimeControllerName)
+
+		return nil
+	}
+
+	ddClient, err := datadogclient.InitDatadogClient(options.Creds)
+	if err != nil {
+		return fmt.Errorf("unable to create Datadog API Client: %w", err)
+	}
+
+	reconciler := &DatadogDowntimeReconciler{
+		Client:   mgr.GetClient(),
+		DDClient: ddClient,
+		Log:      ctrl.Log.WithName("controllers").WithName(downtimeControllerName),
+		Scheme:   mgr.GetScheme(),
+		Recorder: mgr.GetEventRecorderFor(downtimeControllerName),
+	}
+	if err = reconciler.SetupWithManager(mgr); err != nil {
+		return err
+	}
+
+	registerCredentialsRotation(options, reconciler.Log, ddClient, reconciler.UpdateDatadogClient)
+
+	return nil
+}
+
+func startDatadogSLO(logger logr.Logger, mgr manager.Manager, vInfo *version.Info, pInfo kubernetes.PlatformInfo, options SetupOptions) error {
+	if !options.DatadogSLOEnabled {
+		logger.Info("Feature disabled, not starting the controller", "controller", sloControllerName)
+
+		return nil
+	}
+
+	ddClient, err := datadogclient.InitDatadogClient(options.Creds)
+	if err != nil {
+		return fmt.Errorf("unable to create Datadog API Client: %w", err)
+	}
+
+	reconciler := &DatadogSLOReconciler{
+		Client:   mgr.GetClient(),
+		DDClient: ddClient,
+		Log:      ctrl.Log.WithName("controllers").WithName(sloControllerName),
+		Scheme:   mgr.GetScheme(),
+		Recorder: mgr.GetEventRecorderFor(sloControllerName),
+	}
+	if err = reconciler.SetupWithManager(mgr); err != nil {
+		return err
+	}
+
+	registerCredentialsRotation(options, reconciler.Log, ddClient, reconciler.UpdateDatadogClient)
+
+	return nil
+}
+
+func startCredentials(logger logr.Logger, mgr manager.Manager, vInfo *version.Info, pInfo kubernetes.PlatformInfo, options SetupOptions) error {
+	if options.CredentialManager == nil || options.CredentialManager.Secret() == nil {
+		logger.Info("Credentials not read from a Secret, not starting the controller", "controller", credentialsControllerName)
+
+		return nil
+	}
+
+	return (&CredentialsReconciler{
+		CredentialManager: options.CredentialManager,
+		Secret:            *options.CredentialManager.Secret(),
+		Log:               ctrl.Log.WithName("controllers").WithName(credentialsControllerName),
+		Recorder:          mgr.GetEventRecorderFor(credentialsControllerName),
 	}).SetupWithManager(mgr)
 }
+
+// registerCredentialsRotation rebuilds the Datadog API Client, and passes it to update, when the operator credentials
+// are rotated. The replaced client, starting with ddClient, is released.
+func registerCredentialsRotation(options SetupOptions, logger logr.Logger, ddClient datadogclient.DatadogClient, update func(datadogclient.DatadogClient)) {
+	if options.CredentialManager == nil {
+		return
+	}
+
+	var mutex sync.Mutex
+	current := ddClient
+	options.CredentialManager.RegisterCallback(func(creds config.Creds) {
+		newClient, err := datadogclient.InitDatadogClient(creds)
+		if err != nil {
+			logger.Error(err, "Unable to create Datadog API Client with the rotated credentials")
+			return
+		}
+		update(newClient)
+
+		mutex.Lock()
+		defer mutex.Unlock()
+		current.Release()
+		current = newClient
+	})
+}
@@ -31,6 +31,7 @@ import (
 	datadoghqv1alpha1 "github.com/DataDog/datadog-operator/apis/datadoghq/v1alpha1"
 	datadoghqv2alpha1 "github.com/DataDog/datadog-operator/apis/datadoghq/v2alpha1"
 	"github.com/DataDog/datadog-operator/controllers"
+	"github.com/DataDog/datadog-operator/controllers/datadogmonitor"
 	"github.com/DataDog/datadog-operator/pkg/config"
 	"github.com/DataDog/datadog-operator/pkg/controller/debug"
 	"github.com/DataDog/datadog-operator/pkg/secrets"
@@ -86,12 +87,16 @@ func main() {
 	flag.DurationVar(&leaderElectionLeaseDuration, "leader-election-lease-duration", 60*time.Second, "Define LeaseDuration as well as RenewDeadline (leaseDuration / 2) and RetryPeriod (leaseDuration / 4)")
 
 	// Custom flags
-	var printVersion, pprofActive, supportExtendedDaemonset, supportCilium, datadogAgentEnabled, datadogMonitorEnabled, operatorMetricsEnabled, webhookEnabled, v2APIEnabled bool
-	var logEncoder, secretBackendCommand string
+	var printVersion, pprofActive, supportExtendedDaemonset, supportCilium, datadogAgentEnabled, datadogMonitorEnabled, datadogDowntimeEnabled, datadogSLOEnabled, operatorMetricsEnabled, webhookEnabled, admissionWebhookEnabled, v2APIEnabled, dependenciesDiffLogEnabled, datadogAgentFeatureEnabled, datadogAgentTemplateEnabled bool
+	var datadogMonitorRemoteValidation, datadogMonitorValidationFailOpen, datadogMonitorOrphanGC, datadogAgentDefaultingWebhook bool
+	var datadogMonitorValidationTimeout, datadogMonitorOrphanGCGracePeriod time.Duration
+	var logEncoder, secretBackendCommand, secretsDirectory, credentialsSecret, datadogMonitorDeletionPolicy, datadogMonitorClusterName, datadogMonitorWebhookReceiverAddr string
 	var secretBackendArgs stringSlice
 	flag.StringVar(&logEncoder, "logEncoder", "json", "log encoding ('json' or 'console')")
 	flag.StringVar(&secretBackendCommand, "secretBackendCommand", "", "Secret backend command")
 	flag.Var(&secretBackendArgs, "secretBackendArgs", "Space separated arguments of the secret backend command")
+	flag.StringVar(&credentialsSecret, "credentialsSecret", "", "Secret <namespace>/<name> containing the operator API and APP keys in the api_key and app_key keys, watched for rotation. The DD_API_KEY and DD_APP_KEY env vars are used if empty")
+	flag.StringVar(&secretsDirectory, "secretsDirectory", "", "Directory containing one file per secret, resolved with the ENC[<file name>] handle")
 	logLevel := zap.LevelFlag("loglevel", zapcore.InfoLevel, "Set log level")
 	flag.BoolVar(&printVersion, "version", false, "Print version and exit")
 	flag.BoolVar(&pprofActive, "pprof", false, "Enable pprof endpoint")
@@ -99,9 +104,24 @@ func main() {
 	flag.BoolVar(&supportCilium, "supportCilium", false, "Support usage of Cilium network policies.")
 	flag.BoolVar(&datadogAgentEnabled, "datadogAgentEnabled", true, "Enable the DatadogAgent controller")
 	flag.BoolVar(&datadogMonitorEnabled, "datadogMonitorEnabled", false, "Enable the DatadogMonitor controller")
+	flag.BoolVar(&datadogDowntimeEnabled, "datadogDowntimeEnabled", false, "Enable the DatadogDowntime controller")
+	flag.StringVar(&datadogMonitorDeletionPolicy, "datadogMonitorDeletionPolicy", string(datadoghqv1alpha1.DatadogMonitorDeletionPolicyDelete), "Default deletion policy of the DatadogMonitors ('Delete' or 'Retain'), overridden by their spec.deletionPolicy")
+	flag.StringVar(&datadogMonitorClusterName, "datadogMonitorClusterName", "", "Name of the cluster, added to the monitors managed by the operator in the kube_cluster_name tag")
+	flag.BoolVar(&datadogMonitorOrphanGC, "datadogMonitorOrphanGC", false, "Delete the monitors tagged with the cluster name that have no DatadogMonitor, requires datadogMonitorClusterName")
+	flag.DurationVar(&datadogMonitorOrphanGCGracePeriod, "datadogMonitorOrphanGCGracePeriod", datadogmonitor.DefaultOrphanGCGracePeriod, "Time an orphan monitor is reported before being deleted")
+	flag.StringVar(&datadogMonitorWebhookReceiverAddr, "datadogMonitorWebhookReceiverAddr", "", "Address of the endpoint receiving the Datadog webhook notifications of the monitors, authenticated with the DD_MONITOR_WEBHOOK_TOKEN bearer token (disabled if empty)")
+	flag.BoolVar(&datadogSLOEnabled, "datadogSLOEnabled", false, "Enable the DatadogSLO controller")
 	flag.BoolVar(&operatorMetricsEnabled, "operatorMetricsEnabled", true, "Enable sending operator metrics to Datadog")
 	flag.BoolVar(&v2APIEnabled, "v2APIEnabled", true, "Enable the v2 api")
 	flag.BoolVar(&webhookEnabled, "webhookEnabled", true, "Enable CRD conversion webhook.")
+	flag.BoolVar(&admissionWebhookEnabled, "admissionWebhookEnabled", false, "Enable the DatadogAgent and DatadogMonitor validating webhooks, requires the CA injection in their webhook configurations (config/default/webhookcainjection_patch.yaml)")
+	flag.BoolVar(&datadogAgentDefaultingWebhook, "datadogAgentDefaultingWebhook", false, "Set the default values of the v2alpha1 DatadogAgents in their spec with a defaulting webhook, requires admissionWebhookEnabled")
+	flag.BoolVar(&datadogMonitorRemoteValidation, "datadogMonitorRemoteValidation", false, "Validate the DatadogMonitors with the Datadog monitor validate endpoint in the validating webhook")
+	flag.DurationVar(&datadogMonitorValidationTimeout, "datadogMonitorValidationTimeout", datadogmonitor.DefaultValidationTimeout, "Timeout of the Datadog monitor validate endpoint calls of the validating webhook")
+	flag.BoolVar(&datadogMonitorValidationFailOpen, "datadogMonitorValidationFailOpen", true, "Accept the DatadogMonitors when the Datadog monitor validate endpoint can't be reached")
+	flag.BoolVar(&dependenciesDiffLogEnabled, "dependenciesDiffLogEnabled", false, "Log the full diff of each DatadogAgent dependency updated by the operator.")
+	flag.BoolVar(&datadogAgentFeatureEnabled, "datadogAgentFeatureEnabled", false, "Apply the DatadogAgentFeatures to the v2alpha1 DatadogAgents they select, requires v2APIEnabled")
+	flag.BoolVar(&datadogAgentTemplateEnabled, "datadogAgentTemplateEnabled", false, "Merge the DatadogAgentTemplates under the v2alpha1 DatadogAgents referencing them in spec.baseRef, requires v2APIEnabled")
 	maximumGoroutines := flag.Int("maximumGoroutines", defaultMaximumGoroutines, "Override health check threshold for maximum number of goroutines.")
 
 	// Parsing flags
@@ -123,6 +143,13 @@ func main() {
 	// Dispatch CLI flags to each package
 	secrets.SetSecretBackendCommand(secretBackendCommand)
 	secrets.SetSecretBackendArgs(secretBackendArgs)
+	secrets.SetSecretsDirectory(secretsDirectory)
+
+	monitorDeletionPolicy := datadoghqv1alpha1.DatadogMonitorDeletionPolicy(datadogMonitorDeletionPolicy)
+	if monitorDeletionPolicy != datadoghqv1alpha1.DatadogMonitorDeletionPolicyDelete && monitorDeletionPolicy != datadoghqv1alpha1.DatadogMonitorDeletionPolicyRetain {
+		setupLog.Error(fmt.Errorf("unknown deletion policy: %s", datadogMonitorDeletionPolicy), "Invalid datadogMonitorDeletionPolicy flag")
+		os.Exit(1)
+	}
 
 	renewDeadline := leaderElectionLeaseDuration / 2
 	retryPeriod := leaderElectionLeaseDuration / 4
@@ -144,24 +171,58 @@ func main() {
 		os.Exit(1)
 	}
 
+	// Resolve ENC[k8s_secret@<namespace>/<name>/<key>] handles from Kubernetes Secrets
+	secrets.SetKubernetesReader(mgr.GetAPIReader())
+
 	// Custom setup
 	customSetupHealthChecks(setupLog, mgr, maximumGoroutines)
 	customSetupEndpoints(pprofActive, mgr)
 
-	creds, err := config.NewCredentialManager().GetCredentials()
-	if err != nil && datadogMonitorEnabled {
-		setupLog.Error(err, "Unable to get credentials for DatadogMonitor")
+	credsManager := config.NewCredentialManager()
+	if credentialsSecret != "" {
+		secretNsName, parseErr := config.ParseCredentialsSecret(credentialsSecret)
+		if parseErr != nil {
+			setupLog.Error(parseErr, "Invalid credentialsSecret flag")
+			os.Exit(1)
+		}
+		credsManager = config.NewCredentialManagerFromSecret(mgr.GetAPIReader(), secretNsName)
+	}
+
+	creds, err := credsManager.GetCredentials()
+	if err != nil && (datadogMonitorEnabled || datadogDowntimeEnabled || datadogSLOEnabled) {
+		setupLog.Error(err, "Unable to get credentials for DatadogMonitor, DatadogDowntime and DatadogSLO")
 		os.Exit(1)
 	}
 
 	options := controllers.SetupOptions{
-		SupportExtendedDaemonset: supportExtendedDaemonset,
-		SupportCilium:            supportCilium,
-		Creds:                    creds,
-		DatadogAgentEnabled:      datadogAgentEnabled,
-		DatadogMonitorEnabled:    datadogMonitorEnabled,
-		OperatorMetricsEnabled:   operatorMetricsEnabled,
-		V2APIEnabled:             v2APIEnabled,
+		SupportExtendedDaemonset:    supportExtendedDaemonset,
+		SupportCilium:               supportCilium,
+		Creds:                       creds,
+		CredentialManager:           credsManager,
+		DatadogAgentEnabled:         datadogAgentEnabled,
+		DatadogMonitorEnabled:       datadogMonitorEnabled,
+		DatadogDowntimeEnabled:      datadogDowntimeEnabled,
+		DatadogSLOEnabled:           datadogSLOEnabled,
+		OperatorMetricsEnabled:      operatorMetricsEnabled,
+		V2APIEnabled:                v2APIEnabled,
+		DependenciesDiffLogEnabled:  dependenciesDiffLogEnabled,
+		DatadogAgentFeatureEnabled:  datadogAgentFeatureEnabled,
+		DatadogAgentTemplateEnabled: datadogAgentTemplateEnabled,
+		AdmissionWebhookEnabled:     admissionWebhookEnabled,
+		AgentDefaultingWebhook:      datadogAgentDefaultingWebhook,
+		MonitorOptions: datadogmonitor.ReconcilerOptions{
+			DefaultDeletionPolicy:  monitorDeletionPolicy,
+			ClusterName:            datadogMonitorClusterName,
+			OrphanGCEnabled:        datadogMonitorOrphanGC,
+			OrphanGCGracePeriod:    datadogMonitorOrphanGCGracePeriod,
+			WebhookReceiverAddress: datadogMonitorWebhookReceiverAddr,
+			WebhookReceiverToken:   os.Getenv(config.DDMonitorWebhookTokenEnvVar),
+		},
+		MonitorValidatorOptions: datadogmonitor.ValidatorOptions{
+			RemoteValidation: datadogMonitorRemoteValidation,
+			Timeout:          datadogMonitorValidationTimeout,
+			FailOpen:         datadogMonitorValidationFailOpen,
+		},
 	}
 
 	if err = controllers.SetupControllers(setupLog, mgr, options); err != nil {
@@ -28,6 +28,9 @@ const (
 	// DDURLEnvVar is the constant for the env variable DD_URL which is the
 	// host of the Datadog intake server to send data to.
 	DDURLEnvVar = "DD_URL"
+	// DDMonitorWebhookTokenEnvVar is the constant for the env variable DD_MONITOR_WEBHOOK_TOKEN which is the
+	// bearer token authenticating the Datadog webhook notifications received by the operator.
+	DDMonitorWebhookTokenEnvVar = "DD_MONITOR_WEBHOOK_TOKEN"
 )
 
 // GetWatchNamespaces returns the Namespaces the operator should be watching for changes.
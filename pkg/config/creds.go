@@ -6,35 +6,50 @@
 package config
 
 import (
+	"context"
 	"errors"
+	"fmt"
 	"os"
+	"strings"
 	"sync"
 	"time"
 
+	apicommon "github.com/DataDog/datadog-operator/apis/datadoghq/common"
 	"github.com/DataDog/datadog-operator/pkg/secrets"
 
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/wait"
 	"k8s.io/client-go/util/retry"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
+const defaultCredentialsSecretTimeout = 5 * time.Second
+
 // Creds holds the api and app keys.
 type Creds struct {
 	APIKey string
 	AppKey string
 }
 
+// CredentialsChangeCallback is called with the new credentials when they are rotated.
+type CredentialsChangeCallback func(Creds)
+
 // CredentialManager provides the credentials from the operator configuration.
 type CredentialManager struct {
 	secretBackend    secrets.Decryptor
+	secretReader     client.Reader
+	secret           *types.NamespacedName
 	creds            Creds
 	credsMutex       sync.Mutex
 	decryptorBackoff wait.Backoff
+	callbacks        []CredentialsChangeCallback
 }
 
 // NewCredentialManager returns a CredentialManager.
 func NewCredentialManager() *CredentialManager {
 	return &CredentialManager{
-		secretBackend: secrets.NewSecretBackend(),
+		secretBackend: secrets.NewDecryptor(),
 		creds:         Creds{},
 		decryptorBackoff: wait.Backoff{
 			Steps:    5,
@@ -45,6 +60,38 @@ func NewCredentialManager() *CredentialManager {
 	}
 }
 
+// NewCredentialManagerFromSecret returns a CredentialManager reading the credentials
+// from the `api_key` and `app_key` keys of a Secret instead of the environment.
+func NewCredentialManagerFromSecret(reader client.Reader, secret types.NamespacedName) *CredentialManager {
+	cm := NewCredentialManager()
+	cm.secretReader = reader
+	cm.secret = &secret
+
+	return cm
+}
+
+// ParseCredentialsSecret parses a `<namespace>/<name>` Secret reference.
+func ParseCredentialsSecret(value string) (types.NamespacedName, error) {
+	parts := strings.Split(value, "/")
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return types.NamespacedName{}, fmt.Errorf("wrong format, want <namespace>/<name>, got: %s", value)
+	}
+
+	return types.NamespacedName{Namespace: parts[0], Name: parts[1]}, nil
+}
+
+// Secret returns the Secret containing the credentials, nil if they are read from the environment.
+func (cm *CredentialManager) Secret() *types.NamespacedName {
+	return cm.secret
+}
+
+// RegisterCallback registers a function called with the new credentials each time they are rotated.
+func (cm *CredentialManager) RegisterCallback(callback CredentialsChangeCallback) {
+	cm.credsMutex.Lock()
+	defer cm.credsMutex.Unlock()
+	cm.callbacks = append(cm.callbacks, callback)
+}
+
 // GetCredentials returns the API and APP keys respectively from the operator configurations.
 // This function tries to decrypt the secrets using the secret backend if needed.
 // It returns an error if the creds aren't configured or if the secret backend fails to decrypt.
@@ -53,8 +100,46 @@ func (cm *CredentialManager) GetCredentials() (Creds, error) {
 		return creds, nil
 	}
 
-	apiKey := os.Getenv(DDAPIKeyEnvVar)
-	appKey := os.Getenv(DDAppKeyEnvVar)
+	creds, err := cm.readCredentials()
+	if err != nil {
+		return Creds{}, err
+	}
+	cm.cacheCreds(creds)
+
+	return creds, nil
+}
+
+// Refresh reads the credentials again, bypassing the cache.
+// The registered callbacks are called if the credentials changed.
+// It returns whether the credentials changed.
+func (cm *CredentialManager) Refresh() (bool, error) {
+	creds, err := cm.readCredentials()
+	if err != nil {
+		return false, err
+	}
+
+	cm.credsMutex.Lock()
+	changed := cm.creds != creds
+	cm.creds = creds
+	callbacks := make([]CredentialsChangeCallback, len(cm.callbacks))
+	copy(callbacks, cm.callbacks)
+	cm.credsMutex.Unlock()
+
+	if changed {
+		for _, callback := range callbacks {
+			callback(creds)
+		}
+	}
+
+	return changed, nil
+}
+
+// readCredentials reads and decrypts the credentials from the environment or the Secret.
+func (cm *CredentialManager) readCredentials() (Creds, error) {
+	apiKey, appKey, err := cm.readKeys()
+	if err != nil {
+		return Creds{}, err
+	}
 
 	if apiKey == "" || appKey == "" {
 		return Creds{}, errors.New("empty API key and/or App key")
@@ -89,10 +174,24 @@ func (cm *CredentialManager) GetCredentials() (Creds, error) {
 		}
 	}
 
-	creds := Creds{APIKey: apiKey, AppKey: appKey}
-	cm.cacheCreds(creds)
+	return Creds{APIKey: apiKey, AppKey: appKey}, nil
+}
 
-	return creds, nil
+// readKeys returns the API and APP keys, possibly encrypted.
+func (cm *CredentialManager) readKeys() (string, string, error) {
+	if cm.secret == nil {
+		return os.Getenv(DDAPIKeyEnvVar), os.Getenv(DDAppKeyEnvVar), nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), defaultCredentialsSecretTimeout)
+	defer cancel()
+
+	secret := &corev1.Secret{}
+	if err := cm.secretReader.Get(ctx, *cm.secret, secret); err != nil {
+		return "", "", fmt.Errorf("unable to get the credentials secret %s: %w", cm.secret, err)
+	}
+
+	return string(secret.Data[apicommon.DefaultAPIKeyKey]), string(secret.Data[apicommon.DefaultAPPKeyKey]), nil
 }
 
 func (cm *CredentialManager) cacheCreds(creds Creds) {
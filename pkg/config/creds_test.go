@@ -6,12 +6,17 @@
 package config
 
 import (
+	"context"
 	"os"
 	"testing"
 
 	"github.com/DataDog/datadog-operator/pkg/secrets"
 
 	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 )
 
 func Test_getCredentials(t *testing.T) {
@@ -213,3 +218,98 @@ func Test_getCredentials(t *testing.T) {
 		})
 	}
 }
+
+func Test_getCredentialsFromSecret(t *testing.T) {
+	secretNsName := types.NamespacedName{Namespace: "datadog", Name: "operator-creds"}
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Namespace: secretNsName.Namespace, Name: secretNsName.Name},
+		Data: map[string][]byte{
+			"api_key": []byte("foo"),
+			"app_key": []byte("ENC[AppKey]"),
+		},
+	}
+	k8sClient := fake.NewClientBuilder().WithObjects(secret).Build()
+
+	credsManager := NewCredentialManagerFromSecret(k8sClient, secretNsName)
+	decryptor := secrets.NewDummyDecryptor(0)
+	decryptor.On("Decrypt", []string{"ENC[AppKey]"})
+	credsManager.secretBackend = decryptor
+
+	var rotated []Creds
+	credsManager.RegisterCallback(func(creds Creds) { rotated = append(rotated, creds) })
+
+	got, err := credsManager.GetCredentials()
+	assert.NoError(t, err)
+	assert.Equal(t, Creds{APIKey: "foo", AppKey: "DEC[ENC[AppKey]]"}, got)
+	assert.Equal(t, &secretNsName, credsManager.Secret())
+
+	// Refresh without change
+	changed, err := credsManager.Refresh()
+	assert.NoError(t, err)
+	assert.False(t, changed)
+	assert.Empty(t, rotated)
+
+	// Rotate the API key
+	secret.Data["api_key"] = []byte("new-foo")
+	assert.NoError(t, k8sClient.Update(context.TODO(), secret))
+	changed, err = credsManager.Refresh()
+	assert.NoError(t, err)
+	assert.True(t, changed)
+	assert.Equal(t, []Creds{{APIKey: "new-foo", AppKey: "DEC[ENC[AppKey]]"}}, rotated)
+	got, err = credsManager.GetCredentials()
+	assert.NoError(t, err)
+	assert.Equal(t, Creds{APIKey: "new-foo", AppKey: "DEC[ENC[AppKey]]"}, got)
+
+	// The current credentials are kept if the new ones are invalid
+	delete(secret.Data, "app_key")
+	assert.NoError(t, k8sClient.Update(context.TODO(), secret))
+	changed, err = credsManager.Refresh()
+	assert.Error(t, err)
+	assert.False(t, changed)
+	got, err = credsManager.GetCredentials()
+	assert.NoError(t, err)
+	assert.Equal(t, Creds{APIKey: "new-foo", AppKey: "DEC[ENC[AppKey]]"}, got)
+
+	// The current credentials are kept if the Secret is deleted
+	assert.NoError(t, k8sClient.Delete(context.TODO(), secret))
+	_, err = credsManager.Refresh()
+	assert.Error(t, err)
+	assert.Len(t, rotated, 1)
+}
+
+func TestParseCredentialsSecret(t *testing.T) {
+	tests := []struct {
+		name    string
+		value   string
+		want    types.NamespacedName
+		wantErr bool
+	}{
+		{
+			name:  "valid",
+			value: "datadog/operator-creds",
+			want:  types.NamespacedName{Namespace: "datadog", Name: "operator-creds"},
+		},
+		{
+			name:    "missing namespace",
+			value:   "operator-creds",
+			wantErr: true,
+		},
+		{
+			name:    "empty name",
+			value:   "datadog/",
+			wantErr: true,
+		},
+		{
+			name:    "too many parts",
+			value:   "datadog/operator-creds/api_key",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseCredentialsSecret(tt.value)
+			assert.Equal(t, tt.wantErr, err != nil)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
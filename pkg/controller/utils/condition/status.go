@@ -0,0 +1,57 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package condition
+
+import (
+	"fmt"
+
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// ConditionTypeActive means the resource is synced with Datadog
+	ConditionTypeActive = "Active"
+	// ConditionTypeError means the resource has an error
+	ConditionTypeError = "Error"
+
+	syncedReason    = "Synced"
+	syncErrorReason = "SyncError"
+)
+
+// SetErrorActiveStatusConditions sets the Error and Active conditions of a resource using metav1.Conditions to True or False
+func SetErrorActiveStatusConditions(conditions *[]metav1.Condition, generation int64, kind string, err error) {
+	if err != nil {
+		apimeta.SetStatusCondition(conditions, metav1.Condition{
+			Type:               ConditionTypeError,
+			Status:             metav1.ConditionTrue,
+			ObservedGeneration: generation,
+			Reason:             syncErrorReason,
+			Message:            fmt.Sprintf("%v", err),
+		})
+		apimeta.SetStatusCondition(conditions, metav1.Condition{
+			Type:               ConditionTypeActive,
+			Status:             metav1.ConditionFalse,
+			ObservedGeneration: generation,
+			Reason:             syncErrorReason,
+			Message:            fmt.Sprintf("%s error", kind),
+		})
+	} else {
+		apimeta.SetStatusCondition(conditions, metav1.Condition{
+			Type:               ConditionTypeError,
+			Status:             metav1.ConditionFalse,
+			ObservedGeneration: generation,
+			Reason:             syncedReason,
+		})
+		apimeta.SetStatusCondition(conditions, metav1.Condition{
+			Type:               ConditionTypeActive,
+			Status:             metav1.ConditionTrue,
+			ObservedGeneration: generation,
+			Reason:             syncedReason,
+			Message:            fmt.Sprintf("%s ready", kind),
+		})
+	}
+}
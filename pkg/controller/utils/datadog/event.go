@@ -25,6 +25,8 @@ const (
 	UpdateEvent EventType = "Update"
 	// DeletionEvent should be used for resource deletion events
 	DeletionEvent EventType = "Delete"
+	// AdoptionEvent should be used for the adoption of existing resources
+	AdoptionEvent EventType = "Adopt"
 )
 
 // crDetected returns the detection event of a CR
@@ -11,6 +11,7 @@ import (
 
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
+	"github.com/DataDog/datadog-operator/pkg/config"
 	"github.com/DataDog/datadog-operator/pkg/kubernetes"
 	"github.com/DataDog/datadog-operator/pkg/secrets"
 )
@@ -32,19 +33,26 @@ type ForwardersManager struct {
 	v2Enabled    bool
 	forwarders   map[string]*metricsForwarder
 	decryptor    secrets.Decryptor
+	credsManager *config.CredentialManager
 	wg           sync.WaitGroup
 	sync.Mutex
 }
 
 // NewForwardersManager builds a new ForwardersManager
 // ForwardersManager implements the controller-runtime Runnable interface
-func NewForwardersManager(k8sClient client.Client, v2Enabled bool, platformInfo *kubernetes.PlatformInfo) *ForwardersManager {
+// credsManager provides the operator credentials, a new CredentialManager is used if nil
+func NewForwardersManager(k8sClient client.Client, v2Enabled bool, platformInfo *kubernetes.PlatformInfo, credsManager *config.CredentialManager) *ForwardersManager {
+	if credsManager == nil {
+		credsManager = config.NewCredentialManager()
+	}
+
 	return &ForwardersManager{
 		k8sClient:    k8sClient,
 		platformInfo: platformInfo,
 		v2Enabled:    v2Enabled,
 		forwarders:   make(map[string]*metricsForwarder),
-		decryptor:    secrets.NewSecretBackend(),
+		decryptor:    secrets.NewDecryptor(),
+		credsManager: credsManager,
 		wg:           sync.WaitGroup{},
 	}
 }
@@ -64,7 +72,7 @@ func (f *ForwardersManager) Register(obj client.Object) {
 	id := getObjID(obj) // nolint: ifshort
 	if _, found := f.forwarders[id]; !found {
 		log.Info("New Datadog metrics forwarder registred", "ID", id)
-		f.forwarders[id] = newMetricsForwarder(f.k8sClient, f.decryptor, obj, obj.GetObjectKind(), f.v2Enabled, f.platformInfo)
+		f.forwarders[id] = newMetricsForwarder(f.k8sClient, f.decryptor, f.credsManager, obj, obj.GetObjectKind(), f.v2Enabled, f.platformInfo)
 		f.wg.Add(1)
 		go f.forwarders[id].start(&f.wg)
 	}
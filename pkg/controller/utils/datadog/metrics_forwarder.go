@@ -75,9 +75,9 @@ type delegatedAPI interface {
 	delegatedValidateCreds(string, string) (*api.Client, error)
 }
 
-// hashKeys is used to detect if credentials have changed
-// hashKeys is NOT a security function
-func hashKeys(apiKey, appKey string) uint64 {
+// HashKeys is used to detect if credentials have changed, or to index objects by credentials
+// HashKeys is NOT a security function
+func HashKeys(apiKey, appKey string) uint64 {
 	h := fnv.New64()
 	_, _ = h.Write([]byte(apiKey))
 	_, _ = h.Write([]byte(appKey))
@@ -125,7 +125,7 @@ type metricsForwarder struct {
 }
 
 // newMetricsForwarder returs a new Datadog MetricsForwarder instance
-func newMetricsForwarder(k8sClient client.Client, decryptor secrets.Decryptor, obj MonitoredObject, kind schema.ObjectKind, v2Enabled bool, platforminfo *kubernetes.PlatformInfo) *metricsForwarder {
+func newMetricsForwarder(k8sClient client.Client, decryptor secrets.Decryptor, credsManager *config.CredentialManager, obj MonitoredObject, kind schema.ObjectKind, v2Enabled bool, platforminfo *kubernetes.PlatformInfo) *metricsForwarder {
 	return &metricsForwarder{
 		id:                  getObjID(obj),
 		monitoredObjectKind: kind.GroupVersionKind().Kind,
@@ -144,7 +144,7 @@ func newMetricsForwarder(k8sClient client.Client, decryptor secrets.Decryptor, o
 		creds:               sync.Map{},
 		baseURL:             defaultbaseURL,
 		logger:              log.WithValues("CustomResource.Namespace", obj.GetNamespace(), "CustomResource.Name", obj.GetName()),
-		credsManager:        config.NewCredentialManager(),
+		credsManager:        credsManager,
 	}
 }
 
@@ -436,13 +436,13 @@ func (mf *metricsForwarder) initAPIClient(apiKey, appKey string) error {
 		return err
 	}
 	mf.datadogClient = datadogClient
-	mf.keysHash = hashKeys(apiKey, appKey)
+	mf.keysHash = HashKeys(apiKey, appKey)
 	return nil
 }
 
 // updateCredsIfNeeded used to update Datadog apiKey and appKey if they change
 func (mf *metricsForwarder) updateCredsIfNeeded(apiKey, appKey string) error {
-	if mf.keysHash != hashKeys(apiKey, appKey) {
+	if mf.keysHash != HashKeys(apiKey, appKey) {
 		return mf.initAPIClient(apiKey, appKey)
 	}
 	return nil
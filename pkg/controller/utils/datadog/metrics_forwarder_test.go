@@ -327,14 +327,14 @@ func TestMetricsForwarder_updateCredsIfNeeded(t *testing.T) {
 				f := &fakeMetricsForwarder{}
 				return &metricsForwarder{
 					delegator: f,
-					keysHash:  hashKeys("sameApiKey", "sameAppKey"),
+					keysHash:  HashKeys("sameApiKey", "sameAppKey"),
 				}, f
 			},
 			apiKey:  "sameApiKey",
 			appKey:  "sameAppKey",
 			wantErr: false,
 			wantFunc: func(m *metricsForwarder, f *fakeMetricsForwarder) error {
-				if m.keysHash != hashKeys("sameApiKey", "sameAppKey") {
+				if m.keysHash != HashKeys("sameApiKey", "sameAppKey") {
 					return errors.New("Wrong hash update")
 				}
 				if !f.AssertNumberOfCalls(t, "delegatedValidateCreds", 0) {
@@ -350,14 +350,14 @@ func TestMetricsForwarder_updateCredsIfNeeded(t *testing.T) {
 				f.On("delegatedValidateCreds", "newApiKey", "sameAppKey")
 				return &metricsForwarder{
 					delegator: f,
-					keysHash:  hashKeys("oldApiKey", "sameAppKey"),
+					keysHash:  HashKeys("oldApiKey", "sameAppKey"),
 				}, f
 			},
 			apiKey:  "newApiKey",
 			appKey:  "sameAppKey",
 			wantErr: false,
 			wantFunc: func(m *metricsForwarder, f *fakeMetricsForwarder) error {
-				if m.keysHash != hashKeys("newApiKey", "sameAppKey") {
+				if m.keysHash != HashKeys("newApiKey", "sameAppKey") {
 					return errors.New("Wrong hash update")
 				}
 				if !f.AssertNumberOfCalls(t, "delegatedValidateCreds", 1) {
@@ -373,14 +373,14 @@ func TestMetricsForwarder_updateCredsIfNeeded(t *testing.T) {
 				f.On("delegatedValidateCreds", "sameApiKey", "newAppKey")
 				return &metricsForwarder{
 					delegator: f,
-					keysHash:  hashKeys("sameApiKey", "oldAppKey"),
+					keysHash:  HashKeys("sameApiKey", "oldAppKey"),
 				}, f
 			},
 			apiKey:  "sameApiKey",
 			appKey:  "newAppKey",
 			wantErr: false,
 			wantFunc: func(m *metricsForwarder, f *fakeMetricsForwarder) error {
-				if m.keysHash != hashKeys("sameApiKey", "newAppKey") {
+				if m.keysHash != HashKeys("sameApiKey", "newAppKey") {
 					return errors.New("Wrong hash update")
 				}
 				if !f.AssertNumberOfCalls(t, "delegatedValidateCreds", 1) {
@@ -396,14 +396,14 @@ func TestMetricsForwarder_updateCredsIfNeeded(t *testing.T) {
 				f.On("delegatedValidateCreds", "invalidApiKey", "invalidAppKey")
 				return &metricsForwarder{
 					delegator: f,
-					keysHash:  hashKeys("oldApiKey", "oldAppKey"),
+					keysHash:  HashKeys("oldApiKey", "oldAppKey"),
 				}, f
 			},
 			apiKey:  "invalidApiKey",
 			appKey:  "invalidAppKey",
 			wantErr: true,
 			wantFunc: func(m *metricsForwarder, f *fakeMetricsForwarder) error {
-				if m.keysHash != hashKeys("oldApiKey", "oldAppKey") {
+				if m.keysHash != HashKeys("oldApiKey", "oldAppKey") {
 					return errors.New("Wrong hash update")
 				}
 				if !f.AssertNumberOfCalls(t, "delegatedValidateCreds", 1) {
@@ -0,0 +1,154 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package datadogclient
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	apicommon "github.com/DataDog/datadog-operator/apis/datadoghq/common"
+	datadoghqv1alpha1 "github.com/DataDog/datadog-operator/apis/datadoghq/v1alpha1"
+	"github.com/DataDog/datadog-operator/pkg/config"
+	"github.com/DataDog/datadog-operator/pkg/controller/utils/datadog"
+)
+
+const defaultSiteKey = "site"
+
+// clientKey identifies the Datadog API clients by credentials and site
+type clientKey struct {
+	keysHash uint64
+	site     string
+}
+
+// secretRefKey identifies a credentials Secret reference
+type secretRefKey struct {
+	namespace string
+	ref       datadoghqv1alpha1.DatadogCredentialsSecretRef
+}
+
+// ClientCache caches the Datadog API clients built from credentials Secrets
+// so that the resources sharing credentials share a client
+// The zero value is ready to use
+type ClientCache struct {
+	clients map[clientKey]DatadogClient
+	// refs maps the credentials Secret references to the key of their client, so that the client of rotated
+	// credentials is evicted once no reference uses it anymore
+	refs map[secretRefKey]clientKey
+	sync.Mutex
+}
+
+// Get returns the Datadog API client for the given credentials and site, creating it if needed
+func (c *ClientCache) Get(creds config.Creds, site string) (DatadogClient, error) {
+	c.Lock()
+	defer c.Unlock()
+
+	return c.get(creds, site)
+}
+
+func (c *ClientCache) get(creds config.Creds, site string) (DatadogClient, error) {
+	key := newClientKey(creds, site)
+	if ddClient, found := c.clients[key]; found {
+		return ddClient, nil
+	}
+
+	ddClient, err := InitDatadogClientForSite(creds, site)
+	if err != nil {
+		return DatadogClient{}, err
+	}
+
+	if c.clients == nil {
+		c.clients = map[clientKey]DatadogClient{}
+	}
+	c.clients[key] = ddClient
+
+	return ddClient, nil
+}
+
+// GetFromSecret returns the Datadog API client for the credentials and site contained in the referenced Secret
+// The Secret is read with reader, which should not be backed by a cache, e.g. the manager API reader, so that the
+// operator doesn't cache all the Secrets of the cluster
+func (c *ClientCache) GetFromSecret(reader client.Reader, namespace string, ref *datadoghqv1alpha1.DatadogCredentialsSecretRef) (DatadogClient, error) {
+	creds, site, err := GetCredentialsFromSecret(reader, namespace, ref)
+	if err != nil {
+		return DatadogClient{}, err
+	}
+
+	c.Lock()
+	defer c.Unlock()
+
+	ddClient, err := c.get(creds, site)
+	if err != nil {
+		return DatadogClient{}, err
+	}
+
+	if c.refs == nil {
+		c.refs = map[secretRefKey]clientKey{}
+	}
+	refKey := secretRefKey{namespace: namespace, ref: *ref}
+	key := newClientKey(creds, site)
+	oldKey, found := c.refs[refKey]
+	c.refs[refKey] = key
+	if found && oldKey != key {
+		// The credentials of the Secret were rotated
+		c.evict(oldKey)
+	}
+
+	return ddClient, nil
+}
+
+// evict removes the client of key, and releases its rate limiters, if no Secret reference uses it anymore
+func (c *ClientCache) evict(key clientKey) {
+	for _, refClientKey := range c.refs {
+		if refClientKey == key {
+			return
+		}
+	}
+
+	if ddClient, found := c.clients[key]; found {
+		delete(c.clients, key)
+		ddClient.Release()
+	}
+}
+
+func newClientKey(creds config.Creds, site string) clientKey {
+	return clientKey{keysHash: datadog.HashKeys(creds.APIKey, creds.AppKey), site: site}
+}
+
+// GetCredentialsFromSecret returns the credentials and the Datadog site contained in the referenced Secret
+func GetCredentialsFromSecret(reader client.Reader, namespace string, ref *datadoghqv1alpha1.DatadogCredentialsSecretRef) (config.Creds, string, error) {
+	secret := &corev1.Secret{}
+	if err := reader.Get(context.TODO(), types.NamespacedName{Namespace: namespace, Name: ref.Name}, secret); err != nil {
+		return config.Creds{}, "", fmt.Errorf("unable to get the credentials secret %s/%s: %w", namespace, ref.Name, err)
+	}
+
+	apiKeyKey := ref.APIKeyKey
+	if apiKeyKey == "" {
+		apiKeyKey = apicommon.DefaultAPIKeyKey
+	}
+	appKeyKey := ref.APPKeyKey
+	if appKeyKey == "" {
+		appKeyKey = apicommon.DefaultAPPKeyKey
+	}
+	siteKey := ref.SiteKey
+	if siteKey == "" {
+		siteKey = defaultSiteKey
+	}
+
+	creds := config.Creds{
+		APIKey: string(secret.Data[apiKeyKey]),
+		AppKey: string(secret.Data[appKeyKey]),
+	}
+	if creds.APIKey == "" || creds.AppKey == "" {
+		return config.Creds{}, "", fmt.Errorf("credentials secret %s/%s must contain the keys %s and %s", namespace, ref.Name, apiKeyKey, appKeyKey)
+	}
+
+	return creds, string(secret.Data[siteKey]), nil
+}
@@ -0,0 +1,146 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package datadogclient
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	datadoghqv1alpha1 "github.com/DataDog/datadog-operator/apis/datadoghq/v1alpha1"
+	"github.com/DataDog/datadog-operator/pkg/config"
+)
+
+func TestGetCredentialsFromSecret(t *testing.T) {
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "foo", Name: "team-creds"},
+		Data: map[string][]byte{
+			"api_key":     []byte("api"),
+			"app_key":     []byte("app"),
+			"site":        []byte("datadoghq.eu"),
+			"custom-api":  []byte("custom-api"),
+			"custom-app":  []byte("custom-app"),
+			"custom-site": []byte("us3.datadoghq.com"),
+		},
+	}
+
+	tests := []struct {
+		name      string
+		namespace string
+		ref       *datadoghqv1alpha1.DatadogCredentialsSecretRef
+		wantCreds config.Creds
+		wantSite  string
+		wantErr   bool
+	}{
+		{
+			name:      "default keys",
+			namespace: "foo",
+			ref:       &datadoghqv1alpha1.DatadogCredentialsSecretRef{Name: "team-creds"},
+			wantCreds: config.Creds{APIKey: "api", AppKey: "app"},
+			wantSite:  "datadoghq.eu",
+		},
+		{
+			name:      "custom keys",
+			namespace: "foo",
+			ref: &datadoghqv1alpha1.DatadogCredentialsSecretRef{
+				Name:      "team-creds",
+				APIKeyKey: "custom-api",
+				APPKeyKey: "custom-app",
+				SiteKey:   "custom-site",
+			},
+			wantCreds: config.Creds{APIKey: "custom-api", AppKey: "custom-app"},
+			wantSite:  "us3.datadoghq.com",
+		},
+		{
+			name:      "no site",
+			namespace: "foo",
+			ref:       &datadoghqv1alpha1.DatadogCredentialsSecretRef{Name: "team-creds", SiteKey: "not-found"},
+			wantCreds: config.Creds{APIKey: "api", AppKey: "app"},
+		},
+		{
+			name:      "missing key",
+			namespace: "foo",
+			ref:       &datadoghqv1alpha1.DatadogCredentialsSecretRef{Name: "team-creds", APPKeyKey: "not-found"},
+			wantErr:   true,
+		},
+		{
+			name:      "secret in another namespace",
+			namespace: "other",
+			ref:       &datadoghqv1alpha1.DatadogCredentialsSecretRef{Name: "team-creds"},
+			wantErr:   true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			creds, site, err := GetCredentialsFromSecret(fake.NewClientBuilder().WithObjects(secret).Build(), tt.namespace, tt.ref)
+			assert.Equal(t, tt.wantErr, err != nil)
+			assert.Equal(t, tt.wantCreds, creds)
+			assert.Equal(t, tt.wantSite, site)
+		})
+	}
+}
+
+func TestClientCache_Get(t *testing.T) {
+	cache := ClientCache{}
+
+	clientA, err := cache.Get(config.Creds{APIKey: "api", AppKey: "app"}, "")
+	assert.NoError(t, err)
+	clientB, err := cache.Get(config.Creds{APIKey: "api", AppKey: "app"}, "")
+	assert.NoError(t, err)
+	assert.Same(t, clientA.Client, clientB.Client)
+
+	clientC, err := cache.Get(config.Creds{APIKey: "api", AppKey: "app"}, "datadoghq.eu")
+	assert.NoError(t, err)
+	assert.NotSame(t, clientA.Client, clientC.Client)
+
+	clientD, err := cache.Get(config.Creds{APIKey: "other-api", AppKey: "app"}, "")
+	assert.NoError(t, err)
+	assert.NotSame(t, clientA.Client, clientD.Client)
+	assert.Len(t, cache.clients, 3)
+}
+
+func TestClientCache_GetFromSecret_rotation(t *testing.T) {
+	secretA := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "team-a", Name: "creds"},
+		Data:       map[string][]byte{"api_key": []byte("rotated-api"), "app_key": []byte("app")},
+	}
+	secretB := secretA.DeepCopy()
+	secretB.Namespace = "team-b"
+	k8sClient := fake.NewClientBuilder().WithObjects(secretA, secretB).Build()
+	ref := &datadoghqv1alpha1.DatadogCredentialsSecretRef{Name: "creds"}
+	oldKey := newClientKey(config.Creds{APIKey: "rotated-api", AppKey: "app"}, "")
+
+	cache := ClientCache{}
+	clientA, err := cache.GetFromSecret(k8sClient, "team-a", ref)
+	require.NoError(t, err)
+	_, err = cache.GetFromSecret(k8sClient, "team-b", ref)
+	require.NoError(t, err)
+
+	// The client is kept while team-b still uses the old credentials
+	secretA.Data["app_key"] = []byte("new-app")
+	require.NoError(t, k8sClient.Update(context.TODO(), secretA))
+	rotatedA, err := cache.GetFromSecret(k8sClient, "team-a", ref)
+	require.NoError(t, err)
+	assert.NotSame(t, clientA.Client, rotatedA.Client)
+	assert.Contains(t, cache.clients, oldKey)
+
+	// The client of the old credentials is evicted once no Secret uses them
+	secretB.Data["app_key"] = []byte("new-app")
+	require.NoError(t, k8sClient.Update(context.TODO(), secretB))
+	rotatedB, err := cache.GetFromSecret(k8sClient, "team-b", ref)
+	require.NoError(t, err)
+	assert.Same(t, rotatedA.Client, rotatedB.Client)
+	assert.NotContains(t, cache.clients, oldKey)
+	assert.Len(t, cache.clients, 1)
+	sharedRateLimiters.Lock()
+	assert.NotContains(t, sharedRateLimiters.limiters, oldKey)
+	sharedRateLimiters.Unlock()
+}
@@ -9,6 +9,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"net/http"
 	"net/url"
 	"os"
 
@@ -21,10 +22,19 @@ import (
 type DatadogClient struct {
 	Client *datadogapiclientv1.APIClient
 	Auth   context.Context
+
+	// key identifies the rate limiters shared by the client
+	key clientKey
 }
 
 // InitDatadogClient initializes the Datadog API Client and establishes credentials.
 func InitDatadogClient(creds config.Creds) (DatadogClient, error) {
+	return InitDatadogClientForSite(creds, "")
+}
+
+// InitDatadogClientForSite initializes the Datadog API Client for a Datadog site, e.g. datadoghq.eu, and establishes credentials.
+// The operator configuration is used if site is empty.
+func InitDatadogClientForSite(creds config.Creds, site string) (DatadogClient, error) {
 	if creds.APIKey == "" || creds.AppKey == "" {
 		return DatadogClient{}, errors.New("error obtaining API key and/or app key")
 	}
@@ -43,8 +53,19 @@ func InitDatadogClient(creds config.Creds) (DatadogClient, error) {
 		},
 	)
 	configV1 := datadogapiclientv1.NewConfiguration()
+	// The SLO history reports the state of the SLOs managed by the DatadogSLO controller
+	configV1.SetUnstableOperationEnabled("GetSLOHistory", true)
+	// The Datadog API rate limits apply to the whole organization, so the clients sharing credentials share their rate limiters
+	key := newClientKey(creds, site)
+	limiters := getSharedRateLimiters(key)
+	configV1.HTTPClient = &http.Client{Transport: newRateLimitedTransport(http.DefaultTransport, limiters)}
 
-	if apiURL := os.Getenv(config.DDURLEnvVar); apiURL != "" {
+	if site != "" {
+		// The default server (ServerIndex{0}) URL is built from the site.
+		authV1 = context.WithValue(authV1, datadogapiclientv1.ContextServerVariables, map[string]string{
+			"site": site,
+		})
+	} else if apiURL := os.Getenv(config.DDURLEnvVar); apiURL != "" {
 		parsedAPIURL, parseErr := url.Parse(apiURL)
 		if parseErr != nil {
 			return DatadogClient{}, fmt.Errorf(`invalid API Url : %w`, parseErr)
@@ -61,5 +82,39 @@ func InitDatadogClient(creds config.Creds) (DatadogClient, error) {
 	}
 	client := datadogapiclientv1.NewAPIClient(configV1)
 
-	return DatadogClient{Client: client, Auth: authV1}, nil
+	return DatadogClient{Client: client, Auth: authV1, key: key}, nil
+}
+
+// Release releases the rate limiters shared by the client, once it isn't used anymore, e.g. when it is replaced by
+// the client of rotated credentials. It must be called once per initialized client.
+func (c DatadogClient) Release() {
+	if c.Client == nil {
+		return
+	}
+	releaseSharedRateLimiters(c.key)
+}
+
+// TranslateClientError wraps an error returned by the Datadog API Client with msg and the API response body, if any.
+func TranslateClientError(err error, msg string) error {
+	if msg == "" {
+		msg = "an error occurred"
+	}
+
+	var apiErr datadogapiclientv1.GenericOpenAPIError
+	var errURL *url.Error
+	var rateLimitErr *RateLimitError
+	if errors.As(err, &apiErr) {
+		return fmt.Errorf(msg+": %w: %s", err, apiErr.Body())
+	}
+
+	// Keep the rate limit error, so that the reconciles are requeued when the rate limit resets
+	if errors.As(err, &rateLimitErr) {
+		return fmt.Errorf(msg+": %w", rateLimitErr)
+	}
+
+	if errors.As(err, &errURL) {
+		return fmt.Errorf(msg+" (url.Error): %s", errURL)
+	}
+
+	return fmt.Errorf(msg+": %w", err)
 }
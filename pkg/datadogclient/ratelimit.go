@@ -0,0 +1,354 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package datadogclient
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"math/rand"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"golang.org/x/time/rate"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+const (
+	rateLimitLimitHeader     = "X-RateLimit-Limit"
+	rateLimitPeriodHeader    = "X-RateLimit-Period"
+	rateLimitRemainingHeader = "X-RateLimit-Remaining"
+	rateLimitResetHeader     = "X-RateLimit-Reset"
+
+	defaultMaxRetries     = 3
+	defaultRetryBaseDelay = 500 * time.Millisecond
+	// defaultMaxWait is the longest a request waits for the rate limit to reset. Longer waits fail with a
+	// RateLimitError, so that the reconcile is requeued instead of blocking a controller worker.
+	defaultMaxWait = 5 * time.Second
+)
+
+var (
+	throttledRequests = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "datadog_operator_api_throttled_requests_total",
+		Help: "Number of Datadog API requests delayed or rejected by the operator rate limiter",
+	}, []string{"endpoint", "action"})
+	rateLimitedResponses = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "datadog_operator_api_rate_limited_responses_total",
+		Help: "Number of Datadog API responses with the 429 Too Many Requests status",
+	}, []string{"endpoint"})
+	retriedRequests = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "datadog_operator_api_retries_total",
+		Help: "Number of Datadog API requests retried, by response status code",
+	}, []string{"endpoint", "code"})
+	rateLimitRemaining = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "datadog_operator_api_rate_limit_remaining",
+		Help: "Number of Datadog API requests remaining in the current rate limit period",
+	}, []string{"endpoint"})
+)
+
+func init() {
+	metrics.Registry.MustRegister(throttledRequests, rateLimitedResponses, retriedRequests, rateLimitRemaining)
+}
+
+// idPathSegmentRegexp matches the path segments containing resource IDs, e.g. monitor or SLO IDs
+var idPathSegmentRegexp = regexp.MustCompile(`^([0-9]+|[0-9a-f]{32})$`)
+
+// sharedRateLimiters shares the rate limiters of an organization between the Datadog API clients using its credentials,
+// as the Datadog API rate limits apply to the whole organization
+// The rate limiters are counted by the number of clients using them, so that they are dropped with the last client
+var sharedRateLimiters = struct {
+	limiters map[clientKey]*rateLimiters
+	refs     map[clientKey]int
+	sync.Mutex
+}{}
+
+func getSharedRateLimiters(key clientKey) *rateLimiters {
+	sharedRateLimiters.Lock()
+	defer sharedRateLimiters.Unlock()
+
+	if sharedRateLimiters.limiters == nil {
+		sharedRateLimiters.limiters = map[clientKey]*rateLimiters{}
+		sharedRateLimiters.refs = map[clientKey]int{}
+	}
+	limiters, found := sharedRateLimiters.limiters[key]
+	if !found {
+		limiters = &rateLimiters{}
+		sharedRateLimiters.limiters[key] = limiters
+	}
+	sharedRateLimiters.refs[key]++
+
+	return limiters
+}
+
+// releaseSharedRateLimiters drops the rate limiters of key once the last client using them is released
+func releaseSharedRateLimiters(key clientKey) {
+	sharedRateLimiters.Lock()
+	defer sharedRateLimiters.Unlock()
+
+	sharedRateLimiters.refs[key]--
+	if sharedRateLimiters.refs[key] <= 0 {
+		delete(sharedRateLimiters.refs, key)
+		delete(sharedRateLimiters.limiters, key)
+	}
+}
+
+// RateLimitError is returned when a Datadog API request isn't sent, or fails, because the rate limit is exhausted
+type RateLimitError struct {
+	Endpoint string
+	ResetIn  time.Duration
+}
+
+func (e *RateLimitError) Error() string {
+	return fmt.Sprintf("Datadog API rate limit exceeded for %s, resets in %s", e.Endpoint, e.ResetIn)
+}
+
+// GetRateLimitReset returns the time until the Datadog API rate limit resets if err is caused by the rate limit, so that
+// reconciles can be requeued at the reset time
+func GetRateLimitReset(err error) (time.Duration, bool) {
+	var rateLimitErr *RateLimitError
+	if errors.As(err, &rateLimitErr) {
+		return rateLimitErr.ResetIn, true
+	}
+
+	return 0, false
+}
+
+// rateLimiters holds the token bucket limiters of the Datadog API endpoints
+type rateLimiters struct {
+	limiters map[string]*endpointLimiter
+	sync.Mutex
+}
+
+func (r *rateLimiters) get(endpoint string) *endpointLimiter {
+	r.Lock()
+	defer r.Unlock()
+
+	if r.limiters == nil {
+		r.limiters = map[string]*endpointLimiter{}
+	}
+	limiter, found := r.limiters[endpoint]
+	if !found {
+		// The endpoint is not limited until its rate limit headers are known
+		limiter = &endpointLimiter{limiter: rate.NewLimiter(rate.Inf, 1)}
+		r.limiters[endpoint] = limiter
+	}
+
+	return limiter
+}
+
+// endpointLimiter limits the requests to a Datadog API endpoint with a token bucket sized after its rate limit headers
+type endpointLimiter struct {
+	limiter *rate.Limiter
+	// resetAt is the time the rate limit resets, when it is exhausted
+	resetAt time.Time
+	sync.Mutex
+}
+
+// reserve returns how long to wait before sending a request, or a RateLimitError if it is longer than maxWait
+func (l *endpointLimiter) reserve(endpoint string, now time.Time, maxWait time.Duration) (time.Duration, error) {
+	l.Lock()
+	defer l.Unlock()
+
+	var delay time.Duration
+	if now.Before(l.resetAt) {
+		delay = l.resetAt.Sub(now)
+	}
+	reservation := l.limiter.ReserveN(now, 1)
+	if reservationDelay := reservation.DelayFrom(now); reservationDelay > delay {
+		delay = reservationDelay
+	}
+
+	if delay > maxWait {
+		reservation.CancelAt(now)
+		throttledRequests.WithLabelValues(endpoint, "rejected").Inc()
+		return 0, &RateLimitError{Endpoint: endpoint, ResetIn: delay}
+	}
+	if delay > 0 {
+		throttledRequests.WithLabelValues(endpoint, "delayed").Inc()
+	}
+
+	return delay, nil
+}
+
+// update sizes the token bucket after the rate limit headers of a response
+func (l *endpointLimiter) update(endpoint string, header http.Header, now time.Time) {
+	l.Lock()
+	defer l.Unlock()
+
+	limit, limitErr := strconv.Atoi(header.Get(rateLimitLimitHeader))
+	period, periodErr := strconv.Atoi(header.Get(rateLimitPeriodHeader))
+	remaining, err := strconv.Atoi(header.Get(rateLimitRemainingHeader))
+	if err != nil {
+		return
+	}
+	rateLimitRemaining.WithLabelValues(endpoint).Set(float64(remaining))
+
+	if limitErr == nil && periodErr == nil && limit > 0 && period > 0 {
+		newLimit := rate.Limit(float64(limit) / float64(period))
+		if l.limiter.Limit() != newLimit || l.limiter.Burst() != limit {
+			// Resize the bucket, with the tokens remaining in the current period
+			l.limiter = rate.NewLimiter(newLimit, limit)
+			if remaining < limit {
+				l.limiter.ReserveN(now, limit-remaining)
+			}
+		}
+	}
+	if reset := getReset(header); remaining == 0 && reset > 0 {
+		l.resetAt = now.Add(reset)
+	}
+}
+
+// rateLimitedTransport is a http.RoundTripper limiting the requests to the Datadog API after its rate limit headers,
+// and retrying the requests failing with the 429 status code, and the idempotent requests failing with 5xx status codes
+type rateLimitedTransport struct {
+	next           http.RoundTripper
+	limiters       *rateLimiters
+	maxRetries     int
+	retryBaseDelay time.Duration
+	maxWait        time.Duration
+}
+
+func newRateLimitedTransport(next http.RoundTripper, limiters *rateLimiters) *rateLimitedTransport {
+	return &rateLimitedTransport{
+		next:           next,
+		limiters:       limiters,
+		maxRetries:     defaultMaxRetries,
+		retryBaseDelay: defaultRetryBaseDelay,
+		maxWait:        defaultMaxWait,
+	}
+}
+
+// RoundTrip implements http.RoundTripper
+func (t *rateLimitedTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	endpoint := getEndpoint(req)
+	limiter := t.limiters.get(endpoint)
+
+	for attempt := 0; ; attempt++ {
+		delay, err := limiter.reserve(endpoint, time.Now(), t.maxWait)
+		if err != nil {
+			return nil, err
+		}
+		if err = sleep(req.Context(), delay); err != nil {
+			return nil, err
+		}
+
+		if attempt > 0 {
+			if req, err = rewindBody(req); err != nil {
+				return nil, err
+			}
+		}
+
+		resp, err := t.next.RoundTrip(req)
+		if err != nil {
+			return nil, err
+		}
+		limiter.update(endpoint, resp.Header, time.Now())
+
+		if resp.StatusCode != http.StatusTooManyRequests && resp.StatusCode < http.StatusInternalServerError {
+			return resp, nil
+		}
+
+		delay = t.backoff(attempt)
+		if resp.StatusCode == http.StatusTooManyRequests {
+			rateLimitedResponses.WithLabelValues(endpoint).Inc()
+			if reset := getReset(resp.Header); reset > delay {
+				delay = reset
+			}
+			if delay > t.maxWait || attempt >= t.maxRetries {
+				drainBody(resp)
+				return nil, &RateLimitError{Endpoint: endpoint, ResetIn: delay}
+			}
+		} else if attempt >= t.maxRetries || !isIdempotent(req.Method) || (req.Body != nil && req.GetBody == nil) {
+			// A non idempotent request, e.g. a POST creating a monitor, may have been processed before the server error
+			return resp, nil
+		}
+
+		drainBody(resp)
+		retriedRequests.WithLabelValues(endpoint, strconv.Itoa(resp.StatusCode)).Inc()
+		if err = sleep(req.Context(), delay); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// isIdempotent returns true if a request can be sent again without side effect after a server error
+func isIdempotent(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodPut, http.MethodDelete:
+		return true
+	default:
+		return false
+	}
+}
+
+// backoff returns the exponential backoff delay of a retry, with a jitter of +/- 50%
+func (t *rateLimitedTransport) backoff(attempt int) time.Duration {
+	delay := t.retryBaseDelay << attempt
+	return delay/2 + time.Duration(rand.Int63n(int64(delay)+1))
+}
+
+// getEndpoint returns the endpoint of a request, with the resource IDs of its path replaced by a placeholder,
+// e.g. `GET /api/v1/monitor/{id}`
+func getEndpoint(req *http.Request) string {
+	segments := strings.Split(req.URL.Path, "/")
+	for i, segment := range segments {
+		if idPathSegmentRegexp.MatchString(segment) {
+			segments[i] = "{id}"
+		}
+	}
+
+	return fmt.Sprintf("%s %s", req.Method, strings.Join(segments, "/"))
+}
+
+// getReset returns the time until the rate limit resets, read from the response headers
+func getReset(header http.Header) time.Duration {
+	reset, err := strconv.Atoi(header.Get(rateLimitResetHeader))
+	if err != nil || reset < 0 {
+		return 0
+	}
+
+	return time.Duration(reset) * time.Second
+}
+
+func rewindBody(req *http.Request) (*http.Request, error) {
+	if req.Body == nil || req.GetBody == nil {
+		return req, nil
+	}
+	body, err := req.GetBody()
+	if err != nil {
+		return nil, err
+	}
+	newReq := req.Clone(req.Context())
+	newReq.Body = body
+
+	return newReq, nil
+}
+
+func drainBody(resp *http.Response) {
+	_, _ = io.Copy(ioutil.Discard, resp.Body)
+	_ = resp.Body.Close()
+}
+
+func sleep(ctx context.Context, delay time.Duration) error {
+	if delay <= 0 {
+		return nil
+	}
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
@@ -0,0 +1,242 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package datadogclient
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/DataDog/datadog-operator/pkg/config"
+)
+
+func newTestTransport() *rateLimitedTransport {
+	t := newRateLimitedTransport(http.DefaultTransport, &rateLimiters{})
+	t.retryBaseDelay = time.Millisecond
+	return t
+}
+
+func Test_getEndpoint(t *testing.T) {
+	tests := []struct {
+		method string
+		path   string
+		want   string
+	}{
+		{method: http.MethodGet, path: "/api/v1/monitor", want: "GET /api/v1/monitor"},
+		{method: http.MethodPut, path: "/api/v1/monitor/1234", want: "PUT /api/v1/monitor/{id}"},
+		{method: http.MethodGet, path: "/api/v1/slo/0123456789abcdef0123456789abcdef/history", want: "GET /api/v1/slo/{id}/history"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.want, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, "https://api.datadoghq.com"+tt.path, nil)
+			assert.Equal(t, tt.want, getEndpoint(req))
+		})
+	}
+}
+
+func Test_rateLimitedTransport_retry(t *testing.T) {
+	tests := []struct {
+		name         string
+		method       string
+		statusCodes  []int
+		resetHeader  string
+		wantCalls    int
+		wantStatus   int
+		wantErrReset time.Duration
+	}{
+		{
+			name:        "success",
+			statusCodes: []int{http.StatusOK},
+			wantCalls:   1,
+			wantStatus:  http.StatusOK,
+		},
+		{
+			name:        "server errors are retried",
+			method:      http.MethodPut,
+			statusCodes: []int{http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusOK},
+			wantCalls:   3,
+			wantStatus:  http.StatusOK,
+		},
+		{
+			name:        "server errors after the last retry are returned",
+			method:      http.MethodPut,
+			statusCodes: []int{http.StatusInternalServerError},
+			wantCalls:   4,
+			wantStatus:  http.StatusInternalServerError,
+		},
+		{
+			name:        "server errors of non idempotent requests are not retried",
+			method:      http.MethodPost,
+			statusCodes: []int{http.StatusBadGateway, http.StatusOK},
+			wantCalls:   1,
+			wantStatus:  http.StatusBadGateway,
+		},
+		{
+			name:        "client errors are not retried",
+			statusCodes: []int{http.StatusBadRequest},
+			wantCalls:   1,
+			wantStatus:  http.StatusBadRequest,
+		},
+		{
+			name:        "rate limited requests are retried",
+			statusCodes: []int{http.StatusTooManyRequests, http.StatusOK},
+			resetHeader: "0",
+			wantCalls:   2,
+			wantStatus:  http.StatusOK,
+		},
+		{
+			name:         "rate limited requests are not retried after the max wait",
+			statusCodes:  []int{http.StatusTooManyRequests},
+			resetHeader:  "30",
+			wantCalls:    1,
+			wantErrReset: 30 * time.Second,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var calls int
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				// The body is sent again on retries
+				body, _ := ioutil.ReadAll(r.Body)
+				assert.Equal(t, "{}", string(body))
+
+				code := tt.statusCodes[len(tt.statusCodes)-1]
+				if calls < len(tt.statusCodes) {
+					code = tt.statusCodes[calls]
+				}
+				calls++
+				if tt.resetHeader != "" {
+					w.Header().Set(rateLimitResetHeader, tt.resetHeader)
+				}
+				w.WriteHeader(code)
+			}))
+			defer server.Close()
+
+			method := tt.method
+			if method == "" {
+				method = http.MethodPost
+			}
+			req, err := http.NewRequest(method, server.URL+"/api/v1/monitor", bytes.NewBufferString("{}"))
+			require.NoError(t, err)
+			resp, err := (&http.Client{Transport: newTestTransport()}).Do(req)
+
+			assert.Equal(t, tt.wantCalls, calls)
+			if tt.wantErrReset != 0 {
+				reset, rateLimited := GetRateLimitReset(err)
+				assert.True(t, rateLimited)
+				assert.Equal(t, tt.wantErrReset, reset)
+				return
+			}
+			require.NoError(t, err)
+			defer resp.Body.Close()
+			assert.Equal(t, tt.wantStatus, resp.StatusCode)
+		})
+	}
+}
+
+func Test_rateLimitedTransport_exhausted(t *testing.T) {
+	var calls int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.Header().Set(rateLimitLimitHeader, "100")
+		w.Header().Set(rateLimitPeriodHeader, "60")
+		w.Header().Set(rateLimitRemainingHeader, "0")
+		w.Header().Set(rateLimitResetHeader, "42")
+	}))
+	defer server.Close()
+
+	client := &http.Client{Transport: newTestTransport()}
+	resp, err := client.Get(server.URL + "/api/v1/monitor/1")
+	require.NoError(t, err)
+	resp.Body.Close()
+
+	// The rate limit is exhausted until it resets, the other endpoints are not limited
+	_, err = client.Get(server.URL + "/api/v1/monitor/2")
+	reset, rateLimited := GetRateLimitReset(err)
+	assert.True(t, rateLimited)
+	assert.InDelta(t, 42*time.Second, reset, float64(time.Second))
+	assert.Equal(t, 1, calls)
+
+	resp, err = client.Get(server.URL + "/api/v1/slo")
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, 2, calls)
+}
+
+func Test_endpointLimiter_update(t *testing.T) {
+	limiters := &rateLimiters{}
+	limiter := limiters.get("GET /api/v1/monitor")
+	now := time.Now()
+
+	header := http.Header{}
+	header.Set(rateLimitLimitHeader, "10")
+	header.Set(rateLimitPeriodHeader, "10")
+	header.Set(rateLimitRemainingHeader, "5")
+	header.Set(rateLimitResetHeader, "4")
+	limiter.update("GET /api/v1/monitor", header, now)
+
+	// The bucket allows the 5 remaining requests, then 1 request per second
+	for i := 0; i < 5; i++ {
+		delay, err := limiter.reserve("GET /api/v1/monitor", now, time.Second)
+		require.NoError(t, err)
+		assert.Zero(t, delay, fmt.Sprintf("request %d", i))
+	}
+	delay, err := limiter.reserve("GET /api/v1/monitor", now, time.Second)
+	require.NoError(t, err)
+	assert.Equal(t, time.Second, delay)
+
+	_, err = limiter.reserve("GET /api/v1/monitor", now, time.Second)
+	assert.Error(t, err)
+}
+
+func TestInitDatadogClientForSite_sharedRateLimiters(t *testing.T) {
+	creds := config.Creds{APIKey: "api", AppKey: "app"}
+	client1, err := InitDatadogClientForSite(creds, "datadoghq.eu")
+	require.NoError(t, err)
+	client2, err := InitDatadogClientForSite(creds, "datadoghq.eu")
+	require.NoError(t, err)
+	client3, err := InitDatadogClientForSite(creds, "datadoghq.com")
+	require.NoError(t, err)
+
+	limiters := func(c DatadogClient) *rateLimiters {
+		return c.Client.GetConfig().HTTPClient.Transport.(*rateLimitedTransport).limiters
+	}
+	assert.Same(t, limiters(client1), limiters(client2))
+	assert.NotSame(t, limiters(client1), limiters(client3))
+}
+
+func TestDatadogClient_Release(t *testing.T) {
+	creds := config.Creds{APIKey: "released-api", AppKey: "app"}
+	key := newClientKey(creds, "")
+	client1, err := InitDatadogClient(creds)
+	require.NoError(t, err)
+	client2, err := InitDatadogClient(creds)
+	require.NoError(t, err)
+
+	sharedLimiters := func() (*rateLimiters, bool) {
+		sharedRateLimiters.Lock()
+		defer sharedRateLimiters.Unlock()
+		limiters, found := sharedRateLimiters.limiters[key]
+		return limiters, found
+	}
+
+	// The rate limiters are kept while a client uses them
+	client1.Release()
+	limiters, found := sharedLimiters()
+	assert.True(t, found)
+	assert.Same(t, client2.Client.GetConfig().HTTPClient.Transport.(*rateLimitedTransport).limiters, limiters)
+
+	client2.Release()
+	_, found = sharedLimiters()
+	assert.False(t, found)
+}
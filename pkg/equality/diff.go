@@ -0,0 +1,293 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package equality
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	apiequality "k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/DataDog/datadog-operator/pkg/kubernetes"
+)
+
+const (
+	// maxDiffValueLength is the maximum length of a value reported in a FieldDiff.
+	maxDiffValueLength = 64
+	// redactedValue replaces the values of sensitive fields.
+	redactedValue = "<redacted>"
+	// noValue represents a missing field.
+	noValue = "<none>"
+)
+
+// secretNameFragments are the fragments of the secret-looking names, for example `DD_API_KEY`.
+var secretNameFragments = []string{"key", "token", "password", "secret", "credential"}
+
+// comparedFields lists, for each kind, the top-level fields compared by IsEqualObject.
+var comparedFields = map[kubernetes.ObjectKind][]string{
+	kubernetes.ConfigMapKind:                     {"data", "binaryData"},
+	kubernetes.ClusterRolesKind:                  {"rules"},
+	kubernetes.ClusterRoleBindingKind:            {"roleRef", "subjects"},
+	kubernetes.RolesKind:                         {"rules"},
+	kubernetes.RoleBindingKind:                   {"roleRef", "subjects"},
+	kubernetes.MutatingWebhookConfigurationsKind: {"webhooks"},
+	kubernetes.APIServiceKind:                    {"spec"},
+	kubernetes.SecretsKind:                       {"data", "stringData"},
+	kubernetes.ServicesKind:                      {"spec"},
+	kubernetes.PodDisruptionBudgetsKind:          {"spec"},
+	kubernetes.NetworkPoliciesKind:               {"spec"},
+	kubernetes.PodSecurityPoliciesKind:           {"spec"},
+	kubernetes.CiliumNetworkPoliciesKind:         {"specs"},
+}
+
+// FieldDiff describes the difference between the desired and the current value of a field.
+type FieldDiff struct {
+	// Path of the field, for example `spec.ports[0].port`.
+	Path    string
+	Current string
+	Desired string
+}
+
+// String returns a human readable representation of the FieldDiff.
+func (d FieldDiff) String() string {
+	return fmt.Sprintf("%s: %s -> %s", d.Path, d.Current, d.Desired)
+}
+
+// ObjectDiff lists the pending changes of an object.
+type ObjectDiff struct {
+	Kind      string
+	Namespace string
+	Name      string
+	// Create is true if the object doesn't exist yet.
+	Create bool
+	Fields []FieldDiff
+}
+
+// String returns a human readable representation of the ObjectDiff.
+func (d ObjectDiff) String() string {
+	id := d.Name
+	if d.Namespace != "" {
+		id = fmt.Sprintf("%s/%s", d.Namespace, d.Name)
+	}
+	if d.Create {
+		return fmt.Sprintf("%s %s: to create", d.Kind, id)
+	}
+	paths := make([]string, 0, len(d.Fields))
+	for _, field := range d.Fields {
+		paths = append(paths, field.Path)
+	}
+	return fmt.Sprintf("%s %s: %s", d.Kind, id, strings.Join(paths, ", "))
+}
+
+// DiffObject returns the differences between the desired and the current objects.
+// Only the fields compared by IsEqualObject are taken into account, and the values
+// of Secret fields are redacted.
+func DiffObject(kind kubernetes.ObjectKind, desired, current client.Object) []FieldDiff {
+	desiredMap, currentMap, err := toUnstructured(desired, current)
+	if err != nil {
+		return []FieldDiff{{Path: "", Current: noValue, Desired: err.Error()}}
+	}
+
+	d := differ{redact: kind == kubernetes.SecretsKind}
+	if desired.GetName() != current.GetName() {
+		d.add("metadata.name", current.GetName(), desired.GetName())
+	}
+	if desired.GetNamespace() != current.GetNamespace() {
+		d.add("metadata.namespace", current.GetNamespace(), desired.GetNamespace())
+	}
+	d.diff("metadata.ownerReferences", nestedField(desiredMap, "metadata", "ownerReferences"), nestedField(currentMap, "metadata", "ownerReferences"))
+
+	for _, field := range comparedFields[kind] {
+		d.diff(field, desiredMap[field], currentMap[field])
+	}
+	return d.diffs
+}
+
+// DiffDesiredFields returns the differences between the desired and the current objects on the given
+// top-level fields. Fields only set in the current object are ignored, since they are usually
+// defaulted by the API server. The values of the name/value entries with a secret-looking name,
+// like the `DD_API_KEY` environment variable, are redacted.
+func DiffDesiredFields(desired, current client.Object, fields ...string) []FieldDiff {
+	desiredMap, currentMap, err := toUnstructured(desired, current)
+	if err != nil {
+		return []FieldDiff{{Path: "", Current: noValue, Desired: err.Error()}}
+	}
+
+	d := differ{ignoreCurrentOnly: true, redactSecretEntries: true}
+	for _, field := range fields {
+		d.diff(field, desiredMap[field], currentMap[field])
+	}
+	return d.diffs
+}
+
+func toUnstructured(desired, current client.Object) (map[string]interface{}, map[string]interface{}, error) {
+	desiredMap, err := runtime.DefaultUnstructuredConverter.ToUnstructured(desired)
+	if err != nil {
+		return nil, nil, fmt.Errorf("unable to convert the desired object: %w", err)
+	}
+	currentMap, err := runtime.DefaultUnstructuredConverter.ToUnstructured(current)
+	if err != nil {
+		return nil, nil, fmt.Errorf("unable to convert the current object: %w", err)
+	}
+	return desiredMap, currentMap, nil
+}
+
+func nestedField(obj map[string]interface{}, fields ...string) interface{} {
+	var val interface{} = obj
+	for _, field := range fields {
+		m, ok := val.(map[string]interface{})
+		if !ok {
+			return nil
+		}
+		val = m[field]
+	}
+	return val
+}
+
+type differ struct {
+	redact              bool
+	redactSecretEntries bool
+	ignoreCurrentOnly   bool
+	diffs               []FieldDiff
+}
+
+func (d *differ) diff(path string, desired, current interface{}) {
+	if isEmpty(desired) && isEmpty(current) {
+		return
+	}
+	if isEmpty(desired) && d.ignoreCurrentOnly {
+		return
+	}
+
+	desiredMap, desiredIsMap := desired.(map[string]interface{})
+	currentMap, currentIsMap := current.(map[string]interface{})
+	if desiredIsMap && currentIsMap {
+		if d.redactSecretEntries && !d.redact && (isSecretEntry(desiredMap) || isSecretEntry(currentMap)) {
+			d.redact = true
+			defer func() { d.redact = false }()
+		}
+		keys := make([]string, 0, len(desiredMap)+len(currentMap))
+		for key := range desiredMap {
+			keys = append(keys, key)
+		}
+		for key := range currentMap {
+			if _, found := desiredMap[key]; !found {
+				keys = append(keys, key)
+			}
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			d.diff(joinPath(path, key), desiredMap[key], currentMap[key])
+		}
+		return
+	}
+
+	desiredList, desiredIsList := desired.([]interface{})
+	currentList, currentIsList := current.([]interface{})
+	if desiredIsList && currentIsList && len(desiredList) == len(currentList) {
+		for i := range desiredList {
+			d.diff(fmt.Sprintf("%s[%d]", path, i), desiredList[i], currentList[i])
+		}
+		return
+	}
+
+	if !apiequality.Semantic.DeepEqual(desired, current) {
+		d.add(path, current, desired)
+	}
+}
+
+func (d *differ) add(path string, current, desired interface{}) {
+	redact := d.redact || (d.redactSecretEntries && (containsSecretEntry(current) || containsSecretEntry(desired)))
+	d.diffs = append(d.diffs, FieldDiff{
+		Path:    path,
+		Current: format(current, redact),
+		Desired: format(desired, redact),
+	})
+}
+
+func format(val interface{}, redact bool) string {
+	if isEmpty(val) {
+		return noValue
+	}
+	if redact {
+		return redactedValue
+	}
+	var out string
+	if str, ok := val.(string); ok {
+		out = str
+	} else if raw, err := json.Marshal(val); err == nil {
+		out = string(raw)
+	} else {
+		out = fmt.Sprintf("%v", val)
+	}
+	if len(out) > maxDiffValueLength {
+		out = out[:maxDiffValueLength] + "..."
+	}
+	return out
+}
+
+// isSecretEntry returns whether obj is a name/value entry, like an environment variable, with a secret-looking name.
+func isSecretEntry(obj map[string]interface{}) bool {
+	name, _ := obj["name"].(string)
+	if _, found := obj["value"]; !found || name == "" {
+		return false
+	}
+	name = strings.ToLower(name)
+	for _, fragment := range secretNameFragments {
+		if strings.Contains(name, fragment) {
+			return true
+		}
+	}
+	return false
+}
+
+// containsSecretEntry returns whether val contains a name/value entry with a secret-looking name.
+func containsSecretEntry(val interface{}) bool {
+	switch v := val.(type) {
+	case map[string]interface{}:
+		if isSecretEntry(v) {
+			return true
+		}
+		for _, item := range v {
+			if containsSecretEntry(item) {
+				return true
+			}
+		}
+	case []interface{}:
+		for _, item := range v {
+			if containsSecretEntry(item) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func joinPath(path, key string) string {
+	if path == "" {
+		return key
+	}
+	if strings.ContainsAny(key, "./") {
+		return fmt.Sprintf("%s[%q]", path, key)
+	}
+	return fmt.Sprintf("%s.%s", path, key)
+}
+
+func isEmpty(val interface{}) bool {
+	switch v := val.(type) {
+	case nil:
+		return true
+	case map[string]interface{}:
+		return len(v) == 0
+	case []interface{}:
+		return len(v) == 0
+	}
+	return false
+}
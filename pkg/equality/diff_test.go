@@ -0,0 +1,177 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package equality
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/DataDog/datadog-operator/pkg/kubernetes"
+)
+
+func TestDiffObject(t *testing.T) {
+	objectMeta := metav1.ObjectMeta{Namespace: "bar", Name: "foo"}
+
+	tests := []struct {
+		name    string
+		kind    kubernetes.ObjectKind
+		desired client.Object
+		current client.Object
+		want    []FieldDiff
+	}{
+		{
+			name:    "same ConfigMaps",
+			kind:    kubernetes.ConfigMapKind,
+			desired: &corev1.ConfigMap{ObjectMeta: objectMeta, Data: map[string]string{"key": "value"}},
+			current: &corev1.ConfigMap{ObjectMeta: objectMeta, Data: map[string]string{"key": "value"}},
+			want:    nil,
+		},
+		{
+			name:    "ConfigMap keys changed",
+			kind:    kubernetes.ConfigMapKind,
+			desired: &corev1.ConfigMap{ObjectMeta: objectMeta, Data: map[string]string{"key": "new", "added.yaml": "value"}},
+			current: &corev1.ConfigMap{ObjectMeta: objectMeta, Data: map[string]string{"key": "old", "removed": "value"}},
+			want: []FieldDiff{
+				{Path: `data["added.yaml"]`, Current: noValue, Desired: "value"},
+				{Path: "data.key", Current: "old", Desired: "new"},
+				{Path: "data.removed", Current: "value", Desired: noValue},
+			},
+		},
+		{
+			name: "ClusterRole rules changed",
+			kind: kubernetes.ClusterRolesKind,
+			desired: &rbacv1.ClusterRole{ObjectMeta: objectMeta, Rules: []rbacv1.PolicyRule{
+				{Verbs: []string{"get", "list"}, Resources: []string{"pods"}},
+			}},
+			current: &rbacv1.ClusterRole{ObjectMeta: objectMeta, Rules: []rbacv1.PolicyRule{
+				{Verbs: []string{"get"}, Resources: []string{"pods"}},
+			}},
+			want: []FieldDiff{
+				{Path: "rules[0].verbs", Current: `["get"]`, Desired: `["get","list"]`},
+			},
+		},
+		{
+			name: "owner reference changed",
+			kind: kubernetes.ConfigMapKind,
+			desired: &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{
+				Namespace:       "bar",
+				Name:            "foo",
+				OwnerReferences: []metav1.OwnerReference{{Kind: "DatadogAgent", Name: "dda"}},
+			}},
+			current: &corev1.ConfigMap{ObjectMeta: objectMeta},
+			want: []FieldDiff{
+				{Path: "metadata.ownerReferences", Current: noValue, Desired: `[{"apiVersion":"","kind":"DatadogAgent","name":"dda","uid":""}]`},
+			},
+		},
+		{
+			name:    "Secret values are redacted",
+			kind:    kubernetes.SecretsKind,
+			desired: &corev1.Secret{ObjectMeta: objectMeta, Data: map[string][]byte{"api_key": []byte("new")}},
+			current: &corev1.Secret{ObjectMeta: objectMeta, Data: map[string][]byte{"api_key": []byte("old")}},
+			want: []FieldDiff{
+				{Path: "data.api_key", Current: redactedValue, Desired: redactedValue},
+			},
+		},
+		{
+			name:    "long values are truncated",
+			kind:    kubernetes.ConfigMapKind,
+			desired: &corev1.ConfigMap{ObjectMeta: objectMeta, Data: map[string]string{"key": strings.Repeat("a", 100)}},
+			current: &corev1.ConfigMap{ObjectMeta: objectMeta, Data: map[string]string{"key": "b"}},
+			want: []FieldDiff{
+				{Path: "data.key", Current: "b", Desired: strings.Repeat("a", maxDiffValueLength) + "..."},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := DiffObject(tt.kind, tt.desired, tt.current)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestDiffDesiredFields(t *testing.T) {
+	desired := &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "bar", Name: "foo"},
+		Spec: corev1.ServiceSpec{
+			Ports: []corev1.ServicePort{{Name: "http", Port: 8080}},
+		},
+	}
+
+	// Fields defaulted by the api-server are ignored.
+	current := desired.DeepCopy()
+	current.Spec.ClusterIP = "10.0.0.1"
+	current.Spec.Type = corev1.ServiceTypeClusterIP
+	assert.Empty(t, DiffDesiredFields(desired, current, "spec"))
+
+	current.Spec.Ports[0].Port = 8081
+	assert.Equal(t, []FieldDiff{{Path: "spec.ports[0].port", Current: "8081", Desired: "8080"}}, DiffDesiredFields(desired, current, "spec"))
+}
+
+func TestDiffDesiredFields_redactSecretEntries(t *testing.T) {
+	desired := &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "bar", Name: "foo"},
+		Spec: appsv1.DeploymentSpec{
+			Template: corev1.PodTemplateSpec{
+				Spec: corev1.PodSpec{
+					Containers: []corev1.Container{{
+						Name: "agent",
+						Env: []corev1.EnvVar{
+							{Name: "DD_API_KEY", Value: "new-api-key"},
+							{Name: "DD_SITE", Value: "datadoghq.eu"},
+						},
+					}},
+				},
+			},
+		},
+	}
+
+	current := desired.DeepCopy()
+	current.Spec.Template.Spec.Containers[0].Env[0].Value = "old-api-key"
+	current.Spec.Template.Spec.Containers[0].Env[1].Value = "datadoghq.com"
+	assert.Equal(t, []FieldDiff{
+		{Path: "spec.template.spec.containers[0].env[0].value", Current: redactedValue, Desired: redactedValue},
+		{Path: "spec.template.spec.containers[0].env[1].value", Current: "datadoghq.com", Desired: "datadoghq.eu"},
+	}, DiffDesiredFields(desired, current, "spec"))
+
+	// Lists of different lengths are reported as a whole, and redacted if they contain a secret.
+	current = desired.DeepCopy()
+	current.Spec.Template.Spec.Containers[0].Env = current.Spec.Template.Spec.Containers[0].Env[:1]
+	assert.Equal(t, []FieldDiff{
+		{Path: "spec.template.spec.containers[0].env", Current: redactedValue, Desired: redactedValue},
+	}, DiffDesiredFields(desired, current, "spec"))
+}
+
+func TestObjectDiff_String(t *testing.T) {
+	tests := []struct {
+		name string
+		diff ObjectDiff
+		want string
+	}{
+		{
+			name: "create",
+			diff: ObjectDiff{Kind: "configmaps", Namespace: "bar", Name: "foo", Create: true},
+			want: "configmaps bar/foo: to create",
+		},
+		{
+			name: "update cluster scoped object",
+			diff: ObjectDiff{Kind: "clusterroles", Name: "foo", Fields: []FieldDiff{{Path: "rules[0].verbs"}, {Path: "rules[1]"}}},
+			want: "clusterroles foo: rules[0].verbs, rules[1]",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.diff.String())
+		})
+	}
+}
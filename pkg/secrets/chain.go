@@ -0,0 +1,63 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package secrets
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// NewChainDecryptor returns a new ChainDecryptor trying the given Decryptors in order
+func NewChainDecryptor(decryptors ...Decryptor) *ChainDecryptor {
+	return &ChainDecryptor{
+		decryptors: decryptors,
+	}
+}
+
+// Decrypt tries to decrypt each secret of a given string slice with the first Decryptor able to decrypt it
+// The returned error is retriable if one of the Decryptors returned a retriable error
+func (c *ChainDecryptor) Decrypt(encrypted []string) (map[string]string, error) {
+	if len(c.decryptors) == 0 {
+		return nil, NewDecryptorError(errors.New("no secret provider configured"), false)
+	}
+
+	decrypted := map[string]string{}
+	for _, enc := range encrypted {
+		if !IsEnc(enc) {
+			return nil, NewDecryptorError(fmt.Errorf("wrong format, want ENC[<handle>], got: %s", enc), false)
+		}
+
+		value, err := c.decrypt(enc)
+		if err != nil {
+			return nil, err
+		}
+
+		decrypted[enc] = value
+	}
+
+	return decrypted, nil
+}
+
+// decrypt tries the Decryptors in order and returns the first decrypted value
+func (c *ChainDecryptor) decrypt(enc string) (string, error) {
+	errs := make([]string, 0, len(c.decryptors))
+	retriable := false
+	for _, decryptor := range c.decryptors {
+		decrypted, err := decryptor.Decrypt([]string{enc})
+		if err == nil {
+			if value, found := decrypted[enc]; found {
+				return value, nil
+			}
+			err = errors.New("secret not returned")
+		}
+
+		errs = append(errs, err.Error())
+		retriable = retriable || Retriable(err)
+	}
+
+	return "", NewDecryptorError(fmt.Errorf("unable to decrypt '%s': %s", enc, strings.Join(errs, "; ")), retriable)
+}
@@ -0,0 +1,105 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package secrets
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+// staticDecryptor decrypts the secrets it knows and returns err otherwise
+type staticDecryptor struct {
+	secrets map[string]string
+	err     error
+}
+
+func (d *staticDecryptor) Decrypt(encrypted []string) (map[string]string, error) {
+	decrypted := map[string]string{}
+	for _, enc := range encrypted {
+		value, found := d.secrets[enc]
+		if !found {
+			return nil, d.err
+		}
+		decrypted[enc] = value
+	}
+	return decrypted, nil
+}
+
+func TestChainDecryptor_Decrypt(t *testing.T) {
+	first := &staticDecryptor{
+		secrets: map[string]string{"ENC[api_key]": "first_api_key"},
+		err:     NewDecryptorError(errors.New("first error"), false),
+	}
+	second := &staticDecryptor{
+		secrets: map[string]string{"ENC[api_key]": "second_api_key", "ENC[app_key]": "second_app_key"},
+		err:     NewDecryptorError(errors.New("second error"), false),
+	}
+	retriable := &staticDecryptor{
+		err: NewDecryptorError(errors.New("retriable error"), true),
+	}
+
+	tests := []struct {
+		name          string
+		decryptors    []Decryptor
+		encrypted     []string
+		want          map[string]string
+		wantErr       bool
+		wantRetriable bool
+	}{
+		{
+			name:       "first decryptor wins",
+			decryptors: []Decryptor{first, second},
+			encrypted:  []string{"ENC[api_key]", "ENC[app_key]"},
+			want:       map[string]string{"ENC[api_key]": "first_api_key", "ENC[app_key]": "second_app_key"},
+		},
+		{
+			name:       "all decryptors fail",
+			decryptors: []Decryptor{first, second},
+			encrypted:  []string{"ENC[unknown]"},
+			wantErr:    true,
+		},
+		{
+			name:          "one decryptor returns a retriable error",
+			decryptors:    []Decryptor{retriable, first},
+			encrypted:     []string{"ENC[app_key]"},
+			wantErr:       true,
+			wantRetriable: true,
+		},
+		{
+			name:       "retriable error ignored if another decryptor succeeds",
+			decryptors: []Decryptor{retriable, first},
+			encrypted:  []string{"ENC[api_key]"},
+			want:       map[string]string{"ENC[api_key]": "first_api_key"},
+		},
+		{
+			name:       "wrong format",
+			decryptors: []Decryptor{first},
+			encrypted:  []string{"api_key"},
+			wantErr:    true,
+		},
+		{
+			name:      "no decryptor",
+			encrypted: []string{"ENC[api_key]"},
+			wantErr:   true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := NewChainDecryptor(tt.decryptors...).Decrypt(tt.encrypted)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ChainDecryptor.Decrypt() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if Retriable(err) != tt.wantRetriable {
+				t.Errorf("ChainDecryptor.Decrypt() retriable = %v, want %v", Retriable(err), tt.wantRetriable)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ChainDecryptor.Decrypt() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
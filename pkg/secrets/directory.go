@@ -0,0 +1,116 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package secrets
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+const (
+	// MaxSecretFileSize is the maximum size of a file containing a secret
+	MaxSecretFileSize = 8192
+)
+
+// NewDirectoryProvider returns a new DirectoryProvider reading secrets from the given directory
+func NewDirectoryProvider(dir string) *DirectoryProvider {
+	return &DirectoryProvider{
+		dir: dir,
+	}
+}
+
+// Decrypt tries to decrypt a given string slice by reading the files of the directory
+func (p *DirectoryProvider) Decrypt(encrypted []string) (map[string]string, error) {
+	handles, err := extractHandles(encrypted)
+	if err != nil {
+		return nil, NewDecryptorError(err, false)
+	}
+
+	decrypted := map[string]string{}
+	for _, handle := range handles {
+		// Only files at the root of the directory can be read
+		if handle == "" || handle == "." || handle == ".." || strings.ContainsRune(handle, filepath.Separator) {
+			return nil, NewDecryptorError(fmt.Errorf("invalid secret handle '%s', want a file name", handle), false)
+		}
+
+		value, err := ReadSecretFile(filepath.Join(p.dir, handle))
+		if err != nil {
+			return nil, NewDecryptorError(fmt.Errorf("an error occurred while reading '%s': %w", handle, err), false)
+		}
+		if value == "" {
+			return nil, NewDecryptorError(fmt.Errorf("decrypted secret for '%s' is empty", handle), false)
+		}
+
+		decrypted[encFormat(handle)] = value
+	}
+
+	return decrypted, nil
+}
+
+// ReadSecretFile reads a secret from a file
+// Symlinks are only followed if they target a file in the same directory
+func ReadSecretFile(path string) (string, error) {
+	fi, err := os.Lstat(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return "", errors.New("secret does not exist")
+		}
+		return "", err
+	}
+
+	if fi.Mode()&os.ModeSymlink != 0 {
+		// Ensure that the symlink is in the same dir
+		var target string
+		target, err = os.Readlink(path)
+		if err != nil {
+			return "", fmt.Errorf("failed to read symlink target: %w", err)
+		}
+
+		dir := filepath.Dir(path)
+		if !filepath.IsAbs(target) {
+			target, err = filepath.Abs(filepath.Join(dir, target))
+			if err != nil {
+				return "", fmt.Errorf("failed to resolve symlink absolute path: %w", err)
+			}
+		}
+
+		var dirAbs string
+		dirAbs, err = filepath.Abs(dir)
+		if err != nil {
+			return "", fmt.Errorf("failed to resolve absolute path of directory: %w", err)
+		}
+
+		if !strings.HasPrefix(target, dirAbs) {
+			return "", fmt.Errorf("not following symlink %q outside of %q", target, dir)
+		}
+	}
+	fi, err = os.Stat(path)
+	if err != nil {
+		return "", err
+	}
+
+	if fi.Size() > MaxSecretFileSize {
+		return "", errors.New("secret exceeds max allowed size")
+	}
+
+	var file *os.File
+	file, err = os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+
+	var bytes []byte
+	bytes, err = ioutil.ReadAll(file)
+	if err != nil {
+		return "", err
+	}
+	return string(bytes), nil
+}
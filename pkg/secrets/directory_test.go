@@ -0,0 +1,100 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package secrets
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestDirectoryProvider_Decrypt(t *testing.T) {
+	dir := t.TempDir()
+	outsideDir := t.TempDir()
+	writeFile(t, filepath.Join(dir, "api_key"), "decrypted_api_key")
+	writeFile(t, filepath.Join(dir, "app_key"), "decrypted_app_key")
+	writeFile(t, filepath.Join(dir, "empty"), "")
+	writeFile(t, filepath.Join(dir, "too_big"), strings.Repeat("a", MaxSecretFileSize+1))
+	writeFile(t, filepath.Join(outsideDir, "outside"), "outside")
+	if err := os.Symlink("api_key", filepath.Join(dir, "link")); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Symlink(filepath.Join(outsideDir, "outside"), filepath.Join(dir, "outside_link")); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name      string
+		encrypted []string
+		want      map[string]string
+		wantErr   bool
+	}{
+		{
+			name:      "nominal case",
+			encrypted: []string{"ENC[api_key]", "ENC[app_key]"},
+			want:      map[string]string{"ENC[api_key]": "decrypted_api_key", "ENC[app_key]": "decrypted_app_key"},
+		},
+		{
+			name:      "symlink in the directory",
+			encrypted: []string{"ENC[link]"},
+			want:      map[string]string{"ENC[link]": "decrypted_api_key"},
+		},
+		{
+			name:      "symlink outside of the directory",
+			encrypted: []string{"ENC[outside_link]"},
+			wantErr:   true,
+		},
+		{
+			name:      "path traversal",
+			encrypted: []string{"ENC[../outside]"},
+			wantErr:   true,
+		},
+		{
+			name:      "secret not found",
+			encrypted: []string{"ENC[api_key]", "ENC[not_found]"},
+			wantErr:   true,
+		},
+		{
+			name:      "empty secret",
+			encrypted: []string{"ENC[empty]"},
+			wantErr:   true,
+		},
+		{
+			name:      "secret too big",
+			encrypted: []string{"ENC[too_big]"},
+			wantErr:   true,
+		},
+		{
+			name:      "wrong format",
+			encrypted: []string{"api_key"},
+			wantErr:   true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := NewDirectoryProvider(dir).Decrypt(tt.encrypted)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("DirectoryProvider.Decrypt() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if err != nil && Retriable(err) {
+				t.Errorf("DirectoryProvider.Decrypt() error = %v, want a permanent error", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("DirectoryProvider.Decrypt() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+}
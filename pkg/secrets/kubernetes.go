@@ -0,0 +1,92 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package secrets
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// KubernetesSecretHandlePrefix is the prefix of the secret handles resolved by the KubernetesSecretProvider
+	KubernetesSecretHandlePrefix = "k8s_secret@"
+
+	defaultKubernetesSecretTimeout = 5 * time.Second
+)
+
+// NewKubernetesSecretProvider returns a new KubernetesSecretProvider reading Secrets with the given reader
+func NewKubernetesSecretProvider(reader client.Reader) *KubernetesSecretProvider {
+	return &KubernetesSecretProvider{
+		reader:  reader,
+		timeout: defaultKubernetesSecretTimeout,
+	}
+}
+
+// Decrypt tries to decrypt a given string slice by reading Kubernetes Secrets
+func (p *KubernetesSecretProvider) Decrypt(encrypted []string) (map[string]string, error) {
+	handles, err := extractHandles(encrypted)
+	if err != nil {
+		return nil, NewDecryptorError(err, false)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), p.timeout)
+	defer cancel()
+
+	// The API and APP keys are usually stored in the same Secret, only get it once
+	secrets := map[types.NamespacedName]*corev1.Secret{}
+	decrypted := map[string]string{}
+	for _, handle := range handles {
+		nsName, key, err := parseKubernetesSecretHandle(handle)
+		if err != nil {
+			return nil, NewDecryptorError(err, false)
+		}
+
+		secret, found := secrets[nsName]
+		if !found {
+			secret = &corev1.Secret{}
+			if err = p.reader.Get(ctx, nsName, secret); err != nil {
+				// A missing Secret or missing permissions require a user action
+				retriable := !apierrors.IsNotFound(err) && !apierrors.IsForbidden(err)
+				return nil, NewDecryptorError(fmt.Errorf("unable to get the secret %s: %w", nsName, err), retriable)
+			}
+			secrets[nsName] = secret
+		}
+
+		value, found := secret.Data[key]
+		if !found {
+			return nil, NewDecryptorError(fmt.Errorf("key '%s' not found in the secret %s", key, nsName), false)
+		}
+		if len(value) == 0 {
+			return nil, NewDecryptorError(fmt.Errorf("decrypted secret for '%s' is empty", handle), false)
+		}
+
+		decrypted[encFormat(handle)] = string(value)
+	}
+
+	return decrypted, nil
+}
+
+// parseKubernetesSecretHandle returns the Secret and the key referenced by a handle
+// respecting the format k8s_secret@<namespace>/<name>/<key>
+func parseKubernetesSecretHandle(handle string) (types.NamespacedName, string, error) {
+	if !strings.HasPrefix(handle, KubernetesSecretHandlePrefix) {
+		return types.NamespacedName{}, "", fmt.Errorf("wrong format, want %s<namespace>/<name>/<key>, got: %s", KubernetesSecretHandlePrefix, handle)
+	}
+
+	parts := strings.Split(strings.TrimPrefix(handle, KubernetesSecretHandlePrefix), "/")
+	if len(parts) != 3 || parts[0] == "" || parts[1] == "" || parts[2] == "" {
+		return types.NamespacedName{}, "", fmt.Errorf("wrong format, want %s<namespace>/<name>/<key>, got: %s", KubernetesSecretHandlePrefix, handle)
+	}
+
+	return types.NamespacedName{Namespace: parts[0], Name: parts[1]}, parts[2], nil
+}
@@ -0,0 +1,115 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package secrets
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestKubernetesSecretProvider_Decrypt(t *testing.T) {
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "datadog",
+			Name:      "datadog-secret",
+		},
+		Data: map[string][]byte{
+			"api-key": []byte("decrypted_api_key"),
+			"app-key": []byte("decrypted_app_key"),
+			"empty":   {},
+		},
+	}
+
+	tests := []struct {
+		name          string
+		reader        client.Reader
+		encrypted     []string
+		want          map[string]string
+		wantErr       bool
+		wantRetriable bool
+	}{
+		{
+			name:      "nominal case",
+			reader:    fake.NewClientBuilder().WithObjects(secret).Build(),
+			encrypted: []string{"ENC[k8s_secret@datadog/datadog-secret/api-key]", "ENC[k8s_secret@datadog/datadog-secret/app-key]"},
+			want: map[string]string{
+				"ENC[k8s_secret@datadog/datadog-secret/api-key]": "decrypted_api_key",
+				"ENC[k8s_secret@datadog/datadog-secret/app-key]": "decrypted_app_key",
+			},
+		},
+		{
+			name:      "secret not found",
+			reader:    fake.NewClientBuilder().Build(),
+			encrypted: []string{"ENC[k8s_secret@datadog/datadog-secret/api-key]"},
+			wantErr:   true,
+		},
+		{
+			name:      "key not found",
+			reader:    fake.NewClientBuilder().WithObjects(secret).Build(),
+			encrypted: []string{"ENC[k8s_secret@datadog/datadog-secret/not-found]"},
+			wantErr:   true,
+		},
+		{
+			name:      "empty secret",
+			reader:    fake.NewClientBuilder().WithObjects(secret).Build(),
+			encrypted: []string{"ENC[k8s_secret@datadog/datadog-secret/empty]"},
+			wantErr:   true,
+		},
+		{
+			name:          "api server error",
+			reader:        &errorReader{err: errors.New("connection refused")},
+			encrypted:     []string{"ENC[k8s_secret@datadog/datadog-secret/api-key]"},
+			wantErr:       true,
+			wantRetriable: true,
+		},
+		{
+			name:      "not a kubernetes secret handle",
+			reader:    fake.NewClientBuilder().WithObjects(secret).Build(),
+			encrypted: []string{"ENC[api_key]"},
+			wantErr:   true,
+		},
+		{
+			name:      "missing key in the handle",
+			reader:    fake.NewClientBuilder().WithObjects(secret).Build(),
+			encrypted: []string{"ENC[k8s_secret@datadog/datadog-secret]"},
+			wantErr:   true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := NewKubernetesSecretProvider(tt.reader).Decrypt(tt.encrypted)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("KubernetesSecretProvider.Decrypt() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if Retriable(err) != tt.wantRetriable {
+				t.Errorf("KubernetesSecretProvider.Decrypt() retriable = %v, want %v", Retriable(err), tt.wantRetriable)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("KubernetesSecretProvider.Decrypt() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+type errorReader struct {
+	err error
+}
+
+func (r *errorReader) Get(context.Context, client.ObjectKey, client.Object) error {
+	return r.err
+}
+
+func (r *errorReader) List(context.Context, client.ObjectList, ...client.ListOption) error {
+	return r.err
+}
@@ -14,11 +14,15 @@ import (
 	"os/exec"
 	"strings"
 	"time"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
 var (
 	secretBackendCommand = ""
 	secretBackendArgs    = []string{}
+	secretsDirectory     = ""
+	kubernetesReader     client.Reader
 )
 
 const (
@@ -39,6 +43,39 @@ func SetSecretBackendArgs(args []string) {
 	secretBackendArgs = args
 }
 
+// SetSecretsDirectory set the secretsDirectory var
+func SetSecretsDirectory(dir string) {
+	secretsDirectory = dir
+}
+
+// SetKubernetesReader set the kubernetesReader var used to read Kubernetes Secrets
+func SetKubernetesReader(reader client.Reader) {
+	kubernetesReader = reader
+}
+
+// NewDecryptor returns a Decryptor trying the configured secret providers in order:
+// Kubernetes Secrets, secrets directory, then secret backend command
+// It returns a SecretBackend if no provider is configured
+func NewDecryptor() Decryptor {
+	decryptors := []Decryptor{}
+	if kubernetesReader != nil {
+		decryptors = append(decryptors, NewKubernetesSecretProvider(kubernetesReader))
+	}
+	if secretsDirectory != "" {
+		decryptors = append(decryptors, NewDirectoryProvider(secretsDirectory))
+	}
+
+	secretBackend := NewSecretBackend()
+	if secretBackend.isConfigured() || len(decryptors) == 0 {
+		decryptors = append(decryptors, secretBackend)
+	}
+
+	if len(decryptors) == 1 {
+		return decryptors[0]
+	}
+	return NewChainDecryptor(decryptors...)
+}
+
 // NewSecretBackend returns a new SecretBackend instance
 func NewSecretBackend() *SecretBackend {
 	return &SecretBackend{
@@ -11,6 +11,7 @@ import (
 	"time"
 
 	"github.com/stretchr/testify/mock"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
 // DecryptorError describes the error returned by a Decryptor
@@ -53,7 +54,7 @@ func Retriable(err error) bool {
 }
 
 // Decryptor is used to decrypt encrypted secrets
-// Decryptor is implemented by SecretBackend
+// Decryptor is implemented by SecretBackend, DirectoryProvider, KubernetesSecretProvider and ChainDecryptor
 type Decryptor interface {
 	Decrypt([]string) (map[string]string, error)
 }
@@ -67,6 +68,27 @@ type SecretBackend struct {
 	cmdTimeout       time.Duration
 }
 
+// DirectoryProvider retrieves secrets from the files of a directory, e.g. a mounted Secret volume
+// The secret handle is the name of the file containing the secret
+// DirectoryProvider implements the Decryptor interface
+type DirectoryProvider struct {
+	dir string
+}
+
+// KubernetesSecretProvider retrieves secrets from Kubernetes Secrets
+// The secret handle respects the format k8s_secret@<namespace>/<name>/<key>
+// KubernetesSecretProvider implements the Decryptor interface
+type KubernetesSecretProvider struct {
+	reader  client.Reader
+	timeout time.Duration
+}
+
+// ChainDecryptor tries a list of Decryptors in order until one of them decrypts the secret
+// ChainDecryptor implements the Decryptor interface
+type ChainDecryptor struct {
+	decryptors []Decryptor
+}
+
 // Secret defines the structure for secrets in JSON output
 type Secret struct {
 	Value    string `json:"value,omitempty"`